@@ -10,6 +10,7 @@ import (
 
 	"github.com/spf13/cobra/doc"
 
+	importpkg "github.com/vmware-tanzu/kpack-cli/pkg/import"
 	"github.com/vmware-tanzu/kpack-cli/pkg/rootcommand"
 )
 
@@ -23,4 +24,14 @@ func main() {
 	if err != nil {
 		os.Exit(1)
 	}
+
+	schema, err := importpkg.Schema()
+	if err != nil {
+		os.Exit(1)
+	}
+
+	err = os.WriteFile("./docs/dependency-descriptor.schema.json", append(schema, '\n'), 0644)
+	if err != nil {
+		os.Exit(1)
+	}
 }
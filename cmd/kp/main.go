@@ -4,10 +4,12 @@
 package main
 
 import (
+	"errors"
 	"io"
 	"log"
 	"os"
 
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	"github.com/vmware-tanzu/kpack-cli/pkg/rootcommand"
 )
 
@@ -17,6 +19,10 @@ func main() {
 	cmd := rootcommand.GetRootCommand()
 	err := cmd.Execute()
 	if err != nil {
+		var exitErr commands.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,227 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type FailureCause string
+
+const (
+	CauseDetectionFailed    FailureCause = "DetectionFailed"
+	CauseRegistryAuthDenied FailureCause = "RegistryAuthDenied"
+	CauseOOMKilled          FailureCause = "OOMKilled"
+	CauseImagePullFailed    FailureCause = "ImagePullFailed"
+	CauseGitFetchFailed     FailureCause = "GitFetchFailed"
+	CauseUnknown            FailureCause = "Unknown"
+
+	// DefaultDiagnosisLogLines is the number of log lines of the failed step that are inspected
+	DefaultDiagnosisLogLines = 50
+
+	maxExcerptLines = 10
+)
+
+var (
+	detectionFailedRegex = regexp.MustCompile(`(?i)no buildpack groups? passed detection|no buildpacks participating`)
+	registryAuthRegex    = regexp.MustCompile(`(?i)unauthorized|denied|authentication required|forbidden|\b401\b|\b403\b`)
+	gitFetchRegex        = regexp.MustCompile(`(?i)fetch|clone|repository|remote|authentication required|could not resolve host`)
+	imagePullReasons     = map[string]bool{"ErrImagePull": true, "ImagePullBackOff": true, "InvalidImageName": true}
+)
+
+// Diagnosis describes the most likely cause of a failed build
+type Diagnosis struct {
+	Step        string       `json:"step,omitempty"`
+	ExitCode    *int32       `json:"exitCode,omitempty"`
+	Reason      string       `json:"reason,omitempty"`
+	Cause       FailureCause `json:"cause"`
+	Description string       `json:"description"`
+	Excerpt     []string     `json:"excerpt,omitempty"`
+}
+
+type Diagnoser struct {
+	// K8sClient is used to read the pod and logs of the build, only the
+	// step states recorded on the build are inspected when it is nil
+	K8sClient kubernetes.Interface
+	LogLines  int64
+	// Warnings receives a warning when the pod of the build cannot be read,
+	// the diagnosis falls back to the step states recorded on the build
+	Warnings io.Writer
+}
+
+// Diagnose returns the likely cause of a failed build, or nil when the
+// build did not fail or its failed step cannot be determined
+func (d Diagnoser) Diagnose(ctx context.Context, bld v1alpha2.Build) (*Diagnosis, error) {
+	if Status(bld) != "FAILURE" {
+		return nil, nil
+	}
+
+	step, state, pod := d.failedStep(ctx, bld)
+	if state == nil {
+		return nil, nil
+	}
+
+	var logLines []string
+	if pod != nil && step != "" && state.Terminated != nil {
+		logLines = d.lastLogLines(ctx, pod, step)
+	}
+
+	return diagnose(bld, step, *state, logLines), nil
+}
+
+func (d Diagnoser) failedStep(ctx context.Context, bld v1alpha2.Build) (string, *corev1.ContainerState, *corev1.Pod) {
+	if d.K8sClient != nil && bld.Status.PodName != "" {
+		pod, err := d.K8sClient.CoreV1().Pods(bld.Namespace).Get(ctx, bld.Status.PodName, metav1.GetOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) && d.Warnings != nil {
+			_, _ = fmt.Fprintf(d.Warnings, "Warning: could not read pod %q of the build, diagnosing from the build status: %s\n", bld.Status.PodName, err)
+		}
+
+		if err == nil {
+			statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+			for _, status := range statuses {
+				if isStep(status.Name) && failed(status.State) {
+					state := status.State
+					return status.Name, &state, pod
+				}
+			}
+		}
+	}
+
+	// the pod is gone or cannot be read, fall back to the step states recorded on the build which do not include the step names
+	for _, state := range bld.Status.StepStates {
+		if failed(state) {
+			state := state
+			return "", &state, nil
+		}
+	}
+
+	return "", nil, nil
+}
+
+func (d Diagnoser) lastLogLines(ctx context.Context, pod *corev1.Pod, step string) []string {
+	tail := d.LogLines
+	if tail <= 0 {
+		tail = DefaultDiagnosisLogLines
+	}
+
+	stream, err := d.K8sClient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: step,
+		TailLines: &tail,
+	}).Stream(ctx)
+	if err != nil {
+		// logs are best effort, the container state is still useful without them
+		return nil
+	}
+	defer stream.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(ansiRegex.ReplaceAllString(scanner.Text(), "")); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func failed(state corev1.ContainerState) bool {
+	if state.Terminated != nil {
+		return state.Terminated.ExitCode != 0
+	}
+	return state.Waiting != nil && imagePullReasons[state.Waiting.Reason]
+}
+
+func diagnose(bld v1alpha2.Build, step string, state corev1.ContainerState, logLines []string) *Diagnosis {
+	diagnosis := &Diagnosis{Step: step}
+	stepName := step
+	if stepName == "" {
+		stepName = "failed"
+	}
+
+	if state.Waiting != nil {
+		diagnosis.Reason = state.Waiting.Reason
+		diagnosis.Cause = CauseImagePullFailed
+		diagnosis.Description = fmt.Sprintf("The image of the %s step could not be pulled. Check that the builder and run images exist and that the service account can pull them.", stepName)
+		if state.Waiting.Message != "" {
+			diagnosis.Excerpt = []string{state.Waiting.Message}
+		}
+		return diagnosis
+	}
+
+	terminated := state.Terminated
+	exitCode := terminated.ExitCode
+	diagnosis.ExitCode = &exitCode
+	diagnosis.Reason = terminated.Reason
+
+	// the termination message usually repeats the error that ended the step
+	if terminated.Message != "" {
+		logLines = append(logLines, strings.Split(strings.TrimSpace(terminated.Message), "\n")...)
+	}
+
+	if terminated.Reason == "OOMKilled" {
+		diagnosis.Cause = CauseOOMKilled
+		diagnosis.Description = fmt.Sprintf("The %s step ran out of memory and was killed. Consider raising the memory limit of the build.", stepName)
+		diagnosis.Excerpt = lastLines(logLines, 3)
+		return diagnosis
+	}
+
+	// the lifecycle exits with 20 (100 on older platform apis) when no group passes detection
+	if excerpt := matchingLines(logLines, detectionFailedRegex); step == v1alpha2.DetectContainerName && (len(excerpt) > 0 || exitCode == 20 || exitCode == 100) {
+		diagnosis.Cause = CauseDetectionFailed
+		diagnosis.Description = "No buildpack group passed detection. Check that the source contains an app supported by the buildpacks of the builder and that the correct subpath is set."
+		diagnosis.Excerpt = excerpt
+		return diagnosis
+	}
+
+	if excerpt := matchingLines(logLines, registryAuthRegex); step == v1alpha2.ExportContainerName && len(excerpt) > 0 {
+		diagnosis.Cause = CauseRegistryAuthDenied
+		tag := "the image"
+		if len(bld.Spec.Tags) > 0 {
+			tag = bld.Spec.Tags[0]
+		}
+		diagnosis.Description = fmt.Sprintf("The registry denied access while exporting %s. Check that the service account has a registry secret with push access to the image tag.", tag)
+		diagnosis.Excerpt = excerpt
+		return diagnosis
+	}
+
+	if excerpt := matchingLines(logLines, gitFetchRegex); step == v1alpha2.PrepareContainerName && bld.Spec.Source.Git != nil && len(excerpt) > 0 {
+		diagnosis.Cause = CauseGitFetchFailed
+		diagnosis.Description = fmt.Sprintf("The git repository %s could not be fetched at revision %s. Check the url, the revision and the git secret of the service account.", bld.Spec.Source.Git.URL, bld.Spec.Source.Git.Revision)
+		diagnosis.Excerpt = excerpt
+		return diagnosis
+	}
+
+	diagnosis.Cause = CauseUnknown
+	diagnosis.Description = fmt.Sprintf("The %s step failed with exit code %d.", stepName, exitCode)
+	diagnosis.Excerpt = lastLines(logLines, 5)
+	return diagnosis
+}
+
+func matchingLines(lines []string, regex *regexp.Regexp) []string {
+	var matches []string
+	for _, line := range lines {
+		if regex.MatchString(line) {
+			matches = append(matches, line)
+		}
+	}
+	return lastLines(matches, maxExcerptLines)
+}
+
+func lastLines(lines []string, n int) []string {
+	if len(lines) > n {
+		return lines[len(lines)-n:]
+	}
+	return lines
+}
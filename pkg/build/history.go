@@ -0,0 +1,153 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+)
+
+// HistoryEntry summarizes a single build of an image and what changed since the previous build
+type HistoryEntry struct {
+	BuildNumber      string            `json:"buildNumber"`
+	Name             string            `json:"name"`
+	Status           string            `json:"status"`
+	Reasons          []string          `json:"reasons,omitempty"`
+	Revision         string            `json:"revision,omitempty"`
+	RunImage         string            `json:"runImage,omitempty"`
+	PreviousRunImage string            `json:"previousRunImage,omitempty"`
+	Image            string            `json:"image,omitempty"`
+	Started          time.Time         `json:"started"`
+	Finished         *time.Time        `json:"finished,omitempty"`
+	Duration         string            `json:"duration,omitempty"`
+	BuildpackChanges []BuildpackChange `json:"buildpackChanges,omitempty"`
+}
+
+// BuildpackChange describes a buildpack whose version differs from the previous build.
+// An empty OldVersion means the buildpack was added, an empty NewVersion means it was removed.
+type BuildpackChange struct {
+	Id         string `json:"id"`
+	OldVersion string `json:"oldVersion,omitempty"`
+	NewVersion string `json:"newVersion,omitempty"`
+}
+
+// History returns an entry for each build, the builds must be sorted with Sort.
+// Buildpack and run image changes are computed against the closest previous build
+// that reported build metadata, as failed builds often report none.
+func History(builds []v1alpha2.Build) []HistoryEntry {
+	var (
+		entries  []HistoryEntry
+		previous *v1alpha2.Build
+	)
+
+	for i := range builds {
+		bld := builds[i]
+		entry := HistoryEntry{
+			BuildNumber: bld.Labels[v1alpha2.BuildNumberLabel],
+			Name:        bld.Name,
+			Status:      Status(bld),
+			Reasons:     Reasons(bld),
+			Revision:    SourceRevision(bld),
+			RunImage:    bld.Status.Stack.RunImage,
+			Image:       bld.Status.LatestImage,
+			Started:     bld.CreationTimestamp.Time,
+		}
+
+		if !bld.IsRunning() {
+			finished := bld.Status.GetCondition(corev1alpha1.ConditionSucceeded).LastTransitionTime.Inner.Time
+			entry.Finished = &finished
+			if finished.After(entry.Started) {
+				entry.Duration = finished.Sub(entry.Started).Round(time.Second).String()
+			}
+		}
+
+		if previous != nil && len(bld.Status.BuildMetadata) > 0 {
+			entry.BuildpackChanges = BuildpackChanges(previous.Status.BuildMetadata, bld.Status.BuildMetadata)
+			if previous.Status.Stack.RunImage != bld.Status.Stack.RunImage {
+				entry.PreviousRunImage = previous.Status.Stack.RunImage
+			}
+		}
+
+		if len(bld.Status.BuildMetadata) > 0 {
+			previous = &builds[i]
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// BuildpackChanges returns the buildpacks that were added, removed or changed version, sorted by id
+func BuildpackChanges(old, new corev1alpha1.BuildpackMetadataList) []BuildpackChange {
+	oldVersions := map[string]string{}
+	for _, bp := range old {
+		oldVersions[bp.Id] = bp.Version
+	}
+
+	newVersions := map[string]string{}
+	for _, bp := range new {
+		newVersions[bp.Id] = bp.Version
+	}
+
+	var changes []BuildpackChange
+	for id, newVersion := range newVersions {
+		if oldVersion := oldVersions[id]; oldVersion != newVersion {
+			changes = append(changes, BuildpackChange{Id: id, OldVersion: oldVersion, NewVersion: newVersion})
+		}
+	}
+	for id, oldVersion := range oldVersions {
+		if _, ok := newVersions[id]; !ok {
+			changes = append(changes, BuildpackChange{Id: id, OldVersion: oldVersion})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Id < changes[j].Id
+	})
+	return changes
+}
+
+// Status returns the status of a build as displayed by kp
+func Status(bld v1alpha2.Build) string {
+	cond := bld.Status.GetCondition(corev1alpha1.ConditionSucceeded)
+	switch {
+	case cond.IsTrue():
+		return "SUCCESS"
+	case cond.IsFalse():
+		return "FAILURE"
+	case cond.IsUnknown():
+		return "BUILDING"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Reasons returns the reasons recorded in the build reason annotation
+func Reasons(bld v1alpha2.Build) []string {
+	s := strings.Split(bld.Annotations[v1alpha2.BuildReasonAnnotation], ",")
+	if len(s) == 1 && s[0] == "" {
+		return nil
+	}
+	return s
+}
+
+// SourceRevision returns the resolved git revision of a build, or the blob url
+// or source image for builds that do not build from git
+func SourceRevision(bld v1alpha2.Build) string {
+	switch {
+	case bld.Spec.Source.Git != nil:
+		return bld.Spec.Source.Git.Revision
+	case bld.Spec.Source.Blob != nil:
+		return bld.Spec.Source.Blob.URL
+	case bld.Spec.Source.Registry != nil:
+		return bld.Spec.Source.Registry.Image
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	"github.com/pivotal/kpack/pkg/client/clientset/versioned"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+const webhookTimeout = 30 * time.Second
+
+// CompletionEvent is the payload passed to on-complete hooks when a build finishes
+type CompletionEvent struct {
+	Namespace   string   `json:"namespace"`
+	Image       string   `json:"image"`
+	Build       string   `json:"build"`
+	Status      string   `json:"status"`
+	LatestImage string   `json:"latestImage"`
+	Digest      string   `json:"digest"`
+	Reasons     []string `json:"reasons"`
+}
+
+func NewCompletionEvent(bld v1alpha2.Build) CompletionEvent {
+	var digest string
+	if idx := strings.LastIndex(bld.Status.LatestImage, "@"); idx != -1 {
+		digest = bld.Status.LatestImage[idx+1:]
+	}
+
+	reasons := Reasons(bld)
+	if reasons == nil {
+		reasons = []string{}
+	}
+
+	return CompletionEvent{
+		Namespace:   bld.Namespace,
+		Image:       bld.Labels[v1alpha2.ImageLabel],
+		Build:       bld.Labels[v1alpha2.BuildNumberLabel],
+		Status:      Status(bld),
+		LatestImage: bld.Status.LatestImage,
+		Digest:      digest,
+		Reasons:     reasons,
+	}
+}
+
+// CompletionHook is notified when a build finishes
+type CompletionHook interface {
+	Notify(ctx context.Context, event CompletionEvent) error
+}
+
+// NewCompletionHook returns a webhook for http and https urls and a local
+// command hook for anything else
+func NewCompletionHook(target string, out, errOut io.Writer) CompletionHook {
+	if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
+		return WebhookHook{URL: target, Client: &http.Client{Timeout: webhookTimeout}}
+	}
+	return CommandHook{Command: target, Out: out, ErrOut: errOut}
+}
+
+// WebhookHook posts the event as json to a url
+type WebhookHook struct {
+	URL    string
+	Client *http.Client
+}
+
+func (h WebhookHook) Notify(ctx context.Context, event CompletionEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// CommandHook runs a local shell command with the event as json on stdin
+// and its fields in KP_* environment variables
+type CommandHook struct {
+	Command string
+	Out     io.Writer
+	ErrOut  io.Writer
+}
+
+func (h CommandHook) Notify(ctx context.Context, event CompletionEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", h.Command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", h.Command)
+	}
+
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = h.Out
+	cmd.Stderr = h.ErrOut
+	cmd.Env = append(os.Environ(),
+		"KP_NAMESPACE="+event.Namespace,
+		"KP_IMAGE="+event.Image,
+		"KP_BUILD="+event.Build,
+		"KP_STATUS="+event.Status,
+		"KP_LATEST_IMAGE="+event.LatestImage,
+		"KP_DIGEST="+event.Digest,
+		"KP_REASONS="+strings.Join(event.Reasons, ","),
+	)
+	return cmd.Run()
+}
+
+// WatchCompletions lists and then watches the builds matching the options and
+// calls onComplete for every build that finishes after watching started. It
+// returns when the context is done.
+func WatchCompletions(ctx context.Context, client versioned.Interface, namespace string, opts metav1.ListOptions, onComplete func(v1alpha2.Build)) error {
+	buildList, err := client.KpackV1alpha2().Builds(namespace).List(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	// builds that finished before watching started are not reported
+	completed := map[string]bool{}
+	for _, bld := range buildList.Items {
+		if !bld.IsRunning() {
+			completed[bld.Namespace+"/"+bld.Name] = true
+		}
+	}
+
+	resourceVersion := buildList.ResourceVersion
+	for {
+		watchOpts := opts
+		watchOpts.ResourceVersion = resourceVersion
+		watcher, err := client.KpackV1alpha2().Builds(namespace).Watch(ctx, watchOpts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		resourceVersion = consumeCompletions(ctx, watcher, completed, resourceVersion, onComplete)
+		watcher.Stop()
+
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// consumeCompletions handles the events of a watch until it closes or the
+// context is done and returns the last resource version seen
+func consumeCompletions(ctx context.Context, watcher watch.Interface, completed map[string]bool, resourceVersion string, onComplete func(v1alpha2.Build)) string {
+	for {
+		select {
+		case <-ctx.Done():
+			return resourceVersion
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return resourceVersion
+			}
+
+			if event.Type == watch.Error {
+				// the resource version expired, watch again from the current state
+				return ""
+			}
+
+			bld, ok := event.Object.(*v1alpha2.Build)
+			if !ok {
+				continue
+			}
+			resourceVersion = bld.ResourceVersion
+
+			key := bld.Namespace + "/" + bld.Name
+			if event.Type == watch.Deleted {
+				delete(completed, key)
+				continue
+			}
+
+			if bld.IsRunning() || completed[key] {
+				continue
+			}
+			completed[key] = true
+			onComplete(*bld)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"encoding/json"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	"github.com/pivotal/kpack/pkg/registry/imagehelpers"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/registry"
+)
+
+const builderMetadataLabel = "io.buildpacks.builder.metadata"
+
+// Inputs are the resolved inputs of a build along with the image it produced
+type Inputs struct {
+	Source           corev1alpha1.SourceConfig `json:"source"`
+	Env              []corev1.EnvVar           `json:"env,omitempty"`
+	Services         v1alpha2.Services         `json:"services,omitempty"`
+	CNBBindings      corev1alpha1.CNBBindings  `json:"cnbBindings,omitempty"`
+	Builder          string                    `json:"builder"`
+	RunImage         string                    `json:"runImage"`
+	LifecycleVersion string                    `json:"lifecycleVersion,omitempty"`
+	Buildpacks       []string                  `json:"buildpacks,omitempty"`
+	Image            string                    `json:"image,omitempty"`
+}
+
+// ResolvedInputs returns the inputs recorded on a build, the lifecycle version
+// is not recorded on the build and must be read from the builder image
+func ResolvedInputs(bld v1alpha2.Build) Inputs {
+	inputs := Inputs{
+		Source:      bld.Spec.Source,
+		Env:         bld.Spec.Env,
+		Services:    bld.Spec.Services,
+		CNBBindings: bld.Spec.CNBBindings,
+		Builder:     bld.Spec.Builder.Image,
+		RunImage:    bld.Status.Stack.RunImage,
+		Image:       bld.Status.LatestImage,
+	}
+
+	for _, bp := range bld.Status.BuildMetadata {
+		inputs.Buildpacks = append(inputs.Buildpacks, bp.Id+"@"+bp.Version)
+	}
+
+	return inputs
+}
+
+// ApplyInputs returns a copy of the image resource that builds from the source,
+// env and service bindings of the given inputs. The builder and run image cannot
+// be pinned on an image resource and are left untouched.
+func ApplyInputs(img *v1alpha2.Image, inputs Inputs) *v1alpha2.Image {
+	updated := img.DeepCopy()
+	updated.Spec.Source = *inputs.Source.DeepCopy()
+
+	if updated.Spec.Build == nil {
+		if len(inputs.Env) == 0 && len(inputs.Services) == 0 && len(inputs.CNBBindings) == 0 {
+			return updated
+		}
+		updated.Spec.Build = &v1alpha2.ImageBuild{}
+	}
+	updated.Spec.Build.Env = inputs.Env
+	updated.Spec.Build.Services = inputs.Services
+	updated.Spec.Build.CNBBindings = inputs.CNBBindings
+
+	return updated
+}
+
+// LifecycleVersion reads the lifecycle version from the metadata of a builder image
+func LifecycleVersion(keychain authn.Keychain, fetcher registry.Fetcher, builderImage string) (string, error) {
+	img, err := fetcher.Fetch(keychain, builderImage)
+	if err != nil {
+		return "", err
+	}
+
+	metadata, err := imagehelpers.GetStringLabel(img, builderMetadataLabel)
+	if err != nil {
+		return "", err
+	}
+
+	var builderMetadata struct {
+		Lifecycle struct {
+			Version string `json:"version"`
+		} `json:"lifecycle"`
+	}
+	if err := json.Unmarshal([]byte(metadata), &builderMetadata); err != nil {
+		return "", errors.Wrapf(err, "invalid %s label", builderMetadataLabel)
+	}
+
+	return builderMetadata.Lifecycle.Version, nil
+}
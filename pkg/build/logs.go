@@ -0,0 +1,177 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Steps are the containers of a build pod in the order they run
+var Steps = []string{
+	v1alpha2.PrepareContainerName,
+	v1alpha2.AnalyzeContainerName,
+	v1alpha2.DetectContainerName,
+	v1alpha2.RestoreContainerName,
+	v1alpha2.BuildContainerName,
+	v1alpha2.ExportContainerName,
+	v1alpha2.RebaseContainerName,
+	v1alpha2.CompletionContainerName,
+}
+
+var ansiRegex = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+
+type LogOptions struct {
+	// Steps limits the logs to these steps, all steps are included when empty
+	Steps []string
+	// Since only returns logs newer than this duration when non-zero
+	Since time.Duration
+	// Tail only returns this many lines per step when positive
+	Tail       int64
+	Timestamps bool
+	StripANSI  bool
+}
+
+// StepWriterProvider returns the writer the logs of a step are written to
+type StepWriterProvider func(step string) (io.WriteCloser, error)
+
+// PodNotFoundError is returned when the pod of a build has been deleted
+type PodNotFoundError struct {
+	Pod   string
+	Build string
+}
+
+func (e PodNotFoundError) Error() string {
+	return fmt.Sprintf("pod %q of build %q no longer exists", e.Pod, e.Build)
+}
+
+type LogsFetcher struct {
+	K8sClient kubernetes.Interface
+}
+
+// ValidateSteps returns an error when a step is not a known build step
+func ValidateSteps(steps []string) error {
+	for _, step := range steps {
+		if !isStep(step) {
+			return errors.Errorf("invalid step %q, valid steps are: %s", step, strings.Join(Steps, ", "))
+		}
+	}
+	return nil
+}
+
+// Fetch writes the logs currently available for each selected step of the build.
+// Steps that have not started yet are skipped.
+func (f LogsFetcher) Fetch(ctx context.Context, bld v1alpha2.Build, opts LogOptions, writerFor StepWriterProvider) error {
+	if bld.Status.PodName == "" {
+		return errors.Errorf("build %q does not have a pod", bld.Name)
+	}
+
+	pod, err := f.K8sClient.CoreV1().Pods(bld.Namespace).Get(ctx, bld.Status.PodName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return PodNotFoundError{Pod: bld.Status.PodName, Build: bld.Name}
+	} else if err != nil {
+		return err
+	}
+
+	containers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for _, container := range containers {
+		if !isStep(container.Name) || !selected(opts.Steps, container.Name) || !started(pod, container.Name) {
+			continue
+		}
+
+		if err := f.fetchStep(ctx, pod, container.Name, opts, writerFor); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f LogsFetcher) fetchStep(ctx context.Context, pod *corev1.Pod, step string, opts LogOptions, writerFor StepWriterProvider) error {
+	logOpts := &corev1.PodLogOptions{
+		Container:  step,
+		Timestamps: opts.Timestamps,
+	}
+	if opts.Since > 0 {
+		seconds := int64(opts.Since.Seconds())
+		logOpts.SinceSeconds = &seconds
+	}
+	if opts.Tail > 0 {
+		logOpts.TailLines = &opts.Tail
+	}
+
+	stream, err := f.K8sClient.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, logOpts).Stream(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read logs of step %q", step)
+	}
+	defer stream.Close()
+
+	w, err := writerFor(step)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	if !opts.StripANSI {
+		_, err = io.Copy(w, stream)
+		return err
+	}
+
+	reader := bufio.NewReader(stream)
+	for {
+		line, readErr := reader.ReadString('\n')
+		if _, err := io.WriteString(w, ansiRegex.ReplaceAllString(line, "")); err != nil {
+			return err
+		}
+		if readErr == io.EOF {
+			return nil
+		} else if readErr != nil {
+			return readErr
+		}
+	}
+}
+
+func isStep(name string) bool {
+	for _, s := range Steps {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+func selected(steps []string, step string) bool {
+	if len(steps) == 0 {
+		return true
+	}
+	for _, s := range steps {
+		if s == step {
+			return true
+		}
+	}
+	return false
+}
+
+func started(pod *corev1.Pod, container string) bool {
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if status.Name == container {
+			return status.State.Waiting == nil
+		}
+	}
+	// container statuses are not always reported, let the api server decide
+	return true
+}
@@ -0,0 +1,82 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+)
+
+type PruneOptions struct {
+	// OlderThan only prunes builds created before Now minus this duration when non-zero
+	OlderThan time.Duration
+	// KeepLast keeps this many of the most recent builds of each image resource
+	KeepLast   int
+	FailedOnly bool
+	Now        time.Time
+}
+
+// PruneCandidates returns the builds that can be deleted according to the options.
+// The latest build of every image resource and running builds are always kept.
+func PruneCandidates(builds []v1alpha2.Build, opts PruneOptions) []v1alpha2.Build {
+	byImage := map[string][]v1alpha2.Build{}
+	var keys []string
+	for _, bld := range builds {
+		key := bld.Namespace + "/" + bld.Labels[v1alpha2.ImageLabel]
+		if _, ok := byImage[key]; !ok {
+			keys = append(keys, key)
+		}
+		byImage[key] = append(byImage[key], bld)
+	}
+	sort.Strings(keys)
+
+	keep := opts.KeepLast
+	if keep < 1 {
+		keep = 1
+	}
+
+	var candidates []v1alpha2.Build
+	for _, key := range keys {
+		imageBuilds := byImage[key]
+		sort.SliceStable(imageBuilds, func(i, j int) bool {
+			return buildNumber(imageBuilds[i]) < buildNumber(imageBuilds[j])
+		})
+
+		if len(imageBuilds) <= keep {
+			continue
+		}
+
+		for _, bld := range imageBuilds[:len(imageBuilds)-keep] {
+			if prunable(bld, opts) {
+				candidates = append(candidates, bld)
+			}
+		}
+	}
+
+	return candidates
+}
+
+func prunable(bld v1alpha2.Build, opts PruneOptions) bool {
+	if bld.IsRunning() {
+		return false
+	}
+
+	if opts.FailedOnly && Status(bld) != "FAILURE" {
+		return false
+	}
+
+	if opts.OlderThan > 0 && bld.CreationTimestamp.Time.After(opts.Now.Add(-opts.OlderThan)) {
+		return false
+	}
+
+	return true
+}
+
+func buildNumber(bld v1alpha2.Build) int {
+	n, _ := strconv.Atoi(bld.Labels[v1alpha2.BuildNumberLabel])
+	return n
+}
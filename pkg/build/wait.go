@@ -0,0 +1,120 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	"github.com/pivotal/kpack/pkg/client/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// minShortRevision is the shortest git revision matched as a prefix of the
+	// resolved commit sha
+	minShortRevision = 7
+	// maxRevision is the length of a full commit sha
+	maxRevision = 40
+)
+
+// Condition selects the builds to wait for
+type Condition func(bld v1alpha2.Build) bool
+
+// IsCommitSha reports whether the git revision is a commit sha, either the
+// full sha or a prefix of at least seven characters. Branches and tags are
+// resolved to a commit sha on the builds, so only commit shas can be matched
+// by HasRevision.
+func IsCommitSha(revision string) bool {
+	if len(revision) < minShortRevision || len(revision) > maxRevision {
+		return false
+	}
+	for _, c := range revision {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasRevision matches builds of the commit sha, either the full commit sha
+// or a prefix of at least seven characters
+func HasRevision(revision string) Condition {
+	return func(bld v1alpha2.Build) bool {
+		if bld.Spec.Source.Git == nil {
+			return false
+		}
+
+		resolved := bld.Spec.Source.Git.Revision
+		if len(revision) >= minShortRevision {
+			return strings.HasPrefix(resolved, revision)
+		}
+		return resolved == revision
+	}
+}
+
+// NumberedAfter matches builds with a build number greater than n
+func NumberedAfter(n int) Condition {
+	return func(bld v1alpha2.Build) bool {
+		return buildNumber(bld) > n
+	}
+}
+
+// StartedAfter matches builds created after t
+func StartedAfter(t time.Time) Condition {
+	return func(bld v1alpha2.Build) bool {
+		return bld.CreationTimestamp.Time.After(t)
+	}
+}
+
+// AllOf matches builds that match every condition, or any build when there are none
+func AllOf(conditions ...Condition) Condition {
+	return func(bld v1alpha2.Build) bool {
+		for _, c := range conditions {
+			if !c(bld) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// WaitForBuild polls the builds of an image until the latest build matching
+// the condition finishes and returns it. It returns the context error when
+// the context is done first.
+func WaitForBuild(ctx context.Context, client versioned.Interface, namespace, image string, condition Condition, interval time.Duration) (v1alpha2.Build, error) {
+	for {
+		buildList, err := client.KpackV1alpha2().Builds(namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: v1alpha2.ImageLabel + "=" + image,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return v1alpha2.Build{}, ctx.Err()
+			}
+			return v1alpha2.Build{}, err
+		}
+
+		var (
+			latest v1alpha2.Build
+			found  bool
+		)
+		for _, bld := range buildList.Items {
+			if condition(bld) && (!found || buildNumber(bld) > buildNumber(latest)) {
+				latest, found = bld, true
+			}
+		}
+
+		if found && !latest.IsRunning() {
+			return latest, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return v1alpha2.Build{}, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,345 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+var (
+	imagesResource = v1alpha2.SchemeGroupVersion.WithResource("images")
+	buildsResource = v1alpha2.SchemeGroupVersion.WithResource("builds")
+)
+
+// Transition is a change of the status or the current step of a build
+type Transition struct {
+	Build  v1alpha2.Build
+	Status string
+	Step   string
+}
+
+type WatchHandler struct {
+	// OnTransition is called when a build starts, changes step or finishes
+	OnTransition func(Transition)
+	// OnChange is called after any image or build changes
+	OnChange func()
+}
+
+// Watcher keeps images and builds up to date with informers on the dynamic client
+type Watcher struct {
+	dynamicClient dynamic.Interface
+	namespace     string
+	image         string
+
+	mu      sync.Mutex
+	handler WatchHandler
+	images  map[string]v1alpha2.Image
+	builds  map[string]v1alpha2.Build
+	states  map[string]Transition
+	// finished are the builds that finished since the watcher started
+	finished map[string]bool
+}
+
+// NewWatcher returns a watcher of the images and builds of a namespace, or of
+// all namespaces when namespace is empty. When image is set only that image
+// and its builds are watched.
+func NewWatcher(dc dynamic.Interface, namespace, image string) *Watcher {
+	return &Watcher{
+		dynamicClient: dc,
+		namespace:     namespace,
+		image:         image,
+		images:        map[string]v1alpha2.Image{},
+		builds:        map[string]v1alpha2.Build{},
+		states:        map[string]Transition{},
+		finished:      map[string]bool{},
+	}
+}
+
+// Start starts the informers and returns once their caches are synced or
+// the context is done. The informers stop when the context is done.
+func (w *Watcher) Start(ctx context.Context, handler WatchHandler) error {
+	w.handler = handler
+
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(w.dynamicClient, 0, w.namespace, func(options *metav1.ListOptions) {
+		if w.image != "" {
+			options.LabelSelector = v1alpha2.ImageLabel + "=" + w.image
+		}
+	})
+	buildInformer := factory.ForResource(buildsResource).Informer()
+	_, err := buildInformer.AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc:    func(obj interface{}, initial bool) { w.updateBuild(obj, initial) },
+		UpdateFunc: func(_, obj interface{}) { w.updateBuild(obj, false) },
+		DeleteFunc: w.deleteBuild,
+	})
+	if err != nil {
+		return err
+	}
+
+	imageFactory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(w.dynamicClient, 0, w.namespace, func(options *metav1.ListOptions) {
+		if w.image != "" {
+			options.FieldSelector = "metadata.name=" + w.image
+		}
+	})
+	imageInformer := imageFactory.ForResource(imagesResource).Informer()
+	_, err = imageInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    w.updateImage,
+		UpdateFunc: func(_, obj interface{}) { w.updateImage(obj) },
+		DeleteFunc: w.deleteImage,
+	})
+	if err != nil {
+		return err
+	}
+
+	factory.Start(ctx.Done())
+	imageFactory.Start(ctx.Done())
+
+	// syncing only stops early when the context is done, which is not an error
+	cache.WaitForCacheSync(ctx.Done(), buildInformer.HasSynced, imageInformer.HasSynced)
+	return nil
+}
+
+// Images returns the watched images sorted by namespace and name
+func (w *Watcher) Images() []v1alpha2.Image {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	images := make([]v1alpha2.Image, 0, len(w.images))
+	for _, img := range w.images {
+		images = append(images, img)
+	}
+	sort.Slice(images, func(i, j int) bool {
+		return images[i].Namespace+"/"+images[i].Name < images[j].Namespace+"/"+images[j].Name
+	})
+	return images
+}
+
+// ActiveBuilds returns the running builds and the builds that finished since
+// the watcher started, sorted by namespace, image and build number
+func (w *Watcher) ActiveBuilds() []v1alpha2.Build {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var builds []v1alpha2.Build
+	for key, bld := range w.builds {
+		if bld.IsRunning() || w.finished[key] {
+			builds = append(builds, bld)
+		}
+	}
+	sortBuilds(builds)
+	return builds
+}
+
+// LatestBuild returns the build of the image with the highest build number
+func (w *Watcher) LatestBuild(img v1alpha2.Image) (v1alpha2.Build, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var (
+		latest v1alpha2.Build
+		found  bool
+	)
+	for _, bld := range w.builds {
+		if bld.Namespace != img.Namespace || bld.Labels[v1alpha2.ImageLabel] != img.Name {
+			continue
+		}
+		if !found || buildNumber(bld) > buildNumber(latest) {
+			latest, found = bld, true
+		}
+	}
+	return latest, found
+}
+
+func (w *Watcher) updateBuild(obj interface{}, initial bool) {
+	bld := &v1alpha2.Build{}
+	if !fromUnstructured(obj, bld) {
+		return
+	}
+
+	if w.image != "" && bld.Labels[v1alpha2.ImageLabel] != w.image {
+		return
+	}
+
+	w.mu.Lock()
+	key := bld.Namespace + "/" + bld.Name
+	w.builds[key] = *bld
+
+	transition := Transition{Build: *bld, Status: Status(*bld), Step: CurrentStep(*bld)}
+	previous, seen := w.states[key]
+	w.states[key] = transition
+
+	changed := !seen || previous.Status != transition.Status || previous.Step != transition.Step
+	if changed && !initial && !bld.IsRunning() {
+		w.finished[key] = true
+	}
+
+	// builds that already finished before the watch started are not reported
+	report := changed && (!initial || bld.IsRunning())
+	if report && w.handler.OnTransition != nil {
+		w.handler.OnTransition(transition)
+	}
+	w.mu.Unlock()
+
+	w.changed()
+}
+
+func (w *Watcher) deleteBuild(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	bld := &v1alpha2.Build{}
+	if !fromUnstructured(obj, bld) {
+		return
+	}
+
+	w.mu.Lock()
+	key := bld.Namespace + "/" + bld.Name
+	delete(w.builds, key)
+	delete(w.states, key)
+	delete(w.finished, key)
+	w.mu.Unlock()
+
+	w.changed()
+}
+
+func (w *Watcher) updateImage(obj interface{}) {
+	img := &v1alpha2.Image{}
+	if !fromUnstructured(obj, img) {
+		return
+	}
+
+	if w.image != "" && img.Name != w.image {
+		return
+	}
+
+	w.mu.Lock()
+	w.images[img.Namespace+"/"+img.Name] = *img
+	w.mu.Unlock()
+
+	w.changed()
+}
+
+func (w *Watcher) deleteImage(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	img := &v1alpha2.Image{}
+	if !fromUnstructured(obj, img) {
+		return
+	}
+
+	w.mu.Lock()
+	delete(w.images, img.Namespace+"/"+img.Name)
+	w.mu.Unlock()
+
+	w.changed()
+}
+
+func (w *Watcher) changed() {
+	if w.handler.OnChange != nil {
+		w.handler.OnChange()
+	}
+}
+
+func fromUnstructured(obj interface{}, into interface{}) bool {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return false
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, into) == nil
+}
+
+// CurrentStep returns the step a running build is executing, or an empty
+// string when the build is not running or its pod has not started yet.
+// The build status only records the names of completed steps, the current
+// step is the one following them.
+func CurrentStep(bld v1alpha2.Build) string {
+	if !bld.IsRunning() || len(bld.Status.StepStates) == 0 {
+		return ""
+	}
+
+	steps := []string{
+		v1alpha2.PrepareContainerName,
+		v1alpha2.AnalyzeContainerName,
+		v1alpha2.DetectContainerName,
+		v1alpha2.RestoreContainerName,
+		v1alpha2.BuildContainerName,
+		v1alpha2.ExportContainerName,
+		v1alpha2.CompletionContainerName,
+	}
+	// rebase builds only run the rebase and completion steps
+	if len(bld.Status.StepStates) <= 2 {
+		steps = []string{v1alpha2.RebaseContainerName, v1alpha2.CompletionContainerName}
+	}
+
+	completed := len(bld.Status.StepsCompleted)
+	if completed >= len(steps) {
+		return ""
+	}
+	return steps[completed]
+}
+
+// Elapsed returns how long a build has been running, or how long it ran once finished
+func Elapsed(bld v1alpha2.Build, now time.Time) time.Duration {
+	end := now
+	if !bld.IsRunning() {
+		if cond := bld.Status.GetCondition(corev1alpha1.ConditionSucceeded); cond != nil && !cond.LastTransitionTime.Inner.IsZero() {
+			end = cond.LastTransitionTime.Inner.Time
+		}
+	}
+
+	elapsed := end.Sub(bld.CreationTimestamp.Time)
+	if elapsed < 0 {
+		return 0
+	}
+	return elapsed.Round(time.Second)
+}
+
+func sortBuilds(builds []v1alpha2.Build) {
+	sort.Slice(builds, func(i, j int) bool {
+		a := builds[i].Namespace + "/" + builds[i].Labels[v1alpha2.ImageLabel]
+		b := builds[j].Namespace + "/" + builds[j].Labels[v1alpha2.ImageLabel]
+		if a != b {
+			return a < b
+		}
+		return buildNumber(builds[i]) < buildNumber(builds[j])
+	})
+}
+
+func (t Transition) String() string {
+	name := fmt.Sprintf("%s/%s build %s", t.Build.Namespace, t.Build.Labels[v1alpha2.ImageLabel], t.Build.Labels[v1alpha2.BuildNumberLabel])
+
+	var details []string
+	if t.Step != "" {
+		details = append(details, "step: "+t.Step)
+	}
+	if reasons := Reasons(t.Build); len(reasons) > 0 {
+		details = append(details, "reason: "+strings.Join(reasons, ","))
+	}
+
+	status := t.Status
+	if !t.Build.IsRunning() {
+		status = fmt.Sprintf("%s after %s", t.Status, Elapsed(t.Build, time.Now()))
+	}
+
+	if len(details) == 0 {
+		return fmt.Sprintf("%s: %s", name, status)
+	}
+	return fmt.Sprintf("%s: %s (%s)", name, status, strings.Join(details, ", "))
+}
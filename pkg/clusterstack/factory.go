@@ -26,6 +26,8 @@ type Printer interface {
 type Factory struct {
 	Uploader Uploader
 	Printer  Printer
+	// Repository is uploaded to instead of the stack repository of the kp-config when it is set
+	Repository string
 }
 
 func NewFactory(printer Printer, relocator registry.Relocator, fetcher registry.Fetcher) *Factory {
@@ -44,16 +46,16 @@ func (f *Factory) MakeStack(keychain authn.Keychain, name, buildImageTag, runIma
 		return nil, err
 	}
 
-	defaultRepo, err := kpConfig.DefaultRepository()
+	repository, err := f.repository(kpConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := f.Printer.PrintStatus("Uploading to '%s'...", defaultRepo); err != nil {
+	if err := f.Printer.PrintStatus("Uploading to '%s'...", repository); err != nil {
 		return nil, err
 	}
 
-	relocatedBuildImageRef, relocatedRunImageRef, err := f.Uploader.UploadStackImages(keychain, buildImageTag, runImageTag, defaultRepo)
+	relocatedBuildImageRef, relocatedRunImageRef, err := f.Uploader.UploadStackImages(keychain, buildImageTag, runImageTag, repository)
 	if err != nil {
 		return nil, err
 	}
@@ -97,16 +99,20 @@ func (f *Factory) UpdateStack(keychain authn.Keychain, stack *v1alpha2.ClusterSt
 }
 
 func (f *Factory) uploadStackImages(keychain authn.Keychain, buildImageTag, runImageTag string, kpConfig config.KpConfig) (string, string, error) {
-	defaultRepo, err := kpConfig.DefaultRepository()
+	repository, err := f.repository(kpConfig)
 	if err != nil {
 		return "", "", err
 	}
 
-	if err := f.Printer.PrintStatus("Uploading to '%s'...", defaultRepo); err != nil {
+	if err := f.Printer.PrintStatus("Uploading to '%s'...", repository); err != nil {
 		return "", "", err
 	}
 
-	return f.Uploader.UploadStackImages(keychain, buildImageTag, runImageTag, defaultRepo)
+	return f.Uploader.UploadStackImages(keychain, buildImageTag, runImageTag, repository)
+}
+
+func (f *Factory) repository(kpConfig config.KpConfig) (string, error) {
+	return kpConfig.WithRepository(config.StackArtifact, f.Repository).Repository(config.StackArtifact)
 }
 
 func (f *Factory) validate(keychain authn.Keychain, buildTag, runTag string) (string, error) {
@@ -30,6 +30,8 @@ type Printer interface {
 type Factory struct {
 	Uploader BuildpackageUploader
 	Printer  Printer
+	// Repository is uploaded to instead of the buildpackage repository of the kp-config when it is set
+	Repository string
 }
 
 func NewFactory(printer Printer, relocator registry.Relocator, fetcher registry.Fetcher) *Factory {
@@ -62,13 +64,13 @@ func (f *Factory) MakeStore(keychain authn.Keychain, name string, kpConfig confi
 		},
 	}
 
-	defaultRepo, err := kpConfig.DefaultRepository()
+	repository, err := f.repository(kpConfig)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, bp := range buildpackages {
-		uploadedBp, err := f.Uploader.UploadBuildpackage(keychain, bp, defaultRepo)
+		uploadedBp, err := f.Uploader.UploadBuildpackage(keychain, bp, repository)
 		if err != nil {
 			return nil, err
 		}
@@ -84,13 +86,13 @@ func (f *Factory) MakeStore(keychain authn.Keychain, name string, kpConfig confi
 func (f *Factory) AddToStore(keychain authn.Keychain, store *v1alpha2.ClusterStore, kpConfig config.KpConfig, buildpackages ...string) (*v1alpha2.ClusterStore, error) {
 	updatedStore := store.DeepCopy()
 
-	defaultRepo, err := kpConfig.DefaultRepository()
+	repository, err := f.repository(kpConfig)
 	if err != nil {
 		return nil, err
 	}
 
 	for _, bp := range buildpackages {
-		uploadedBp, err := f.Uploader.UploadBuildpackage(keychain, bp, defaultRepo)
+		uploadedBp, err := f.Uploader.UploadBuildpackage(keychain, bp, repository)
 		if err != nil {
 			return nil, err
 		}
@@ -140,6 +142,10 @@ func (f *Factory) RemoveFromStore(store *v1alpha2.ClusterStore, buildpackages ..
 	return newStore, nil
 }
 
+func (f *Factory) repository(kpConfig config.KpConfig) (string, error) {
+	return kpConfig.WithRepository(config.BuildpackageArtifact, f.Repository).Repository(config.BuildpackageArtifact)
+}
+
 func getStoreImage(store *v1alpha2.ClusterStore, buildpackage string) (corev1alpha1.ImageSource, bool) {
 	for _, bp := range store.Status.Buildpacks {
 		if fmt.Sprintf("%s@%s", bp.Id, bp.Version) == buildpackage {
@@ -0,0 +1,112 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"sort"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/build"
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+// cancelDeadlineSeconds is the active deadline set on the pod of a canceled build.
+// Deleting the pod is not enough as kpack recreates the pod of a running build.
+const cancelDeadlineSeconds int64 = 1
+
+func NewCancelCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+	var (
+		namespace   string
+		buildNumber string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "cancel <image-name>",
+		Short: "Cancel a running image resource build",
+		Long: `Cancel a running build of an image resource in the provided namespace.
+
+The build pod is stopped by setting its active deadline, the build is then marked as failed by kpack.
+
+The build defaults to the latest build number.
+The namespace defaults to the kubernetes current-context namespace.`,
+		Example:      "kp build cancel my-image\nkp build cancel my-image -b 2 -n my-namespace",
+		Args:         commands.ExactArgsWithUsage(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cs, err := clientSetProvider.GetClientSet(namespace)
+			if err != nil {
+				return err
+			}
+
+			ch, err := commands.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+
+			buildList, err := cs.KpackClient.KpackV1alpha2().Builds(cs.Namespace).List(ctx, metav1.ListOptions{
+				LabelSelector: v1alpha2.ImageLabel + "=" + args[0],
+			})
+			if err != nil {
+				return err
+			}
+
+			if len(buildList.Items) == 0 {
+				return errors.New("no builds found")
+			}
+
+			sort.Slice(buildList.Items, build.Sort(buildList.Items))
+			bld, err := findBuild(buildList, buildNumber)
+			if err != nil {
+				return err
+			}
+
+			number := bld.Labels[v1alpha2.BuildNumberLabel]
+			if !bld.IsRunning() {
+				return errors.Errorf("build %s of image %q is not running, its status is %s", number, args[0], getStatus(bld))
+			}
+
+			if bld.Status.PodName == "" {
+				return errors.Errorf("build %s of image %q does not have a pod yet, try again shortly", number, args[0])
+			}
+
+			pod, err := cs.K8sClient.CoreV1().Pods(cs.Namespace).Get(ctx, bld.Status.PodName, metav1.GetOptions{})
+			if k8serrors.IsNotFound(err) {
+				return errors.Errorf("pod %q of build %s no longer exists", bld.Status.PodName, number)
+			} else if err != nil {
+				return err
+			}
+
+			if pod.Spec.ActiveDeadlineSeconds == nil || *pod.Spec.ActiveDeadlineSeconds > cancelDeadlineSeconds {
+				deadline := cancelDeadlineSeconds
+				updatedPod := pod.DeepCopy()
+				updatedPod.Spec.ActiveDeadlineSeconds = &deadline
+
+				patch, err := k8s.CreatePatch(pod, updatedPod)
+				if err != nil {
+					return err
+				}
+
+				_, err = cs.K8sClient.CoreV1().Pods(cs.Namespace).Patch(ctx, pod.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+				if err != nil {
+					return err
+				}
+			}
+
+			return ch.PrintResult("Canceled build %s of image %q, pod %q is being stopped", number, args[0], pod.Name)
+		},
+	}
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
+	cmd.Flags().StringVarP(&buildNumber, "build", "b", "", "build number")
+
+	return cmd
+}
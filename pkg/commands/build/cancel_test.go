@@ -0,0 +1,95 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package build_test
+
+import (
+	"testing"
+
+	"github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/sclevine/spec"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfakes "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands/build"
+	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
+)
+
+func TestBuildCancelCommand(t *testing.T) {
+	spec.Run(t, "TestBuildCancelCommand", testBuildCancelCommand)
+}
+
+func testBuildCancelCommand(t *testing.T, when spec.G, it spec.S) {
+	const (
+		image     = "test-image"
+		namespace = "some-namespace"
+	)
+
+	cmdFunc := func(k8sClientSet *k8sfakes.Clientset, kpackClientSet *fake.Clientset) *cobra.Command {
+		clientSetProvider := testhelpers.GetFakeClusterProvider(k8sClientSet, kpackClientSet)
+		return build.NewCancelCommand(clientSetProvider)
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "pod-three",
+			Namespace: namespace,
+		},
+	}
+
+	objects := func(objs ...runtime.Object) []runtime.Object {
+		return append(testhelpers.BuildsToRuntimeObjs(testhelpers.MakeTestBuilds(image, namespace)), objs...)
+	}
+
+	it("stops the pod of the latest running build", func() {
+		testhelpers.CommandTest{
+			Objects:        objects(pod),
+			Args:           []string{image, "-n", namespace},
+			ExpectedOutput: "Canceled build 3 of image \"test-image\", pod \"pod-three\" is being stopped\n",
+			ExpectPatches: []string{
+				`{"spec":{"activeDeadlineSeconds":1}}`,
+			},
+		}.TestK8sAndKpack(t, cmdFunc)
+	})
+
+	it("does not patch a pod that is already being stopped", func() {
+		deadline := int64(1)
+		stoppingPod := pod.DeepCopy()
+		stoppingPod.Spec.ActiveDeadlineSeconds = &deadline
+
+		testhelpers.CommandTest{
+			Objects:        objects(stoppingPod),
+			Args:           []string{image, "-n", namespace, "-b", "3"},
+			ExpectedOutput: "Canceled build 3 of image \"test-image\", pod \"pod-three\" is being stopped\n",
+		}.TestK8sAndKpack(t, cmdFunc)
+	})
+
+	it("errors when the build is not running", func() {
+		testhelpers.CommandTest{
+			Objects:             objects(pod),
+			Args:                []string{image, "-n", namespace, "-b", "1"},
+			ExpectErr:           true,
+			ExpectedErrorOutput: "Error: build 1 of image \"test-image\" is not running, its status is SUCCESS\n",
+		}.TestK8sAndKpack(t, cmdFunc)
+	})
+
+	it("errors when the pod no longer exists", func() {
+		testhelpers.CommandTest{
+			Objects:             objects(),
+			Args:                []string{image, "-n", namespace},
+			ExpectErr:           true,
+			ExpectedErrorOutput: "Error: pod \"pod-three\" of build 3 no longer exists\n",
+		}.TestK8sAndKpack(t, cmdFunc)
+	})
+
+	it("errors when there are no builds", func() {
+		testhelpers.CommandTest{
+			Args:                []string{image, "-n", namespace},
+			ExpectErr:           true,
+			ExpectedErrorOutput: "Error: no builds found\n",
+		}.TestK8sAndKpack(t, cmdFunc)
+	})
+}
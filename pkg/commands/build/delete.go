@@ -0,0 +1,102 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/build"
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+type ConfirmationProvider interface {
+	Confirm(message string, okayResponses ...string) (bool, error)
+}
+
+func NewDeleteCommand(clientSetProvider k8s.ClientSetProvider, confirmationProvider ConfirmationProvider) *cobra.Command {
+	var (
+		namespace   string
+		buildNumber string
+		forceDelete bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "delete <image-name> --build <number>",
+		Short: "Delete an image resource build",
+		Long: `Delete a specific build of an image resource in the provided namespace.
+
+The latest build of an image resource cannot be deleted as kpack uses it to decide when to rebuild.
+
+The namespace defaults to the kubernetes current-context namespace.`,
+		Example:      "kp build delete my-image -b 2\nkp build delete my-image -b 2 -n my-namespace --force",
+		Args:         commands.ExactArgsWithUsage(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if buildNumber == "" {
+				return errors.New("the --build flag is required")
+			}
+
+			cs, err := clientSetProvider.GetClientSet(namespace)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+
+			buildList, err := cs.KpackClient.KpackV1alpha2().Builds(cs.Namespace).List(ctx, metav1.ListOptions{
+				LabelSelector: v1alpha2.ImageLabel + "=" + args[0],
+			})
+			if err != nil {
+				return err
+			}
+
+			if len(buildList.Items) == 0 {
+				return errors.New("no builds found")
+			}
+
+			sort.Slice(buildList.Items, build.Sort(buildList.Items))
+			bld, err := findBuild(buildList, buildNumber)
+			if err != nil {
+				return err
+			}
+
+			if bld.Name == buildList.Items[len(buildList.Items)-1].Name {
+				return errors.Errorf("build %s is the latest build of image %q and cannot be deleted", buildNumber, args[0])
+			}
+
+			if !forceDelete {
+				message := fmt.Sprintf("Please confirm deletion of build %s of image %q by typing 'y': ", buildNumber, args[0])
+				confirmed, err := confirmationProvider.Confirm(message)
+				if err != nil {
+					return err
+				}
+
+				if !confirmed {
+					_, err = fmt.Fprintln(cmd.OutOrStdout(), "Skipping Build deletion")
+					return err
+				}
+			}
+
+			err = cs.KpackClient.KpackV1alpha2().Builds(cs.Namespace).Delete(ctx, bld.Name, metav1.DeleteOptions{})
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "Build %s of image %q deleted\n", buildNumber, args[0])
+			return err
+		},
+	}
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
+	cmd.Flags().StringVarP(&buildNumber, "build", "b", "", "build number to delete")
+	cmd.Flags().BoolVarP(&forceDelete, "force", "f", false, "force deletion without confirmation")
+
+	return cmd
+}
@@ -0,0 +1,114 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package build_test
+
+import (
+	"testing"
+
+	"github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/sclevine/spec"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	clientgotesting "k8s.io/client-go/testing"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands/build"
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands/fakes"
+	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
+)
+
+func TestBuildDeleteCommand(t *testing.T) {
+	spec.Run(t, "TestBuildDeleteCommand", testBuildDeleteCommand)
+}
+
+func testBuildDeleteCommand(t *testing.T, when spec.G, it spec.S) {
+	const (
+		image            = "test-image"
+		defaultNamespace = "some-default-namespace"
+	)
+
+	var fakeConfirmationProvider *fakes.FakeConfirmationProvider
+
+	cmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
+		clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
+		return build.NewDeleteCommand(clientSetProvider, fakeConfirmationProvider)
+	}
+
+	builds := testhelpers.BuildsToRuntimeObjs(testhelpers.MakeTestBuilds(image, defaultNamespace))
+
+	it.Before(func() {
+		fakeConfirmationProvider = fakes.NewFakeConfirmationProvider(true, nil)
+	})
+
+	it("confirms and deletes the build", func() {
+		testhelpers.CommandTest{
+			Objects:        builds,
+			Args:           []string{image, "-b", "2"},
+			ExpectedOutput: "Build 2 of image \"test-image\" deleted\n",
+			ExpectDeletes: []clientgotesting.DeleteActionImpl{
+				{
+					ActionImpl: clientgotesting.ActionImpl{
+						Namespace: defaultNamespace,
+					},
+					Name: "build-two",
+				},
+			},
+		}.TestKpack(t, cmdFunc)
+		require.NoError(t, fakeConfirmationProvider.WasRequestedWithMsg("Please confirm deletion of build 2 of image \"test-image\" by typing 'y': "))
+	})
+
+	it("does not delete the build when confirmation is not given", func() {
+		fakeConfirmationProvider = fakes.NewFakeConfirmationProvider(false, nil)
+
+		testhelpers.CommandTest{
+			Objects:        builds,
+			Args:           []string{image, "-b", "2"},
+			ExpectedOutput: "Skipping Build deletion\n",
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("deletes the build without confirmation when forced", func() {
+		testhelpers.CommandTest{
+			Objects:        builds,
+			Args:           []string{image, "-b", "1", "--force"},
+			ExpectedOutput: "Build 1 of image \"test-image\" deleted\n",
+			ExpectDeletes: []clientgotesting.DeleteActionImpl{
+				{
+					ActionImpl: clientgotesting.ActionImpl{
+						Namespace: defaultNamespace,
+					},
+					Name: "build-one",
+				},
+			},
+		}.TestKpack(t, cmdFunc)
+		require.False(t, fakeConfirmationProvider.WasRequested())
+	})
+
+	it("does not delete the latest build", func() {
+		testhelpers.CommandTest{
+			Objects:             builds,
+			Args:                []string{image, "-b", "3"},
+			ExpectErr:           true,
+			ExpectedErrorOutput: "Error: build 3 is the latest build of image \"test-image\" and cannot be deleted\n",
+		}.TestKpack(t, cmdFunc)
+		require.False(t, fakeConfirmationProvider.WasRequested())
+	})
+
+	it("requires the build flag", func() {
+		testhelpers.CommandTest{
+			Objects:             builds,
+			Args:                []string{image},
+			ExpectErr:           true,
+			ExpectedErrorOutput: "Error: the --build flag is required\n",
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("errors when the build does not exist", func() {
+		testhelpers.CommandTest{
+			Objects:             builds,
+			Args:                []string{image, "-b", "7"},
+			ExpectErr:           true,
+			ExpectedErrorOutput: "Error: build \"7\" not found\n",
+		}.TestKpack(t, cmdFunc)
+	})
+}
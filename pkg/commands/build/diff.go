@@ -0,0 +1,114 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/build"
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/dockercreds"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+	"github.com/vmware-tanzu/kpack-cli/pkg/registry"
+)
+
+type Differ interface {
+	Diff(dOld, dNew interface{}) (string, error)
+}
+
+func NewDiffCommand(clientSetProvider k8s.ClientSetProvider, differ Differ, rup registry.UtilProvider) *cobra.Command {
+	var (
+		namespace string
+		tlsCfg    registry.TLSConfig
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff <image-name> <build-number> <other-build-number>",
+		Short: "Compare the inputs and outputs of two builds",
+		Long: `Prints the differences between two builds of an image resource in the provided namespace.
+
+The resolved source, env, service bindings, builder and run images, lifecycle version,
+buildpacks and the resulting image of both builds are compared.
+
+The lifecycle version is read from the builder image, therefore you must have credentials to access the registry on your machine.
+Env vars can be used for registry auth as described in https://github.com/vmware-tanzu/kpack-cli/blob/main/docs/auth.md
+
+The namespace defaults to the kubernetes current-context namespace.`,
+		Example:      "kp build diff my-image 41 42\nkp build diff my-image 1 3 -n my-namespace",
+		Args:         commands.ExactArgsWithUsage(3),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cs, err := clientSetProvider.GetClientSet(namespace)
+			if err != nil {
+				return err
+			}
+
+			buildList, err := cs.KpackClient.KpackV1alpha2().Builds(cs.Namespace).List(cmd.Context(), metav1.ListOptions{
+				LabelSelector: v1alpha2.ImageLabel + "=" + args[0],
+			})
+			if err != nil {
+				return err
+			}
+
+			if len(buildList.Items) == 0 {
+				return errors.New("no builds found")
+			}
+
+			sort.Slice(buildList.Items, build.Sort(buildList.Items))
+			oldBuild, err := findBuild(buildList, args[1])
+			if err != nil {
+				return err
+			}
+
+			newBuild, err := findBuild(buildList, args[2])
+			if err != nil {
+				return err
+			}
+
+			lifecycleVersions := map[string]string{}
+			fetcher := rup.Fetcher(tlsCfg)
+			resolve := func(bld v1alpha2.Build) build.Inputs {
+				inputs := build.ResolvedInputs(bld)
+				if inputs.Builder == "" {
+					return inputs
+				}
+
+				version, ok := lifecycleVersions[inputs.Builder]
+				if !ok {
+					var lookupErr error
+					version, lookupErr = build.LifecycleVersion(dockercreds.DefaultKeychain, fetcher, inputs.Builder)
+					if lookupErr != nil {
+						_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: could not read lifecycle version from builder %q: %s\n", inputs.Builder, lookupErr)
+					}
+					lifecycleVersions[inputs.Builder] = version
+				}
+				inputs.LifecycleVersion = version
+				return inputs
+			}
+
+			diff, err := differ.Diff(resolve(oldBuild), resolve(newBuild))
+			if err != nil {
+				return err
+			}
+
+			if diff == "" {
+				_, err = fmt.Fprintf(cmd.OutOrStdout(), "Builds %s and %s have the same inputs and outputs\n", args[1], args[2])
+				return err
+			}
+
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "Comparing build %s to build %s of image %q\n\n%s", args[1], args[2], args[0], diff)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
+	commands.SetTLSFlags(cmd, &tlsCfg)
+	return cmd
+}
@@ -0,0 +1,109 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package build_test
+
+import (
+	"testing"
+
+	"github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/sclevine/spec"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	buildpkg "github.com/vmware-tanzu/kpack-cli/pkg/build"
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands/build"
+	commandsfakes "github.com/vmware-tanzu/kpack-cli/pkg/commands/fakes"
+	registryfakes "github.com/vmware-tanzu/kpack-cli/pkg/registry/fakes"
+	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
+)
+
+func TestBuildDiffCommand(t *testing.T) {
+	spec.Run(t, "TestBuildDiffCommand", testBuildDiffCommand)
+}
+
+func testBuildDiffCommand(t *testing.T, when spec.G, it spec.S) {
+	const (
+		image            = "test-image"
+		defaultNamespace = "some-default-namespace"
+	)
+
+	var (
+		differ  *commandsfakes.FakeDiffer
+		fetcher *registryfakes.Fetcher
+	)
+
+	it.Before(func() {
+		differ = &commandsfakes.FakeDiffer{DiffResult: "some-diff\n"}
+		fetcher = &registryfakes.Fetcher{}
+		fetcher.AddImage("some-repo.com/my-builder", registryfakes.NewFakeLabeledImage(
+			"io.buildpacks.builder.metadata",
+			`{"lifecycle":{"version":"0.16.0"}}`,
+			"builder-digest",
+		))
+	})
+
+	cmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
+		clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
+		return build.NewDiffCommand(clientSetProvider, differ, registryfakes.UtilProvider{FakeFetcher: fetcher})
+	}
+
+	builds := testhelpers.BuildsToRuntimeObjs(testhelpers.MakeTestBuilds(image, defaultNamespace))
+
+	it("diffs the resolved inputs of both builds", func() {
+		testhelpers.CommandTest{
+			Objects: builds,
+			Args:    []string{image, "1", "2"},
+			ExpectedOutput: `Comparing build 1 to build 2 of image "test-image"
+
+some-diff
+`,
+		}.TestKpack(t, cmdFunc)
+
+		oldInputs, newInputs := differ.Args()
+		require.Equal(t, buildpkg.Inputs{
+			Builder:          "some-repo.com/my-builder",
+			RunImage:         "some-repo.com/run-image",
+			LifecycleVersion: "0.16.0",
+			Buildpacks:       []string{"bp-id-1@bp-version-1", "bp-id-2@bp-version-2"},
+			Image:            "repo.com/image-1:tag",
+		}, oldInputs)
+		require.Equal(t, buildpkg.Inputs{
+			Image: "repo.com/image-2:tag",
+		}, newInputs)
+		require.Equal(t, 1, fetcher.CallCount())
+	})
+
+	it("reports when the builds are the same", func() {
+		differ.DiffResult = ""
+
+		testhelpers.CommandTest{
+			Objects:        builds,
+			Args:           []string{image, "1", "3"},
+			ExpectedOutput: "Builds 1 and 3 have the same inputs and outputs\n",
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("warns when the lifecycle version cannot be read", func() {
+		fetcher = &registryfakes.Fetcher{}
+
+		testhelpers.CommandTest{
+			Objects: builds,
+			Args:    []string{image, "1", "3"},
+			ExpectedOutput: `Comparing build 1 to build 3 of image "test-image"
+
+some-diff
+`,
+			ExpectedErrorOutput: "Warning: could not read lifecycle version from builder \"some-repo.com/my-builder\": image not found: \"some-repo.com/my-builder\"\n",
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("errors when a build does not exist", func() {
+		testhelpers.CommandTest{
+			Objects:             builds,
+			Args:                []string{image, "1", "7"},
+			ExpectErr:           true,
+			ExpectedErrorOutput: "Error: build \"7\" not found\n",
+		}.TestKpack(t, cmdFunc)
+	})
+}
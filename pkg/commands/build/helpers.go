@@ -4,24 +4,14 @@
 package build
 
 import (
-	"strings"
-
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
 	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/build"
 )
 
 func getStatus(b v1alpha2.Build) string {
-	cond := b.Status.GetCondition(corev1alpha1.ConditionSucceeded)
-	switch {
-	case cond.IsTrue():
-		return "SUCCESS"
-	case cond.IsFalse():
-		return "FAILURE"
-	case cond.IsUnknown():
-		return "BUILDING"
-	default:
-		return "UNKNOWN"
-	}
+	return build.Status(b)
 }
 
 func getStarted(b v1alpha2.Build) string {
@@ -50,11 +40,7 @@ func getTruncatedReason(b v1alpha2.Build) string {
 }
 
 func getReasons(b v1alpha2.Build) []string {
-	s := strings.Split(b.Annotations[v1alpha2.BuildReasonAnnotation], ",")
-	if len(s) == 1 && s[0] == "" {
-		return nil
-	}
-	return s
+	return build.Reasons(b)
 }
 
 func mostImportantReason(r []string) string {
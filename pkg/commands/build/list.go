@@ -5,11 +5,11 @@ package build
 
 import (
 	"sort"
+	"strings"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/build"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
@@ -18,10 +18,18 @@ import (
 
 func NewListCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 	var (
-		namespace string
+		namespace     string
 		allNamespaces bool
+		listFlags     commands.ListFlags
 	)
 
+	sorters := map[string]func(a, b v1alpha2.Build) bool{
+		"name":    func(a, b v1alpha2.Build) bool { return a.Name < b.Name },
+		"image":   func(a, b v1alpha2.Build) bool { return a.Labels[v1alpha2.ImageLabel] < b.Labels[v1alpha2.ImageLabel] },
+		"created": func(a, b v1alpha2.Build) bool { return commands.ByCreationTimestamp(a.ObjectMeta, b.ObjectMeta) },
+		"status":  func(a, b v1alpha2.Build) bool { return getStatus(a) < getStatus(b) },
+	}
+
 	cmd := &cobra.Command{
 		Use:   "list [image-resource-name]",
 		Short: "List builds",
@@ -29,7 +37,7 @@ func NewListCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 
 The namespace defaults to the kubernetes current-context namespace.`,
 
-		Example:      "kp build list\nkp build list my-image\nkp build list my-image -n my-namespace\nkp build list -A",
+		Example:      "kp build list\nkp build list my-image\nkp build list my-image -n my-namespace\nkp build list -A\nkp build list -l team=payments --sort-by created --wide",
 		Args:         commands.OptionalArgsWithUsage(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -38,10 +46,10 @@ The namespace defaults to the kubernetes current-context namespace.`,
 				return err
 			}
 
-			opts := metav1.ListOptions{}
+			opts := listFlags.ListOptions()
 
 			if len(args) > 0 {
-				opts.LabelSelector = v1alpha2.ImageLabel + "=" + args[0]
+				opts.LabelSelector = joinSelectors(v1alpha2.ImageLabel+"="+args[0], opts.LabelSelector)
 			}
 
 			var buildNamespace string
@@ -61,30 +69,44 @@ The namespace defaults to the kubernetes current-context namespace.`,
 				return errors.New("no builds found")
 			} else {
 				sort.Slice(buildList.Items, build.Sort(buildList.Items))
-				return displayBuildsTable(cmd, buildList)
+				if err = commands.SortItems(buildList.Items, listFlags.SortBy, sorters); err != nil {
+					return err
+				}
+				return displayBuildsTable(cmd, buildList, listFlags)
 			}
 		},
 	}
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
 	cmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "Return objects found in all namespaces")
+	commands.SetListFlags(cmd, &listFlags, commands.SortKeys(sorters)...)
 
 	return cmd
 }
 
-func displayBuildsTable(cmd *cobra.Command, buildList *v1alpha2.BuildList) error {
-	writer, err := commands.NewTableWriter(cmd.OutOrStdout(), "Build", "Status", "Built Image", "Reason", "Image Resource")
+func displayBuildsTable(cmd *cobra.Command, buildList *v1alpha2.BuildList, listFlags commands.ListFlags) error {
+	headers := []string{"Build", "Status", "Built Image", "Reason", "Image Resource"}
+	if listFlags.Wide {
+		headers = append(headers, "Name", "Started", "Finished", "Namespace")
+	}
+
+	writer, err := listFlags.NewTableWriter(cmd.OutOrStdout(), headers...)
 	if err != nil {
 		return err
 	}
 
 	for _, bld := range buildList.Items {
-		err := writer.AddRow(
+		row := []string{
 			bld.Labels[v1alpha2.BuildNumberLabel],
 			getStatus(bld),
 			bld.Status.LatestImage,
 			getTruncatedReason(bld),
 			bld.Labels[v1alpha2.ImageLabel],
-		)
+		}
+		if listFlags.Wide {
+			row = append(row, bld.Name, getStarted(bld), getFinished(bld), bld.Namespace)
+		}
+
+		err := writer.AddRow(row...)
 		if err != nil {
 			return err
 		}
@@ -92,3 +114,13 @@ func displayBuildsTable(cmd *cobra.Command, buildList *v1alpha2.BuildList) error
 
 	return writer.Write()
 }
+
+func joinSelectors(selectors ...string) string {
+	var nonEmpty []string
+	for _, s := range selectors {
+		if s != "" {
+			nonEmpty = append(nonEmpty, s)
+		}
+	}
+	return strings.Join(nonEmpty, ",")
+}
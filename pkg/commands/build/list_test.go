@@ -107,6 +107,56 @@ func testBuildListCommand(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 
+		when("a label selector is specified", func() {
+			it("lists the matching builds of the image", func() {
+				testhelpers.CommandTest{
+					Objects: testhelpers.BuildsToRuntimeObjs(testhelpers.MakeTestBuilds(image, defaultNamespace)),
+					Args:    []string{image, "-l", "image.kpack.io/buildNumber=2"},
+					ExpectedOutput: `BUILD    STATUS     BUILT IMAGE             REASON     IMAGE RESOURCE
+2        FAILURE    repo.com/image-2:tag    COMMIT+    test-image
+
+`,
+				}.TestKpack(t, cmdFunc)
+			})
+		})
+
+		when("sort-by is specified", func() {
+			it("sorts the builds by the provided key", func() {
+				testhelpers.CommandTest{
+					Objects: testhelpers.BuildsToRuntimeObjs(testhelpers.MakeTestBuilds(image, defaultNamespace)),
+					Args:    []string{"--sort-by", "created", "--no-headers"},
+					ExpectedOutput: `1    SUCCESS     repo.com/image-1:tag          CONFIG     test-image
+1    BUILDING    repo.com/other-image-1:tag    UNKNOWN    some-other-image
+2    FAILURE     repo.com/image-2:tag          COMMIT+    test-image
+3    BUILDING    repo.com/image-3:tag          TRIGGER    test-image
+`,
+				}.TestKpack(t, cmdFunc)
+			})
+
+			it("errors with the valid keys when the key is invalid", func() {
+				testhelpers.CommandTest{
+					Objects:             testhelpers.BuildsToRuntimeObjs(testhelpers.MakeTestBuilds(image, defaultNamespace)),
+					Args:                []string{"--sort-by", "bogus"},
+					ExpectErr:           true,
+					ExpectedErrorOutput: "Error: invalid sort-by key \"bogus\", valid keys are: created, image, name, status\n",
+				}.TestKpack(t, cmdFunc)
+			})
+		})
+
+		when("wide is specified", func() {
+			it("prints additional columns", func() {
+				testhelpers.CommandTest{
+					Objects: testhelpers.BuildsToRuntimeObjs(testhelpers.MakeTestBuilds(image, defaultNamespace)),
+					Args:    []string{image, "--wide"},
+					ExpectedOutput: `BUILD    STATUS      BUILT IMAGE             REASON     IMAGE RESOURCE    NAME           STARTED                FINISHED               NAMESPACE
+1        SUCCESS     repo.com/image-1:tag    CONFIG     test-image        build-one      0001-01-01 00:00:00    0001-01-01 00:00:00    some-default-namespace
+2        FAILURE     repo.com/image-2:tag    COMMIT+    test-image        build-two      0001-01-01 01:00:00    0001-01-01 00:00:00    some-default-namespace
+3        BUILDING    repo.com/image-3:tag    TRIGGER    test-image        build-three    0001-01-01 05:00:00                           some-default-namespace
+
+`,
+				}.TestKpack(t, cmdFunc)
+			})
+		})
 
 		when("an image is specified", func() {
 			const expectedOutput = `BUILD    STATUS      BUILT IMAGE             REASON     IMAGE RESOURCE
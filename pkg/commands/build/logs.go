@@ -5,7 +5,12 @@ package build
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
 	"github.com/pivotal/kpack/pkg/logs"
@@ -22,21 +27,42 @@ func NewLogsCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 	var (
 		namespace   string
 		buildNumber string
+		logOpts     build.LogOptions
+		outputDir   string
+		allBuilds   bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "logs <image-name>",
 		Short: "Tails logs for an image resource build",
-		Long: `Tails logs from the containers of a specific build of an image resource in the provided namespace.
+		Long: fmt.Sprintf(`Tails logs from the containers of a specific build of an image resource in the provided namespace.
 
 The build defaults to the latest build number.
 The namespace defaults to the kubernetes current-context namespace.
 
-Use the flag --timestamps to include the timestamps for the logs`,
-		Example:      "kp build logs my-image\nkp build logs my-image -b 2 -n my-namespace",
+Use the flag --timestamps to include the timestamps for the logs
+
+The flags --step, --since, --tail, --output-dir, --all-builds and --strip-ansi print the logs
+currently available for each step instead of following the build.
+Valid steps are: %s
+
+With --output-dir, the logs of each step are written to <output-dir>/build-<number>/<step>.log`, strings.Join(build.Steps, ", ")),
+		Example: `kp build logs my-image
+kp build logs my-image -b 2 -n my-namespace
+kp build logs my-image --step build --tail 100
+kp build logs my-image --step detect,build --since 10m --strip-ansi
+kp build logs my-image --all-builds --output-dir ./logs`,
 		Args:         commands.ExactArgsWithUsage(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := build.ValidateSteps(logOpts.Steps); err != nil {
+				return err
+			}
+
+			if allBuilds && buildNumber != "" {
+				return errors.New("the --build and --all-builds flags cannot be used together")
+			}
+
 			cs, err := clientSetProvider.GetClientSet(namespace)
 			if err != nil {
 				return err
@@ -53,17 +79,101 @@ Use the flag --timestamps to include the timestamps for the logs`,
 				return errors.New("no builds found")
 			} else {
 				ch, err := commands.NewCommandHelper(cmd)
+				if err != nil {
+					return err
+				}
+
 				sort.Slice(buildList.Items, build.Sort(buildList.Items))
+
+				logOpts.Timestamps = ch.ShowTimestamp()
+				fetcher := build.LogsFetcher{K8sClient: cs.K8sClient}
+
+				if allBuilds {
+					for _, bld := range buildList.Items {
+						err := fetchBuildLogs(cmd, fetcher, bld, logOpts, outputDir, true)
+						if _, ok := err.(build.PodNotFoundError); ok {
+							_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: skipping build %s: %s\n", bld.Labels[v1alpha2.BuildNumberLabel], err)
+							continue
+						} else if err != nil {
+							return err
+						}
+					}
+					return nil
+				}
+
 				bld, err := findBuild(buildList, buildNumber)
 				if err != nil {
 					return err
 				}
-				return logs.NewBuildLogsClient(cs.K8sClient).Tail(context.Background(), cmd.OutOrStdout(), args[0], bld.Labels[v1alpha2.BuildNumberLabel], cs.Namespace, ch.ShowTimestamp())
+
+				if !usesLogsFetcher(cmd) {
+					return logs.NewBuildLogsClient(cs.K8sClient).Tail(context.Background(), cmd.OutOrStdout(), args[0], bld.Labels[v1alpha2.BuildNumberLabel], cs.Namespace, ch.ShowTimestamp())
+				}
+
+				return fetchBuildLogs(cmd, fetcher, bld, logOpts, outputDir, false)
 			}
 		},
 	}
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
 	cmd.Flags().StringVarP(&buildNumber, "build", "b", "", "build number")
 	cmd.Flags().BoolP("timestamps", "t", false, "show log timestamps")
+	cmd.Flags().StringSliceVar(&logOpts.Steps, "step", nil, "only show the logs of these steps (can be set more than once or comma separated)")
+	cmd.Flags().DurationVar(&logOpts.Since, "since", 0, "only show logs newer than a relative duration like 5s, 2m, or 3h")
+	cmd.Flags().Int64Var(&logOpts.Tail, "tail", 0, "number of lines to show from the end of the logs of each step")
+	cmd.Flags().StringVar(&outputDir, "output-dir", "", "write the logs of each step to a file in this directory")
+	cmd.Flags().BoolVar(&allBuilds, "all-builds", false, "show the logs of every retained build of the image")
+	cmd.Flags().BoolVar(&logOpts.StripANSI, "strip-ansi", false, "remove ANSI color codes from the logs")
 	return cmd
 }
+
+func usesLogsFetcher(cmd *cobra.Command) bool {
+	for _, flag := range []string{"step", "since", "tail", "output-dir", "all-builds", "strip-ansi"} {
+		if cmd.Flags().Changed(flag) {
+			return true
+		}
+	}
+	return false
+}
+
+func fetchBuildLogs(cmd *cobra.Command, fetcher build.LogsFetcher, bld v1alpha2.Build, opts build.LogOptions, outputDir string, multipleBuilds bool) error {
+	buildNumber := bld.Labels[v1alpha2.BuildNumberLabel]
+
+	if outputDir != "" {
+		dir := filepath.Join(outputDir, "build-"+buildNumber)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+
+		err := fetcher.Fetch(cmd.Context(), bld, opts, func(step string) (io.WriteCloser, error) {
+			return os.Create(filepath.Join(dir, step+".log"))
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(cmd.OutOrStdout(), "Wrote logs of build %s to %s\n", buildNumber, dir)
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if multipleBuilds {
+		if _, err := fmt.Fprintf(out, "===== BUILD %s =====\n", buildNumber); err != nil {
+			return err
+		}
+	}
+
+	return fetcher.Fetch(cmd.Context(), bld, opts, func(step string) (io.WriteCloser, error) {
+		if _, err := fmt.Fprintf(out, "===> %s\n", strings.ToUpper(step)); err != nil {
+			return nil, err
+		}
+		return nopWriteCloser{out}, nil
+	})
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
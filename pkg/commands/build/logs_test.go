@@ -4,11 +4,18 @@
 package build_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
 	"github.com/sclevine/spec"
 	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfakes "k8s.io/client-go/kubernetes/fake"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands/build"
 	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
@@ -81,4 +88,132 @@ func testBuildLogsCommand(t *testing.T, when spec.G, it spec.S) {
 			})
 		})
 	})
+
+	when("selecting steps and limits", func() {
+		const namespace = "some-namespace"
+
+		k8sCmdFunc := func(k8sClientSet *k8sfakes.Clientset, kpackClientSet *fake.Clientset) *cobra.Command {
+			clientSetProvider := testhelpers.GetFakeClusterProvider(k8sClientSet, kpackClientSet)
+			return build.NewLogsCommand(clientSetProvider)
+		}
+
+		makePod := func(name string) *corev1.Pod {
+			return &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+				},
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{
+						{Name: "prepare"},
+						{Name: "detect"},
+						{Name: "build"},
+						{Name: "export"},
+					},
+					Containers: []corev1.Container{
+						{Name: "completion"},
+					},
+				},
+				Status: corev1.PodStatus{
+					InitContainerStatuses: []corev1.ContainerStatus{
+						{Name: "prepare", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}}},
+						{Name: "detect", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}}},
+						{Name: "build", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+						{Name: "export", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{}}},
+					},
+					ContainerStatuses: []corev1.ContainerStatus{
+						{Name: "completion", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{}}},
+					},
+				},
+			}
+		}
+
+		objects := func(pods ...string) []runtime.Object {
+			objs := testhelpers.BuildsToRuntimeObjs(testhelpers.MakeTestBuilds(image, namespace))
+			for _, pod := range pods {
+				objs = append(objs, makePod(pod))
+			}
+			return objs
+		}
+
+		it("prints the logs of each started step of the latest build", func() {
+			testhelpers.CommandTest{
+				Objects: objects("pod-three"),
+				Args:    []string{image, "-n", namespace, "--tail", "10"},
+				ExpectedOutput: `===> PREPARE
+fake logs===> DETECT
+fake logs===> BUILD
+fake logs`,
+			}.TestK8sAndKpack(t, k8sCmdFunc)
+		})
+
+		it("only prints the selected steps", func() {
+			testhelpers.CommandTest{
+				Objects: objects("pod-one"),
+				Args:    []string{image, "-n", namespace, "-b", "1", "--step", "build"},
+				ExpectedOutput: `===> BUILD
+fake logs`,
+			}.TestK8sAndKpack(t, k8sCmdFunc)
+		})
+
+		it("errors on an invalid step", func() {
+			testhelpers.CommandTest{
+				Objects:             objects("pod-one"),
+				Args:                []string{image, "-n", namespace, "--step", "compile"},
+				ExpectErr:           true,
+				ExpectedErrorOutput: "Error: invalid step \"compile\", valid steps are: prepare, analyze, detect, restore, build, export, rebase, completion\n",
+			}.TestK8sAndKpack(t, k8sCmdFunc)
+		})
+
+		it("errors when the pod of the build no longer exists", func() {
+			testhelpers.CommandTest{
+				Objects:             objects(),
+				Args:                []string{image, "-n", namespace, "--since", "5m"},
+				ExpectErr:           true,
+				ExpectedErrorOutput: "Error: pod \"pod-three\" of build \"build-three\" no longer exists\n",
+			}.TestK8sAndKpack(t, k8sCmdFunc)
+		})
+
+		it("prints the logs of every build and skips builds without a pod", func() {
+			testhelpers.CommandTest{
+				Objects: objects("pod-one", "pod-three"),
+				Args:    []string{image, "-n", namespace, "--all-builds", "--step", "detect"},
+				ExpectedOutput: `===== BUILD 1 =====
+===> DETECT
+fake logs===== BUILD 2 =====
+===== BUILD 3 =====
+===> DETECT
+fake logs`,
+				ExpectedErrorOutput: "Warning: skipping build 2: pod \"pod-two\" of build \"build-two\" no longer exists\n",
+			}.TestK8sAndKpack(t, k8sCmdFunc)
+		})
+
+		it("does not allow --all-builds with --build", func() {
+			testhelpers.CommandTest{
+				Args:                []string{image, "-n", namespace, "--all-builds", "-b", "1"},
+				ExpectErr:           true,
+				ExpectedErrorOutput: "Error: the --build and --all-builds flags cannot be used together\n",
+			}.TestK8sAndKpack(t, k8sCmdFunc)
+		})
+
+		it("writes the logs of each step to a file", func() {
+			outputDir := t.TempDir()
+			buildDir := filepath.Join(outputDir, "build-1")
+
+			testhelpers.CommandTest{
+				Objects:        objects("pod-one"),
+				Args:           []string{image, "-n", namespace, "-b", "1", "--step", "prepare,build", "--output-dir", outputDir},
+				ExpectedOutput: "Wrote logs of build 1 to " + buildDir + "\n",
+			}.TestK8sAndKpack(t, k8sCmdFunc)
+
+			for _, step := range []string{"prepare", "build"} {
+				contents, err := os.ReadFile(filepath.Join(buildDir, step+".log"))
+				require.NoError(t, err)
+				require.Equal(t, "fake logs", string(contents))
+			}
+
+			_, err := os.Stat(filepath.Join(buildDir, "detect.log"))
+			require.True(t, os.IsNotExist(err))
+		})
+	})
 }
@@ -0,0 +1,161 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/build"
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+func NewPruneCommand(clientSetProvider k8s.ClientSetProvider, confirmationProvider ConfirmationProvider) *cobra.Command {
+	var (
+		namespace     string
+		allNamespaces bool
+		olderThan     string
+		keepLast      int
+		failedOnly    bool
+		forceDelete   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "prune [image-resource-name]",
+		Short: "Delete old builds",
+		Long: `Delete old builds of an image resource or of every image resource in the provided namespace.
+
+At least one of --older-than, --keep-last or --failed-only must be set.
+The latest build of every image resource and running builds are never deleted.
+
+The builds to delete are listed before asking for confirmation, use --dry-run to only list them.
+
+The namespace defaults to the kubernetes current-context namespace.`,
+		Example:      "kp build prune my-image --keep-last 5\nkp build prune --older-than 30d --failed-only\nkp build prune -A --older-than 720h --dry-run",
+		Args:         commands.OptionalArgsWithUsage(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if olderThan == "" && keepLast == 0 && !failedOnly {
+				return errors.New("at least one of --older-than, --keep-last or --failed-only must be set")
+			}
+
+			if keepLast < 0 {
+				return errors.New("--keep-last must not be negative")
+			}
+
+			opts := build.PruneOptions{
+				KeepLast:   keepLast,
+				FailedOnly: failedOnly,
+				Now:        time.Now(),
+			}
+
+			if olderThan != "" {
+				age, err := commands.ParseAge(olderThan)
+				if err != nil {
+					return errors.Errorf("invalid --older-than %q: %s", olderThan, err)
+				}
+				opts.OlderThan = age
+			}
+
+			cs, err := clientSetProvider.GetClientSet(namespace)
+			if err != nil {
+				return err
+			}
+
+			ch, err := commands.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+
+			listOpts := metav1.ListOptions{}
+			if len(args) > 0 {
+				listOpts.LabelSelector = v1alpha2.ImageLabel + "=" + args[0]
+			}
+
+			buildNamespace := cs.Namespace
+			if allNamespaces {
+				buildNamespace = ""
+			}
+
+			buildList, err := cs.KpackClient.KpackV1alpha2().Builds(buildNamespace).List(ctx, listOpts)
+			if err != nil {
+				return err
+			}
+
+			candidates := build.PruneCandidates(buildList.Items, opts)
+			if len(candidates) == 0 {
+				return ch.PrintResult("No builds to prune")
+			}
+
+			if err = displayPruneTable(cmd, candidates); err != nil {
+				return err
+			}
+
+			if ch.IsDryRun() {
+				return ch.PrintResult("%d build(s) would be deleted", len(candidates))
+			}
+
+			if !forceDelete {
+				message := fmt.Sprintf("Please confirm deletion of %d build(s) by typing 'y': ", len(candidates))
+				confirmed, err := confirmationProvider.Confirm(message)
+				if err != nil {
+					return err
+				}
+
+				if !confirmed {
+					_, err = fmt.Fprintln(cmd.OutOrStdout(), "Skipping Build deletion")
+					return err
+				}
+			}
+
+			for _, bld := range candidates {
+				err = cs.KpackClient.KpackV1alpha2().Builds(bld.Namespace).Delete(ctx, bld.Name, metav1.DeleteOptions{})
+				if err != nil {
+					return errors.Wrapf(err, "failed to delete build %s of image %q", bld.Labels[v1alpha2.BuildNumberLabel], bld.Labels[v1alpha2.ImageLabel])
+				}
+			}
+
+			return ch.PrintResult("%d build(s) deleted", len(candidates))
+		},
+	}
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
+	cmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "prune builds in all namespaces")
+	cmd.Flags().StringVar(&olderThan, "older-than", "", "only delete builds created longer ago than this duration, such as 72h or 30d")
+	cmd.Flags().IntVar(&keepLast, "keep-last", 0, "number of the most recent builds of each image resource to keep (the latest build is always kept)")
+	cmd.Flags().BoolVar(&failedOnly, "failed-only", false, "only delete failed builds")
+	cmd.Flags().BoolVarP(&forceDelete, "force", "f", false, "force deletion without confirmation")
+	cmd.Flags().Bool(commands.DryRunFlag, false, "only list the builds that would be deleted")
+
+	return cmd
+}
+
+func displayPruneTable(cmd *cobra.Command, builds []v1alpha2.Build) error {
+	writer, err := commands.NewTableWriter(cmd.OutOrStdout(), "Image Resource", "Build", "Status", "Started", "Namespace")
+	if err != nil {
+		return err
+	}
+
+	for _, bld := range builds {
+		err := writer.AddRow(
+			bld.Labels[v1alpha2.ImageLabel],
+			bld.Labels[v1alpha2.BuildNumberLabel],
+			getStatus(bld),
+			getStarted(bld),
+			bld.Namespace,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.Write()
+}
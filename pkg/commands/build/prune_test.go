@@ -0,0 +1,150 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package build_test
+
+import (
+	"testing"
+
+	"github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/sclevine/spec"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	clientgotesting "k8s.io/client-go/testing"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands/build"
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands/fakes"
+	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
+)
+
+func TestBuildPruneCommand(t *testing.T) {
+	spec.Run(t, "TestBuildPruneCommand", testBuildPruneCommand)
+}
+
+func testBuildPruneCommand(t *testing.T, when spec.G, it spec.S) {
+	const (
+		image            = "test-image"
+		defaultNamespace = "some-default-namespace"
+		otherNamespace   = "some-other-namespace"
+	)
+
+	var fakeConfirmationProvider *fakes.FakeConfirmationProvider
+
+	cmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
+		clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
+		return build.NewPruneCommand(clientSetProvider, fakeConfirmationProvider)
+	}
+
+	builds := testhelpers.BuildsToRuntimeObjs(testhelpers.MakeTestBuilds(image, defaultNamespace))
+
+	it.Before(func() {
+		fakeConfirmationProvider = fakes.NewFakeConfirmationProvider(true, nil)
+	})
+
+	it("deletes all but the most recent builds of each image resource", func() {
+		testhelpers.CommandTest{
+			Objects: builds,
+			Args:    []string{"--keep-last", "1"},
+			ExpectedOutput: `IMAGE RESOURCE    BUILD    STATUS     STARTED                NAMESPACE
+test-image        1        SUCCESS    0001-01-01 00:00:00    some-default-namespace
+test-image        2        FAILURE    0001-01-01 01:00:00    some-default-namespace
+
+2 build(s) deleted
+`,
+			ExpectDeletes: []clientgotesting.DeleteActionImpl{
+				{ActionImpl: clientgotesting.ActionImpl{Namespace: defaultNamespace}, Name: "build-one"},
+				{ActionImpl: clientgotesting.ActionImpl{Namespace: defaultNamespace}, Name: "build-two"},
+			},
+		}.TestKpack(t, cmdFunc)
+		require.NoError(t, fakeConfirmationProvider.WasRequestedWithMsg("Please confirm deletion of 2 build(s) by typing 'y': "))
+	})
+
+	it("only deletes failed builds", func() {
+		testhelpers.CommandTest{
+			Objects: builds,
+			Args:    []string{image, "--failed-only", "--force"},
+			ExpectedOutput: `IMAGE RESOURCE    BUILD    STATUS     STARTED                NAMESPACE
+test-image        2        FAILURE    0001-01-01 01:00:00    some-default-namespace
+
+1 build(s) deleted
+`,
+			ExpectDeletes: []clientgotesting.DeleteActionImpl{
+				{ActionImpl: clientgotesting.ActionImpl{Namespace: defaultNamespace}, Name: "build-two"},
+			},
+		}.TestKpack(t, cmdFunc)
+		require.False(t, fakeConfirmationProvider.WasRequested())
+	})
+
+	it("only lists the builds on a dry run", func() {
+		testhelpers.CommandTest{
+			Objects: builds,
+			Args:    []string{"--older-than", "30d", "--dry-run"},
+			ExpectedOutput: `IMAGE RESOURCE    BUILD    STATUS     STARTED                NAMESPACE
+test-image        1        SUCCESS    0001-01-01 00:00:00    some-default-namespace
+test-image        2        FAILURE    0001-01-01 01:00:00    some-default-namespace
+
+2 build(s) would be deleted (dry run)
+`,
+		}.TestKpack(t, cmdFunc)
+		require.False(t, fakeConfirmationProvider.WasRequested())
+	})
+
+	it("prunes builds in all namespaces", func() {
+		otherBuilds := testhelpers.BuildsToRuntimeObjs(testhelpers.MakeTestBuilds(image, otherNamespace))
+
+		testhelpers.CommandTest{
+			Objects: append(builds, otherBuilds...),
+			Args:    []string{"-A", "--failed-only", "--force"},
+			ExpectedOutput: `IMAGE RESOURCE    BUILD    STATUS     STARTED                NAMESPACE
+test-image        2        FAILURE    0001-01-01 01:00:00    some-default-namespace
+test-image        2        FAILURE    0001-01-01 01:00:00    some-other-namespace
+
+2 build(s) deleted
+`,
+			ExpectDeletes: []clientgotesting.DeleteActionImpl{
+				{ActionImpl: clientgotesting.ActionImpl{Namespace: defaultNamespace}, Name: "build-two"},
+				{ActionImpl: clientgotesting.ActionImpl{Namespace: otherNamespace}, Name: "build-two"},
+			},
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("does not delete builds when confirmation is not given", func() {
+		fakeConfirmationProvider = fakes.NewFakeConfirmationProvider(false, nil)
+
+		testhelpers.CommandTest{
+			Objects: builds,
+			Args:    []string{"--failed-only"},
+			ExpectedOutput: `IMAGE RESOURCE    BUILD    STATUS     STARTED                NAMESPACE
+test-image        2        FAILURE    0001-01-01 01:00:00    some-default-namespace
+
+Skipping Build deletion
+`,
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("reports when there is nothing to prune", func() {
+		testhelpers.CommandTest{
+			Objects:        builds,
+			Args:           []string{image, "--keep-last", "3"},
+			ExpectedOutput: "No builds to prune\n",
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("requires a prune criteria", func() {
+		testhelpers.CommandTest{
+			Objects:             builds,
+			Args:                []string{image},
+			ExpectErr:           true,
+			ExpectedErrorOutput: "Error: at least one of --older-than, --keep-last or --failed-only must be set\n",
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("errors on an invalid age", func() {
+		testhelpers.CommandTest{
+			Objects:             builds,
+			Args:                []string{"--older-than", "yesterday"},
+			ExpectErr:           true,
+			ExpectedErrorOutput: "Error: invalid --older-than \"yesterday\": time: invalid duration \"yesterday\"\n",
+		}.TestKpack(t, cmdFunc)
+	})
+}
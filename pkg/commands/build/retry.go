@@ -0,0 +1,201 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/build"
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+func NewRetryCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+	var (
+		namespace   string
+		buildNumber string
+		pin         bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "retry <image-name> --build <number>",
+		Short: "Trigger a new build with the inputs of a previous build",
+		Long: `Trigger a new build of an image resource using the resolved inputs of a previous build.
+
+The env vars and service bindings of the image resource are patched to the ones of the given build.
+The builder and run image cannot be pinned, the new build uses the current builder of the image resource.
+
+When the build used a different source than the image resource, such as the resolved commit of the branch that
+the image resource follows, retrying it pins the image resource to that source until it is patched again.
+The command fails unless --pin is provided and prints the "kp image patch" command that restores the previous source.
+
+When the image resource already has the inputs of the build, a new build is triggered instead.
+
+The namespace defaults to the kubernetes current-context namespace.`,
+		Example:      "kp build retry my-image -b 3\nkp build retry my-image -b 3 --pin\nkp build retry my-image -b 3 -n my-namespace --dry-run",
+		Args:         commands.ExactArgsWithUsage(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if buildNumber == "" {
+				return errors.New("the --build flag is required")
+			}
+
+			cs, err := clientSetProvider.GetClientSet(namespace)
+			if err != nil {
+				return err
+			}
+
+			ch, err := commands.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+
+			buildList, err := cs.KpackClient.KpackV1alpha2().Builds(cs.Namespace).List(ctx, metav1.ListOptions{
+				LabelSelector: v1alpha2.ImageLabel + "=" + args[0],
+			})
+			if err != nil {
+				return err
+			}
+
+			if len(buildList.Items) == 0 {
+				return errors.New("no builds found")
+			}
+
+			sort.Slice(buildList.Items, build.Sort(buildList.Items))
+			bld, err := findBuild(buildList, buildNumber)
+			if err != nil {
+				return err
+			}
+
+			img, err := cs.KpackClient.KpackV1alpha2().Images(cs.Namespace).Get(ctx, args[0], metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+
+			updatedImage := build.ApplyInputs(img, build.ResolvedInputs(bld))
+			sourceChanged := !equality.Semantic.DeepEqual(img.Spec.Source, updatedImage.Spec.Source)
+			if sourceChanged && !pin {
+				return errors.Errorf("build %s was built from %s but Image Resource %q builds from %s, use --pin to pin the image resource to the source of the build",
+					buildNumber, describeSource(updatedImage.Spec.Source), img.Name, describeSource(img.Spec.Source))
+			}
+
+			if err = ch.PrintStatus("Retrying build %s of Image Resource %q...", buildNumber, img.Name); err != nil {
+				return err
+			}
+
+			patch, err := k8s.CreatePatch(img, updatedImage)
+			if err != nil {
+				return err
+			}
+
+			if len(patch) == 0 {
+				return triggerLatestBuild(cmd, ch, cs, buildList, img.Name)
+			}
+
+			if !ch.IsDryRun() {
+				updatedImage, err = cs.KpackClient.KpackV1alpha2().Images(cs.Namespace).Patch(ctx, img.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+				if err != nil {
+					return err
+				}
+			}
+
+			if err = ch.PrintObjs([]runtime.Object{updatedImage}); err != nil {
+				return err
+			}
+
+			if err = ch.PrintResult("Image Resource %q patched with the inputs of build %s", img.Name, buildNumber); err != nil {
+				return err
+			}
+
+			if !sourceChanged {
+				return nil
+			}
+
+			if err = ch.PrintResult("Image Resource %q is now pinned to %s", img.Name, describeSource(updatedImage.Spec.Source)); err != nil {
+				return err
+			}
+
+			if restore := restoreSourceFlags(img.Spec.Source); restore != "" {
+				restoreCmd := fmt.Sprintf("kp image patch %s %s", img.Name, restore)
+				if namespace != "" {
+					restoreCmd += " -n " + namespace
+				}
+				return ch.PrintResult("Restore the previous source with %q", restoreCmd)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
+	cmd.Flags().StringVarP(&buildNumber, "build", "b", "", "build number to retry")
+	cmd.Flags().BoolVar(&pin, "pin", false, "pin the image resource to the source of the build when it differs")
+	commands.SetDryRunOutputFlags(cmd)
+
+	return cmd
+}
+
+func triggerLatestBuild(cmd *cobra.Command, ch *commands.CommandHelper, cs k8s.ClientSet, buildList *v1alpha2.BuildList, imageName string) error {
+	original := buildList.Items[len(buildList.Items)-1].DeepCopy()
+	nextBuildNumber, _ := strconv.Atoi(original.Labels[v1alpha2.BuildNumberLabel])
+	nextBuildNumber++
+
+	if !ch.IsDryRun() {
+		patched := original.DeepCopy()
+		if patched.Annotations == nil {
+			patched.Annotations = map[string]string{}
+		}
+		patched.Annotations[v1alpha2.BuildNeededAnnotation] = time.Now().String()
+
+		patch, err := k8s.CreatePatch(original, patched)
+		if err != nil {
+			return err
+		}
+
+		_, err = cs.KpackClient.KpackV1alpha2().Builds(cs.Namespace).Patch(cmd.Context(), original.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+		if err != nil {
+			return err
+		}
+	}
+
+	return ch.PrintResult("Triggered build for Image Resource %q with Build Number %d", imageName, nextBuildNumber)
+}
+
+func describeSource(source corev1alpha1.SourceConfig) string {
+	switch {
+	case source.Git != nil:
+		return "git revision " + source.Git.Revision
+	case source.Blob != nil:
+		return "blob " + source.Blob.URL
+	case source.Registry != nil:
+		return "source image " + source.Registry.Image
+	default:
+		return "no source"
+	}
+}
+
+// restoreSourceFlags returns the flags of "kp image patch" that restore the
+// source, local source code cannot be restored from the image resource
+func restoreSourceFlags(source corev1alpha1.SourceConfig) string {
+	switch {
+	case source.Git != nil:
+		return "--git-revision " + source.Git.Revision
+	case source.Blob != nil:
+		return "--blob " + source.Blob.URL
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package build_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	"github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/sclevine/spec"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands/build"
+	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
+)
+
+func TestBuildRetryCommand(t *testing.T) {
+	spec.Run(t, "TestBuildRetryCommand", testBuildRetryCommand)
+}
+
+func testBuildRetryCommand(t *testing.T, when spec.G, it spec.S) {
+	const (
+		imageName        = "test-image"
+		defaultNamespace = "some-default-namespace"
+	)
+
+	cmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
+		clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
+		return build.NewRetryCommand(clientSetProvider)
+	}
+
+	makeBuild := func(number, revision string, env ...corev1.EnvVar) *v1alpha2.Build {
+		return &v1alpha2.Build{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "build-" + number,
+				Namespace: defaultNamespace,
+				Labels: map[string]string{
+					v1alpha2.ImageLabel:       imageName,
+					v1alpha2.BuildNumberLabel: number,
+				},
+			},
+			Spec: v1alpha2.BuildSpec{
+				Source: corev1alpha1.SourceConfig{
+					Git: &corev1alpha1.Git{
+						URL:      "https://github.com/some/repo",
+						Revision: revision,
+					},
+				},
+				Env: env,
+			},
+		}
+	}
+
+	img := &v1alpha2.Image{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      imageName,
+			Namespace: defaultNamespace,
+		},
+		Spec: v1alpha2.ImageSpec{
+			Tag: "some-registry.io/some-repo",
+			Source: corev1alpha1.SourceConfig{
+				Git: &corev1alpha1.Git{
+					URL:      "https://github.com/some/repo",
+					Revision: "main",
+				},
+			},
+		},
+	}
+
+	buildOne := makeBuild("1", "abc123", corev1.EnvVar{Name: "FOO", Value: "bar"})
+	buildTwo := makeBuild("2", "def456")
+
+	it("patches the image resource with the inputs of the build and reports the previous revision", func() {
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{img, buildOne, buildTwo},
+			Args:    []string{imageName, "-b", "1", "--pin"},
+			ExpectedOutput: `Retrying build 1 of Image Resource "test-image"...
+Image Resource "test-image" patched with the inputs of build 1
+Image Resource "test-image" is now pinned to git revision abc123
+Restore the previous source with "kp image patch test-image --git-revision main"
+`,
+			ExpectPatches: []string{
+				`{"spec":{"build":{"env":[{"name":"FOO","value":"bar"}],"resources":{}},"source":{"git":{"revision":"abc123"}}}}`,
+			},
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("triggers a new build when the image resource already has the inputs of the build", func() {
+		pinnedImg := img.DeepCopy()
+		pinnedImg.Spec.Source.Git.Revision = "def456"
+
+		clientSet := fake.NewSimpleClientset(pinnedImg, buildOne, buildTwo)
+		cmd := cmdFunc(clientSet)
+		cmd.SetArgs([]string{imageName, "-b", "2"})
+
+		out := &bytes.Buffer{}
+		cmd.SetOut(out)
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, `Retrying build 2 of Image Resource "test-image"...
+Triggered build for Image Resource "test-image" with Build Number 3
+`, out.String())
+
+		actions, err := testhelpers.ActionRecorderList{clientSet}.ActionsByVerb()
+		require.NoError(t, err)
+		require.Len(t, actions.Patches, 1)
+		require.Equal(t, "build-2", actions.Patches[0].GetName())
+		require.Contains(t, string(actions.Patches[0].GetPatch()), v1alpha2.BuildNeededAnnotation)
+	})
+
+	it("does not patch the image resource on a dry run", func() {
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{img, buildOne, buildTwo},
+			Args:    []string{imageName, "-b", "1", "--pin", "--dry-run"},
+			ExpectedOutput: `Retrying build 1 of Image Resource "test-image"... (dry run)
+Image Resource "test-image" patched with the inputs of build 1 (dry run)
+Image Resource "test-image" is now pinned to git revision abc123 (dry run)
+Restore the previous source with "kp image patch test-image --git-revision main" (dry run)
+`,
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("requires --pin to change the source of the image resource", func() {
+		testhelpers.CommandTest{
+			Objects:             []runtime.Object{img, buildOne, buildTwo},
+			Args:                []string{imageName, "-b", "1"},
+			ExpectErr:           true,
+			ExpectedErrorOutput: "Error: build 1 was built from git revision abc123 but Image Resource \"test-image\" builds from git revision main, use --pin to pin the image resource to the source of the build\n",
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("patches the env of the image resource without --pin when the source is unchanged", func() {
+		pinnedImg := img.DeepCopy()
+		pinnedImg.Spec.Source.Git.Revision = "abc123"
+
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{pinnedImg, buildOne, buildTwo},
+			Args:    []string{imageName, "-b", "1", "-n", defaultNamespace},
+			ExpectedOutput: `Retrying build 1 of Image Resource "test-image"...
+Image Resource "test-image" patched with the inputs of build 1
+`,
+			ExpectPatches: []string{
+				`{"spec":{"build":{"env":[{"name":"FOO","value":"bar"}],"resources":{}}}}`,
+			},
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("requires the build flag", func() {
+		testhelpers.CommandTest{
+			Objects:             []runtime.Object{img, buildOne, buildTwo},
+			Args:                []string{imageName},
+			ExpectErr:           true,
+			ExpectedErrorOutput: "Error: the --build flag is required\n",
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("errors when the build does not exist", func() {
+		testhelpers.CommandTest{
+			Objects:             []runtime.Object{img, buildOne, buildTwo},
+			Args:                []string{imageName, "-b", "5"},
+			ExpectErr:           true,
+			ExpectedErrorOutput: "Error: build \"5\" not found\n",
+		}.TestKpack(t, cmdFunc)
+	})
+}
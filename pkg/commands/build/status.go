@@ -27,6 +27,8 @@ func NewStatusCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 	var (
 		namespace   string
 		buildNumber string
+		output      string
+		logLines    int64
 	)
 
 	cmd := &cobra.Command{
@@ -34,9 +36,12 @@ func NewStatusCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 		Short: "Display status for an image resource build",
 		Long: `Prints detailed information about the status of a specific build of an image resource in the provided namespace.
 
+When the build failed, the failed step, its exit code and the last lines of its logs are inspected
+to report the likely cause of the failure along with the relevant log excerpt.
+
 The build defaults to the latest build number.
 The namespace defaults to the kubernetes current-context namespace.`,
-		Example:      "kp build status my-image\nkp build status my-image -b 2 -n my-namespace",
+		Example:      "kp build status my-image\nkp build status my-image -b 2 -n my-namespace\nkp build status my-image -o json",
 		Args:         commands.ExactArgsWithUsage(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -61,12 +66,27 @@ The namespace defaults to the kubernetes current-context namespace.`,
 					return err
 				}
 
-				return displayBuildStatus(cmd, bld)
+				diagnoser := build.Diagnoser{K8sClient: cs.K8sClient, LogLines: logLines, Warnings: cmd.ErrOrStderr()}
+				diagnosis, err := diagnoser.Diagnose(cmd.Context(), bld)
+				if err != nil {
+					return err
+				}
+
+				switch output {
+				case "":
+					return displayBuildStatus(cmd, bld, diagnosis)
+				case "json":
+					return displayBuildStatusJson(cmd, bld, diagnosis)
+				default:
+					return errors.Errorf("invalid output format %q, supported formats are: json", output)
+				}
 			}
 		},
 	}
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
 	cmd.Flags().StringVarP(&buildNumber, "build", "b", "", "build number")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "output format; supported formats are: json")
+	cmd.Flags().Int64Var(&logLines, "log-lines", build.DefaultDiagnosisLogLines, "number of log lines of a failed step to inspect")
 
 	return cmd
 }
@@ -96,7 +116,7 @@ func findBuild(buildList *v1alpha2.BuildList, buildNumberString string) (v1alpha
 	return v1alpha2.Build{}, errors.Errorf("build \"%d\" not found", buildNumber)
 }
 
-func displayBuildStatus(cmd *cobra.Command, bld v1alpha2.Build) error {
+func displayBuildStatus(cmd *cobra.Command, bld v1alpha2.Build, diagnosis *build.Diagnosis) error {
 	statusWriter := commands.NewStatusWriter(cmd.OutOrStdout())
 
 	reason, err := buildReason(bld)
@@ -125,6 +145,13 @@ func displayBuildStatus(cmd *cobra.Command, bld v1alpha2.Build) error {
 		return err
 	}
 
+	if diagnosis != nil {
+		err = statusWriter.AddBlock("", diagnosisItems(diagnosis)...)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = statusWriter.AddBlock(
 		"",
 		"Started", getStarted(bld),
@@ -242,3 +269,66 @@ func reasonsAndChanges(changesJson string) (string, string, error) {
 	changesStr := strings.TrimSuffix(sb.String(), "\n")
 	return reasonsStr, changesStr, nil
 }
+
+func diagnosisItems(diagnosis *build.Diagnosis) []string {
+	items := []string{"Likely Cause", string(diagnosis.Cause)}
+	if diagnosis.Step != "" {
+		items = append(items, "Failed Step", diagnosis.Step)
+	}
+	if diagnosis.ExitCode != nil {
+		items = append(items, "Exit Code", strconv.Itoa(int(*diagnosis.ExitCode)))
+	}
+	if diagnosis.Reason != "" {
+		items = append(items, "Container Reason", diagnosis.Reason)
+	}
+	items = append(items, "Description", diagnosis.Description)
+	if len(diagnosis.Excerpt) > 0 {
+		items = append(items, "Excerpt", strings.Join(diagnosis.Excerpt, "\n\t"))
+	}
+	return items
+}
+
+type buildStatusOutput struct {
+	Name          string                             `json:"name"`
+	BuildNumber   string                             `json:"buildNumber"`
+	Image         string                             `json:"image"`
+	Status        string                             `json:"status"`
+	Reasons       []string                           `json:"reasons,omitempty"`
+	StatusReason  string                             `json:"statusReason,omitempty"`
+	StatusMessage string                             `json:"statusMessage,omitempty"`
+	Started       string                             `json:"started"`
+	Finished      string                             `json:"finished,omitempty"`
+	PodName       string                             `json:"podName"`
+	Builder       string                             `json:"builder"`
+	RunImage      string                             `json:"runImage"`
+	Source        corev1alpha1.SourceConfig          `json:"source"`
+	Buildpacks    corev1alpha1.BuildpackMetadataList `json:"buildpacks,omitempty"`
+	LikelyCause   *build.Diagnosis                   `json:"likelyCause,omitempty"`
+}
+
+func displayBuildStatusJson(cmd *cobra.Command, bld v1alpha2.Build, diagnosis *build.Diagnosis) error {
+	out := buildStatusOutput{
+		Name:        bld.Name,
+		BuildNumber: bld.Labels[v1alpha2.BuildNumberLabel],
+		Image:       bld.Status.LatestImage,
+		Status:      getStatus(bld),
+		Reasons:     getReasons(bld),
+		Started:     getStarted(bld),
+		Finished:    getFinished(bld),
+		PodName:     bld.Status.PodName,
+		Builder:     bld.Spec.Builder.Image,
+		RunImage:    bld.Status.Stack.RunImage,
+		Source:      bld.Spec.Source,
+		Buildpacks:  bld.Status.BuildMetadata,
+		LikelyCause: diagnosis,
+	}
+
+	if cond := bld.Status.GetCondition(corev1alpha1.ConditionSucceeded); cond != nil {
+		out.StatusReason = cond.Reason
+		out.StatusMessage = cond.Message
+	}
+
+	encoder := json.NewEncoder(cmd.OutOrStdout())
+	encoder.SetIndent("", "    ")
+	return encoder.Encode(out)
+}
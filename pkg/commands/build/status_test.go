@@ -4,6 +4,7 @@
 package build_test
 
 import (
+	"errors"
 	"strings"
 	"testing"
 	"text/template"
@@ -16,8 +17,11 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	k8sfakes "k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands/build"
@@ -797,6 +801,268 @@ bp-id-2         bp-version-2         mysupercoolsite2.com
 			})
 		})
 	})
+
+	when("the build failed", func() {
+		const namespace = "some-namespace"
+
+		k8sCmdFunc := func(k8sClientSet *k8sfakes.Clientset, kpackClientSet *fake.Clientset) *cobra.Command {
+			clientSetProvider := testhelpers.GetFakeClusterProvider(k8sClientSet, kpackClientSet)
+			return build.NewStatusCommand(clientSetProvider)
+		}
+
+		makePod := func(failedStep string, state corev1.ContainerState) *corev1.Pod {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "pod-two",
+					Namespace: namespace,
+				},
+			}
+			for _, step := range []string{"prepare", "analyze", "detect", "restore", "build", "export"} {
+				status := corev1.ContainerStatus{Name: step, State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}}}
+				if step == failedStep {
+					status.State = state
+				}
+				pod.Status.InitContainerStatuses = append(pod.Status.InitContainerStatuses, status)
+				if step == failedStep {
+					break
+				}
+			}
+			return pod
+		}
+
+		objects := func(pod *corev1.Pod) []runtime.Object {
+			return append(testhelpers.BuildsToRuntimeObjs(testhelpers.MakeTestBuilds(image, namespace)), pod)
+		}
+
+		it("shows the likely cause when the registry denied the export", func() {
+			pod := makePod("export", corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				ExitCode: 1,
+				Reason:   "Error",
+				Message:  "ERROR: failed to export: writing image: UNAUTHORIZED: authentication required",
+			}})
+
+			testhelpers.CommandTest{
+				Objects: objects(pod),
+				Args:    []string{image, "-b", "2", "-n", namespace},
+				ExpectedOutput: `Image:     repo.com/image-2:tag
+Status:    FAILURE
+Reason:    COMMIT,BUILDPACK
+
+Likely Cause:        RegistryAuthDenied
+Failed Step:         export
+Exit Code:           1
+Container Reason:    Error
+Description:         The registry denied access while exporting the image. Check that the service account has a registry secret with push access to the image tag.
+Excerpt:             ERROR: failed to export: writing image: UNAUTHORIZED: authentication required
+
+Started:     0001-01-01 01:00:00
+Finished:    0001-01-01 00:00:00
+
+Pod Name:    pod-two
+
+Builder:      --
+Run Image:    --
+
+Source:    Local Source
+
+BUILDPACK ID    BUILDPACK VERSION    HOMEPAGE
+
+`,
+			}.TestK8sAndKpack(t, k8sCmdFunc)
+		})
+
+		it("shows the likely cause when no buildpack group passed detection", func() {
+			pod := makePod("detect", corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				ExitCode: 20,
+				Reason:   "Error",
+			}})
+
+			testhelpers.CommandTest{
+				Objects: objects(pod),
+				Args:    []string{image, "-b", "2", "-n", namespace},
+				ExpectedOutput: `Image:     repo.com/image-2:tag
+Status:    FAILURE
+Reason:    COMMIT,BUILDPACK
+
+Likely Cause:        DetectionFailed
+Failed Step:         detect
+Exit Code:           20
+Container Reason:    Error
+Description:         No buildpack group passed detection. Check that the source contains an app supported by the buildpacks of the builder and that the correct subpath is set.
+
+Started:     0001-01-01 01:00:00
+Finished:    0001-01-01 00:00:00
+
+Pod Name:    pod-two
+
+Builder:      --
+Run Image:    --
+
+Source:    Local Source
+
+BUILDPACK ID    BUILDPACK VERSION    HOMEPAGE
+
+`,
+			}.TestK8sAndKpack(t, k8sCmdFunc)
+		})
+
+		it("shows the likely cause as json", func() {
+			pod := makePod("build", corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				ExitCode: 137,
+				Reason:   "OOMKilled",
+			}})
+
+			testhelpers.CommandTest{
+				Objects: objects(pod),
+				Args:    []string{image, "-b", "2", "-n", namespace, "-o", "json"},
+				ExpectedOutput: `{
+    "name": "build-two",
+    "buildNumber": "2",
+    "image": "repo.com/image-2:tag",
+    "status": "FAILURE",
+    "reasons": [
+        "COMMIT",
+        "BUILDPACK"
+    ],
+    "started": "0001-01-01 01:00:00",
+    "finished": "0001-01-01 00:00:00",
+    "podName": "pod-two",
+    "builder": "",
+    "runImage": "",
+    "source": {},
+    "likelyCause": {
+        "step": "build",
+        "exitCode": 137,
+        "reason": "OOMKilled",
+        "cause": "OOMKilled",
+        "description": "The build step ran out of memory and was killed. Consider raising the memory limit of the build.",
+        "excerpt": [
+            "fake logs"
+        ]
+    }
+}
+`,
+			}.TestK8sAndKpack(t, k8sCmdFunc)
+		})
+
+		it("shows the likely cause when an image could not be pulled", func() {
+			pod := makePod("prepare", corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+				Reason:  "ImagePullBackOff",
+				Message: `Back-off pulling image "some-repo.com/my-builder"`,
+			}})
+
+			testhelpers.CommandTest{
+				Objects: objects(pod),
+				Args:    []string{image, "-b", "2", "-n", namespace, "-o", "json"},
+				ExpectedOutput: `{
+    "name": "build-two",
+    "buildNumber": "2",
+    "image": "repo.com/image-2:tag",
+    "status": "FAILURE",
+    "reasons": [
+        "COMMIT",
+        "BUILDPACK"
+    ],
+    "started": "0001-01-01 01:00:00",
+    "finished": "0001-01-01 00:00:00",
+    "podName": "pod-two",
+    "builder": "",
+    "runImage": "",
+    "source": {},
+    "likelyCause": {
+        "step": "prepare",
+        "reason": "ImagePullBackOff",
+        "cause": "ImagePullFailed",
+        "description": "The image of the prepare step could not be pulled. Check that the builder and run images exist and that the service account can pull them.",
+        "excerpt": [
+            "Back-off pulling image \"some-repo.com/my-builder\""
+        ]
+    }
+}
+`,
+			}.TestK8sAndKpack(t, k8sCmdFunc)
+		})
+
+		it("does not show a likely cause when the pod no longer exists", func() {
+			testhelpers.CommandTest{
+				Objects: testhelpers.BuildsToRuntimeObjs(testhelpers.MakeTestBuilds(image, namespace)),
+				Args:    []string{image, "-b", "2", "-n", namespace},
+				ExpectedOutput: `Image:     repo.com/image-2:tag
+Status:    FAILURE
+Reason:    COMMIT,BUILDPACK
+
+Started:     0001-01-01 01:00:00
+Finished:    0001-01-01 00:00:00
+
+Pod Name:    pod-two
+
+Builder:      --
+Run Image:    --
+
+Source:    Local Source
+
+BUILDPACK ID    BUILDPACK VERSION    HOMEPAGE
+
+`,
+			}.TestK8sAndKpack(t, k8sCmdFunc)
+		})
+
+		it("falls back to the step states of the build when the pod cannot be read", func() {
+			builds := testhelpers.MakeTestBuilds(image, namespace)
+			for _, bld := range builds {
+				if bld.Name == "build-two" {
+					bld.Status.StepStates = []corev1.ContainerState{
+						{Terminated: &corev1.ContainerStateTerminated{}},
+						{Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"}},
+					}
+				}
+			}
+
+			forbiddenCmdFunc := func(k8sClientSet *k8sfakes.Clientset, kpackClientSet *fake.Clientset) *cobra.Command {
+				k8sClientSet.PrependReactor("get", "pods", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+					return true, nil, k8serrors.NewForbidden(corev1.Resource("pods"), "pod-two", errors.New("access denied"))
+				})
+				return k8sCmdFunc(k8sClientSet, kpackClientSet)
+			}
+
+			testhelpers.CommandTest{
+				Objects: testhelpers.BuildsToRuntimeObjs(builds),
+				Args:    []string{image, "-b", "2", "-n", namespace},
+				ExpectedOutput: `Image:     repo.com/image-2:tag
+Status:    FAILURE
+Reason:    COMMIT,BUILDPACK
+
+Likely Cause:        OOMKilled
+Exit Code:           137
+Container Reason:    OOMKilled
+Description:         The failed step ran out of memory and was killed. Consider raising the memory limit of the build.
+
+Started:     0001-01-01 01:00:00
+Finished:    0001-01-01 00:00:00
+
+Pod Name:    pod-two
+
+Builder:      --
+Run Image:    --
+
+Source:    Local Source
+
+BUILDPACK ID    BUILDPACK VERSION    HOMEPAGE
+
+`,
+				ExpectedErrorOutput: "Warning: could not read pod \"pod-two\" of the build, diagnosing from the build status: pods \"pod-two\" is forbidden: access denied\n",
+			}.TestK8sAndKpack(t, forbiddenCmdFunc)
+		})
+
+		it("errors on an unknown output format", func() {
+			testhelpers.CommandTest{
+				Objects:             testhelpers.BuildsToRuntimeObjs(testhelpers.MakeTestBuilds(image, namespace)),
+				Args:                []string{image, "-n", namespace, "-o", "yaml"},
+				ExpectErr:           true,
+				ExpectedErrorOutput: "Error: invalid output format \"yaml\", supported formats are: json\n",
+			}.TestK8sAndKpack(t, k8sCmdFunc)
+		})
+	})
 }
 
 type outputGenerator struct {
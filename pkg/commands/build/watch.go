@@ -0,0 +1,119 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package build
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	"github.com/pivotal/kpack/pkg/client/clientset/versioned"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/build"
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+func NewWatchCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+	var (
+		namespace     string
+		allNamespaces bool
+		plain         bool
+		onComplete    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "watch [image-resource-name]",
+		Short: "Watch builds as they run",
+		Long: `Shows a live table of the running builds in the provided namespace with their current step, elapsed time and reason.
+Builds that finish while watching stay in the table with their outcome.
+
+With --plain, or when the output is not a terminal, one line is printed each time a build starts, moves to the next step or finishes, which is suited to CI logs.
+
+With --on-complete, nothing is displayed while builds run. Instead, each time a build finishes with success or failure,
+the hook is run. An http or https url receives a POST with a json payload holding the namespace, image, build number, status,
+latest image, digest and reasons of the build. Anything else is run as a local shell command with the same json on stdin
+and in the KP_NAMESPACE, KP_IMAGE, KP_BUILD, KP_STATUS, KP_LATEST_IMAGE, KP_DIGEST and KP_REASONS environment variables.
+A failing hook is reported and the watch continues.
+
+The command runs until interrupted.
+
+The namespace defaults to the kubernetes current-context namespace.`,
+		Example:      "kp build watch\nkp build watch my-image\nkp build watch -A\nkp build watch -A --plain\nkp build watch my-image --on-complete https://hooks.example.com/kpack\nkp build watch my-image --on-complete './deploy.sh'",
+		Args:         commands.OptionalArgsWithUsage(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cs, err := clientSetProvider.GetClientSet(namespace)
+			if err != nil {
+				return err
+			}
+
+			watchNamespace := cs.Namespace
+			if allNamespaces {
+				watchNamespace = ""
+			}
+
+			var image string
+			if len(args) > 0 {
+				image = args[0]
+			}
+
+			if onComplete != "" {
+				return watchCompletions(cmd, cs.KpackClient, watchNamespace, image, build.NewCompletionHook(onComplete, cmd.OutOrStdout(), cmd.ErrOrStderr()))
+			}
+
+			watcher := build.NewWatcher(cs.DynamicClient, watchNamespace, image)
+			live := !plain && commands.IsTerminal(cmd.OutOrStdout())
+
+			return commands.Watch(cmd.Context(), watcher, cmd.OutOrStdout(), live, commands.WatchTable{
+				Headers: []string{"Image Resource", "Build", "Status", "Step", "Elapsed", "Reason", "Namespace"},
+				Rows: func(now time.Time) [][]string {
+					var rows [][]string
+					for _, bld := range watcher.ActiveBuilds() {
+						rows = append(rows, []string{
+							bld.Labels[v1alpha2.ImageLabel],
+							bld.Labels[v1alpha2.BuildNumberLabel],
+							getStatus(bld),
+							build.CurrentStep(bld),
+							build.Elapsed(bld, now).String(),
+							getTruncatedReason(bld),
+							bld.Namespace,
+						})
+					}
+					return rows
+				},
+				Empty: "No builds in progress",
+			})
+		},
+	}
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
+	cmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "Watch builds in all namespaces")
+	cmd.Flags().BoolVar(&plain, "plain", false, "print a line per build transition instead of a live table")
+	cmd.Flags().StringVar(&onComplete, "on-complete", "", "command to run or url to POST to when a build finishes")
+	return cmd
+}
+
+func watchCompletions(cmd *cobra.Command, client versioned.Interface, namespace, image string, hook build.CompletionHook) error {
+	var opts metav1.ListOptions
+	if image != "" {
+		opts.LabelSelector = v1alpha2.ImageLabel + "=" + image
+	}
+
+	ctx := cmd.Context()
+	return build.WatchCompletions(ctx, client, namespace, opts, func(bld v1alpha2.Build) {
+		if image != "" && bld.Labels[v1alpha2.ImageLabel] != image {
+			return
+		}
+
+		event := build.NewCompletionEvent(bld)
+		name := fmt.Sprintf("%s/%s build %s: %s", event.Namespace, event.Image, event.Build, event.Status)
+		if err := hook.Notify(ctx, event); err != nil {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "%s, on-complete hook failed: %s\n", name, err)
+			return
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s, ran on-complete hook\n", name)
+	})
+}
@@ -0,0 +1,225 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package build_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	"github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands/build"
+	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
+)
+
+func TestBuildWatchCommand(t *testing.T) {
+	spec.Run(t, "TestBuildWatchCommand", testBuildWatchCommand)
+}
+
+func testBuildWatchCommand(t *testing.T, when spec.G, it spec.S) {
+	const namespace = "some-namespace"
+
+	created := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	makeBuild := func(image, number string, status corev1.ConditionStatus, stepsCompleted ...string) *v1alpha2.Build {
+		return &v1alpha2.Build{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              image + "-build-" + number,
+				Namespace:         namespace,
+				CreationTimestamp: metav1.NewTime(created),
+				Labels: map[string]string{
+					v1alpha2.ImageLabel:       image,
+					v1alpha2.BuildNumberLabel: number,
+				},
+				Annotations: map[string]string{
+					v1alpha2.BuildReasonAnnotation: "CONFIG",
+				},
+			},
+			Status: v1alpha2.BuildStatus{
+				Status: corev1alpha1.Status{
+					Conditions: corev1alpha1.Conditions{
+						{
+							Type:               corev1alpha1.ConditionSucceeded,
+							Status:             status,
+							LastTransitionTime: corev1alpha1.VolatileTime{Inner: metav1.NewTime(created.Add(2 * time.Minute))},
+						},
+					},
+				},
+				StepStates:     make([]corev1.ContainerState, 7),
+				StepsCompleted: stepsCompleted,
+			},
+		}
+	}
+
+	it("prints a line for each transition of the builds while watching", func() {
+		running := makeBuild("some-image", "2", corev1.ConditionUnknown, "prepare", "analyze")
+		dynamicClient := testhelpers.NewFakeDynamicClient(
+			makeBuild("some-image", "1", corev1.ConditionTrue),
+			running,
+			makeBuild("other-image", "1", corev1.ConditionUnknown),
+		)
+
+		out := &testhelpers.SyncBuffer{}
+		cmd := build.NewWatchCommand(testhelpers.GetFakeDynamicProvider(dynamicClient, namespace))
+		cmd.SetArgs([]string{"some-image"})
+		cmd.SetOut(out)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- cmd.ExecuteContext(ctx) }()
+
+		require.Eventually(t, func() bool {
+			return out.String() == "some-namespace/some-image build 2: BUILDING (step: detect, reason: CONFIG)\n"
+		}, 5*time.Second, 10*time.Millisecond, out.String())
+
+		running.Status.StepsCompleted = append(running.Status.StepsCompleted, "detect")
+		testhelpers.UpdateDynamicObject(t, dynamicClient, testhelpers.BuildsResource, running)
+
+		finished := makeBuild("some-image", "2", corev1.ConditionTrue, "prepare", "analyze", "detect", "restore", "build", "export", "completion")
+		testhelpers.UpdateDynamicObject(t, dynamicClient, testhelpers.BuildsResource, finished)
+
+		require.Eventually(t, func() bool {
+			return out.String() == "some-namespace/some-image build 2: BUILDING (step: detect, reason: CONFIG)\n"+
+				"some-namespace/some-image build 2: BUILDING (step: restore, reason: CONFIG)\n"+
+				"some-namespace/some-image build 2: SUCCESS after 2m0s (reason: CONFIG)\n"
+		}, 5*time.Second, 10*time.Millisecond, out.String())
+
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	it("does not print builds that finished before watching", func() {
+		dynamicClient := testhelpers.NewFakeDynamicClient(makeBuild("some-image", "1", corev1.ConditionFalse))
+
+		out := &testhelpers.SyncBuffer{}
+		cmd := build.NewWatchCommand(testhelpers.GetFakeDynamicProvider(dynamicClient, namespace))
+		cmd.SetArgs([]string{"--plain"})
+		cmd.SetOut(out)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- cmd.ExecuteContext(ctx) }()
+
+		pending := makeBuild("some-image", "2", corev1.ConditionUnknown)
+		pending.Status.StepStates = nil
+		require.Eventually(t, func() bool {
+			// the build may be created before the watch starts, in which case it is reported from the initial list
+			_ = testhelpers.CreateDynamicObject(dynamicClient, testhelpers.BuildsResource, pending)
+			return out.String() == "some-namespace/some-image build 2: BUILDING (reason: CONFIG)\n"
+		}, 5*time.Second, 10*time.Millisecond, out.String())
+
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	when("--on-complete is used", func() {
+		var (
+			kpackClient *fake.Clientset
+			watching    chan struct{}
+			out         *testhelpers.SyncBuffer
+			errOut      *testhelpers.SyncBuffer
+		)
+
+		it.Before(func() {
+			running := makeBuild("some-image", "2", corev1.ConditionUnknown)
+			kpackClient = fake.NewSimpleClientset(makeBuild("some-image", "1", corev1.ConditionTrue), running)
+
+			watching = make(chan struct{})
+			var once sync.Once
+			kpackClient.PrependWatchReactor("builds", func(action k8stesting.Action) (bool, watch.Interface, error) {
+				w, err := kpackClient.Tracker().Watch(action.GetResource(), action.GetNamespace())
+				once.Do(func() { close(watching) })
+				return true, w, err
+			})
+
+			out = &testhelpers.SyncBuffer{}
+			errOut = &testhelpers.SyncBuffer{}
+		})
+
+		// runUntil runs the command, finishes build 2 once the watch started and
+		// waits for the output to match before stopping the command
+		runUntil := func(args []string, expectedOut, expectedErrOut string) {
+			cmd := build.NewWatchCommand(testhelpers.GetFakeKpackProvider(kpackClient, namespace))
+			cmd.SetArgs(args)
+			cmd.SetOut(out)
+			cmd.SetErr(errOut)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error)
+			go func() { done <- cmd.ExecuteContext(ctx) }()
+
+			<-watching
+			finished := makeBuild("some-image", "2", corev1.ConditionTrue)
+			finished.Status.LatestImage = "some-registry.io/some-repo@sha256:abc123"
+			_, err := kpackClient.KpackV1alpha2().Builds(namespace).Update(ctx, finished, metav1.UpdateOptions{})
+			require.NoError(t, err)
+
+			require.Eventually(t, func() bool {
+				return out.String() == expectedOut && errOut.String() == expectedErrOut
+			}, 5*time.Second, 10*time.Millisecond, out.String()+errOut.String())
+
+			cancel()
+			require.NoError(t, <-done)
+		}
+
+		it("posts the completed build to a webhook", func() {
+			payloads := make(chan string, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, http.MethodPost, r.Method)
+				require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				payloads <- string(body)
+			}))
+			defer server.Close()
+
+			runUntil([]string{"some-image", "--on-complete", server.URL}, "some-namespace/some-image build 2: SUCCESS, ran on-complete hook\n", "")
+
+			require.JSONEq(t, `{
+				"namespace": "some-namespace",
+				"image": "some-image",
+				"build": "2",
+				"status": "SUCCESS",
+				"latestImage": "some-registry.io/some-repo@sha256:abc123",
+				"digest": "sha256:abc123",
+				"reasons": ["CONFIG"]
+			}`, <-payloads)
+		})
+
+		it("reports a failing webhook and keeps watching", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			}))
+			defer server.Close()
+
+			runUntil([]string{"--on-complete", server.URL}, "", "some-namespace/some-image build 2: SUCCESS, on-complete hook failed: webhook returned status 500\n")
+		})
+
+		it("runs a local command with the completed build", func() {
+			if runtime.GOOS == "windows" {
+				t.Skip("uses a posix shell")
+			}
+
+			runUntil(
+				[]string{"some-image", "--on-complete", `echo "$KP_IMAGE $KP_BUILD $KP_STATUS $KP_DIGEST"`},
+				"some-image 2 SUCCESS sha256:abc123\nsome-namespace/some-image build 2: SUCCESS, ran on-complete hook\n",
+				"",
+			)
+		})
+	})
+}
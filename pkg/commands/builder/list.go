@@ -10,7 +10,7 @@ import (
 	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
@@ -19,15 +19,23 @@ import (
 func NewListCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 	var (
 		namespace string
+		listFlags commands.ListFlags
 	)
 
+	sorters := map[string]func(a, b v1alpha2.Builder) bool{
+		"name":    func(a, b v1alpha2.Builder) bool { return a.Name < b.Name },
+		"created": func(a, b v1alpha2.Builder) bool { return commands.ByCreationTimestamp(a.ObjectMeta, b.ObjectMeta) },
+		"ready":   func(a, b v1alpha2.Builder) bool { return getStatus(a) < getStatus(b) },
+		"stack":   func(a, b v1alpha2.Builder) bool { return a.Status.Stack.ID < b.Status.Stack.ID },
+	}
+
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List available builders",
 		Long: `Prints a table of the most important information about the available builders in the provided namespace.
 
 The namespace defaults to the kubernetes current-context namespace.`,
-		Example:      "kp builder list\nkp builder list -n my-namespace",
+		Example:      "kp builder list\nkp builder list -n my-namespace\nkp builder list -l team=payments --sort-by stack --wide",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cs, err := clientSetProvider.GetClientSet(namespace)
@@ -35,7 +43,7 @@ The namespace defaults to the kubernetes current-context namespace.`,
 				return err
 			}
 
-			builderList, err := cs.KpackClient.KpackV1alpha2().Builders(cs.Namespace).List(cmd.Context(), metav1.ListOptions{})
+			builderList, err := cs.KpackClient.KpackV1alpha2().Builders(cs.Namespace).List(cmd.Context(), listFlags.ListOptions())
 			if err != nil {
 				return err
 			}
@@ -44,29 +52,43 @@ The namespace defaults to the kubernetes current-context namespace.`,
 				return errors.New("no builders found")
 			} else {
 				sort.Slice(builderList.Items, Sort(builderList.Items))
-				return displayClusterBuildersTable(cmd, builderList)
+				if err = commands.SortItems(builderList.Items, listFlags.SortBy, sorters); err != nil {
+					return err
+				}
+				return displayClusterBuildersTable(cmd, builderList, listFlags)
 			}
 		},
 	}
 
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
+	commands.SetListFlags(cmd, &listFlags, commands.SortKeys(sorters)...)
 
 	return cmd
 }
 
-func displayClusterBuildersTable(cmd *cobra.Command, builderList *v1alpha2.BuilderList) error {
-	writer, err := commands.NewTableWriter(cmd.OutOrStdout(), "Name", "Ready", "Stack", "Image")
+func displayClusterBuildersTable(cmd *cobra.Command, builderList *v1alpha2.BuilderList, listFlags commands.ListFlags) error {
+	headers := []string{"Name", "Ready", "Stack", "Image"}
+	if listFlags.Wide {
+		headers = append(headers, "Tag", "Stack Ref", "Store Ref")
+	}
+
+	writer, err := listFlags.NewTableWriter(cmd.OutOrStdout(), headers...)
 	if err != nil {
 		return err
 	}
 
 	for _, bldr := range builderList.Items {
-		err := writer.AddRow(
+		row := []string{
 			bldr.ObjectMeta.Name,
 			getStatus(bldr),
 			bldr.Status.Stack.ID,
 			bldr.Status.LatestImage,
-		)
+		}
+		if listFlags.Wide {
+			row = append(row, bldr.Spec.Tag, objectRefText(bldr.Spec.Stack), objectRefText(bldr.Spec.Store))
+		}
+
+		err := writer.AddRow(row...)
 
 		if err != nil {
 			return err
@@ -76,6 +98,13 @@ func displayClusterBuildersTable(cmd *cobra.Command, builderList *v1alpha2.Build
 	return writer.Write()
 }
 
+func objectRefText(ref corev1.ObjectReference) string {
+	if ref.Name == "" {
+		return ""
+	}
+	return ref.Kind + "/" + ref.Name
+}
+
 func Sort(builds []v1alpha2.Builder) func(i int, j int) bool {
 	return func(i, j int) bool {
 		return builds[j].ObjectMeta.Name > builds[i].ObjectMeta.Name
@@ -456,4 +456,21 @@ test-builder-3    true     io.buildpacks.stacks.bionic    some-registry.com/test
 			})
 		})
 	})
+
+	when("list flags are provided", func() {
+		it("sorts the builders and prints additional columns", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					defaultNamespacedBuilder1,
+					defaultNamespacedBuilder2,
+					defaultNamespacedBuilder3,
+				},
+				Args: []string{"--sort-by", "stack", "--wide", "--no-headers"},
+				ExpectedOutput: `test-builder-2    false                                                                           some-registry.com/test-builder-2    ClusterStack/test-stack    ClusterStore/test-store
+test-builder-3    true     io.buildpacks.stacks.bionic    some-registry.com/test-builder-3:tag    some-registry.com/test-builder-3    ClusterStack/test-stack    ClusterStore/test-store
+test-builder-1    true     io.buildpacks.stacks.centos    some-registry.com/test-builder-1:tag    some-registry.com/test-builder-1    ClusterStack/test-stack    ClusterStore/test-store
+`,
+			}.TestKpack(t, cmdFunc)
+		})
+	})
 }
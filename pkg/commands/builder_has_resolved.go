@@ -1,13 +1,14 @@
 // Copyright 2020-Present VMware, Inc.
 // SPDX-License-Identifier: Apache-2.0
 
-package _import
+package commands
 
 import (
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"knative.dev/pkg/apis/duck"
 )
@@ -19,7 +20,9 @@ type builderWaitable struct {
 	Status v1alpha2.BuilderStatus `json:"status"`
 }
 
-func builderHasResolved(expectedStoreGen, expectedStackGen int64) func(event watch.Event) (bool, error) {
+// BuilderHasResolved is an extra wait check for builders that is satisfied once
+// the builder has observed the expected store and stack generations
+func BuilderHasResolved(expectedStoreGen, expectedStackGen int64) func(event watch.Event) (bool, error) {
 	return func(e watch.Event) (bool, error) {
 		u := &unstructured.Unstructured{}
 		var err error
@@ -41,3 +44,26 @@ func builderHasResolved(expectedStoreGen, expectedStackGen int64) func(event wat
 		return true, nil
 	}
 }
+
+// BuilderHasRebuilt is an extra wait check for builders that is satisfied once
+// the latest image of the builder differs from the one it had before a change
+// that rebuilds every builder, such as a new lifecycle. The previous images are
+// keyed by the namespace and name of the builders.
+func BuilderHasRebuilt(previousImages map[string]string) func(event watch.Event) (bool, error) {
+	return func(e watch.Event) (bool, error) {
+		u := &unstructured.Unstructured{}
+		var err error
+		u.Object, err = runtime.DefaultUnstructuredConverter.ToUnstructured(e.Object)
+		if err != nil {
+			return false, err
+		}
+
+		bw := &builderWaitable{}
+		if err := duck.FromUnstructured(u, bw); err != nil {
+			return false, err
+		}
+
+		previous, ok := previousImages[types.NamespacedName{Namespace: bw.Namespace, Name: bw.Name}.String()]
+		return !ok || bw.Status.LatestImage != previous, nil
+	}
+}
@@ -1,7 +1,7 @@
 // Copyright 2020-Present VMware, Inc.
 // SPDX-License-Identifier: Apache-2.0
 
-package _import
+package commands
 
 import (
 	"testing"
@@ -36,7 +36,7 @@ func testBuilderHasResolved(t *testing.T, when spec.G, it spec.S) {
 		e := watch.Event{
 			Object: cb,
 		}
-		done, err := builderHasResolved(storeGeneration, stackGeneration)(e)
+		done, err := BuilderHasResolved(storeGeneration, stackGeneration)(e)
 		require.NoError(t, err)
 		require.True(t, done)
 	})
@@ -50,7 +50,7 @@ func testBuilderHasResolved(t *testing.T, when spec.G, it spec.S) {
 		e := watch.Event{
 			Object: cb,
 		}
-		done, err := builderHasResolved(storeGeneration, stackGeneration)(e)
+		done, err := BuilderHasResolved(storeGeneration, stackGeneration)(e)
 		require.NoError(t, err)
 		require.True(t, done)
 	})
@@ -64,7 +64,7 @@ func testBuilderHasResolved(t *testing.T, when spec.G, it spec.S) {
 		e := watch.Event{
 			Object: cb,
 		}
-		done, err := builderHasResolved(storeGeneration, stackGeneration)(e)
+		done, err := BuilderHasResolved(storeGeneration, stackGeneration)(e)
 		require.NoError(t, err)
 		require.False(t, done)
 	})
@@ -78,8 +78,49 @@ func testBuilderHasResolved(t *testing.T, when spec.G, it spec.S) {
 		e := watch.Event{
 			Object: cb,
 		}
-		done, err := builderHasResolved(storeGeneration, stackGeneration)(e)
+		done, err := BuilderHasResolved(storeGeneration, stackGeneration)(e)
 		require.NoError(t, err)
 		require.False(t, done)
 	})
 }
+
+func TestBuilderHasRebuilt(t *testing.T) {
+	spec.Run(t, "TestBuilderHasRebuilt", testBuilderHasRebuilt)
+}
+
+func testBuilderHasRebuilt(t *testing.T, when spec.G, it spec.S) {
+	previousImages := map[string]string{
+		"/some-cb":                    "some-registry.io/builder@sha256:old",
+		"some-namespace/some-builder": "some-registry.io/builder@sha256:old",
+	}
+
+	it("returns false while the builder has its previous image", func() {
+		b := &v1alpha2.Builder{}
+		b.Namespace = "some-namespace"
+		b.Name = "some-builder"
+		b.Status.LatestImage = "some-registry.io/builder@sha256:old"
+
+		done, err := BuilderHasRebuilt(previousImages)(watch.Event{Object: b})
+		require.NoError(t, err)
+		require.False(t, done)
+	})
+
+	it("returns true once the builder has a new image", func() {
+		cb := &v1alpha2.ClusterBuilder{}
+		cb.Name = "some-cb"
+		cb.Status.LatestImage = "some-registry.io/builder@sha256:new"
+
+		done, err := BuilderHasRebuilt(previousImages)(watch.Event{Object: cb})
+		require.NoError(t, err)
+		require.True(t, done)
+	})
+
+	it("returns true for builders without a previous image", func() {
+		cb := &v1alpha2.ClusterBuilder{}
+		cb.Name = "other-cb"
+
+		done, err := BuilderHasRebuilt(previousImages)(watch.Event{Object: cb})
+		require.NoError(t, err)
+		require.True(t, done)
+	})
+}
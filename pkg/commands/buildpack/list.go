@@ -5,12 +5,12 @@ package buildpack
 
 import (
 	"sort"
+	"strings"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
 	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
@@ -19,15 +19,23 @@ import (
 func NewListCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 	var (
 		namespace string
+		listFlags commands.ListFlags
 	)
 
+	sorters := map[string]func(a, b v1alpha2.Buildpack) bool{
+		"name":    func(a, b v1alpha2.Buildpack) bool { return a.Name < b.Name },
+		"created": func(a, b v1alpha2.Buildpack) bool { return commands.ByCreationTimestamp(a.ObjectMeta, b.ObjectMeta) },
+		"ready":   func(a, b v1alpha2.Buildpack) bool { return getStatus(a) < getStatus(b) },
+		"image":   func(a, b v1alpha2.Buildpack) bool { return a.Spec.Image < b.Spec.Image },
+	}
+
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List available buildpacks",
 		Long: `Prints a table of the most important information about the available buildpacks in the provided namespace.
 
 The namespace defaults to the kubernetes current-context namespace.`,
-		Example:      "kp buildpack list\nkp buildpack list -n my-namespace",
+		Example:      "kp buildpack list\nkp buildpack list -n my-namespace\nkp buildpack list -l team=payments --sort-by created --wide",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			cs, err := clientSetProvider.GetClientSet(namespace)
@@ -35,7 +43,7 @@ The namespace defaults to the kubernetes current-context namespace.`,
 				return err
 			}
 
-			bpList, err := cs.KpackClient.KpackV1alpha2().Buildpacks(cs.Namespace).List(cmd.Context(), metav1.ListOptions{})
+			bpList, err := cs.KpackClient.KpackV1alpha2().Buildpacks(cs.Namespace).List(cmd.Context(), listFlags.ListOptions())
 			if err != nil {
 				return err
 			}
@@ -44,29 +52,43 @@ The namespace defaults to the kubernetes current-context namespace.`,
 				return errors.New("no buildpacks found")
 			} else {
 				sort.Slice(bpList.Items, Sort(bpList.Items))
+				if err = commands.SortItems(bpList.Items, listFlags.SortBy, sorters); err != nil {
+					return err
+				}
 
-				return displayBuildpacksTable(cmd, bpList)
+				return displayBuildpacksTable(cmd, bpList, listFlags)
 			}
 		},
 	}
 
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
+	commands.SetListFlags(cmd, &listFlags, commands.SortKeys(sorters)...)
 
 	return cmd
 }
 
-func displayBuildpacksTable(cmd *cobra.Command, bpList *v1alpha2.BuildpackList) error {
-	writer, err := commands.NewTableWriter(cmd.OutOrStdout(), "Name", "Ready", "Image")
+func displayBuildpacksTable(cmd *cobra.Command, bpList *v1alpha2.BuildpackList, listFlags commands.ListFlags) error {
+	headers := []string{"Name", "Ready", "Image"}
+	if listFlags.Wide {
+		headers = append(headers, "Service Account", "Buildpacks")
+	}
+
+	writer, err := listFlags.NewTableWriter(cmd.OutOrStdout(), headers...)
 	if err != nil {
 		return err
 	}
 
 	for _, bp := range bpList.Items {
-		err := writer.AddRow(
+		row := []string{
 			bp.Name,
 			getStatus(bp),
 			bp.Spec.Image,
-		)
+		}
+		if listFlags.Wide {
+			row = append(row, bp.Spec.ServiceAccountName, buildpackIds(bp))
+		}
+
+		err := writer.AddRow(row...)
 
 		if err != nil {
 			return err
@@ -76,6 +98,14 @@ func displayBuildpacksTable(cmd *cobra.Command, bpList *v1alpha2.BuildpackList)
 	return writer.Write()
 }
 
+func buildpackIds(bp v1alpha2.Buildpack) string {
+	var ids []string
+	for _, status := range bp.Status.Buildpacks {
+		ids = append(ids, status.Id+"@"+status.Version)
+	}
+	return strings.Join(ids, ",")
+}
+
 func Sort(bps []v1alpha2.Buildpack) func(i int, j int) bool {
 	return func(i, j int) bool {
 		return bps[j].Name > bps[i].Name
@@ -197,4 +197,24 @@ test-buildpack-3    true     some-registry.com/test-buildpack-3
 			})
 		})
 	})
+
+	when("list flags are provided", func() {
+		it("filters with a label selector and prints additional columns", func() {
+			buildpack2.Labels = map[string]string{"team": "payments"}
+			buildpack2.Spec.ServiceAccountName = "some-sa"
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					buildpack1,
+					buildpack2,
+					buildpack3,
+				},
+				Args: []string{"-l", "team=payments", "--wide"},
+				ExpectedOutput: `NAME                READY    IMAGE                                 SERVICE ACCOUNT    BUILDPACKS
+test-buildpack-2    false    some-registry.com/test-buildpack-2    some-sa            org.cloudfoundry.go@0.0.3
+
+`,
+			}.TestKpack(t, cmdFunc)
+		})
+	})
 }
@@ -10,19 +10,29 @@ import (
 	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 )
 
 func NewListCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+	var listFlags commands.ListFlags
+
+	sorters := map[string]func(a, b v1alpha2.ClusterBuilder) bool{
+		"name": func(a, b v1alpha2.ClusterBuilder) bool { return a.Name < b.Name },
+		"created": func(a, b v1alpha2.ClusterBuilder) bool {
+			return commands.ByCreationTimestamp(a.ObjectMeta, b.ObjectMeta)
+		},
+		"ready": func(a, b v1alpha2.ClusterBuilder) bool { return getStatus(a) < getStatus(b) },
+		"stack": func(a, b v1alpha2.ClusterBuilder) bool { return a.Status.Stack.ID < b.Status.Stack.ID },
+	}
 
 	cmd := &cobra.Command{
 		Use:          "list",
 		Short:        "List available cluster builders",
 		Long:         `Prints a table of the most important information about the available cluster builders.`,
-		Example:      "kp clusterbuilder list",
+		Example:      "kp clusterbuilder list\nkp clusterbuilder list -l team=payments --sort-by stack --wide",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cs, err := clientSetProvider.GetClientSet("")
@@ -30,7 +40,7 @@ func NewListCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 				return err
 			}
 
-			clusterBuilderList, err := cs.KpackClient.KpackV1alpha2().ClusterBuilders().List(cmd.Context(), metav1.ListOptions{})
+			clusterBuilderList, err := cs.KpackClient.KpackV1alpha2().ClusterBuilders().List(cmd.Context(), listFlags.ListOptions())
 			if err != nil {
 				return err
 			}
@@ -39,27 +49,41 @@ func NewListCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 				return errors.New("no clusterbuilders found")
 			} else {
 				sort.Slice(clusterBuilderList.Items, Sort(clusterBuilderList.Items))
-				return displayClusterBuildersTable(cmd, clusterBuilderList)
+				if err = commands.SortItems(clusterBuilderList.Items, listFlags.SortBy, sorters); err != nil {
+					return err
+				}
+				return displayClusterBuildersTable(cmd, clusterBuilderList, listFlags)
 			}
 		},
 	}
+	commands.SetListFlags(cmd, &listFlags, commands.SortKeys(sorters)...)
 
 	return cmd
 }
 
-func displayClusterBuildersTable(cmd *cobra.Command, builderList *v1alpha2.ClusterBuilderList) error {
-	writer, err := commands.NewTableWriter(cmd.OutOrStdout(), "Name", "Ready", "Stack", "Image")
+func displayClusterBuildersTable(cmd *cobra.Command, builderList *v1alpha2.ClusterBuilderList, listFlags commands.ListFlags) error {
+	headers := []string{"Name", "Ready", "Stack", "Image"}
+	if listFlags.Wide {
+		headers = append(headers, "Tag", "Stack Ref", "Store Ref")
+	}
+
+	writer, err := listFlags.NewTableWriter(cmd.OutOrStdout(), headers...)
 	if err != nil {
 		return err
 	}
 
 	for _, bldr := range builderList.Items {
-		err := writer.AddRow(
+		row := []string{
 			bldr.ObjectMeta.Name,
 			getStatus(bldr),
 			bldr.Status.Stack.ID,
 			bldr.Status.LatestImage,
-		)
+		}
+		if listFlags.Wide {
+			row = append(row, bldr.Spec.Tag, objectRefText(bldr.Spec.Stack), objectRefText(bldr.Spec.Store))
+		}
+
+		err := writer.AddRow(row...)
 
 		if err != nil {
 			return err
@@ -69,6 +93,13 @@ func displayClusterBuildersTable(cmd *cobra.Command, builderList *v1alpha2.Clust
 	return writer.Write()
 }
 
+func objectRefText(ref corev1.ObjectReference) string {
+	if ref.Name == "" {
+		return ""
+	}
+	return ref.Kind + "/" + ref.Name
+}
+
 func Sort(builds []v1alpha2.ClusterBuilder) func(i int, j int) bool {
 	return func(i, j int) bool {
 		return builds[j].ObjectMeta.Name > builds[i].ObjectMeta.Name
@@ -254,4 +254,30 @@ test-builder-3    true     io.buildpacks.stacks.bionic    some-registry.com/test
 			})
 		})
 	})
+
+	when("list flags are provided", func() {
+		it("sorts the clusterbuilders by the provided key", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					clusterBuilder1,
+					clusterBuilder2,
+					clusterBuilder3,
+				},
+				Args: []string{"--sort-by", "ready", "--no-headers"},
+				ExpectedOutput: `test-builder-2    false                                   
+test-builder-1    true     io.buildpacks.stacks.centos    some-registry.com/test-builder-1:tag
+test-builder-3    true     io.buildpacks.stacks.bionic    some-registry.com/test-builder-3:tag
+`,
+			}.TestKpack(t, cmdFunc)
+		})
+
+		it("errors with the valid keys when the sort key is invalid", func() {
+			testhelpers.CommandTest{
+				Objects:             []runtime.Object{clusterBuilder1},
+				Args:                []string{"--sort-by", "bogus"},
+				ExpectErr:           true,
+				ExpectedErrorOutput: "Error: invalid sort-by key \"bogus\", valid keys are: created, name, ready, stack\n",
+			}.TestKpack(t, cmdFunc)
+		})
+	})
 }
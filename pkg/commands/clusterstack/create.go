@@ -24,6 +24,8 @@ func NewCreateCommand(clientSetProvider k8s.ClientSetProvider, rup registry.Util
 		buildImageRef string
 		runImageRef   string
 		tlsCfg        registry.TLSConfig
+		publicKey     string
+		repository    string
 	)
 
 	cmd := &cobra.Command{
@@ -31,13 +33,18 @@ func NewCreateCommand(clientSetProvider k8s.ClientSetProvider, rup registry.Util
 		Short: "Create a cluster stack",
 		Long: `Create a cluster-scoped stack by providing command line arguments.
 
-The run and build images will be uploaded to the default repository.
+The run and build images will be uploaded to the stack repository, or to --repository when it is provided.
 Therefore, you must have credentials to access the registry on your machine.
 Additionally, your cluster must have read access to the registry.
 
+The source images are verified before they are uploaded. Pin the expected digest of an image with <image>@sha256:<digest>
+and provide --public-key to require a cosign signature made with the key.
+
 Env vars can be used for registry auth as described in https://github.com/vmware-tanzu/kpack-cli/blob/main/docs/auth.md
 
 The default repository is read from the "default.repository" key in the "kp-config" ConfigMap within "kpack" namespace.
+The stack repository is read from the "stack.repository" key in the "kp-config" ConfigMap within "kpack" namespace
+and falls back to the default repository when it is not set.
 The default service account used is read from the "default.repository.serviceaccount" key in the "kp-config" ConfigMap within "kpack" namespace.
 `,
 		Example: `kp clusterstack create my-stack --build-image my-registry.com/build --run-image my-registry.com/run
@@ -57,7 +64,13 @@ kp clusterstack create my-stack --build-image ../path/to/build.tar --run-image .
 
 			ctx := cmd.Context()
 
-			factory := clusterstack.NewFactory(ch, rup.Relocator(ch.Writer(), tlsCfg, ch.IsUploading()), rup.Fetcher(tlsCfg))
+			fetcher, err := commands.VerifySources(ch, dockercreds.DefaultKeychain, rup.Fetcher(tlsCfg), publicKey, buildImageRef, runImageRef)
+			if err != nil {
+				return err
+			}
+
+			factory := clusterstack.NewFactory(ch, rup.Relocator(ch.Writer(), tlsCfg, ch.IsUploading()), fetcher)
+			factory.Repository = repository
 
 			name := args[0]
 			return create(ctx, name, buildImageRef, runImageRef, factory, ch, cs, newWaiter(cs.DynamicClient))
@@ -65,8 +78,10 @@ kp clusterstack create my-stack --build-image ../path/to/build.tar --run-image .
 	}
 	cmd.Flags().StringVarP(&buildImageRef, "build-image", "b", "", "build image tag or local tar file path")
 	cmd.Flags().StringVarP(&runImageRef, "run-image", "r", "", "run image tag or local tar file path")
+	cmd.Flags().StringVar(&repository, "repository", "", "repository to upload the stack images to instead of the stack repository of the kp-config")
 	commands.SetImgUploadDryRunOutputFlags(cmd)
 	commands.SetTLSFlags(cmd, &tlsCfg)
+	commands.SetPublicKeyFlag(cmd, &publicKey)
 	_ = cmd.MarkFlagRequired("build-image")
 	_ = cmd.MarkFlagRequired("run-image")
 	return cmd
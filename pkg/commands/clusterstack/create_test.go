@@ -119,6 +119,59 @@ ClusterStack "stack-name" created
 			require.Len(t, fakeWaiter.WaitCalls, 1)
 		})
 
+		it("uploads to the stack repository of the kp-config", func() {
+			config.Data["stack.repository"] = "stack-registry.io/stacks"
+			expectedStack.Spec.BuildImage.Image = "stack-registry.io/stacks@sha256:build-image-digest"
+			expectedStack.Spec.RunImage.Image = "stack-registry.io/stacks@sha256:run-image-digest"
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					config,
+				},
+				Args: []string{
+					"stack-name",
+					"--build-image", "some-registry.io/repo/some-build-image",
+					"--run-image", "some-registry.io/repo/some-run-image",
+				},
+				ExpectedOutput: `Creating ClusterStack...
+Uploading to 'stack-registry.io/stacks'...
+	Uploading 'stack-registry.io/stacks@sha256:build-image-digest'
+	Uploading 'stack-registry.io/stacks@sha256:run-image-digest'
+ClusterStack "stack-name" created
+`,
+				ExpectCreates: []runtime.Object{
+					expectedStack,
+				},
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+
+		it("uploads to the repository provided with --repository", func() {
+			config.Data["stack.repository"] = "stack-registry.io/stacks"
+			expectedStack.Spec.BuildImage.Image = "other-registry.io/my-stacks@sha256:build-image-digest"
+			expectedStack.Spec.RunImage.Image = "other-registry.io/my-stacks@sha256:run-image-digest"
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					config,
+				},
+				Args: []string{
+					"stack-name",
+					"--build-image", "some-registry.io/repo/some-build-image",
+					"--run-image", "some-registry.io/repo/some-run-image",
+					"--repository", "other-registry.io/my-stacks",
+				},
+				ExpectedOutput: `Creating ClusterStack...
+Uploading to 'other-registry.io/my-stacks'...
+	Uploading 'other-registry.io/my-stacks@sha256:build-image-digest'
+	Uploading 'other-registry.io/my-stacks@sha256:run-image-digest'
+ClusterStack "stack-name" created
+`,
+				ExpectCreates: []runtime.Object{
+					expectedStack,
+				},
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+
 		it("fails when default.repository key is not found in kp-config configmap", func() {
 			badConfig := &corev1.ConfigMap{
 				ObjectMeta: metav1.ObjectMeta{
@@ -143,6 +196,48 @@ ClusterStack "stack-name" created
 			}.TestK8sAndKpack(t, cmdFunc)
 		})
 
+		it("fails without uploading when a pinned digest does not match the source image", func() {
+			const (
+				pinnedDigest = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+				buildImage   = "some-registry.io/repo/some-build-image@" + pinnedDigest
+			)
+			fakeRegistryUtilProvider.FakeFetcher.(*registryfakes.Fetcher).AddStackImages(registryfakes.StackInfo{
+				StackID:  "stack-id",
+				BuildImg: registryfakes.ImageInfo{Ref: buildImage, Digest: "build-image-digest"},
+				RunImg:   stackInfo.RunImg,
+			})
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					config,
+				},
+				Args: []string{
+					"stack-name",
+					"--build-image", buildImage,
+					"--run-image", "some-registry.io/repo/some-run-image",
+				},
+				ExpectErr: true,
+				ExpectedErrorOutput: "Error: verification failed for 1 source image(s), no images were relocated:\n" +
+					"\t" + buildImage + ": digest sha256:build-image-digest does not match the expected digest " + pinnedDigest + "\n",
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+
+		it("fails when the public key cannot be read", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					config,
+				},
+				Args: []string{
+					"stack-name",
+					"--build-image", "some-registry.io/repo/some-build-image",
+					"--run-image", "some-registry.io/repo/some-run-image",
+					"--public-key", "./testdata/missing.pub",
+				},
+				ExpectErr:           true,
+				ExpectedErrorOutput: "Error: open ./testdata/missing.pub: no such file or directory\n",
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+
 		when("output flag is used", func() {
 			it("can output in yaml format", func() {
 				const resourceYAML = `apiVersion: kpack.io/v1alpha2
@@ -7,6 +7,8 @@ import (
 	"context"
 	"io"
 	"k8s.io/apimachinery/pkg/runtime"
+	"reflect"
+	"time"
 
 	"github.com/google/go-containerregistry/pkg/authn"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
@@ -19,6 +21,7 @@ import (
 	"github.com/vmware-tanzu/kpack-cli/pkg/clusterstack"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	"github.com/vmware-tanzu/kpack-cli/pkg/config"
+	"github.com/vmware-tanzu/kpack-cli/pkg/dependents"
 	"github.com/vmware-tanzu/kpack-cli/pkg/dockercreds"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 	"github.com/vmware-tanzu/kpack-cli/pkg/registry"
@@ -34,9 +37,13 @@ type ImageRelocator interface {
 
 func NewPatchCommand(clientSetProvider k8s.ClientSetProvider, rup registry.UtilProvider, newWaiter func(dynamic.Interface) commands.ResourceWaiter) *cobra.Command {
 	var (
-		buildImageRef string
-		runImageRef   string
-		tlsCfg        registry.TLSConfig
+		buildImageRef     string
+		runImageRef       string
+		waitForDependents bool
+		tlsCfg            registry.TLSConfig
+		publicKey         string
+		repository        string
+		scheduleTimeout   time.Duration
 	)
 
 	cmd := &cobra.Command{
@@ -45,15 +52,24 @@ func NewPatchCommand(clientSetProvider k8s.ClientSetProvider, rup registry.UtilP
 		Short:   "Patch a cluster stack",
 		Long: `Patches the run and build images of a specific cluster-scoped stack.
 
-The run and build images will be uploaded to the the registry configured on your stack.
+The run and build images will be uploaded to the stack repository, or to --repository when it is provided.
 Therefore, you must have credentials to access the registry on your machine.
 
+The source images are verified before they are uploaded. Pin the expected digest of an image with <image>@sha256:<digest>
+and provide --public-key to require a cosign signature made with the key.
+
 Env vars can be used for registry auth as described in https://github.com/vmware-tanzu/kpack-cli/blob/main/docs/auth.md
 
 The default repository is read from the "default.repository" key in the "kp-config" ConfigMap within "kpack" namespace.
-The default service account used is read from the "default.repository.serviceaccount" key in the "kp-config" ConfigMap within "kpack" namespace.`,
+The stack repository is read from the "stack.repository" key in the "kp-config" ConfigMap within "kpack" namespace
+and falls back to the default repository when it is not set.
+The default service account used is read from the "default.repository.serviceaccount" key in the "kp-config" ConfigMap within "kpack" namespace.
+
+With --wait-for-dependents, kp waits for the builders using the stack to be updated
+and follows the builds of the images using those builders until they finish.`,
 		Example: `kp clusterstack patch my-stack --build-image my-registry.com/build --run-image my-registry.com/run
-kp clusterstack patch my-stack --build-image ../path/to/build.tar --run-image ../path/to/run.tar`,
+kp clusterstack patch my-stack --build-image ../path/to/build.tar --run-image ../path/to/run.tar
+kp clusterstack patch my-stack --build-image my-registry.com/build --run-image my-registry.com/run --wait-for-dependents`,
 		Args:         commands.ExactArgsWithUsage(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -74,16 +90,53 @@ kp clusterstack patch my-stack --build-image ../path/to/build.tar --run-image ..
 				return err
 			}
 
-			factory := clusterstack.NewFactory(ch, rup.Relocator(ch.Writer(), tlsCfg, ch.IsUploading()), rup.Fetcher(tlsCfg))
+			fetcher, err := commands.VerifySources(ch, dockercreds.DefaultKeychain, rup.Fetcher(tlsCfg), publicKey, buildImageRef, runImageRef)
+			if err != nil {
+				return err
+			}
+
+			factory := clusterstack.NewFactory(ch, rup.Relocator(ch.Writer(), tlsCfg, ch.IsUploading()), fetcher)
+			factory.Repository = repository
+
+			trackDependents := waitForDependents && !ch.IsDryRun()
+
+			var deps dependents.Dependents
+			if trackDependents {
+				deps, err = dependents.Find(ctx, cs.KpackClient, dependents.UsesClusterStack(stack.Name))
+				if err != nil {
+					return err
+				}
+			}
+
+			w := newWaiter(cs.DynamicClient)
+			err = patch(ctx, dockercreds.DefaultKeychain, stack, buildImageRef, runImageRef, factory, ch, cs, w)
+			if err != nil || !trackDependents {
+				return err
+			}
+
+			updatedStack, err := cs.KpackClient.KpackV1alpha2().ClusterStacks().Get(ctx, stack.Name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+
+			if reflect.DeepEqual(updatedStack.Spec, stack.Spec) {
+				return nil
+			}
 
-			return patch(ctx, dockercreds.DefaultKeychain, stack, buildImageRef, runImageRef, factory, ch, cs, newWaiter(cs.DynamicClient))
+			tracker := dependents.NewTracker(cs.KpackClient, w, ch, commands.GetWaitTimeout(cmd))
+			tracker.ScheduleTimeout = scheduleTimeout
+			return tracker.Track(ctx, deps, commands.BuilderHasResolved(0, updatedStack.Generation))
 		},
 	}
 
 	cmd.Flags().StringVarP(&buildImageRef, "build-image", "b", "", "build image tag or local tar file path")
 	cmd.Flags().StringVarP(&runImageRef, "run-image", "r", "", "run image tag or local tar file path")
+	cmd.Flags().StringVar(&repository, "repository", "", "repository to upload the stack images to instead of the stack repository of the kp-config")
+	cmd.Flags().BoolVar(&waitForDependents, "wait-for-dependents", false, "wait for the builders and images using the stack to be rebuilt")
+	dependents.SetScheduleTimeoutFlag(cmd, &scheduleTimeout)
 	commands.SetImgUploadDryRunOutputFlags(cmd)
 	commands.SetTLSFlags(cmd, &tlsCfg)
+	commands.SetPublicKeyFlag(cmd, &publicKey)
 	_ = cmd.MarkFlagRequired("build-image")
 	_ = cmd.MarkFlagRequired("run-image")
 	return cmd
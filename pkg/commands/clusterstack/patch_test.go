@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
 	kpackfakes "github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
 	"github.com/sclevine/spec"
 	"github.com/spf13/cobra"
@@ -28,6 +29,169 @@ import (
 
 func TestUpdateCommand(t *testing.T) {
 	spec.Run(t, "TestUpdateCommand", testUpdateCommand(clusterstackcmds.NewPatchCommand))
+	spec.Run(t, "TestPatchCommandWaitForDependents", testPatchCommandWaitForDependents)
+}
+
+func testPatchCommandWaitForDependents(t *testing.T, when spec.G, it spec.S) {
+	fakeRegistryUtilProvider := &registryfakes.UtilProvider{
+		FakeFetcher: registryfakes.NewStackImagesFetcher(
+			registryfakes.StackInfo{
+				StackID: "stack-id",
+				BuildImg: registryfakes.ImageInfo{
+					Ref:    "some-registry.io/repo/new-build",
+					Digest: "new-build-image-digest",
+				},
+				RunImg: registryfakes.ImageInfo{
+					Ref:    "some-registry.io/repo/new-run",
+					Digest: "new-run-image-digest",
+				},
+			},
+		),
+	}
+
+	stack := &v1alpha2.ClusterStack{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "stack-name",
+		},
+		Spec: v1alpha2.ClusterStackSpec{
+			Id: "stack-id",
+			BuildImage: v1alpha2.ClusterStackSpecImage{
+				Image: "default-registry.io/default-repo@sha256:build-image-digest",
+			},
+			RunImage: v1alpha2.ClusterStackSpecImage{
+				Image: "default-registry.io/default-repo@sha256:run-image-digest",
+			},
+		},
+		Status: v1alpha2.ClusterStackStatus{
+			ResolvedClusterStack: v1alpha2.ResolvedClusterStack{
+				Id: "stack-id",
+			},
+		},
+	}
+
+	config := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kp-config",
+			Namespace: "kpack",
+		},
+		Data: map[string]string{
+			"default.repository": "default-registry.io/default-repo",
+		},
+	}
+
+	builder := &v1alpha2.Builder{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "some-builder",
+			Namespace: "some-namespace",
+		},
+		Spec: v1alpha2.NamespacedBuilderSpec{
+			BuilderSpec: v1alpha2.BuilderSpec{
+				Stack: corev1.ObjectReference{
+					Kind: v1alpha2.ClusterStackKind,
+					Name: "stack-name",
+				},
+			},
+		},
+	}
+
+	otherBuilder := &v1alpha2.Builder{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other-builder",
+			Namespace: "some-namespace",
+		},
+		Spec: v1alpha2.NamespacedBuilderSpec{
+			BuilderSpec: v1alpha2.BuilderSpec{
+				Stack: corev1.ObjectReference{
+					Kind: v1alpha2.ClusterStackKind,
+					Name: "other-stack",
+				},
+			},
+		},
+	}
+
+	img := &v1alpha2.Image{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "some-image",
+			Namespace: "some-namespace",
+		},
+		Spec: v1alpha2.ImageSpec{
+			Builder: corev1.ObjectReference{
+				Kind: v1alpha2.BuilderKind,
+				Name: "some-builder",
+			},
+		},
+		Status: v1alpha2.ImageStatus{
+			BuildCounter: 1,
+		},
+	}
+
+	failedBuild := &v1alpha2.Build{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "some-image-build-2",
+			Namespace: "some-namespace",
+			Labels: map[string]string{
+				v1alpha2.ImageLabel:       "some-image",
+				v1alpha2.BuildNumberLabel: "2",
+			},
+			Annotations: map[string]string{
+				v1alpha2.BuildReasonAnnotation: "STACK",
+			},
+		},
+		Status: v1alpha2.BuildStatus{
+			Status: corev1alpha1.Status{
+				Conditions: corev1alpha1.Conditions{
+					{
+						Type:   corev1alpha1.ConditionSucceeded,
+						Status: corev1.ConditionFalse,
+					},
+				},
+			},
+		},
+	}
+
+	fakeWaiter := &commandsfakes.FakeWaiter{}
+
+	cmdFunc := func(k8sClientSet *k8sfakes.Clientset, kpackClientSet *kpackfakes.Clientset) *cobra.Command {
+		clientSetProvider := testhelpers.GetFakeClusterProvider(k8sClientSet, kpackClientSet)
+		return clusterstackcmds.NewPatchCommand(clientSetProvider, fakeRegistryUtilProvider, func(dynamic.Interface) commands.ResourceWaiter {
+			return fakeWaiter
+		})
+	}
+
+	it("waits for the builders using the stack and reports the builds of their images", func() {
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{
+				config,
+				stack,
+				builder,
+				otherBuilder,
+				img,
+				failedBuild,
+			},
+			Args: []string{
+				"stack-name",
+				"--build-image", "some-registry.io/repo/new-build",
+				"--run-image", "some-registry.io/repo/new-run",
+				"--wait-for-dependents",
+			},
+			ExpectErr: true,
+			ExpectPatches: []string{
+				`{"spec":{"buildImage":{"image":"default-registry.io/default-repo@sha256:new-build-image-digest"},"runImage":{"image":"default-registry.io/default-repo@sha256:new-run-image-digest"}}}`,
+			},
+			ExpectedOutput: `Updating ClusterStack...
+Uploading to 'default-registry.io/default-repo'...
+	Uploading 'default-registry.io/default-repo@sha256:new-build-image-digest'
+	Uploading 'default-registry.io/default-repo@sha256:new-run-image-digest'
+ClusterStack "stack-name" updated
+Waiting on 1 builder(s) and 1 image(s) that depend on this change...
+Ready: Builder 'some-builder'
+Image 'some-namespace/some-image': build 2 failed (STACK)
+`,
+			ExpectedErrorOutput: "Error: 1 of 1 images did not rebuild successfully\n",
+		}.TestK8sAndKpack(t, cmdFunc)
+		require.Len(t, fakeWaiter.WaitCalls, 2)
+		require.Len(t, fakeWaiter.WaitCalls[1].ExtraChecks, 1)
+	})
 }
 
 func testUpdateCommand(imageCommand func(clientSetProvider k8s.ClientSetProvider, rup registry.UtilProvider, newWaiter func(dynamic.Interface) commands.ResourceWaiter) *cobra.Command) func(t *testing.T, when spec.G, it spec.S) {
@@ -21,6 +21,8 @@ func NewSaveCommand(clientSetProvider k8s.ClientSetProvider, rup registry.UtilPr
 		buildImageRef string
 		runImageRef   string
 		tlsCfg        registry.TLSConfig
+		publicKey     string
+		repository    string
 	)
 
 	cmd := &cobra.Command{
@@ -28,13 +30,18 @@ func NewSaveCommand(clientSetProvider k8s.ClientSetProvider, rup registry.UtilPr
 		Short: "Create or patch a cluster stack",
 		Long: `Create or patch a cluster-scoped stack by providing command line arguments.
 
-The run and build images will be uploaded to the default repository.
+The run and build images will be uploaded to the stack repository, or to --repository when it is provided.
 Therefore, you must have credentials to access the registry on your machine.
 Additionally, your cluster must have read access to the registry.
 
+The source images are verified before they are uploaded. Pin the expected digest of an image with <image>@sha256:<digest>
+and provide --public-key to require a cosign signature made with the key.
+
 Env vars can be used for registry auth as described in https://github.com/vmware-tanzu/kpack-cli/blob/main/docs/auth.md
 
 The default repository is read from the "default.repository" key in the "kp-config" ConfigMap within "kpack" namespace.
+The stack repository is read from the "stack.repository" key in the "kp-config" ConfigMap within "kpack" namespace
+and falls back to the default repository when it is not set.
 The default service account used is read from the "default.repository.serviceaccount" key in the "kp-config" ConfigMap within "kpack" namespace.
 `,
 		Example: `kp clusterstack save my-stack --build-image my-registry.com/build --run-image my-registry.com/run
@@ -55,7 +62,13 @@ kp clusterstack save my-stack --build-image ../path/to/build.tar --run-image ../
 				return err
 			}
 
-			factory := clusterstack.NewFactory(ch, rup.Relocator(ch.Writer(), tlsCfg, ch.IsUploading()), rup.Fetcher(tlsCfg))
+			fetcher, err := commands.VerifySources(ch, dockercreds.DefaultKeychain, rup.Fetcher(tlsCfg), publicKey, buildImageRef, runImageRef)
+			if err != nil {
+				return err
+			}
+
+			factory := clusterstack.NewFactory(ch, rup.Relocator(ch.Writer(), tlsCfg, ch.IsUploading()), fetcher)
+			factory.Repository = repository
 
 			name := args[0]
 			cStack, err := cs.KpackClient.KpackV1alpha2().ClusterStacks().Get(ctx, name, metav1.GetOptions{})
@@ -70,8 +83,10 @@ kp clusterstack save my-stack --build-image ../path/to/build.tar --run-image ../
 	}
 	cmd.Flags().StringVarP(&buildImageRef, "build-image", "b", "", "build image tag or local tar file path")
 	cmd.Flags().StringVarP(&runImageRef, "run-image", "r", "", "run image tag or local tar file path")
+	cmd.Flags().StringVar(&repository, "repository", "", "repository to upload the stack images to instead of the stack repository of the kp-config")
 	commands.SetImgUploadDryRunOutputFlags(cmd)
 	commands.SetTLSFlags(cmd, &tlsCfg)
+	commands.SetPublicKeyFlag(cmd, &publicKey)
 	_ = cmd.MarkFlagRequired("build-image")
 	_ = cmd.MarkFlagRequired("run-image")
 	return cmd
@@ -6,6 +6,8 @@ package clusterstore
 import (
 	"context"
 	"k8s.io/apimachinery/pkg/types"
+	"reflect"
+	"time"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
 	"github.com/pkg/errors"
@@ -18,6 +20,7 @@ import (
 	"github.com/vmware-tanzu/kpack-cli/pkg/clusterstore"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	"github.com/vmware-tanzu/kpack-cli/pkg/config"
+	"github.com/vmware-tanzu/kpack-cli/pkg/dependents"
 	"github.com/vmware-tanzu/kpack-cli/pkg/dockercreds"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 	"github.com/vmware-tanzu/kpack-cli/pkg/registry"
@@ -25,8 +28,12 @@ import (
 
 func NewAddCommand(clientSetProvider k8s.ClientSetProvider, rup registry.UtilProvider, newWaiter func(dynamic.Interface) commands.ResourceWaiter) *cobra.Command {
 	var (
-		buildpackages []string
-		tlsCfg        registry.TLSConfig
+		buildpackages     []string
+		waitForDependents bool
+		tlsCfg            registry.TLSConfig
+		publicKey         string
+		repository        string
+		scheduleTimeout   time.Duration
 	)
 
 	cmd := &cobra.Command{
@@ -34,16 +41,25 @@ func NewAddCommand(clientSetProvider k8s.ClientSetProvider, rup registry.UtilPro
 		Short: "Add buildpackage(s) to cluster store",
 		Long: `Upload buildpackage(s) to a specific cluster-scoped buildpack store.
 
-Buildpackages will be uploaded to the default repository.
+Buildpackages will be uploaded to the buildpackage repository, or to --repository when it is provided.
 Therefore, you must have credentials to access the registry on your machine.
 
+The buildpackages are verified before they are uploaded. Pin the expected digest of a buildpackage with <image>@sha256:<digest>
+and provide --public-key to require a cosign signature made with the key.
+
 Env vars can be used for registry auth as described in https://github.com/vmware-tanzu/kpack-cli/blob/main/docs/auth.md
 
 The default repository is read from the "default.repository" key in the "kp-config" ConfigMap within "kpack" namespace.
+The buildpackage repository is read from the "buildpackage.repository" key in the "kp-config" ConfigMap within "kpack" namespace
+and falls back to the default repository when it is not set.
+
+With --wait-for-dependents, kp waits for the builders using the store to be updated
+and follows the builds of the images using those builders until they finish.
 `,
 		Example: `kp clusterstore add my-store -b my-registry.com/my-buildpackage
 kp clusterstore add my-store -b my-registry.com/my-buildpackage -b my-registry.com/my-other-buildpackage -b my-registry.com/my-third-buildpackage
-kp clusterstore add my-store -b ../path/to/my-local-buildpackage.cnb`,
+kp clusterstore add my-store -b ../path/to/my-local-buildpackage.cnb
+kp clusterstore add my-store -b my-registry.com/my-buildpackage --wait-for-dependents`,
 		Args:         commands.ExactArgsWithUsage(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -68,16 +84,52 @@ kp clusterstore add my-store -b ../path/to/my-local-buildpackage.cnb`,
 			}
 
 			relocator := rup.Relocator(ch.Writer(), tlsCfg, ch.IsUploading())
-			fetcher := rup.Fetcher(tlsCfg)
+			fetcher, err := commands.VerifySources(ch, dockercreds.DefaultKeychain, rup.Fetcher(tlsCfg), publicKey, buildpackages...)
+			if err != nil {
+				return err
+			}
+
 			factory := clusterstore.NewFactory(ch, relocator, fetcher)
+			factory.Repository = repository
+
+			trackDependents := waitForDependents && !ch.IsDryRun()
+
+			var deps dependents.Dependents
+			if trackDependents {
+				deps, err = dependents.Find(ctx, cs.KpackClient, dependents.UsesClusterStore(store.Name))
+				if err != nil {
+					return err
+				}
+			}
+
+			w := newWaiter(cs.DynamicClient)
+			err = update(ctx, store, buildpackages, factory, ch, cs, w)
+			if err != nil || !trackDependents {
+				return err
+			}
+
+			updatedStore, err := cs.KpackClient.KpackV1alpha2().ClusterStores().Get(ctx, store.Name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+
+			if reflect.DeepEqual(updatedStore.Spec, store.Spec) {
+				return nil
+			}
 
-			return update(ctx, store, buildpackages, factory, ch, cs, newWaiter(cs.DynamicClient))
+			tracker := dependents.NewTracker(cs.KpackClient, w, ch, commands.GetWaitTimeout(cmd))
+			tracker.ScheduleTimeout = scheduleTimeout
+			return tracker.Track(ctx, deps, commands.BuilderHasResolved(updatedStore.Generation, 0))
 		},
 	}
 
 	cmd.Flags().StringArrayVarP(&buildpackages, "buildpackage", "b", []string{}, "location of the buildpackage")
+	cmd.Flags().StringVar(&repository, "repository", "", "repository to upload the buildpackages to instead of the buildpackage repository of the kp-config")
+	cmd.Flags().BoolVar(&waitForDependents, "wait-for-dependents", false, "wait for the builders and images using the store to be rebuilt")
+	dependents.SetScheduleTimeoutFlag(cmd, &scheduleTimeout)
 	commands.SetImgUploadDryRunOutputFlags(cmd)
 	commands.SetTLSFlags(cmd, &tlsCfg)
+	commands.SetPublicKeyFlag(cmd, &publicKey)
 	return cmd
 }
 
@@ -29,6 +29,132 @@ import (
 func TestClusterStoreAddCommand(t *testing.T) {
 	spec.Run(t, "TestClusterStoreAddCommand", testAddCommand(storecmds.NewAddCommand))
 	spec.Run(t, "TestClusterStoreAddCommandDNEError", testAddCommandDNEError)
+	spec.Run(t, "TestClusterStoreAddCommandWaitForDependents", testAddCommandWaitForDependents)
+}
+
+func testAddCommandWaitForDependents(t *testing.T, when spec.G, it spec.S) {
+	fakeRegistryUtilProvider := &registryfakes.UtilProvider{
+		FakeFetcher: registryfakes.NewBuildpackImagesFetcher(
+			registryfakes.BuildpackImgInfo{
+				Id: "new-buildpack-id",
+				ImageInfo: registryfakes.ImageInfo{
+					Ref:    "some-registry.io/repo/new-buildpack",
+					Digest: "new-buildpack-digest",
+				},
+			},
+		),
+	}
+
+	fakeWaiter := &commandsfakes.FakeWaiter{}
+
+	existingStore := &v1alpha2.ClusterStore{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "store-name",
+		},
+	}
+
+	config := &corev1.ConfigMap{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "kp-config",
+			Namespace: "kpack",
+		},
+		Data: map[string]string{
+			"default.repository": "default-registry.io/default-repo",
+		},
+	}
+
+	clusterBuilder := &v1alpha2.ClusterBuilder{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "some-cb",
+		},
+		Spec: v1alpha2.ClusterBuilderSpec{
+			BuilderSpec: v1alpha2.BuilderSpec{
+				Store: corev1.ObjectReference{
+					Kind: v1alpha2.ClusterStoreKind,
+					Name: "store-name",
+				},
+			},
+		},
+	}
+
+	makeImage := func(name, builder string) *v1alpha2.Image {
+		return &v1alpha2.Image{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      name,
+				Namespace: "some-namespace",
+			},
+			Spec: v1alpha2.ImageSpec{
+				Builder: corev1.ObjectReference{
+					Kind: v1alpha2.ClusterBuilderKind,
+					Name: builder,
+				},
+			},
+			Status: v1alpha2.ImageStatus{
+				BuildCounter: 3,
+			},
+		}
+	}
+
+	newBuild := &v1alpha2.Build{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "some-image-build-4",
+			Namespace: "some-namespace",
+			Labels: map[string]string{
+				v1alpha2.ImageLabel:       "some-image",
+				v1alpha2.BuildNumberLabel: "4",
+			},
+			Annotations: map[string]string{
+				v1alpha2.BuildReasonAnnotation: "BUILDPACK",
+			},
+		},
+		Status: v1alpha2.BuildStatus{
+			Status: corev1alpha1.Status{
+				Conditions: corev1alpha1.Conditions{
+					{
+						Type:   corev1alpha1.ConditionSucceeded,
+						Status: corev1.ConditionTrue,
+					},
+				},
+			},
+		},
+	}
+
+	cmdFunc := func(k8sClientSet *k8sfakes.Clientset, kpackClientSet *kpackfakes.Clientset) *cobra.Command {
+		clientSetProvider := testhelpers.GetFakeClusterProvider(k8sClientSet, kpackClientSet)
+		return storecmds.NewAddCommand(clientSetProvider, fakeRegistryUtilProvider, func(dynamic.Interface) commands.ResourceWaiter {
+			return fakeWaiter
+		})
+	}
+
+	it("waits for the builders using the store and reports the builds of their images", func() {
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{
+				config,
+				existingStore,
+				clusterBuilder,
+				makeImage("some-image", "some-cb"),
+				makeImage("other-image", "other-cb"),
+				newBuild,
+			},
+			Args: []string{
+				"store-name",
+				"--buildpackage", "some-registry.io/repo/new-buildpack",
+				"--wait-for-dependents",
+			},
+			ExpectPatches: []string{
+				`{"spec":{"sources":[{"image":"default-registry.io/default-repo@sha256:new-buildpack-digest"}]}}`,
+			},
+			ExpectedOutput: `Adding to ClusterStore...
+	Uploading 'default-registry.io/default-repo@sha256:new-buildpack-digest'
+	Added Buildpackage
+ClusterStore "store-name" updated
+Waiting on 1 builder(s) and 1 image(s) that depend on this change...
+Ready: ClusterBuilder 'some-cb'
+Image 'some-namespace/some-image': build 4 succeeded (BUILDPACK)
+`,
+		}.TestK8sAndKpack(t, cmdFunc)
+		require.Len(t, fakeWaiter.WaitCalls, 2)
+	})
 }
 
 func testAddCommand(clusterStackCommand func(clientSetProvider k8s.ClientSetProvider, rup registry.UtilProvider, newWaiter func(dynamic.Interface) commands.ResourceWaiter) *cobra.Command) func(t *testing.T, when spec.G, it spec.S) {
@@ -136,6 +262,53 @@ ClusterStore "store-name" updated (no change)
 			}.TestK8sAndKpack(t, cmdFunc)
 		})
 
+		it("uploads to the buildpackage repository of the kp-config", func() {
+			config.Data["buildpackage.repository"] = "buildpackage-registry.io/buildpackages"
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					config,
+					existingStore,
+				},
+				Args: []string{
+					"store-name",
+					"--buildpackage", "some-registry.io/repo/new-buildpack",
+				},
+				ExpectPatches: []string{
+					`{"spec":{"sources":[{"image":"default-registry.io/default-repo/old-buildpack-id@sha256:old-buildpack-digest"},{"image":"buildpackage-registry.io/buildpackages@sha256:new-buildpack-digest"}]}}`,
+				},
+				ExpectedOutput: `Adding to ClusterStore...
+	Uploading 'buildpackage-registry.io/buildpackages@sha256:new-buildpack-digest'
+	Added Buildpackage
+ClusterStore "store-name" updated
+`,
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+
+		it("uploads to the repository provided with --repository", func() {
+			config.Data["buildpackage.repository"] = "buildpackage-registry.io/buildpackages"
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					config,
+					existingStore,
+				},
+				Args: []string{
+					"store-name",
+					"--buildpackage", "some-registry.io/repo/new-buildpack",
+					"--repository", "other-registry.io/my-buildpackages",
+				},
+				ExpectPatches: []string{
+					`{"spec":{"sources":[{"image":"default-registry.io/default-repo/old-buildpack-id@sha256:old-buildpack-digest"},{"image":"other-registry.io/my-buildpackages@sha256:new-buildpack-digest"}]}}`,
+				},
+				ExpectedOutput: `Adding to ClusterStore...
+	Uploading 'other-registry.io/my-buildpackages@sha256:new-buildpack-digest'
+	Added Buildpackage
+ClusterStore "store-name" updated
+`,
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+
 		it("errors when default.repository key is not found", func() {
 			testhelpers.CommandTest{
 				Objects: []runtime.Object{
@@ -23,6 +23,8 @@ func NewCreateCommand(clientSetProvider k8s.ClientSetProvider, rup registry.Util
 	var (
 		buildpackages []string
 		tlsCfg        registry.TLSConfig
+		publicKey     string
+		repository    string
 	)
 
 	cmd := &cobra.Command{
@@ -30,13 +32,18 @@ func NewCreateCommand(clientSetProvider k8s.ClientSetProvider, rup registry.Util
 		Short: "Create a cluster store",
 		Long: `Create a cluster-scoped buildpack store by providing command line arguments.
 
-Buildpackages will be uploaded to the default repository.
+Buildpackages will be uploaded to the buildpackage repository, or to --repository when it is provided.
 Therefore, you must have credentials to access the registry on your machine.
 
+The buildpackages are verified before they are uploaded. Pin the expected digest of a buildpackage with <image>@sha256:<digest>
+and provide --public-key to require a cosign signature made with the key.
+
 Env vars can be used for registry auth as described in https://github.com/vmware-tanzu/kpack-cli/blob/main/docs/auth.md
 
 This clusterstore will be created only if it does not exist.
 The default repository is read from the "default.repository" key in the "kp-config" ConfigMap within "kpack" namespace.
+The buildpackage repository is read from the "buildpackage.repository" key in the "kp-config" ConfigMap within "kpack" namespace
+and falls back to the default repository when it is not set.
 The default service account used is read from the "default.repository.serviceaccount" key in the "kp-config" ConfigMap within "kpack" namespace.
 `,
 		Example: `kp clusterstore create my-store -b my-registry.com/my-buildpackage
@@ -57,7 +64,13 @@ kp clusterstore create my-store -b ../path/to/my-local-buildpackage.cnb`,
 
 			ctx := cmd.Context()
 
-			factory := clusterstore.NewFactory(ch, rup.Relocator(ch.Writer(), tlsCfg, ch.IsUploading()), rup.Fetcher(tlsCfg))
+			fetcher, err := commands.VerifySources(ch, dockercreds.DefaultKeychain, rup.Fetcher(tlsCfg), publicKey, buildpackages...)
+			if err != nil {
+				return err
+			}
+
+			factory := clusterstore.NewFactory(ch, rup.Relocator(ch.Writer(), tlsCfg, ch.IsUploading()), fetcher)
+			factory.Repository = repository
 
 			name := args[0]
 			return create(ctx, name, buildpackages, factory, ch, cs, newWaiter(cs.DynamicClient))
@@ -65,8 +78,10 @@ kp clusterstore create my-store -b ../path/to/my-local-buildpackage.cnb`,
 	}
 
 	cmd.Flags().StringArrayVarP(&buildpackages, "buildpackage", "b", []string{}, "location of the buildpackage")
+	cmd.Flags().StringVar(&repository, "repository", "", "repository to upload the buildpackages to instead of the buildpackage repository of the kp-config")
 	commands.SetImgUploadDryRunOutputFlags(cmd)
 	commands.SetTLSFlags(cmd, &tlsCfg)
+	commands.SetPublicKeyFlag(cmd, &publicKey)
 	return cmd
 }
 
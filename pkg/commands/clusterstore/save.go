@@ -11,6 +11,7 @@ import (
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/clusterstore"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/dockercreds"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 	"github.com/vmware-tanzu/kpack-cli/pkg/registry"
 )
@@ -19,6 +20,8 @@ func NewSaveCommand(clientSetProvider k8s.ClientSetProvider, rup registry.UtilPr
 	var (
 		buildpackages []string
 		tlsCfg        registry.TLSConfig
+		publicKey     string
+		repository    string
 	)
 
 	cmd := &cobra.Command{
@@ -26,13 +29,18 @@ func NewSaveCommand(clientSetProvider k8s.ClientSetProvider, rup registry.UtilPr
 		Short: "Create or update a cluster store",
 		Long: `Create or update a cluster-scoped buildpack store by providing command line arguments.
 
-Buildpackages will be uploaded to the default repository.
+Buildpackages will be uploaded to the buildpackage repository, or to --repository when it is provided.
 Therefore, you must have credentials to access the registry on your machine.
 
+The buildpackages are verified before they are uploaded. Pin the expected digest of a buildpackage with <image>@sha256:<digest>
+and provide --public-key to require a cosign signature made with the key.
+
 Env vars can be used for registry auth as described in https://github.com/vmware-tanzu/kpack-cli/blob/main/docs/auth.md
 
 This clusterstore will be created only if it does not exist, otherwise it will be updated.
 The default repository is read from the "default.repository" key in the "kp-config" ConfigMap within "kpack" namespace.
+The buildpackage repository is read from the "buildpackage.repository" key in the "kp-config" ConfigMap within "kpack" namespace
+and falls back to the default repository when it is not set.
 The default service account used is read from the "default.repository.serviceaccount" key in the "kp-config" ConfigMap within "kpack" namespace.
 `,
 		Example: `kp clusterstore save my-store -b my-registry.com/my-buildpackage
@@ -55,7 +63,13 @@ kp clusterstore save my-store -b ../path/to/my-local-buildpackage.cnb`,
 			}
 
 			name := args[0]
-			factory := clusterstore.NewFactory(ch, rup.Relocator(ch.Writer(), tlsCfg, ch.IsUploading()), rup.Fetcher(tlsCfg))
+			fetcher, err := commands.VerifySources(ch, dockercreds.DefaultKeychain, rup.Fetcher(tlsCfg), publicKey, buildpackages...)
+			if err != nil {
+				return err
+			}
+
+			factory := clusterstore.NewFactory(ch, rup.Relocator(ch.Writer(), tlsCfg, ch.IsUploading()), fetcher)
+			factory.Repository = repository
 
 			clusterStore, err := cs.KpackClient.KpackV1alpha2().ClusterStores().Get(ctx, name, metav1.GetOptions{})
 			if k8serrors.IsNotFound(err) {
@@ -69,7 +83,9 @@ kp clusterstore save my-store -b ../path/to/my-local-buildpackage.cnb`,
 	}
 
 	cmd.Flags().StringArrayVarP(&buildpackages, "buildpackage", "b", []string{}, "location of the buildpackage")
+	cmd.Flags().StringVar(&repository, "repository", "", "repository to upload the buildpackages to instead of the buildpackage repository of the kp-config")
 	commands.SetImgUploadDryRunOutputFlags(cmd)
 	commands.SetTLSFlags(cmd, &tlsCfg)
+	commands.SetPublicKeyFlag(cmd, &publicKey)
 	return cmd
 }
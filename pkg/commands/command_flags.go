@@ -5,6 +5,9 @@ package commands
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -15,9 +18,11 @@ import (
 const (
 	caCertPathFlag  = "registry-ca-cert-path"
 	verifyCertsFlag = "registry-verify-certs"
+	publicKeyFlag   = "public-key"
 
 	caCertPathFlagUsage  = "add CA certificate for registry API (format: /tmp/ca.crt)"
 	verifyCertsFlagUsage = "set whether to verify server's certificate chain and host name"
+	publicKeyFlagUsage   = "path to a cosign public key that must have signed the source images before they are relocated"
 	dryRunUsage          = `perform validation with no side-effects; no objects are sent to the server.
   The --dry-run flag can be used in combination with the --output flag to
   view the Kubernetes resource(s) without sending anything to the server.`
@@ -37,6 +42,10 @@ func SetTLSFlags(cmd *cobra.Command, cfg *registry.TLSConfig) {
 	cmd.Flags().BoolVar(&cfg.VerifyCerts, verifyCertsFlag, true, verifyCertsFlagUsage)
 }
 
+func SetPublicKeyFlag(cmd *cobra.Command, publicKey *string) {
+	cmd.Flags().StringVar(publicKey, publicKeyFlag, "", publicKeyFlagUsage)
+}
+
 func SetDryRunOutputFlags(cmd *cobra.Command) {
 	cmd.Flags().Bool(DryRunFlag, false, dryRunUsage)
 	cmd.Flags().String(OutputFlag, "", outputUsage)
@@ -46,3 +55,15 @@ func SetImgUploadDryRunOutputFlags(cmd *cobra.Command) {
 	SetDryRunOutputFlags(cmd)
 	cmd.Flags().Bool(DryRunImgUploadFlag, false, dryRunImgUploadUsage)
 }
+
+// ParseAge parses a duration such as 90m or 12h, with an additional d suffix for days
+func ParseAge(value string) (time.Duration, error) {
+	if days := strings.TrimSuffix(value, "d"); days != value {
+		d, err := strconv.ParseFloat(days, 64)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(d * float64(24*time.Hour)), nil
+	}
+	return time.ParseDuration(value)
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/config"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+func NewRepositoryCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+	var unset bool
+
+	cmd := &cobra.Command{
+		Use:   "repository <stack|buildpackage|lifecycle|source> [url]",
+		Short: "Set or Get the repository of an artifact",
+		Long: `Set or Get the repository that the images of an artifact are uploaded to
+
+kp uploads stack images, buildpackages, the lifecycle and local source code to the default repository.
+Each of these artifacts may instead be uploaded to a separate repository, such as to apply different retention or access policies.
+
+  "stack" for the build and run images of cluster stacks
+  "buildpackage" for the buildpackages of cluster stores, cluster buildpacks and buildpacks
+  "lifecycle" for the lifecycle image
+  "source" for the local source code of images without a --local-path-destination-image
+
+The repository of an artifact is stored in the kp-config config map in the kpack namespace under the "<artifact>.repository" key.
+Without a url, the repository that the images of the artifact are uploaded to is printed.
+Use --unset to upload the images of the artifact to the default repository again.
+
+The repository may be overridden for a single resource with the --repository flag of the clusterstack and clusterstore commands
+or with the repository of a resource in a dependency descriptor.
+`,
+		Example: `kp config repository stack
+kp config repository stack my-registry.com/my-stacks
+kp config repository buildpackage my-registry.com/my-buildpackages
+kp config repository source --unset`,
+		Args:         cobra.RangeArgs(1, 2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			artifact, err := config.ParseArtifact(args[0])
+			if err != nil {
+				return err
+			}
+
+			ch, err := commands.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			cs, err := clientSetProvider.GetClientSet("")
+			if err != nil {
+				return err
+			}
+
+			configHelper := config.NewKpConfigProvider(cs.K8sClient)
+
+			if len(args) == 1 && !unset {
+				kpConfig := configHelper.GetKpConfig(ctx)
+
+				repo, err := kpConfig.Repository(artifact)
+				if err != nil {
+					return err
+				}
+
+				return ch.Printlnf("%s", repo)
+			}
+
+			var repository string
+			if len(args) == 2 {
+				if unset {
+					return errors.New("cannot use --unset with a url")
+				}
+				repository = args[1]
+			}
+
+			err = configHelper.SetRepository(ctx, artifact, repository)
+			if err != nil {
+				return err
+			}
+
+			return ch.Printlnf("kp-config set")
+		},
+	}
+
+	cmd.Flags().BoolVar(&unset, "unset", false, "remove the repository of the artifact to upload its images to the default repository")
+	return cmd
+}
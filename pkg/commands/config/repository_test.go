@@ -0,0 +1,122 @@
+package config
+
+import (
+	"testing"
+
+	kpackfakes "github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/sclevine/spec"
+	"github.com/spf13/cobra"
+	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfakes "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRepositoryCommand(t *testing.T) {
+	spec.Run(t, "TestRepositoryCommand", testRepositoryCommand)
+}
+
+func testRepositoryCommand(t *testing.T, when spec.G, it spec.S) {
+	cmdFunc := func(k8sClientSet *k8sfakes.Clientset, _ *kpackfakes.Clientset) *cobra.Command {
+		return NewRepositoryCommand(testhelpers.GetFakeClusterProvider(k8sClientSet, nil))
+	}
+
+	kpConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kp-config",
+			Namespace: "kpack",
+		},
+		Data: map[string]string{
+			"default.repository": "default-repo",
+			"stack.repository":   "stack-repo",
+		},
+	}
+
+	when("running command without a url", func() {
+		it("prints the repository of the artifact", func() {
+			testhelpers.CommandTest{
+				Objects:        []runtime.Object{kpConfig},
+				Args:           []string{"stack"},
+				ExpectedOutput: "stack-repo\n",
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+
+		it("prints the default repository when the artifact has no repository", func() {
+			testhelpers.CommandTest{
+				Objects:        []runtime.Object{kpConfig},
+				Args:           []string{"buildpackage"},
+				ExpectedOutput: "default-repo\n",
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+
+		it("prints an error when there is no repository", func() {
+			testhelpers.CommandTest{
+				Objects:             []runtime.Object{},
+				Args:                []string{"stack"},
+				ExpectErr:           true,
+				ExpectedErrorOutput: "Error: failed to get default repository: use \"kp config default-repository\" to set\n",
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+	})
+
+	when("setting the repository", func() {
+		it("updates the existing config map if it exists", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{kpConfig},
+				Args:    []string{"source", "source-repo"},
+				ExpectPatches: []string{
+					`{"data":{"source.repository":"source-repo"}}`,
+				},
+				ExpectedOutput: "kp-config set\n",
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+
+		it("creates a new config map if it doesn't exist", func() {
+			testhelpers.CommandTest{
+				Objects:        []runtime.Object{},
+				Args:           []string{"lifecycle", "lifecycle-repo"},
+				ExpectedOutput: "kp-config set\n",
+				ExpectCreates: []runtime.Object{
+					&corev1.ConfigMap{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "kp-config",
+							Namespace: "kpack",
+						},
+						Data: map[string]string{
+							"lifecycle.repository": "lifecycle-repo",
+						},
+					},
+				},
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+
+		it("removes the repository with --unset", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{kpConfig},
+				Args:    []string{"stack", "--unset"},
+				ExpectPatches: []string{
+					`{"data":{"stack.repository":null}}`,
+				},
+				ExpectedOutput: "kp-config set\n",
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+
+		it("errors when --unset is used with a url", func() {
+			testhelpers.CommandTest{
+				Objects:             []runtime.Object{kpConfig},
+				Args:                []string{"stack", "stack-repo", "--unset"},
+				ExpectErr:           true,
+				ExpectedErrorOutput: "Error: cannot use --unset with a url\n",
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+	})
+
+	it("errors for an unknown artifact", func() {
+		testhelpers.CommandTest{
+			Args:                []string{"builder"},
+			ExpectErr:           true,
+			ExpectedErrorOutput: "Error: unknown artifact 'builder', must be one of stack, buildpackage, lifecycle or source\n",
+		}.TestK8sAndKpack(t, cmdFunc)
+	})
+}
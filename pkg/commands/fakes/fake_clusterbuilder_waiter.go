@@ -5,6 +5,7 @@ package fakes
 
 import (
 	"context"
+	"sync"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	watchTools "k8s.io/client-go/tools/watch"
@@ -17,12 +18,23 @@ type WaitCall struct {
 
 type FakeWaiter struct {
 	WaitCalls []WaitCall
+	// Errors maps the name of an object to the error returned when waiting on it
+	Errors map[string]error
+
+	mu sync.Mutex
 }
 
 func (f *FakeWaiter) Wait(ctx context.Context, ob runtime.Object, checks ...watchTools.ConditionFunc) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	f.WaitCalls = append(f.WaitCalls, WaitCall{
 		Object:      ob,
 		ExtraChecks: checks,
 	})
+
+	if named, ok := ob.(interface{ GetName() string }); ok {
+		return f.Errors[named.GetName()]
+	}
 	return nil
 }
@@ -12,9 +12,15 @@ import (
 
 type FakeImageWaiter struct {
 	Calls []*v1alpha2.Image
+	// WaitUntilDone makes Wait block until the context is done and return its error
+	WaitUntilDone bool
 }
 
 func (f *FakeImageWaiter) Wait(ctx context.Context, writer io.Writer, image *v1alpha2.Image) (string, error) {
 	f.Calls = append(f.Calls, image)
+	if f.WaitUntilDone {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
 	return "", nil
 }
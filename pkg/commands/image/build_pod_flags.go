@@ -0,0 +1,53 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/image"
+)
+
+// buildPodFlags are the flags for the build pod settings that are only
+// changed when provided, so that an empty value can remove the setting
+type buildPodFlags struct {
+	affinityFile     string
+	runtimeClassName string
+	schedulerName    string
+	buildTimeout     string
+}
+
+func setBuildPodFlags(cmd *cobra.Command, factory *image.Factory, flags *buildPodFlags, canDelete bool) {
+	cmd.Flags().StringArrayVar(&factory.ResourceRequests, "request", []string{}, "build pod resource request such as memory=4Gi")
+	cmd.Flags().StringArrayVar(&factory.ResourceLimits, "limit", []string{}, "build pod resource limit such as memory=8Gi")
+	cmd.Flags().StringArrayVar(&factory.NodeSelector, "node-selector", []string{}, "build pod node selector as <KEY>=<VALUE>")
+	cmd.Flags().StringArrayVar(&factory.Tolerations, "toleration", []string{}, "build pod toleration as <KEY>[=<VALUE>][:<EFFECT>]")
+	cmd.Flags().StringVar(&flags.affinityFile, "affinity-file", "", "path to a yaml file with the build pod affinity")
+	cmd.Flags().StringVar(&flags.runtimeClassName, "runtime-class", "", "runtime class name of the build pod")
+	cmd.Flags().StringVar(&flags.schedulerName, "scheduler-name", "", "scheduler of the build pod")
+	cmd.Flags().StringVar(&flags.buildTimeout, "build-timeout", "", "maximum duration of a build such as 90m")
+
+	if canDelete {
+		cmd.Flags().StringArrayVar(&factory.DeleteResourceRequests, "delete-request", []string{}, "build pod resource requests to remove")
+		cmd.Flags().StringArrayVar(&factory.DeleteResourceLimits, "delete-limit", []string{}, "build pod resource limits to remove")
+		cmd.Flags().StringArrayVar(&factory.DeleteNodeSelector, "delete-node-selector", []string{}, "build pod node selector keys to remove")
+		cmd.Flags().StringArrayVar(&factory.DeleteTolerations, "delete-toleration", []string{}, "build pod toleration keys to remove")
+	}
+}
+
+// apply sets the build pod settings of the factory for the flags that were provided
+func (b *buildPodFlags) apply(cmd *cobra.Command, factory *image.Factory) {
+	if cmd.Flag("affinity-file").Changed {
+		factory.AffinityFile = &b.affinityFile
+	}
+	if cmd.Flag("runtime-class").Changed {
+		factory.RuntimeClassName = &b.runtimeClassName
+	}
+	if cmd.Flag("scheduler-name").Changed {
+		factory.SchedulerName = &b.schedulerName
+	}
+	if cmd.Flag("build-timeout").Changed {
+		factory.BuildTimeout = &b.buildTimeout
+	}
+}
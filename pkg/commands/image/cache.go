@@ -0,0 +1,113 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/build"
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/image"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+// cachePollInterval is how often to check whether kpack deleted the cache volume
+const cachePollInterval = time.Second
+
+type ConfirmationProvider interface {
+	Confirm(message string, okayResponses ...string) (bool, error)
+}
+
+func setCacheFlags(cmd *cobra.Command, factory *image.Factory) {
+	cmd.Flags().StringVar(&factory.CacheStorageClass, "cache-storage-class", "", "storage class of the cache volume, leave empty to use the cluster default")
+	cmd.Flags().StringVar(&factory.CacheImage, "cache-image", "", "registry location of an image to use as cache instead of a volume")
+	cmd.Flags().BoolVar(&factory.NoCache, "no-cache", false, "build without a cache")
+}
+
+// confirmCacheRecreate asks to confirm recreating the volume cache when the
+// patch shrinks it or changes its storage class, which deletes the cached layers
+func confirmCacheRecreate(img *v1alpha2.Image, factory *image.Factory, confirmationProvider ConfirmationProvider, force bool) (bool, error) {
+	required, err := factory.CacheRecreateRequired(img)
+	if err != nil || !required {
+		return true, err
+	}
+
+	if !force {
+		message := fmt.Sprintf("Shrinking the cache or changing its storage class recreates the cache volume and deletes the cached layers of image resource %q. Please confirm by typing 'y': ", img.Name)
+		confirmed, err := confirmationProvider.Confirm(message)
+		if err != nil || !confirmed {
+			return false, err
+		}
+	}
+
+	factory.RecreateCache = true
+	return true, nil
+}
+
+// removeVolumeCache removes the volume cache from the image resource and waits
+// up to the timeout for kpack to delete its persistent volume claim so that it
+// can be recreated. It fails without removing the cache while a build of the
+// image resource is running as the build pod holds on to the volume.
+func removeVolumeCache(ctx context.Context, img *v1alpha2.Image, ch *commands.CommandHelper, cs k8s.ClientSet, timeout time.Duration) (*v1alpha2.Image, error) {
+	builds, err := cs.KpackClient.KpackV1alpha2().Builds(cs.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: v1alpha2.ImageLabel + "=" + img.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, bld := range builds.Items {
+		if status := build.Status(bld); status != "SUCCESS" && status != "FAILURE" {
+			return nil, errors.Errorf("build %s of Image Resource %q is running, the cache was not removed, retry once the build finishes", bld.Labels[v1alpha2.BuildNumberLabel], img.Name)
+		}
+	}
+
+	if err := ch.PrintStatus("Removing cache of Image Resource %q...", img.Name); err != nil {
+		return nil, err
+	}
+
+	withoutCache := img.DeepCopy()
+	withoutCache.Spec.Cache = &v1alpha2.ImageCacheConfig{}
+
+	p, err := k8s.CreatePatch(img, withoutCache)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err = cs.KpackClient.KpackV1alpha2().Images(cs.Namespace).Patch(ctx, img.Name, types.MergePatchType, p, metav1.PatchOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(cachePollInterval)
+	defer ticker.Stop()
+
+	pvcs := cs.K8sClient.CoreV1().PersistentVolumeClaims(cs.Namespace)
+	for {
+		_, err := pvcs.Get(ctx, img.CacheName(), metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			return img, nil
+		} else if err != nil && ctx.Err() == nil {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.Errorf("timed out after %s waiting for kpack to delete the cache volume of Image Resource %q, the image resource has no cache until it is patched again", timeout, img.Name)
+		case <-ticker.C:
+		}
+	}
+}
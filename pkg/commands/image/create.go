@@ -5,13 +5,16 @@ package image
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/config"
 	"github.com/vmware-tanzu/kpack-cli/pkg/image"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 	"github.com/vmware-tanzu/kpack-cli/pkg/registry"
@@ -23,7 +26,10 @@ func NewCreateCommand(clientSetProvider k8s.ClientSetProvider, rup registry.Util
 		namespace string
 		subPath   string
 		factory   image.Factory
+		podFlags  buildPodFlags
 		tlsCfg    registry.TLSConfig
+		specFile  string
+		overlay   string
 	)
 
 	cmd := &cobra.Command{
@@ -42,7 +48,8 @@ The flags for this command determine how the build will retrieve source code:
 
 Local source code will be pushed to the same registry provided for the image resource tag.
 --local-path-destination-image can be used to specify the repository of the source code image.
-If not specified, the source code image will be pushed to the <image-tag-repo>-source repo.
+If not specified, the source code image will be pushed to the repository read from the "source.repository" key in the "kp-config"
+ConfigMap within "kpack" namespace, or to the <image-tag-repo>-source repo when it is not set.
 Therefore, you must have credentials to access the registry on your machine.
 --registry-ca-cert-path and --registry-verify-certs are only used for local source type.
 
@@ -50,20 +57,61 @@ Environment variables may be provided by using the "--env" flag.
 For each environment variable, supply the "--env" flag followed by the key value pair.
 For example, "--env key1=value1 --env key2=value2 ...".
 
+Environment variables may also be read from dotenv files of NAME=value lines with the "--env-file" flag.
+To keep values such as build time tokens out of the image resource, reference the key of a secret with "--env-from-secret"
+or of a config map with "--env-from-configmap" as [ENV_NAME=]NAME:KEY, the environment variable is named after the key by default.
+For example, "--env-from-secret maven-creds:MAVEN_TOKEN --env-from-configmap MAVEN_MIRROR=maven-settings:mirror-url".
+
 Service bindings may be provided by using the "--service-binding" flag.
 For each service binding, supply the "--service-binding" flag followed by the <KIND>:<APIVERSION>:<NAME> or just <NAME> which will default the kind to "Secret".
 For example, "--service-binding my-secret-1 --service-binding Secret:v1:my-secret-2 --service-binding CustomProvisionedService:v1beta1:my-ps
 
+Build pod resources may be provided by using the "--request" and "--limit" flags followed by <NAME>=<QUANTITY> for cpu, memory or ephemeral-storage.
+For example, "--request memory=4Gi --limit memory=8Gi".
+Build pods may be scheduled with "--node-selector" followed by <KEY>=<VALUE>, "--toleration" followed by <KEY>[=<VALUE>][:<EFFECT>]
+and an affinity read from a yaml file with "--affinity-file". The "--runtime-class", "--scheduler-name" and "--build-timeout" flags
+set the runtime class, the scheduler and the maximum duration of a build such as 90m.
+The priority class of build pods is assigned by kpack from the build reason and cannot be set on the image resource.
+
+The --cache-size and --cache-storage-class flags configure a volume cache, --cache-image configures an image registry cache instead
+and --no-cache disables caching.
+
+The image resource may instead be declared in an image spec file, such as a kp-image.yaml file versioned next to the application source, with "--file".
+The file declares the name and the same settings as the flags, along with per environment overlays selected with "--env-overlay".
+
+Images built with a service account that has cosign secrets, created with "kp secret create --cosign-key", are signed by kpack.
+Annotations may be added to the signatures by using the "--cosign-annotation" flag followed by the <KEY>=<VALUE> pair.
+The repository that signatures are pushed to is not part of the image resource, set it on the secret with "--cosign-repository".
+
 Env vars can be used for registry auth as described in https://github.com/vmware-tanzu/kpack-cli/blob/main/docs/auth.md"`,
 		Example: `kp image create my-image --tag my-registry.com/my-repo --git https://my-repo.com/my-app.git --git-revision my-branch
 kp image create my-image --tag my-registry.com/my-repo --blob https://my-blob-host.com/my-blob
 kp image create my-image --tag my-registry.com/my-repo --local-path /path/to/local/source/code
 kp image create my-image --tag my-registry.com/my-repo --local-path /path/to/local/source/code --builder my-builder -n my-namespace
 kp image create my-image --tag my-registry.com/my-repo --blob https://my-blob-host.com/my-blob --env foo=bar --env color=red --env food=apple
-kp image create my-image --tag my-registry.com/my-repo --blob https://my-blob-host.com/my-blob --service-binding my-secret-1 --service-binding Secret:v1:my-secret-2 --service-binding CustomProvisionedService:v1beta1:my-ps`,
-		Args:         commands.ExactArgsWithUsage(1),
+kp image create my-image --tag my-registry.com/my-repo --blob https://my-blob-host.com/my-blob --service-binding my-secret-1 --service-binding Secret:v1:my-secret-2 --service-binding CustomProvisionedService:v1beta1:my-ps
+kp image create -f kp-image.yaml --env-overlay dev`,
+		Args:         nameArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			var name string
+			if specFile != "" {
+				input, err := readSpecFile(cmd, specFile, overlay, args, namespace)
+				if err != nil {
+					return err
+				}
+				if input.tag == "" {
+					return errors.Errorf("tag is required in %s to create the resource", specFile)
+				}
+				name, namespace, tag, factory = input.name, input.namespace, input.tag, input.factory
+			} else if tag == "" {
+				return fmt.Errorf("required flag(s) \"tag\" not set\n\n%s", cmd.UsageString())
+			} else {
+				name = args[0]
+				factory.SubPath = &subPath
+				podFlags.apply(cmd, &factory)
+			}
+
 			cs, err := clientSetProvider.GetClientSet(namespace)
 			if err != nil {
 				return err
@@ -74,23 +122,18 @@ kp image create my-image --tag my-registry.com/my-repo --blob https://my-blob-ho
 				return err
 			}
 
-			name := args[0]
-
-			factory.SubPath = &subPath
 			factory.SourceUploader = rup.SourceUploader(ch.Writer(), tlsCfg, ch.IsUploading())
 			factory.Printer = ch
 
 			ctx := cmd.Context()
+			setSourceRepository(ctx, cs, &factory)
 			img, err := create(ctx, name, tag, &factory, ch, cs)
 			if err != nil {
 				return err
 			}
 
 			if ch.ShouldWait() {
-				_, err := newImageWaiter(cs).Wait(ctx, cmd.OutOrStdout(), img)
-				if err != nil {
-					return err
-				}
+				return waitForImage(cmd, newImageWaiter(cs), img)
 			}
 			return nil
 		},
@@ -102,20 +145,26 @@ kp image create my-image --tag my-registry.com/my-repo --blob https://my-blob-ho
 	cmd.Flags().StringVar(&factory.GitRevision, "git-revision", "", "git revision such as commit, tag, or branch (default \"main\")")
 	cmd.Flags().StringVar(&factory.Blob, "blob", "", "source code blob url")
 	cmd.Flags().StringVar(&factory.LocalPath, "local-path", "", "path to local source code")
-	cmd.Flags().StringVar(&factory.LocalPathDestinationImage, "local-path-destination-image", "", "registry location of where the local source code will be uploaded to (default \"<image-tag-repo>-source\")")
+	cmd.Flags().StringVar(&factory.LocalPathDestinationImage, "local-path-destination-image", "", "registry location of where the local source code will be uploaded to (default \"source.repository\" of the kp-config or \"<image-tag-repo>-source\")")
 	cmd.Flags().StringVar(&subPath, "sub-path", "", "build code at the sub path located within the source code directory")
 	cmd.Flags().StringVarP(&factory.Builder, "builder", "b", "", "builder name")
 	cmd.Flags().StringVarP(&factory.ClusterBuilder, "cluster-builder", "c", "", "cluster builder name")
 	cmd.Flags().StringArrayVarP(&factory.Env, "env", "e", []string{}, "build time environment variables")
+	cmd.Flags().StringArrayVar(&factory.EnvFile, "env-file", []string{}, "path to a dotenv file of build time environment variables")
+	cmd.Flags().StringArrayVar(&factory.EnvFromSecret, "env-from-secret", []string{}, "build time environment variable from the key of a secret as [ENV_NAME=]SECRET:KEY")
+	cmd.Flags().StringArrayVar(&factory.EnvFromConfigMap, "env-from-configmap", []string{}, "build time environment variable from the key of a config map as [ENV_NAME=]CONFIGMAP:KEY")
 	cmd.Flags().StringArrayVarP(&factory.ServiceBinding, "service-binding", "s", []string{}, "build time service bindings")
+	cmd.Flags().StringArrayVar(&factory.CosignAnnotations, "cosign-annotation", []string{}, "annotation to add to the cosign signatures of built images as KEY=VALUE")
 	cmd.Flags().StringVar(&factory.CacheSize, "cache-size", "", "cache size as a kubernetes quantity (default \"2G\")")
+	setCacheFlags(cmd, &factory)
 	cmd.Flags().StringVar(&factory.SuccessBuildHistoryLimit, "success-build-history-limit", "", "number of successful builds to keep, leave empty to use cluster default")
 	cmd.Flags().StringVar(&factory.FailedBuildHistoryLimit, "failed-build-history-limit", "", "number of failed builds to keep, leave empty to use cluster default")
+	setBuildPodFlags(cmd, &factory, &podFlags, false)
 	cmd.Flags().StringVar(&factory.ServiceAccount, "service-account", "default", "service account name to use")
 	cmd.Flags().BoolP("wait", "w", false, "wait for image create to be reconciled and tail resulting build logs")
+	setSpecFileFlags(cmd, &specFile, &overlay)
 	commands.SetImgUploadDryRunOutputFlags(cmd)
 	commands.SetTLSFlags(cmd, &tlsCfg)
-	_ = cmd.MarkFlagRequired("tag")
 	return cmd
 }
 
@@ -149,3 +198,14 @@ func create(ctx context.Context, name, tag string, factory *image.Factory, ch *c
 
 	return img, ch.PrintResult("Image Resource %q created", img.Name)
 }
+
+// setSourceRepository uploads local source code to the source repository of the
+// kp-config when no destination image is provided
+func setSourceRepository(ctx context.Context, cs k8s.ClientSet, factory *image.Factory) {
+	if factory.LocalPath == "" || factory.LocalPathDestinationImage != "" {
+		return
+	}
+
+	kpConfig := config.NewKpConfigProvider(cs.K8sClient).GetKpConfig(ctx)
+	factory.LocalPathDestinationImage = kpConfig.ArtifactRepository(config.SourceArtifact)
+}
@@ -4,6 +4,7 @@
 package image_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
 	"strconv"
@@ -22,7 +23,9 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	k8sfakes "k8s.io/client-go/kubernetes/fake"
 
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	cmdFakes "github.com/vmware-tanzu/kpack-cli/pkg/commands/fakes"
 	imgcmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/image"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
@@ -51,7 +54,14 @@ func testCreateCommand(imageCommand func(clientSetProvider k8s.ClientSetProvider
 		fakeImageWaiter := &cmdFakes.FakeImageWaiter{}
 
 		cmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
-			clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
+			clientSetProvider := testhelpers.GetFakeProvider(k8sfakes.NewSimpleClientset(), clientSet, defaultNamespace)
+			return imageCommand(clientSetProvider, registryUtilProvider, func(set k8s.ClientSet) imgcmds.ImageWaiter {
+				return fakeImageWaiter
+			})
+		}
+
+		k8sCmdFunc := func(k8sClientSet *k8sfakes.Clientset, kpackClientSet *fake.Clientset) *cobra.Command {
+			clientSetProvider := testhelpers.GetFakeProvider(k8sClientSet, kpackClientSet, defaultNamespace)
 			return imageCommand(clientSetProvider, registryUtilProvider, func(set k8s.ClientSet) imgcmds.ImageWaiter {
 				return fakeImageWaiter
 			})
@@ -534,6 +544,62 @@ Image Resource "some-image" created
 
 				assert.Len(t, fakeImageWaiter.Calls, 0)
 			})
+			it("uploads the source to the source repository of the kp-config", func() {
+				expectedImage := &v1alpha2.Image{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "Image",
+						APIVersion: "kpack.io/v1alpha2",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "some-image",
+						Namespace:   defaultNamespace,
+						Annotations: map[string]string{},
+					},
+					Spec: v1alpha2.ImageSpec{
+						Tag: "some-registry.io/some-repo",
+						Builder: corev1.ObjectReference{
+							Kind: v1alpha2.ClusterBuilderKind,
+							Name: "default",
+						},
+						ServiceAccountName: "default",
+						Source: corev1alpha1.SourceConfig{
+							Registry: &corev1alpha1.Registry{
+								Image: "some-registry.io/some-sources:source-id",
+							},
+						},
+						Build: &v1alpha2.ImageBuild{},
+					},
+				}
+				require.NoError(t, setLastAppliedAnnotation(expectedImage))
+
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						&corev1.ConfigMap{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      "kp-config",
+								Namespace: "kpack",
+							},
+							Data: map[string]string{
+								"default.repository": "some-registry.io/some-default-repo",
+								"source.repository":  "some-registry.io/some-sources",
+							},
+						},
+					},
+					Args: []string{
+						"some-image",
+						"--tag", "some-registry.io/some-repo",
+						"--local-path", "some-local-path",
+					},
+					ExpectedOutput: `Creating Image Resource...
+Uploading to 'some-registry.io/some-sources'...
+	Uploading 'some-registry.io/some-sources:source-id'
+Image Resource "some-image" created
+`,
+					ExpectCreates: []runtime.Object{
+						expectedImage,
+					},
+				}.TestK8sAndKpack(t, k8sCmdFunc)
+			})
 		})
 
 		when("the image uses a non-default builder", func() {
@@ -698,6 +764,26 @@ Image Resource "some-image" created
 			assert.Equal(t, fakeImageWaiter.Calls[0], expectedImage)
 		})
 
+		it("stops waiting on the image after the wait timeout", func() {
+			fakeImageWaiter.WaitUntilDone = true
+
+			cmd := cmdFunc(fake.NewSimpleClientset())
+			cmd.Flags().Duration(commands.WaitTimeoutFlag, commands.DefaultWaitTimeout, "")
+			cmd.SetArgs([]string{
+				"some-image",
+				"--tag", "some-registry.io/some-repo",
+				"--git", "some-git-url",
+				"--wait",
+				"--wait-timeout", "50ms",
+			})
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+
+			err := cmd.Execute()
+			require.EqualError(t, err, `timed out after 50ms waiting for the build of image resource "some-image"`)
+			assert.Len(t, fakeImageWaiter.Calls, 1)
+		})
+
 		when("output flag is used", func() {
 			when("the image config is invalid", func() {
 				it("returns an error", func() {
@@ -1045,6 +1131,61 @@ Image Resource "some-image" created
 				assert.Len(t, fakeImageWaiter.Calls, 0)
 			})
 		})
+
+		when("cosign annotations are provided", func() {
+			it("creates the image with the annotations", func() {
+				expectedImage := &v1alpha2.Image{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "Image",
+						APIVersion: "kpack.io/v1alpha2",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "some-image",
+						Namespace:   defaultNamespace,
+						Annotations: map[string]string{},
+					},
+					Spec: v1alpha2.ImageSpec{
+						Tag: "some-registry.io/some-repo",
+						Builder: corev1.ObjectReference{
+							Kind: v1alpha2.ClusterBuilderKind,
+							Name: "default",
+						},
+						ServiceAccountName: "default",
+						Source: corev1alpha1.SourceConfig{
+							Git: &corev1alpha1.Git{
+								URL:      "some-git-url",
+								Revision: "some-git-rev",
+							},
+						},
+						Build: &v1alpha2.ImageBuild{},
+						Cosign: &v1alpha2.CosignConfig{
+							Annotations: []v1alpha2.CosignAnnotation{
+								{Name: "team", Value: "payments"},
+								{Name: "env", Value: "prod"},
+							},
+						},
+					},
+				}
+
+				require.NoError(t, setLastAppliedAnnotation(expectedImage))
+				testhelpers.CommandTest{
+					Args: []string{
+						"some-image",
+						"--tag", "some-registry.io/some-repo",
+						"--git", "some-git-url",
+						"--git-revision", "some-git-rev",
+						"--cosign-annotation", "team=payments",
+						"--cosign-annotation", "env=prod",
+					},
+					ExpectedOutput: `Creating Image Resource...
+Image Resource "some-image" created
+`,
+					ExpectCreates: []runtime.Object{
+						expectedImage,
+					},
+				}.TestKpack(t, cmdFunc)
+			})
+		})
 	}
 }
 
@@ -0,0 +1,175 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/build"
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+func NewHistoryCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+	var (
+		namespace string
+		output    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "history <name>",
+		Short: "Display the build history of an image resource",
+		Long: `Prints a timeline of every retained build of an image resource in the provided namespace.
+
+Each build shows its reasons, source revision, run image, duration and the buildpacks
+whose versions changed compared to the previous build.
+
+The output can be a table (default), json or a compact changelog listing the newest build first.
+
+The namespace defaults to the kubernetes current-context namespace.`,
+		Example:      "kp image history my-image\nkp image history my-image -o changelog\nkp image history my-image -o json -n my-namespace",
+		Args:         commands.ExactArgsWithUsage(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cs, err := clientSetProvider.GetClientSet(namespace)
+			if err != nil {
+				return err
+			}
+
+			buildList, err := cs.KpackClient.KpackV1alpha2().Builds(cs.Namespace).List(cmd.Context(), metav1.ListOptions{
+				LabelSelector: v1alpha2.ImageLabel + "=" + args[0],
+			})
+			if err != nil {
+				return err
+			}
+
+			if len(buildList.Items) == 0 {
+				return errors.New("no builds found")
+			}
+
+			sort.Slice(buildList.Items, build.Sort(buildList.Items))
+			history := build.History(buildList.Items)
+
+			switch output {
+			case "table":
+				return displayHistoryTable(cmd.OutOrStdout(), history)
+			case "json":
+				return displayHistoryJson(cmd.OutOrStdout(), history)
+			case "changelog":
+				return displayHistoryChangelog(cmd.OutOrStdout(), history)
+			default:
+				return errors.Errorf("invalid output format %q, supported formats are: table, json, changelog", output)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "output format; supported formats are: table, json, changelog")
+
+	return cmd
+}
+
+func displayHistoryTable(out io.Writer, history []build.HistoryEntry) error {
+	writer, err := commands.NewTableWriter(out, "Build", "Status", "Reasons", "Revision", "Run Image", "Duration", "Buildpack Changes")
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range history {
+		var changes []string
+		for _, change := range entry.BuildpackChanges {
+			changes = append(changes, formatBuildpackChange(change))
+		}
+
+		err := writer.AddRow(
+			entry.BuildNumber,
+			entry.Status,
+			strings.Join(entry.Reasons, ","),
+			entry.Revision,
+			shortDigest(entry.RunImage),
+			entry.Duration,
+			strings.Join(changes, ", "),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.Write()
+}
+
+func displayHistoryJson(out io.Writer, history []build.HistoryEntry) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "    ")
+	return encoder.Encode(history)
+}
+
+func displayHistoryChangelog(out io.Writer, history []build.HistoryEntry) error {
+	for i := len(history) - 1; i >= 0; i-- {
+		entry := history[i]
+
+		line := fmt.Sprintf("#%s %s", entry.BuildNumber, entry.Status)
+		if len(entry.Reasons) > 0 {
+			line += fmt.Sprintf(" (%s)", strings.Join(entry.Reasons, ","))
+		}
+		if entry.Revision != "" {
+			line += " " + entry.Revision
+		}
+		if entry.Duration != "" {
+			line += " in " + entry.Duration
+		}
+		if _, err := fmt.Fprintln(out, line); err != nil {
+			return err
+		}
+
+		if entry.PreviousRunImage != "" {
+			if _, err := fmt.Fprintf(out, "  ~ run image %s -> %s\n", shortDigest(entry.PreviousRunImage), shortDigest(entry.RunImage)); err != nil {
+				return err
+			}
+		}
+
+		for _, change := range entry.BuildpackChanges {
+			if _, err := fmt.Fprintf(out, "  %s\n", formatBuildpackChange(change)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func formatBuildpackChange(change build.BuildpackChange) string {
+	switch {
+	case change.OldVersion == "":
+		return fmt.Sprintf("+ %s@%s", change.Id, change.NewVersion)
+	case change.NewVersion == "":
+		return fmt.Sprintf("- %s@%s", change.Id, change.OldVersion)
+	default:
+		return fmt.Sprintf("~ %s %s -> %s", change.Id, change.OldVersion, change.NewVersion)
+	}
+}
+
+// shortDigest returns the first 12 characters of the digest of an image reference,
+// or the reference itself when it is not referenced by digest
+func shortDigest(ref string) string {
+	i := strings.LastIndex(ref, "@sha256:")
+	if i < 0 {
+		return ref
+	}
+
+	digest := ref[i+len("@sha256:"):]
+	if len(digest) > 12 {
+		digest = digest[:12]
+	}
+	return "sha256:" + digest
+}
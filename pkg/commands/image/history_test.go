@@ -0,0 +1,156 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	"github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/sclevine/spec"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands/image"
+	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
+)
+
+func TestImageHistoryCommand(t *testing.T) {
+	spec.Run(t, "TestImageHistoryCommand", testImageHistoryCommand)
+}
+
+func testImageHistoryCommand(t *testing.T, when spec.G, it spec.S) {
+	const (
+		imageName        = "test-image"
+		defaultNamespace = "some-default-namespace"
+	)
+
+	cmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
+		clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
+		return image.NewHistoryCommand(clientSetProvider)
+	}
+
+	makeBuild := func(number string, created time.Time, duration time.Duration, status corev1.ConditionStatus, reason, revision, runImage string, buildpacks ...corev1alpha1.BuildpackMetadata) *v1alpha2.Build {
+		return &v1alpha2.Build{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              imageName + "-build-" + number,
+				Namespace:         defaultNamespace,
+				CreationTimestamp: metav1.Time{Time: created},
+				Labels: map[string]string{
+					v1alpha2.ImageLabel:       imageName,
+					v1alpha2.BuildNumberLabel: number,
+				},
+				Annotations: map[string]string{
+					v1alpha2.BuildReasonAnnotation: reason,
+				},
+			},
+			Spec: v1alpha2.BuildSpec{
+				Source: corev1alpha1.SourceConfig{
+					Git: &corev1alpha1.Git{URL: "https://github.com/org/repo", Revision: revision},
+				},
+			},
+			Status: v1alpha2.BuildStatus{
+				Status: corev1alpha1.Status{
+					Conditions: corev1alpha1.Conditions{
+						{
+							Type:               corev1alpha1.ConditionSucceeded,
+							Status:             status,
+							LastTransitionTime: corev1alpha1.VolatileTime{Inner: metav1.Time{Time: created.Add(duration)}},
+						},
+					},
+				},
+				Stack:         corev1alpha1.BuildStack{RunImage: runImage},
+				BuildMetadata: buildpacks,
+			},
+		}
+	}
+
+	start := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	runImageOne := "registry.io/run@sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	runImageTwo := "registry.io/run@sha256:2222222222222222222222222222222222222222222222222222222222222222"
+
+	builds := []runtime.Object{
+		makeBuild("1", start, 2*time.Minute, corev1.ConditionTrue, "CONFIG", "abc123", runImageOne,
+			corev1alpha1.BuildpackMetadata{Id: "paketo/node", Version: "1.0.0"},
+			corev1alpha1.BuildpackMetadata{Id: "paketo/npm", Version: "0.5.0"},
+		),
+		makeBuild("2", start.Add(time.Hour), 90*time.Second, corev1.ConditionFalse, "COMMIT", "def456", ""),
+		makeBuild("3", start.Add(2*time.Hour), 3*time.Minute, corev1.ConditionTrue, "BUILDPACK,STACK", "def456", runImageTwo,
+			corev1alpha1.BuildpackMetadata{Id: "paketo/node", Version: "1.1.0"},
+			corev1alpha1.BuildpackMetadata{Id: "paketo/yarn", Version: "0.1.0"},
+		),
+	}
+
+	it("displays a table of the builds", func() {
+		testhelpers.CommandTest{
+			Objects: builds,
+			Args:    []string{imageName},
+			ExpectedOutput: `BUILD    STATUS     REASONS            REVISION    RUN IMAGE              DURATION    BUILDPACK CHANGES
+1        SUCCESS    CONFIG             abc123      sha256:111111111111    2m0s        
+2        FAILURE    COMMIT             def456                             1m30s       
+3        SUCCESS    BUILDPACK,STACK    def456      sha256:222222222222    3m0s        ~ paketo/node 1.0.0 -> 1.1.0, - paketo/npm@0.5.0, + paketo/yarn@0.1.0
+
+`,
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("displays a changelog with the newest build first", func() {
+		testhelpers.CommandTest{
+			Objects: builds,
+			Args:    []string{imageName, "-o", "changelog"},
+			ExpectedOutput: `#3 SUCCESS (BUILDPACK,STACK) def456 in 3m0s
+  ~ run image sha256:111111111111 -> sha256:222222222222
+  ~ paketo/node 1.0.0 -> 1.1.0
+  - paketo/npm@0.5.0
+  + paketo/yarn@0.1.0
+#2 FAILURE (COMMIT) def456 in 1m30s
+#1 SUCCESS (CONFIG) abc123 in 2m0s
+`,
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("displays the history as json", func() {
+		testhelpers.CommandTest{
+			Objects: builds[:1],
+			Args:    []string{imageName, "-o", "json"},
+			ExpectedOutput: `[
+    {
+        "buildNumber": "1",
+        "name": "test-image-build-1",
+        "status": "SUCCESS",
+        "reasons": [
+            "CONFIG"
+        ],
+        "revision": "abc123",
+        "runImage": "registry.io/run@sha256:1111111111111111111111111111111111111111111111111111111111111111",
+        "started": "2023-01-01T10:00:00Z",
+        "finished": "2023-01-01T10:02:00Z",
+        "duration": "2m0s"
+    }
+]
+`,
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("errors on an unknown output format", func() {
+		testhelpers.CommandTest{
+			Objects:             builds,
+			Args:                []string{imageName, "-o", "yaml"},
+			ExpectErr:           true,
+			ExpectedErrorOutput: "Error: invalid output format \"yaml\", supported formats are: table, json, changelog\n",
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("errors when there are no builds", func() {
+		testhelpers.CommandTest{
+			Args:                []string{imageName},
+			ExpectErr:           true,
+			ExpectedErrorOutput: "Error: no builds found\n",
+		}.TestKpack(t, cmdFunc)
+	})
+}
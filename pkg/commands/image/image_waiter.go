@@ -8,8 +8,26 @@ import (
 	"io"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 )
 
 type ImageWaiter interface {
 	Wait(ctx context.Context, writer io.Writer, image *v1alpha2.Image) (string, error)
 }
+
+// waitForImage waits for the build of the image resource, bounded by the
+// global --wait-timeout flag
+func waitForImage(cmd *cobra.Command, waiter ImageWaiter, img *v1alpha2.Image) error {
+	timeout := commands.GetWaitTimeout(cmd)
+	ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+	defer cancel()
+
+	_, err := waiter.Wait(ctx, cmd.OutOrStdout(), img)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return errors.Errorf("timed out after %s waiting for the build of image resource %q", timeout, img.Name)
+	}
+	return err
+}
@@ -4,10 +4,13 @@
 package image
 
 import (
+	"fmt"
 	"sort"
+	"time"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
 	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	kpackv1alpha2 "github.com/pivotal/kpack/pkg/client/clientset/versioned/typed/build/v1alpha2"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -21,8 +24,20 @@ func NewListCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 		namespace     string
 		allNamespaces bool
 		filters       []string
+		listFlags     commands.ListFlags
 	)
 
+	sorters := map[string]func(a, b v1alpha2.Image) bool{
+		"name":      func(a, b v1alpha2.Image) bool { return a.Name < b.Name },
+		"namespace": func(a, b v1alpha2.Image) bool { return a.Namespace < b.Namespace },
+		"created":   func(a, b v1alpha2.Image) bool { return commands.ByCreationTimestamp(a.ObjectMeta, b.ObjectMeta) },
+		"ready":     func(a, b v1alpha2.Image) bool { return getReadyText(a) < getReadyText(b) },
+		"latest-build-time": func(a, b v1alpha2.Image) bool {
+			// populated below once the latest builds are known
+			return false
+		},
+	}
+
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List image resources",
@@ -32,7 +47,11 @@ The namespace defaults to the kubernetes current-context namespace.`,
 		Example: `kp image list
 kp image list -A
 kp image list -n my-namespace
-kp image list --filter ready=true --filter latest-reason=commit,trigger`,
+kp image list --filter ready=true --filter latest-reason=commit,trigger
+kp image list --filter "stack!=io.buildpacks.stacks.jammy" --filter "git-revision=main|git-revision=release-*"
+kp image list --filter tag=registry.io/team/* --filter build-age>7d
+kp image list -l team=payments --sort-by latest-build-time
+kp image list --no-headers --wide`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cs, err := clientSetProvider.GetClientSet(namespace)
 			if err != nil {
@@ -47,12 +66,21 @@ kp image list --filter ready=true --filter latest-reason=commit,trigger`,
 				imagesNamespace = cs.Namespace
 			}
 
-			imageList, err := cs.KpackClient.KpackV1alpha2().Images(imagesNamespace).List(cmd.Context(), metav1.ListOptions{})
+			imageList, err := cs.KpackClient.KpackV1alpha2().Images(imagesNamespace).List(cmd.Context(), listFlags.ListOptions())
 			if err != nil {
 				return err
 			}
 
-			imageList, err = filterImageList(imageList, filters)
+			var buildTimes latestBuildTimes
+			loadBuildTimes := func() (latestBuildTimes, error) {
+				if buildTimes != nil {
+					return buildTimes, nil
+				}
+				buildTimes, err = getLatestBuildTimes(cmd, cs.KpackClient.KpackV1alpha2().Builds(imagesNamespace))
+				return buildTimes, err
+			}
+
+			imageList, err = filterImageList(imageList, filters, filterContext{now: time.Now(), buildTimes: loadBuildTimes})
 			if err != nil {
 				return err
 			}
@@ -61,10 +89,24 @@ kp image list --filter ready=true --filter latest-reason=commit,trigger`,
 				return imageList.Items[i].Name < imageList.Items[j].Name
 			})
 
+			if listFlags.SortBy == "latest-build-time" {
+				buildTimes, err := loadBuildTimes()
+				if err != nil {
+					return err
+				}
+				sorters["latest-build-time"] = func(a, b v1alpha2.Image) bool {
+					return buildTimes.before(a, b)
+				}
+			}
+
+			if err = commands.SortItems(imageList.Items, listFlags.SortBy, sorters); err != nil {
+				return err
+			}
+
 			if len(imageList.Items) == 0 {
 				return errors.New("no image resources found")
 			} else {
-				return displayImagesTable(cmd, imageList)
+				return displayImagesTable(cmd, imageList, listFlags)
 			}
 
 		},
@@ -72,26 +114,30 @@ kp image list --filter ready=true --filter latest-reason=commit,trigger`,
 	}
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
 	cmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "Return objects found in all namespaces")
-	cmd.Flags().StringArrayVar(&filters, "filter", nil,
-		`Each new filter argument requires an additional filter flag.
-Multiple values can be provided using comma separation.
-Supported filters and values:
-  builder=string
-  clusterbuilder=string
-  latest-reason=commit,trigger,config,stack,buildpack
-  ready=true,false,unknown`)
+	cmd.Flags().StringArrayVar(&filters, "filter", nil, filterUsage)
+	commands.SetListFlags(cmd, &listFlags, commands.SortKeys(sorters)...)
 
 	return cmd
 }
 
-func displayImagesTable(cmd *cobra.Command, imageList *v1alpha2.ImageList) error {
-	writer, err := commands.NewTableWriter(cmd.OutOrStdout(), "NAME", "READY", "LATEST REASON", "LATEST IMAGE", "NAMESPACE")
+func displayImagesTable(cmd *cobra.Command, imageList *v1alpha2.ImageList, listFlags commands.ListFlags) error {
+	headers := []string{"NAME", "READY", "LATEST REASON", "LATEST IMAGE", "NAMESPACE"}
+	if listFlags.Wide {
+		headers = append(headers, "BUILDER", "LATEST BUILD", "LATEST STACK")
+	}
+
+	writer, err := listFlags.NewTableWriter(cmd.OutOrStdout(), headers...)
 	if err != nil {
 		return err
 	}
 
 	for _, img := range imageList.Items {
-		err := writer.AddRow(img.Name, getReadyText(img), img.Status.LatestBuildReason, img.Status.LatestImage, img.Namespace)
+		row := []string{img.Name, getReadyText(img), img.Status.LatestBuildReason, img.Status.LatestImage, img.Namespace}
+		if listFlags.Wide {
+			row = append(row, fmt.Sprintf("%s/%s", img.Spec.Builder.Kind, img.Spec.Builder.Name), img.Status.LatestBuildRef, img.Status.LatestStack)
+		}
+
+		err := writer.AddRow(row...)
 		if err != nil {
 			return err
 		}
@@ -100,6 +146,41 @@ func displayImagesTable(cmd *cobra.Command, imageList *v1alpha2.ImageList) error
 	return writer.Write()
 }
 
+type latestBuildTimes map[string]time.Time
+
+func getLatestBuildTimes(cmd *cobra.Command, builds kpackv1alpha2.BuildInterface) (latestBuildTimes, error) {
+	buildList, err := builds.List(cmd.Context(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	times := latestBuildTimes{}
+	for _, bld := range buildList.Items {
+		times[bld.Namespace+"/"+bld.Name] = bld.CreationTimestamp.Time
+	}
+	return times, nil
+}
+
+func (t latestBuildTimes) latest(image v1alpha2.Image) (time.Time, bool) {
+	built, ok := t[image.Namespace+"/"+image.Status.LatestBuildRef]
+	return built, ok
+}
+
+// before orders images by the creation time of their latest build,
+// images without a build are sorted last
+func (t latestBuildTimes) before(a, b v1alpha2.Image) bool {
+	aTime, aOk := t.latest(a)
+	bTime, bOk := t.latest(b)
+	switch {
+	case !aOk:
+		return false
+	case !bOk:
+		return true
+	default:
+		return aTime.Before(bTime)
+	}
+}
+
 func getReadyText(img v1alpha2.Image) string {
 	cond := img.Status.GetCondition(corev1alpha1.ConditionReady)
 	if cond == nil {
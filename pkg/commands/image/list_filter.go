@@ -4,21 +4,114 @@
 package image
 
 import (
-	"fmt"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
 	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
-	corev1 "k8s.io/api/core/v1"
+	"github.com/pkg/errors"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 )
 
+const filterUsage = `Each new filter argument requires an additional filter flag, images must match every filter.
+A filter is one or more expressions separated by '|', an image matches a filter when any expression matches.
+Expressions use '=' or '!=' (build-age uses '<' or '>'), multiple values can be provided using comma separation.
+Values of name, tag, builder, clusterbuilder, git-url, git-revision, service-account and stack support '*' and '?' globs.
+Supported filters and values:
+  name=string
+  tag=string
+  builder=string
+  clusterbuilder=string
+  latest-reason=commit,trigger,config,stack,buildpack
+  ready=true,false,unknown
+  source-type=git,blob,local
+  git-url=string
+  git-revision=string
+  service-account=string
+  rebase=true,false
+  build-age<duration (e.g. build-age<24h or build-age>7d)
+  stack=string`
+
+// filterContext provides the data needed to evaluate filters that go beyond the image resource
+type filterContext struct {
+	now        time.Time
+	buildTimes func() (latestBuildTimes, error)
+}
+
 type filter struct {
-	filterFunc func(image v1alpha2.Image, values []string) bool
-	values     []string
+	expressions []expression
 }
 
-func filterImageList(images *v1alpha2.ImageList, flags []string) (*v1alpha2.ImageList, error) {
+type expression struct {
+	key      string
+	operator string
+	values   []string
+	age      time.Duration
+}
+
+type filterKey struct {
+	values     func(image v1alpha2.Image) []string
+	ignoreCase bool
+	age        bool
+}
+
+var filterKeys = map[string]filterKey{
+	"name": {values: func(image v1alpha2.Image) []string {
+		return []string{image.Name}
+	}},
+	"tag": {values: func(image v1alpha2.Image) []string {
+		return append([]string{image.Spec.Tag}, image.Spec.AdditionalTags...)
+	}},
+	"builder": {values: func(image v1alpha2.Image) []string {
+		return builderNameOfKind(image, v1alpha2.BuilderKind)
+	}},
+	"clusterbuilder": {values: func(image v1alpha2.Image) []string {
+		return builderNameOfKind(image, v1alpha2.ClusterBuilderKind)
+	}},
+	"latest-reason": {ignoreCase: true, values: func(image v1alpha2.Image) []string {
+		return strings.Split(image.Status.LatestBuildReason, ",")
+	}},
+	"ready": {ignoreCase: true, values: func(image v1alpha2.Image) []string {
+		return []string{getReadyText(image)}
+	}},
+	"source-type": {ignoreCase: true, values: func(image v1alpha2.Image) []string {
+		return []string{sourceType(image.Spec.Source)}
+	}},
+	"git-url": {values: func(image v1alpha2.Image) []string {
+		if image.Spec.Source.Git == nil {
+			return nil
+		}
+		return []string{image.Spec.Source.Git.URL}
+	}},
+	"git-revision": {values: func(image v1alpha2.Image) []string {
+		if image.Spec.Source.Git == nil {
+			return nil
+		}
+		return []string{image.Spec.Source.Git.Revision}
+	}},
+	"service-account": {values: func(image v1alpha2.Image) []string {
+		if image.Spec.ServiceAccountName == "" {
+			return []string{"default"}
+		}
+		return []string{image.Spec.ServiceAccountName}
+	}},
+	"rebase": {ignoreCase: true, values: func(image v1alpha2.Image) []string {
+		// kpack rebases an image when the run image is the only change
+		return []string{strconv.FormatBool(image.Status.LatestBuildReason == "STACK")}
+	}},
+	"build-age": {age: true},
+	"stack": {values: func(image v1alpha2.Image) []string {
+		return []string{image.Status.LatestStack}
+	}},
+}
+
+var expressionRegex = regexp.MustCompile(`^([a-z-]+)(!=|=|<|>)(.+)$`)
+
+func filterImageList(images *v1alpha2.ImageList, flags []string, ctx filterContext) (*v1alpha2.ImageList, error) {
 	filters, err := parseFilters(flags)
 	if err != nil {
 		return nil, err
@@ -28,9 +121,17 @@ func filterImageList(images *v1alpha2.ImageList, flags []string) (*v1alpha2.Imag
 		return images, nil
 	}
 
+	var buildTimes latestBuildTimes
+	if requiresBuilds(filters) {
+		buildTimes, err = ctx.buildTimes()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var filteredItems []v1alpha2.Image
 	for _, item := range images.Items {
-		if matchesAll(item, filters) {
+		if matchesAll(item, filters, ctx.now, buildTimes) {
 			filteredItems = append(filteredItems, item)
 		}
 	}
@@ -40,52 +141,76 @@ func filterImageList(images *v1alpha2.ImageList, flags []string) (*v1alpha2.Imag
 }
 
 func parseFilters(flags []string) ([]filter, error) {
-	var (
-		filters             []filter
-		builderRegex        = regexp.MustCompile(`^builder=(.+)$`)
-		clusterBuilderRegex = regexp.MustCompile(`^clusterbuilder=(.+)$`)
-		latestReasonRegex   = regexp.MustCompile(`^latest-reason=(.+)$`)
-		statusRegex         = regexp.MustCompile(`^ready=(.+)$`)
-	)
+	var filters []filter
 
 	for _, flag := range flags {
-		m := builderRegex.FindStringSubmatch(flag)
-		if len(m) == 2 {
-			filters = append(filters, filter{values: m[1:], filterFunc: func(image v1alpha2.Image, values []string) bool {
-				return image.Spec.Builder.Kind == v1alpha2.BuilderKind && image.Spec.Builder.Name == values[0]
-			}})
-			continue
+		var f filter
+		for _, exp := range strings.Split(flag, "|") {
+			e, err := parseExpression(strings.TrimSpace(exp))
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid filter argument %q", flag)
+			}
+			f.expressions = append(f.expressions, e)
 		}
+		filters = append(filters, f)
+	}
 
-		m = clusterBuilderRegex.FindStringSubmatch(flag)
-		if len(m) == 2 {
-			filters = append(filters, filter{values: m[1:], filterFunc: func(image v1alpha2.Image, values []string) bool {
-				return image.Spec.Builder.Kind == v1alpha2.ClusterBuilderKind && image.Spec.Builder.Name == values[0]
-			}})
-			continue
-		}
+	return filters, nil
+}
 
-		m = latestReasonRegex.FindStringSubmatch(flag)
-		if len(m) == 2 {
-			filters = append(filters, filter{values: strings.Split(m[1], ","), filterFunc: matchesLatestReason})
-			continue
-		}
+func parseExpression(exp string) (expression, error) {
+	m := expressionRegex.FindStringSubmatch(exp)
+	if len(m) != 4 {
+		return expression{}, errors.Errorf("expected an expression like key=value, valid keys are: %s", validFilterKeys())
+	}
+
+	key, operator, value := m[1], m[2], m[3]
+	fk, ok := filterKeys[key]
+	if !ok {
+		return expression{}, errors.Errorf("unknown key %q, valid keys are: %s", key, validFilterKeys())
+	}
 
-		m = statusRegex.FindStringSubmatch(flag)
-		if len(m) == 2 {
-			filters = append(filters, filter{values: strings.Split(m[1], ","), filterFunc: matchesStatus})
-			continue
+	if fk.age {
+		if operator != "<" && operator != ">" {
+			return expression{}, errors.Errorf("%s only supports the '<' and '>' operators", key)
+		}
+		age, err := commands.ParseAge(value)
+		if err != nil {
+			return expression{}, errors.Errorf("%s requires a duration such as 12h or 7d", key)
 		}
+		return expression{key: key, operator: operator, age: age}, nil
+	}
 
-		return nil, fmt.Errorf(`invalid filter argument "%s"`, flag)
+	if operator != "=" && operator != "!=" {
+		return expression{}, errors.Errorf("%s only supports the '=' and '!=' operators", key)
 	}
 
-	return filters, nil
+	return expression{key: key, operator: operator, values: strings.Split(value, ",")}, nil
 }
 
-func matchesAll(image v1alpha2.Image, fs []filter) bool {
+func validFilterKeys() string {
+	var keys []string
+	for k := range filterKeys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ", ")
+}
+
+func requiresBuilds(fs []filter) bool {
 	for _, f := range fs {
-		if !f.filterFunc(image, f.values) {
+		for _, e := range f.expressions {
+			if filterKeys[e.key].age {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchesAll(image v1alpha2.Image, fs []filter, now time.Time, buildTimes latestBuildTimes) bool {
+	for _, f := range fs {
+		if !f.matches(image, now, buildTimes) {
 			return false
 		}
 	}
@@ -93,35 +218,81 @@ func matchesAll(image v1alpha2.Image, fs []filter) bool {
 	return true
 }
 
-func matchesStatus(image v1alpha2.Image, values []string) bool {
-	contains := func(q corev1.ConditionStatus) bool {
-		for _, v := range values {
-			if strings.ToLower(string(q)) == strings.ToLower(v) {
-				return true
-			}
+func (f filter) matches(image v1alpha2.Image, now time.Time, buildTimes latestBuildTimes) bool {
+	for _, e := range f.expressions {
+		if e.matches(image, now, buildTimes) {
+			return true
 		}
+	}
+
+	return false
+}
+
+func (e expression) matches(image v1alpha2.Image, now time.Time, buildTimes latestBuildTimes) bool {
+	fk := filterKeys[e.key]
 
-		return false
+	if fk.age {
+		built, ok := buildTimes.latest(image)
+		if !ok {
+			return false
+		}
+		if e.operator == "<" {
+			return now.Sub(built) < e.age
+		}
+		return now.Sub(built) > e.age
 	}
 
-	cond := image.Status.GetCondition(corev1alpha1.ConditionReady)
+	found := false
+	for _, actual := range fk.values(image) {
+		for _, pattern := range e.values {
+			if globMatch(pattern, actual, fk.ignoreCase) {
+				found = true
+			}
+		}
+	}
 
-	switch cond {
-	case nil:
-		return contains(corev1.ConditionUnknown)
-	default:
-		return contains(cond.Status)
+	if e.operator == "!=" {
+		return !found
 	}
+	return found
 }
 
-func matchesLatestReason(image v1alpha2.Image, values []string) bool {
-	for _, v := range values {
-		for _, reason := range strings.Split(image.Status.LatestBuildReason, ",") {
-			if strings.ToLower(reason) == strings.ToLower(v) {
-				return true
-			}
+// globMatch reports whether s matches pattern, where '*' matches any sequence
+// of characters, including '/', and '?' matches a single character
+func globMatch(pattern, s string, ignoreCase bool) bool {
+	if !strings.ContainsAny(pattern, "*?") {
+		if ignoreCase {
+			return strings.EqualFold(pattern, s)
 		}
+		return pattern == s
 	}
 
-	return false
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+	expr = "^" + expr + "$"
+	if ignoreCase {
+		expr = "(?i)" + expr
+	}
+	return regexp.MustCompile(expr).MatchString(s)
+}
+
+func builderNameOfKind(image v1alpha2.Image, kind string) []string {
+	if image.Spec.Builder.Kind != kind {
+		return nil
+	}
+	return []string{image.Spec.Builder.Name}
+}
+
+func sourceType(source corev1alpha1.SourceConfig) string {
+	switch {
+	case source.Git != nil:
+		return "git"
+	case source.Blob != nil:
+		return "blob"
+	case source.Registry != nil:
+		return "local"
+	default:
+		return ""
+	}
 }
@@ -5,6 +5,7 @@ package image
 
 import (
 	"testing"
+	"time"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
 	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
@@ -75,7 +76,7 @@ func testFilter(t *testing.T, when spec.G, it spec.S) {
 
 	when("the builder filter is specified", func() {
 		it("filters images", func() {
-			imgs, err := filterImageList(images, []string{"builder=some-builder"})
+			imgs, err := filterImageList(images, []string{"builder=some-builder"}, filterContext{})
 			require.NoError(t, err)
 
 			require.Len(t, imgs.Items, 1)
@@ -85,7 +86,7 @@ func testFilter(t *testing.T, when spec.G, it spec.S) {
 
 	when("the clusterbuilder filter is specified", func() {
 		it("filters images", func() {
-			imgs, err := filterImageList(images, []string{"clusterbuilder=some-cluster-builder"})
+			imgs, err := filterImageList(images, []string{"clusterbuilder=some-cluster-builder"}, filterContext{})
 			require.NoError(t, err)
 
 			require.Len(t, imgs.Items, 1)
@@ -95,7 +96,7 @@ func testFilter(t *testing.T, when spec.G, it spec.S) {
 
 	when("the status filter is specified", func() {
 		it("filters images", func() {
-			imgs, err := filterImageList(images, []string{"ready=true,some-other-status"})
+			imgs, err := filterImageList(images, []string{"ready=true,some-other-status"}, filterContext{})
 			require.NoError(t, err)
 
 			require.Len(t, imgs.Items, 1)
@@ -105,7 +106,7 @@ func testFilter(t *testing.T, when spec.G, it spec.S) {
 
 	when("the latest-reason filter is specified", func() {
 		it("filters images", func() {
-			imgs, err := filterImageList(images, []string{"latest-reason=commit,some-other-build-reason"})
+			imgs, err := filterImageList(images, []string{"latest-reason=commit,some-other-build-reason"}, filterContext{})
 			require.NoError(t, err)
 
 			require.Len(t, imgs.Items, 1)
@@ -150,7 +151,7 @@ func testFilter(t *testing.T, when spec.G, it spec.S) {
 
 	when("multiple filters are specified", func() {
 		it("filters images matching all criteria", func() {
-			imgs, err := filterImageList(imagesWithSameBuilder, []string{"builder=some-builder", "latest-reason=commit"})
+			imgs, err := filterImageList(imagesWithSameBuilder, []string{"builder=some-builder", "latest-reason=commit"}, filterContext{})
 			require.NoError(t, err)
 
 			require.Len(t, imgs.Items, 1)
@@ -160,9 +161,170 @@ func testFilter(t *testing.T, when spec.G, it spec.S) {
 
 	when("an invalid filter is specified", func() {
 		it("returns a helpful error message", func() {
-			_, err := filterImageList(imagesWithSameBuilder, []string{"some-invalid-filter=some-value"})
+			_, err := filterImageList(imagesWithSameBuilder, []string{"some-invalid-filter=some-value"}, filterContext{})
 			require.Error(t, err, "invalid filter argument \"some-invalid-filter=some-value\"")
 		})
 	})
 
+	richImages := func() *v1alpha2.ImageList {
+		return &v1alpha2.ImageList{
+			Items: []v1alpha2.Image{
+				{
+					ObjectMeta: v1.ObjectMeta{Name: "payments-api", Namespace: "some-namespace"},
+					Spec: v1alpha2.ImageSpec{
+						Tag: "registry.io/payments/api",
+						Source: corev1alpha1.SourceConfig{
+							Git: &corev1alpha1.Git{URL: "https://github.com/org/payments", Revision: "main"},
+						},
+					},
+					Status: v1alpha2.ImageStatus{
+						LatestBuildRef:    "payments-api-build-2",
+						LatestBuildReason: "STACK",
+						LatestStack:       "io.buildpacks.stacks.bionic",
+					},
+				},
+				{
+					ObjectMeta: v1.ObjectMeta{Name: "payments-worker", Namespace: "some-namespace"},
+					Spec: v1alpha2.ImageSpec{
+						Tag:                "registry.io/payments/worker",
+						ServiceAccountName: "payments-sa",
+						Source: corev1alpha1.SourceConfig{
+							Git: &corev1alpha1.Git{URL: "https://github.com/org/payments", Revision: "release-1.2"},
+						},
+					},
+					Status: v1alpha2.ImageStatus{
+						LatestBuildRef:    "payments-worker-build-5",
+						LatestBuildReason: "COMMIT",
+						LatestStack:       "io.buildpacks.stacks.jammy",
+					},
+				},
+				{
+					ObjectMeta: v1.ObjectMeta{Name: "search", Namespace: "some-namespace"},
+					Spec: v1alpha2.ImageSpec{
+						Tag:            "registry.io/search/app",
+						AdditionalTags: []string{"other-registry.io/search/app"},
+						Source: corev1alpha1.SourceConfig{
+							Blob: &corev1alpha1.Blob{URL: "https://blobs.io/search.tgz"},
+						},
+					},
+					Status: v1alpha2.ImageStatus{
+						LatestStack: "io.buildpacks.stacks.jammy",
+					},
+				},
+				{
+					ObjectMeta: v1.ObjectMeta{Name: "local-app", Namespace: "some-namespace"},
+					Spec: v1alpha2.ImageSpec{
+						Tag: "registry.io/local/app",
+						Source: corev1alpha1.SourceConfig{
+							Registry: &corev1alpha1.Registry{Image: "registry.io/local/source"},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	names := func(list *v1alpha2.ImageList) []string {
+		var n []string
+		for _, img := range list.Items {
+			n = append(n, img.Name)
+		}
+		return n
+	}
+
+	when("a negated filter is specified", func() {
+		it("filters out matching images", func() {
+			imgs, err := filterImageList(richImages(), []string{"stack!=io.buildpacks.stacks.jammy"}, filterContext{})
+			require.NoError(t, err)
+			require.Equal(t, []string{"payments-api", "local-app"}, names(imgs))
+		})
+	})
+
+	when("a filter contains multiple expressions", func() {
+		it("matches images satisfying any expression", func() {
+			imgs, err := filterImageList(richImages(), []string{"git-revision=main|source-type=blob"}, filterContext{})
+			require.NoError(t, err)
+			require.Equal(t, []string{"payments-api", "search"}, names(imgs))
+		})
+	})
+
+	when("a glob is specified", func() {
+		it("matches names and tags", func() {
+			imgs, err := filterImageList(richImages(), []string{"name=payments-*", "git-revision=release-?.*"}, filterContext{})
+			require.NoError(t, err)
+			require.Equal(t, []string{"payments-worker"}, names(imgs))
+
+			imgs, err = filterImageList(richImages(), []string{"tag=other-registry.io/*"}, filterContext{})
+			require.NoError(t, err)
+			require.Equal(t, []string{"search"}, names(imgs))
+		})
+	})
+
+	when("the source filters are specified", func() {
+		it("filters images", func() {
+			imgs, err := filterImageList(richImages(), []string{"source-type=local"}, filterContext{})
+			require.NoError(t, err)
+			require.Equal(t, []string{"local-app"}, names(imgs))
+
+			imgs, err = filterImageList(richImages(), []string{"git-url=https://github.com/org/payments"}, filterContext{})
+			require.NoError(t, err)
+			require.Equal(t, []string{"payments-api", "payments-worker"}, names(imgs))
+		})
+	})
+
+	when("the service-account filter is specified", func() {
+		it("treats an empty service account as default", func() {
+			imgs, err := filterImageList(richImages(), []string{"service-account=default", "source-type=git"}, filterContext{})
+			require.NoError(t, err)
+			require.Equal(t, []string{"payments-api"}, names(imgs))
+		})
+	})
+
+	when("the rebase filter is specified", func() {
+		it("filters images whose latest build was a rebase", func() {
+			imgs, err := filterImageList(richImages(), []string{"rebase=true"}, filterContext{})
+			require.NoError(t, err)
+			require.Equal(t, []string{"payments-api"}, names(imgs))
+		})
+	})
+
+	when("the build-age filter is specified", func() {
+		now := time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC)
+		ctx := filterContext{
+			now: now,
+			buildTimes: func() (latestBuildTimes, error) {
+				return latestBuildTimes{
+					"some-namespace/payments-api-build-2":    now.Add(-2 * time.Hour),
+					"some-namespace/payments-worker-build-5": now.Add(-8 * 24 * time.Hour),
+				}, nil
+			},
+		}
+
+		it("filters images by the age of their latest build", func() {
+			imgs, err := filterImageList(richImages(), []string{"build-age<24h"}, ctx)
+			require.NoError(t, err)
+			require.Equal(t, []string{"payments-api"}, names(imgs))
+
+			imgs, err = filterImageList(richImages(), []string{"build-age>7d"}, ctx)
+			require.NoError(t, err)
+			require.Equal(t, []string{"payments-worker"}, names(imgs))
+		})
+
+		it("does not load builds for other filters", func() {
+			_, err := filterImageList(richImages(), []string{"name=search"}, filterContext{})
+			require.NoError(t, err)
+		})
+
+		it("requires a comparison operator", func() {
+			_, err := filterImageList(richImages(), []string{"build-age=1h"}, ctx)
+			require.EqualError(t, err, `invalid filter argument "build-age=1h": build-age only supports the '<' and '>' operators`)
+		})
+	})
+
+	when("an unknown key is specified", func() {
+		it("lists the valid keys", func() {
+			_, err := filterImageList(richImages(), []string{"name=search|colour=blue"}, filterContext{})
+			require.EqualError(t, err, `invalid filter argument "name=search|colour=blue": unknown key "colour", valid keys are: build-age, builder, clusterbuilder, git-revision, git-url, latest-reason, name, ready, rebase, service-account, source-type, stack, tag`)
+		})
+	})
 }
@@ -5,6 +5,7 @@ package image_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
 	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
@@ -355,4 +356,85 @@ test-image-4    False      COMMIT           test-registry.io/test-image-4@sha256
 			})
 		})
 	})
+
+	when("list flags are provided", func() {
+		makeImage := func(name, latestBuild string, labels map[string]string) *v1alpha2.Image {
+			return &v1alpha2.Image{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      name,
+					Namespace: defaultNamespace,
+					Labels:    labels,
+				},
+				Spec: v1alpha2.ImageSpec{
+					Builder: corev1.ObjectReference{Kind: v1alpha2.ClusterBuilderKind, Name: "default"},
+				},
+				Status: v1alpha2.ImageStatus{
+					LatestBuildReason: "COMMIT",
+					LatestBuildRef:    latestBuild,
+					LatestStack:       "io.buildpacks.stacks.bionic",
+					LatestImage:       "test-registry.io/" + name + "@sha256:abcdef123",
+				},
+			}
+		}
+		makeBuild := func(name string, created time.Time) *v1alpha2.Build {
+			return &v1alpha2.Build{
+				ObjectMeta: v1.ObjectMeta{
+					Name:              name,
+					Namespace:         defaultNamespace,
+					CreationTimestamp: v1.Time{Time: created},
+				},
+			}
+		}
+
+		image1 := makeImage("test-image-1", "test-image-1-build-1", map[string]string{"team": "payments"})
+		image2 := makeImage("test-image-2", "test-image-2-build-1", map[string]string{"team": "search"})
+		image3 := makeImage("test-image-3", "", map[string]string{"team": "payments"})
+
+		it("filters images with a label selector", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{image1, image2, image3},
+				Args:    []string{"-l", "team=payments"},
+				ExpectedOutput: `NAME            READY      LATEST REASON    LATEST IMAGE                                      NAMESPACE
+test-image-1    Unknown    COMMIT           test-registry.io/test-image-1@sha256:abcdef123    some-default-namespace
+test-image-3    Unknown    COMMIT           test-registry.io/test-image-3@sha256:abcdef123    some-default-namespace
+
+`,
+			}.TestKpack(t, cmdFunc)
+		})
+
+		it("sorts images by the time of their latest build", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					image1, image2, image3,
+					makeBuild("test-image-1-build-1", time.Time{}.Add(2*time.Hour)),
+					makeBuild("test-image-2-build-1", time.Time{}.Add(1*time.Hour)),
+				},
+				Args: []string{"--sort-by", "latest-build-time", "--no-headers"},
+				ExpectedOutput: `test-image-2    Unknown    COMMIT    test-registry.io/test-image-2@sha256:abcdef123    some-default-namespace
+test-image-1    Unknown    COMMIT    test-registry.io/test-image-1@sha256:abcdef123    some-default-namespace
+test-image-3    Unknown    COMMIT    test-registry.io/test-image-3@sha256:abcdef123    some-default-namespace
+`,
+			}.TestKpack(t, cmdFunc)
+		})
+
+		it("prints additional columns in wide mode", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{image1},
+				Args:    []string{"--wide"},
+				ExpectedOutput: `NAME            READY      LATEST REASON    LATEST IMAGE                                      NAMESPACE                 BUILDER                   LATEST BUILD            LATEST STACK
+test-image-1    Unknown    COMMIT           test-registry.io/test-image-1@sha256:abcdef123    some-default-namespace    ClusterBuilder/default    test-image-1-build-1    io.buildpacks.stacks.bionic
+
+`,
+			}.TestKpack(t, cmdFunc)
+		})
+
+		it("errors with the valid keys when the sort key is invalid", func() {
+			testhelpers.CommandTest{
+				Objects:             []runtime.Object{image1},
+				Args:                []string{"--sort-by", "bogus"},
+				ExpectErr:           true,
+				ExpectedErrorOutput: "Error: invalid sort-by key \"bogus\", valid keys are: created, latest-build-time, name, namespace, ready\n",
+			}.TestKpack(t, cmdFunc)
+		})
+	})
 }
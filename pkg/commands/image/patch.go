@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"k8s.io/apimachinery/pkg/runtime"
+	"time"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
 	"github.com/spf13/cobra"
@@ -19,12 +20,14 @@ import (
 	"github.com/vmware-tanzu/kpack-cli/pkg/registry"
 )
 
-func NewPatchCommand(clientSetProvider k8s.ClientSetProvider, rup registry.UtilProvider, newImageWaiter func(k8s.ClientSet) ImageWaiter) *cobra.Command {
+func NewPatchCommand(clientSetProvider k8s.ClientSetProvider, rup registry.UtilProvider, newImageWaiter func(k8s.ClientSet) ImageWaiter, confirmationProvider ConfirmationProvider) *cobra.Command {
 	var (
 		namespace string
 		subPath   string
 		factory   image.Factory
+		podFlags  buildPodFlags
 		tlsCfg    registry.TLSConfig
+		force     bool
 	)
 
 	cmd := &cobra.Command{
@@ -41,7 +44,8 @@ The flags for this command determine how the build will retrieve source code:
   "--blob" to use source code hosted in a blob store
   "--local-path" to use source code from the local machine
 
-Local source code will be pushed to the same registry as the existing image resource tag.
+Local source code will be pushed to the same registry as the existing image resource tag, or to the repository read from
+the "source.repository" key in the "kp-config" ConfigMap within "kpack" namespace when it is set.
 Therefore, you must have credentials to access the registry on your machine.
 
 All tags found under Image.spec.additionalTags will be added to your built OCI image.
@@ -53,11 +57,34 @@ Environment variables may be provided by using the "--env" flag or deleted by us
 For each environment variable, supply the "--env" flag followed by the key value pair.
 For example, "--env key1=value1 --env key2=value2 --delete-env key3 --delete-env key3".
 
+Environment variables may also be read from dotenv files of NAME=value lines with the "--env-file" flag.
+To keep values such as build time tokens out of the image resource, reference the key of a secret with "--env-from-secret"
+or of a config map with "--env-from-configmap" as [ENV_NAME=]NAME:KEY, the environment variable is named after the key by default.
+For example, "--env-from-secret maven-creds:MAVEN_TOKEN --env-from-configmap MAVEN_MIRROR=maven-settings:mirror-url".
+References are deleted by name with "--delete-env" like any other environment variable.
+
 Service bindings may be provided by using the "--service-binding" flag or deleted by using the "--delete-service-binding" flag.
 For each service binding, supply the "--service-binding" flag followed by the <KIND>:<APIVERSION>:<NAME> or just <NAME> which will default the kind to "Secret".
 For example, "--service-binding my-secret-1 --service-binding CustomProvisionedService:v1beta1:my-ps" --delete-service-binding Secret:v1:my-secret-2
 
-The --cache-size flag can only be used to increase the size of the existing cache.
+Build pod resources may be provided by using the "--request" and "--limit" flags followed by <NAME>=<QUANTITY> for cpu, memory or ephemeral-storage.
+For example, "--request memory=4Gi --limit memory=8Gi".
+Build pods may be scheduled with "--node-selector" followed by <KEY>=<VALUE>, "--toleration" followed by <KEY>[=<VALUE>][:<EFFECT>]
+and an affinity read from a yaml file with "--affinity-file". The "--runtime-class", "--scheduler-name" and "--build-timeout" flags
+set the runtime class, the scheduler and the maximum duration of a build such as 90m.
+The priority class of build pods is assigned by kpack from the build reason and cannot be set on the image resource.
+The "--delete-request", "--delete-limit", "--delete-node-selector" and "--delete-toleration" flags remove a setting by name or key,
+an empty "--affinity-file", "--runtime-class", "--scheduler-name" or "--build-timeout" removes that setting.
+
+The --cache-size and --cache-storage-class flags configure a volume cache, --cache-image configures an image registry cache instead
+and --no-cache disables caching. Switching between a volume and a registry cache removes the cache volume.
+Shrinking the volume cache or changing its storage class recreates the cache volume after confirmation, which deletes the cached layers.
+The cache is not recreated while a build is running, kp waits up to --wait-timeout for kpack to delete the old cache volume.
+
+Images built with a service account that has cosign secrets, created with "kp secret create --cosign-key", are signed by kpack.
+Annotations may be added to the signatures by using the "--cosign-annotation" flag followed by the <KEY>=<VALUE> pair
+or deleted by using the "--delete-cosign-annotation" flag followed by the key.
+The repository that signatures are pushed to is not part of the image resource, set it on the secret with "--cosign-repository".
 
 Env vars can be used for registry auth as described in https://github.com/vmware-tanzu/kpack-cli/blob/main/docs/auth.md
 `,
@@ -89,21 +116,27 @@ kp image patch my-image --tag my-registry.com/my-repo --blob https://my-blob-hos
 
 			factory.SourceUploader = rup.SourceUploader(ch.Writer(), tlsCfg, ch.CanChangeState())
 			factory.Printer = ch
+			setSourceRepository(ctx, cs, &factory)
 
 			if cmd.Flag("sub-path").Changed {
 				factory.SubPath = &subPath
 			}
+			podFlags.apply(cmd, &factory)
+
+			proceed, err := confirmCacheRecreate(img, &factory, confirmationProvider, force || ch.IsDryRun())
+			if err != nil {
+				return err
+			} else if !proceed {
+				return ch.PrintResult("Skipping Image Resource patch")
+			}
 
-			wasPatched, img, err := patch(ctx, img, &factory, ch, cs)
+			wasPatched, img, err := patch(ctx, img, &factory, ch, cs, commands.GetWaitTimeout(cmd))
 			if err != nil {
 				return err
 			}
 
 			if wasPatched && ch.ShouldWait() {
-				_, err = newImageWaiter(cs).Wait(cmd.Context(), cmd.OutOrStdout(), img)
-				if err != nil {
-					return err
-				}
+				return waitForImage(cmd, newImageWaiter(cs), img)
 			}
 
 			return nil
@@ -121,12 +154,20 @@ kp image patch my-image --tag my-registry.com/my-repo --blob https://my-blob-hos
 	cmd.Flags().StringVar(&factory.Builder, "builder", "", "builder name")
 	cmd.Flags().StringVar(&factory.ClusterBuilder, "cluster-builder", "", "cluster builder name")
 	cmd.Flags().StringArrayVarP(&factory.Env, "env", "e", []string{}, "build time environment variables to add/replace")
+	cmd.Flags().StringArrayVar(&factory.EnvFile, "env-file", []string{}, "path to a dotenv file of build time environment variables")
+	cmd.Flags().StringArrayVar(&factory.EnvFromSecret, "env-from-secret", []string{}, "build time environment variable from the key of a secret as [ENV_NAME=]SECRET:KEY")
+	cmd.Flags().StringArrayVar(&factory.EnvFromConfigMap, "env-from-configmap", []string{}, "build time environment variable from the key of a config map as [ENV_NAME=]CONFIGMAP:KEY")
 	cmd.Flags().StringArrayVarP(&factory.DeleteEnv, "delete-env", "d", []string{}, "build time environment variables to remove")
 	cmd.Flags().StringArrayVarP(&factory.ServiceBinding, "service-binding", "s", []string{}, "build time service bindings to add/replace")
 	cmd.Flags().StringArrayVarP(&factory.DeleteServiceBinding, "delete-service-binding", "", []string{}, "build time service bindings to remove")
+	cmd.Flags().StringArrayVar(&factory.CosignAnnotations, "cosign-annotation", []string{}, "annotation to add to the cosign signatures of built images as KEY=VALUE")
+	cmd.Flags().StringArrayVar(&factory.DeleteCosignAnnotations, "delete-cosign-annotation", []string{}, "keys of the cosign signature annotations to remove")
 	cmd.Flags().StringVar(&factory.CacheSize, "cache-size", "", "cache size as a kubernetes quantity")
+	setCacheFlags(cmd, &factory)
+	cmd.Flags().BoolVar(&force, "force", false, "recreate the cache without confirmation when shrinking it or changing its storage class")
 	cmd.Flags().StringVar(&factory.SuccessBuildHistoryLimit, "success-build-history-limit", "", "number of successful builds to keep, leave empty to use cluster default")
 	cmd.Flags().StringVar(&factory.FailedBuildHistoryLimit, "failed-build-history-limit", "", "number of failed builds to keep, leave empty to use cluster default")
+	setBuildPodFlags(cmd, &factory, &podFlags, true)
 	cmd.Flags().StringVar(&factory.ServiceAccount, "service-account", "", "service account name to use")
 	cmd.Flags().BoolP("wait", "w", false, "wait for image resource patch to be reconciled and tail resulting build logs")
 	commands.SetImgUploadDryRunOutputFlags(cmd)
@@ -134,7 +175,7 @@ kp image patch my-image --tag my-registry.com/my-repo --blob https://my-blob-hos
 	return cmd
 }
 
-func patch(ctx context.Context, img *v1alpha2.Image, factory *image.Factory, ch *commands.CommandHelper, cs k8s.ClientSet) (bool, *v1alpha2.Image, error) {
+func patch(ctx context.Context, img *v1alpha2.Image, factory *image.Factory, ch *commands.CommandHelper, cs k8s.ClientSet, cacheTimeout time.Duration) (bool, *v1alpha2.Image, error) {
 	if err := ch.PrintStatus("Patching Image Resource..."); err != nil {
 		return false, nil, err
 	}
@@ -150,6 +191,20 @@ func patch(ctx context.Context, img *v1alpha2.Image, factory *image.Factory, ch
 	}
 
 	hasPatch := len(p) > 0
+	if hasPatch && factory.RecreateCache && !ch.IsDryRun() {
+		if img, err = removeVolumeCache(ctx, img, ch, cs, cacheTimeout); err != nil {
+			return hasPatch, nil, err
+		}
+
+		// the patch is computed again against the image without its cache so
+		// that it carries the whole volume cache rather than its changed fields
+		recreated := img.DeepCopy()
+		recreated.Spec = updatedImage.Spec
+		if p, err = k8s.CreatePatch(img, recreated); err != nil {
+			return hasPatch, nil, err
+		}
+	}
+
 	if hasPatch && !ch.IsDryRun() {
 		updatedImage, err = cs.KpackClient.KpackV1alpha2().Images(cs.Namespace).Patch(ctx, img.Name, types.MergePatchType, p, metav1.PatchOptions{})
 		if err != nil {
@@ -4,6 +4,10 @@
 package image_test
 
 import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
@@ -12,10 +16,16 @@ import (
 	"github.com/sclevine/spec"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	k8sfakes "k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
 
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	cmdFakes "github.com/vmware-tanzu/kpack-cli/pkg/commands/fakes"
 	imgcmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/image"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
@@ -28,18 +38,19 @@ func TestImagePatchCommand(t *testing.T) {
 	spec.Run(t, "TestImageCreateCommand", testPatchCommand(imgcmds.NewPatchCommand))
 }
 
-func testPatchCommand(imageCommand func(clientSetProvider k8s.ClientSetProvider, rup registry.UtilProvider, newImageWaiter func(k8s.ClientSet) imgcmds.ImageWaiter) *cobra.Command) func(t *testing.T, when spec.G, it spec.S) {
+func testPatchCommand(imageCommand func(clientSetProvider k8s.ClientSetProvider, rup registry.UtilProvider, newImageWaiter func(k8s.ClientSet) imgcmds.ImageWaiter, confirmationProvider imgcmds.ConfirmationProvider) *cobra.Command) func(t *testing.T, when spec.G, it spec.S) {
 	return func(t *testing.T, when spec.G, it spec.S) {
 		const defaultNamespace = "some-default-namespace"
 
 		registryUtilProvider := registryfakes.UtilProvider{}
 		fakeImageWaiter := &cmdFakes.FakeImageWaiter{}
+		fakeConfirmationProvider := cmdFakes.NewFakeConfirmationProvider(true, nil)
 
 		cmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
-			clientSetProvider := testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)
+			clientSetProvider := testhelpers.GetFakeProvider(k8sfakes.NewSimpleClientset(), clientSet, defaultNamespace)
 			return imageCommand(clientSetProvider, registryUtilProvider, func(set k8s.ClientSet) imgcmds.ImageWaiter {
 				return fakeImageWaiter
-			})
+			}, fakeConfirmationProvider)
 		}
 
 		existingImage := &v1alpha2.Image{
@@ -408,6 +419,27 @@ Image Resource "some-image" patched
 
 				assert.Len(t, fakeImageWaiter.Calls, 0)
 			})
+
+			it("can reference keys of secrets and config maps", func() {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						existingImage,
+					},
+					Args: []string{
+						"some-image",
+						"--env-from-secret", "key1=some-secret:some-key",
+						"--env-from-configmap", "some-config-map:key3",
+					},
+					ExpectedOutput: `Patching Image Resource...
+Image Resource "some-image" patched
+`,
+					ExpectPatches: []string{
+						`{"spec":{"build":{"env":[{"name":"key1","valueFrom":{"secretKeyRef":{"key":"some-key","name":"some-secret"}}},{"name":"key2","value":"value2"},{"name":"key3","valueFrom":{"configMapKeyRef":{"key":"key3","name":"some-config-map"}}}]}}}`,
+					},
+				}.TestKpack(t, cmdFunc)
+
+				assert.Len(t, fakeImageWaiter.Calls, 0)
+			})
 		})
 
 		when("patching service bindings", func() {
@@ -451,6 +483,68 @@ Image Resource "some-image" patched
 			})
 		})
 
+		when("patching build pod settings", func() {
+			it("can add resources, scheduling and timeout settings", func() {
+				affinityFile := filepath.Join(t.TempDir(), "affinity.yaml")
+				require.NoError(t, os.WriteFile(affinityFile, []byte(`nodeAffinity:
+  requiredDuringSchedulingIgnoredDuringExecution:
+    nodeSelectorTerms:
+    - matchExpressions:
+      - key: pool
+        operator: In
+        values: [java-builds]
+`), 0644))
+
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						existingImage,
+					},
+					Args: []string{
+						"some-image",
+						"--request", "memory=4Gi",
+						"--limit", "memory=8Gi",
+						"--node-selector", "disktype=ssd",
+						"--toleration", "dedicated=builds:NoSchedule",
+						"--affinity-file", affinityFile,
+						"--scheduler-name", "some-scheduler",
+						"--build-timeout", "90m",
+					},
+					ExpectedOutput: `Patching Image Resource...
+Image Resource "some-image" patched
+`,
+					ExpectPatches: []string{
+						`{"spec":{"build":{"affinity":{"nodeAffinity":{"requiredDuringSchedulingIgnoredDuringExecution":{"nodeSelectorTerms":[{"matchExpressions":[{"key":"pool","operator":"In","values":["java-builds"]}]}]}}},"buildTimeout":5400,"nodeSelector":{"disktype":"ssd"},"resources":{"limits":{"memory":"8Gi"},"requests":{"memory":"4Gi"}},"schedulerName":"some-scheduler","tolerations":[{"effect":"NoSchedule","key":"dedicated","operator":"Equal","value":"builds"}]}}}`,
+					},
+				}.TestKpack(t, cmdFunc)
+				assert.Len(t, fakeImageWaiter.Calls, 0)
+			})
+
+			it("can remove them", func() {
+				buildTimeout := int64(5400)
+				withPod := existingImage.DeepCopy()
+				withPod.Spec.Build.NodeSelector = map[string]string{"disktype": "ssd"}
+				withPod.Spec.Build.BuildTimeout = &buildTimeout
+
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						withPod,
+					},
+					Args: []string{
+						"some-image",
+						"--delete-node-selector", "disktype",
+						"--build-timeout", "",
+					},
+					ExpectedOutput: `Patching Image Resource...
+Image Resource "some-image" patched
+`,
+					ExpectPatches: []string{
+						`{"spec":{"build":{"buildTimeout":null,"nodeSelector":null}}}`,
+					},
+				}.TestKpack(t, cmdFunc)
+				assert.Len(t, fakeImageWaiter.Calls, 0)
+			})
+		})
+
 		it("can patch cache size", func() {
 			testhelpers.CommandTest{
 				Objects: []runtime.Object{
@@ -470,6 +564,271 @@ Image Resource "some-image" patched
 			assert.Len(t, fakeImageWaiter.Calls, 0)
 		})
 
+		when("shrinking the cache volume", func() {
+			cacheSize := resource.MustParse("2G")
+			withVolume := existingImage.DeepCopy()
+			withVolume.Spec.Cache = &v1alpha2.ImageCacheConfig{
+				Volume: &v1alpha2.ImagePersistentVolumeCache{Size: &cacheSize},
+			}
+			cacheVolume := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      withVolume.CacheName(),
+					Namespace: defaultNamespace,
+				},
+			}
+
+			// kpackDeletesCache deletes the cache volume once the image resource is patched like kpack does
+			// once the image resource no longer needs a volume cache
+			kpackDeletesCache := func(k8sClientSet *k8sfakes.Clientset, kpackClientSet *fake.Clientset) {
+				kpackClientSet.PrependReactor("patch", "images", func(clientgotesting.Action) (bool, runtime.Object, error) {
+					err := k8sClientSet.Tracker().Delete(corev1.SchemeGroupVersion.WithResource("persistentvolumeclaims"), defaultNamespace, withVolume.CacheName())
+					if err != nil && !k8serrors.IsNotFound(err) {
+						return true, nil, err
+					}
+					return false, nil, nil
+				})
+			}
+
+			shrinkCmdFunc := func(confirmationProvider imgcmds.ConfirmationProvider) func(*k8sfakes.Clientset, *fake.Clientset) *cobra.Command {
+				return func(k8sClientSet *k8sfakes.Clientset, kpackClientSet *fake.Clientset) *cobra.Command {
+					kpackDeletesCache(k8sClientSet, kpackClientSet)
+					clientSetProvider := testhelpers.GetFakeClusterProvider(k8sClientSet, kpackClientSet)
+					return imageCommand(clientSetProvider, registryUtilProvider, func(set k8s.ClientSet) imgcmds.ImageWaiter {
+						return fakeImageWaiter
+					}, confirmationProvider)
+				}
+			}
+
+			it("recreates the cache volume after confirmation", func() {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						withVolume,
+						cacheVolume,
+					},
+					Args: []string{
+						"some-image",
+						"-n", defaultNamespace,
+						"--cache-size", "1G",
+					},
+					ExpectedOutput: `Patching Image Resource...
+Removing cache of Image Resource "some-image"...
+Image Resource "some-image" patched
+`,
+					ExpectPatches: []string{
+						`{"spec":{"cache":{"volume":null}}}`,
+						`{"spec":{"cache":{"volume":{"size":"1G"}}}}`,
+					},
+				}.TestK8sAndKpack(t, shrinkCmdFunc(fakeConfirmationProvider))
+
+				require.NoError(t, fakeConfirmationProvider.WasRequestedWithMsg(`Shrinking the cache or changing its storage class recreates the cache volume and deletes the cached layers of image resource "some-image". Please confirm by typing 'y': `))
+			})
+
+			it("does not remove the cache while a build is running", func() {
+				runningBuild := &v1alpha2.Build{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "some-image-build-1",
+						Namespace: defaultNamespace,
+						Labels: map[string]string{
+							v1alpha2.ImageLabel:       "some-image",
+							v1alpha2.BuildNumberLabel: "1",
+						},
+					},
+					Status: v1alpha2.BuildStatus{
+						Status: corev1alpha1.Status{
+							Conditions: corev1alpha1.Conditions{
+								{Type: corev1alpha1.ConditionSucceeded, Status: corev1.ConditionUnknown},
+							},
+						},
+					},
+				}
+
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						withVolume,
+						cacheVolume,
+						runningBuild,
+					},
+					Args: []string{
+						"some-image",
+						"-n", defaultNamespace,
+						"--cache-size", "1G",
+						"--force",
+					},
+					ExpectErr:           true,
+					ExpectedOutput:      "Patching Image Resource...\n",
+					ExpectedErrorOutput: "Error: build 1 of Image Resource \"some-image\" is running, the cache was not removed, retry once the build finishes\n",
+				}.TestK8sAndKpack(t, shrinkCmdFunc(fakeConfirmationProvider))
+			})
+
+			it("times out when kpack does not delete the cache volume", func() {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						withVolume,
+						cacheVolume,
+					},
+					Args: []string{
+						"some-image",
+						"-n", defaultNamespace,
+						"--cache-size", "1G",
+						"--force",
+						"--wait-timeout", "50ms",
+					},
+					ExpectErr: true,
+					ExpectedOutput: `Patching Image Resource...
+Removing cache of Image Resource "some-image"...
+`,
+					ExpectPatches: []string{
+						`{"spec":{"cache":{"volume":null}}}`,
+					},
+					ExpectedErrorOutput: "Error: timed out after 50ms waiting for kpack to delete the cache volume of Image Resource \"some-image\", the image resource has no cache until it is patched again\n",
+				}.TestK8sAndKpack(t, func(k8sClientSet *k8sfakes.Clientset, kpackClientSet *fake.Clientset) *cobra.Command {
+					clientSetProvider := testhelpers.GetFakeClusterProvider(k8sClientSet, kpackClientSet)
+					cmd := imageCommand(clientSetProvider, registryUtilProvider, func(set k8s.ClientSet) imgcmds.ImageWaiter {
+						return fakeImageWaiter
+					}, fakeConfirmationProvider)
+					cmd.Flags().Duration(commands.WaitTimeoutFlag, commands.DefaultWaitTimeout, "")
+					return cmd
+				})
+			})
+
+			it("does not patch when the recreation is not confirmed", func() {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						withVolume,
+						cacheVolume,
+					},
+					Args: []string{
+						"some-image",
+						"-n", defaultNamespace,
+						"--cache-size", "1G",
+					},
+					ExpectedOutput: "Skipping Image Resource patch\n",
+				}.TestK8sAndKpack(t, shrinkCmdFunc(cmdFakes.NewFakeConfirmationProvider(false, nil)))
+			})
+
+			it("keeps the size of the cache volume when only its storage class changes", func() {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						withVolume,
+						cacheVolume,
+					},
+					Args: []string{
+						"some-image",
+						"-n", defaultNamespace,
+						"--cache-storage-class", "fast",
+						"--force",
+					},
+					ExpectedOutput: `Patching Image Resource...
+Removing cache of Image Resource "some-image"...
+Image Resource "some-image" patched
+`,
+					ExpectPatches: []string{
+						`{"spec":{"cache":{"volume":null}}}`,
+						`{"spec":{"cache":{"volume":{"size":"2G","storageClassName":"fast"}}}}`,
+					},
+				}.TestK8sAndKpack(t, shrinkCmdFunc(fakeConfirmationProvider))
+			})
+
+			it("keeps the custom storage class of the cache volume when shrinking it", func() {
+				customClass := withVolume.DeepCopy()
+				customClass.Spec.Cache.Volume.StorageClassName = "custom"
+
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						customClass,
+						cacheVolume,
+					},
+					Args: []string{
+						"some-image",
+						"-n", defaultNamespace,
+						"--cache-size", "1G",
+						"--force",
+					},
+					ExpectedOutput: `Patching Image Resource...
+Removing cache of Image Resource "some-image"...
+Image Resource "some-image" patched
+`,
+					ExpectPatches: []string{
+						`{"spec":{"cache":{"volume":null}}}`,
+						`{"spec":{"cache":{"volume":{"size":"1G","storageClassName":"custom"}}}}`,
+					},
+				}.TestK8sAndKpack(t, shrinkCmdFunc(fakeConfirmationProvider))
+			})
+
+			it("removes the cache, waits for kpack to delete the volume and recreates the whole volume cache", func() {
+				customClass := withVolume.DeepCopy()
+				customClass.Spec.Cache.Volume.StorageClassName = "custom"
+
+				k8sClientSet := k8sfakes.NewSimpleClientset(cacheVolume)
+				kpackClientSet := fake.NewSimpleClientset(customClass)
+				cmd := shrinkCmdFunc(fakeConfirmationProvider)(k8sClientSet, kpackClientSet)
+				cmd.SetArgs([]string{"some-image", "-n", defaultNamespace, "--cache-size", "1G", "--force"})
+				cmd.SetOut(&bytes.Buffer{})
+				cmd.SetErr(&bytes.Buffer{})
+				require.NoError(t, cmd.Execute())
+
+				_, err := k8sClientSet.CoreV1().PersistentVolumeClaims(defaultNamespace).Get(context.Background(), withVolume.CacheName(), metav1.GetOptions{})
+				require.True(t, k8serrors.IsNotFound(err))
+
+				var patches []clientgotesting.PatchAction
+				for _, action := range kpackClientSet.Actions() {
+					if patchAction, ok := action.(clientgotesting.PatchAction); ok {
+						patches = append(patches, patchAction)
+					}
+				}
+				require.Len(t, patches, 2)
+				require.JSONEq(t, `{"spec":{"cache":{"volume":{"size":"1G","storageClassName":"custom"}}}}`, string(patches[1].GetPatch()))
+
+				recreated, err := kpackClientSet.KpackV1alpha2().Images(defaultNamespace).Get(context.Background(), "some-image", metav1.GetOptions{})
+				require.NoError(t, err)
+				require.Equal(t, "custom", recreated.Spec.Cache.Volume.StorageClassName)
+				require.Equal(t, "1G", recreated.Spec.Cache.Volume.Size.String())
+			})
+
+			it("does not ask for confirmation with --force", func() {
+				declined := cmdFakes.NewFakeConfirmationProvider(false, nil)
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						withVolume,
+						cacheVolume,
+					},
+					Args: []string{
+						"some-image",
+						"-n", defaultNamespace,
+						"--cache-size", "1G",
+						"--force",
+					},
+					ExpectedOutput: `Patching Image Resource...
+Removing cache of Image Resource "some-image"...
+Image Resource "some-image" patched
+`,
+					ExpectPatches: []string{
+						`{"spec":{"cache":{"volume":null}}}`,
+						`{"spec":{"cache":{"volume":{"size":"1G"}}}}`,
+					},
+				}.TestK8sAndKpack(t, shrinkCmdFunc(declined))
+				require.False(t, declined.WasRequested())
+			})
+		})
+
+		it("can switch to a registry cache", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					existingImage,
+				},
+				Args: []string{
+					"some-image",
+					"--cache-image", "some-registry.io/some-cache",
+				},
+				ExpectedOutput: `Patching Image Resource...
+Image Resource "some-image" patched
+`,
+				ExpectPatches: []string{
+					`{"spec":{"cache":{"registry":{"tag":"some-registry.io/some-cache"}}}}`,
+				},
+			}.TestKpack(t, cmdFunc)
+		})
+
 		it("will wait on the image update if requested", func() {
 			testhelpers.CommandTest{
 				Objects: []runtime.Object{
@@ -497,6 +856,35 @@ Image Resource "some-image" patched
 			assert.Equal(t, fakeImageWaiter.Calls[0], expectedWaitImage)
 		})
 
+		it("stops waiting on the image update after the wait timeout", func() {
+			fakeImageWaiter.WaitUntilDone = true
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					existingImage,
+				},
+				Args: []string{
+					"some-image",
+					"--git-revision", "some-new-revision",
+					"--wait",
+					"--wait-timeout", "50ms",
+				},
+				ExpectErr: true,
+				ExpectedOutput: `Patching Image Resource...
+Image Resource "some-image" patched
+`,
+				ExpectedErrorOutput: "Error: timed out after 50ms waiting for the build of image resource \"some-image\"\n",
+				ExpectPatches: []string{
+					`{"spec":{"source":{"git":{"revision":"some-new-revision"}}}}`,
+				},
+			}.TestKpack(t, func(clientSet *fake.Clientset) *cobra.Command {
+				cmd := cmdFunc(clientSet)
+				cmd.Flags().Duration(commands.WaitTimeoutFlag, commands.DefaultWaitTimeout, "")
+				return cmd
+			})
+			assert.Len(t, fakeImageWaiter.Calls, 1)
+		})
+
 		when("output flag is used", func() {
 			it("can output resources in yaml and does not wait", func() {
 				const resourceYAML = `apiVersion: kpack.io/v1alpha2
@@ -16,13 +16,17 @@ import (
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 )
 
-func NewSaveCommand(clientSetProvider k8s.ClientSetProvider, rup registry.UtilProvider, newImageWaiter func(k8s.ClientSet) ImageWaiter) *cobra.Command {
+func NewSaveCommand(clientSetProvider k8s.ClientSetProvider, rup registry.UtilProvider, newImageWaiter func(k8s.ClientSet) ImageWaiter, confirmationProvider ConfirmationProvider) *cobra.Command {
 	var (
 		tag       string
 		namespace string
 		subPath   string
 		factory   image.Factory
+		podFlags  buildPodFlags
 		tlsCfg    registry.TLSConfig
+		force     bool
+		specFile  string
+		overlay   string
 	)
 
 	cmd := &cobra.Command{
@@ -32,7 +36,10 @@ func NewSaveCommand(clientSetProvider k8s.ClientSetProvider, rup registry.UtilPr
 This image resource will be created only if it does not exist in the provided namespace, otherwise it will be patched.
 
 The --tag flag is required for a create but is immutable and will be ignored for a patch.
-The --cache-size flag can only be used to create or increase the size of the existing cache.
+The --cache-size and --cache-storage-class flags configure a volume cache, --cache-image configures an image registry cache instead
+and --no-cache disables caching. Switching between a volume and a registry cache removes the cache volume.
+Shrinking the volume cache or changing its storage class recreates the cache volume after confirmation, which deletes the cached layers.
+The cache is not recreated while a build is running, kp waits up to --wait-timeout for kpack to delete the old cache volume.
 
 The namespace defaults to the kubernetes current-context namespace.
 
@@ -44,17 +51,43 @@ The flags for this command determine how the build will retrieve source code:
 
 Local source code will be pushed to the same registry provided for the image resource tag.
 --local-path-destination-image can be used to specify the repository of the source code image.
-If not specified, the source code image will be pushed to the <image-tag-repo>-source repo.
+If not specified, the source code image will be pushed to the repository read from the "source.repository" key in the "kp-config"
+ConfigMap within "kpack" namespace, or to the <image-tag-repo>-source repo when it is not set.
 Therefore, you must have credentials to access the registry on your machine.
 
 Environment variables may be provided by using the "--env" flag or deleted by using the "--delete-env" flag.
 For each environment variable, supply the "--env" flag followed by the key value pair.
 For example, "--env key1=value1 --env key2=value2 --delete-env key3".
 
+Environment variables may also be read from dotenv files of NAME=value lines with the "--env-file" flag.
+To keep values such as build time tokens out of the image resource, reference the key of a secret with "--env-from-secret"
+or of a config map with "--env-from-configmap" as [ENV_NAME=]NAME:KEY, the environment variable is named after the key by default.
+For example, "--env-from-secret maven-creds:MAVEN_TOKEN --env-from-configmap MAVEN_MIRROR=maven-settings:mirror-url".
+References are deleted by name with "--delete-env" like any other environment variable.
+
 Service bindings may be provided by using the "--service-binding" flag or deleted by using the "--delete-service-binding" flag.
 For each service binding, supply the "--service-binding" flag followed by the <KIND>:<APIVERSION>:<NAME> or just <NAME> which will default the kind to "Secret".
 For example, "--service-binding my-secret-1 --service-binding CustomProvisionedService:v1beta1:my-ps --delete-service-binding Secret:v1:my-secret-2"
 
+Build pod resources may be provided by using the "--request" and "--limit" flags followed by <NAME>=<QUANTITY> for cpu, memory or ephemeral-storage.
+For example, "--request memory=4Gi --limit memory=8Gi".
+Build pods may be scheduled with "--node-selector" followed by <KEY>=<VALUE>, "--toleration" followed by <KEY>[=<VALUE>][:<EFFECT>]
+and an affinity read from a yaml file with "--affinity-file". The "--runtime-class", "--scheduler-name" and "--build-timeout" flags
+set the runtime class, the scheduler and the maximum duration of a build such as 90m.
+The priority class of build pods is assigned by kpack from the build reason and cannot be set on the image resource.
+The "--delete-request", "--delete-limit", "--delete-node-selector" and "--delete-toleration" flags remove a setting by name or key,
+an empty "--affinity-file", "--runtime-class", "--scheduler-name" or "--build-timeout" removes that setting.
+
+The image resource may instead be declared in an image spec file, such as a kp-image.yaml file versioned next to the application source, with "--file".
+The file declares the name and the same settings as the flags, along with per environment overlays selected with "--env-overlay".
+With a file, the image resource is reconciled to match the file as a whole. Settings of an existing image resource that the file
+does not declare are removed, and the changes are printed as a diff before the image resource is patched.
+
+Images built with a service account that has cosign secrets, created with "kp secret create --cosign-key", are signed by kpack.
+Annotations may be added to the signatures by using the "--cosign-annotation" flag followed by the <KEY>=<VALUE> pair
+or deleted by using the "--delete-cosign-annotation" flag followed by the key.
+The repository that signatures are pushed to is not part of the image resource, set it on the secret with "--cosign-repository".
+
 Env vars can be used for registry auth as described in https://github.com/vmware-tanzu/kpack-cli/blob/main/docs/auth.md
 `,
 		Example: `kp image create my-image --tag my-registry.com/my-repo --git https://my-repo.com/my-app.git --git-revision my-branch
@@ -62,10 +95,23 @@ kp image save my-image --tag my-registry.com/my-repo --blob https://my-blob-host
 kp image save my-image --tag my-registry.com/my-repo --local-path /path/to/local/source/code
 kp image save my-image --tag my-registry.com/my-repo --local-path /path/to/local/source/code --builder my-builder -n my-namespace
 kp image save my-image --tag my-registry.com/my-repo --blob https://my-blob-host.com/my-blob --env foo=bar --env color=red --env food=apple --delete-env apple --delete-env potato
-kp image save my-image --tag my-registry.com/my-repo --blob https://my-blob-host.com/my-blob --service-binding my-secret --service-binding CustomProvisionedService:v1:my-ps --delete-service-binding Secret:v1:my-secret-2`,
-		Args:         commands.ExactArgsWithUsage(1),
+kp image save my-image --tag my-registry.com/my-repo --blob https://my-blob-host.com/my-blob --service-binding my-secret --service-binding CustomProvisionedService:v1:my-ps --delete-service-binding Secret:v1:my-secret-2
+kp image save -f kp-image.yaml --env-overlay prod`,
+		Args:         nameArgs,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			var name string
+			if specFile != "" {
+				input, err := readSpecFile(cmd, specFile, overlay, args, namespace)
+				if err != nil {
+					return err
+				}
+				name, namespace, tag, factory = input.name, input.namespace, input.tag, input.factory
+			} else {
+				name = args[0]
+				podFlags.apply(cmd, &factory)
+			}
+
 			cs, err := clientSetProvider.GetClientSet(namespace)
 			if err != nil {
 				return err
@@ -76,31 +122,49 @@ kp image save my-image --tag my-registry.com/my-repo --blob https://my-blob-host
 				return err
 			}
 
-			name := args[0]
 			shouldWait := ch.ShouldWait()
 
 			factory.SourceUploader = rup.SourceUploader(ch.Writer(), tlsCfg, ch.CanChangeState())
 			factory.Printer = ch
 
 			ctx := cmd.Context()
+			setSourceRepository(ctx, cs, &factory)
 
 			img, err := cs.KpackClient.KpackV1alpha2().Images(cs.Namespace).Get(ctx, name, metav1.GetOptions{})
 			if k8serrors.IsNotFound(err) {
-				if tag == "" {
+				if tag == "" && specFile != "" {
+					return errors.Errorf("tag is required in %s to create the resource", specFile)
+				} else if tag == "" {
 					return errors.New("--tag is required to create the resource")
 				}
 
-				factory.SubPath = &subPath
+				if specFile == "" {
+					factory.SubPath = &subPath
+				}
 				img, err = create(ctx, name, tag, &factory, ch, cs)
 			} else if err != nil {
 				return err
+			} else if specFile != "" {
+				var reconciled bool
+				reconciled, img, err = reconcile(ctx, img, tag, &factory, ch, cs)
+				if !reconciled {
+					shouldWait = false
+				}
 			} else {
 				if cmd.Flag("sub-path").Changed {
 					factory.SubPath = &subPath
 				}
 
+				var proceed bool
+				proceed, err = confirmCacheRecreate(img, &factory, confirmationProvider, force || ch.IsDryRun())
+				if err != nil {
+					return err
+				} else if !proceed {
+					return ch.PrintResult("Skipping Image Resource patch")
+				}
+
 				var patched bool
-				patched, img, err = patch(ctx, img, &factory, ch, cs)
+				patched, img, err = patch(ctx, img, &factory, ch, cs, commands.GetWaitTimeout(cmd))
 				if !patched {
 					shouldWait = false
 				}
@@ -111,9 +175,7 @@ kp image save my-image --tag my-registry.com/my-repo --blob https://my-blob-host
 			}
 
 			if shouldWait {
-				if _, err := newImageWaiter(cs).Wait(ctx, cmd.OutOrStdout(), img); err != nil {
-					return err
-				}
+				return waitForImage(cmd, newImageWaiter(cs), img)
 			}
 			return nil
 		},
@@ -127,19 +189,28 @@ kp image save my-image --tag my-registry.com/my-repo --blob https://my-blob-host
 	cmd.Flags().StringVar(&factory.GitRevision, "git-revision", "", "git revision such as commit, tag, or branch (default \"main\")")
 	cmd.Flags().StringVar(&factory.Blob, "blob", "", "source code blob url")
 	cmd.Flags().StringVar(&factory.LocalPath, "local-path", "", "path to local source code")
-	cmd.Flags().StringVar(&factory.LocalPathDestinationImage, "local-path-destination-image", "", "registry location of where the local source code will be uploaded to (default \"<image-tag-repo>-source\")")
+	cmd.Flags().StringVar(&factory.LocalPathDestinationImage, "local-path-destination-image", "", "registry location of where the local source code will be uploaded to (default \"source.repository\" of the kp-config or \"<image-tag-repo>-source\")")
 	cmd.Flags().StringVar(&subPath, "sub-path", "", "build code at the sub path located within the source code directory")
 	cmd.Flags().StringVar(&factory.CacheSize, "cache-size", "", "cache size as a kubernetes quantity (default \"2G\")")
+	setCacheFlags(cmd, &factory)
+	cmd.Flags().BoolVar(&force, "force", false, "recreate the cache without confirmation when shrinking it or changing its storage class")
 	cmd.Flags().StringVar(&factory.SuccessBuildHistoryLimit, "success-build-history-limit", "", "number of successful builds to keep, leave empty to use cluster default")
 	cmd.Flags().StringVar(&factory.FailedBuildHistoryLimit, "failed-build-history-limit", "", "number of failed builds to keep, leave empty to use cluster default")
 	cmd.Flags().StringVarP(&factory.Builder, "builder", "b", "", "builder name")
 	cmd.Flags().StringVarP(&factory.ClusterBuilder, "cluster-builder", "c", "", "cluster builder name")
 	cmd.Flags().StringArrayVarP(&factory.Env, "env", "e", []string{}, "build time environment variables")
+	cmd.Flags().StringArrayVar(&factory.EnvFile, "env-file", []string{}, "path to a dotenv file of build time environment variables")
+	cmd.Flags().StringArrayVar(&factory.EnvFromSecret, "env-from-secret", []string{}, "build time environment variable from the key of a secret as [ENV_NAME=]SECRET:KEY")
+	cmd.Flags().StringArrayVar(&factory.EnvFromConfigMap, "env-from-configmap", []string{}, "build time environment variable from the key of a config map as [ENV_NAME=]CONFIGMAP:KEY")
 	cmd.Flags().StringArrayVarP(&factory.DeleteEnv, "delete-env", "d", []string{}, "build time environment variables to remove")
 	cmd.Flags().StringArrayVarP(&factory.ServiceBinding, "service-binding", "s", []string{}, "build time service bindings to add/replace")
 	cmd.Flags().StringArrayVarP(&factory.DeleteServiceBinding, "delete-service-binding", "", []string{}, "build time service bindings to remove")
+	cmd.Flags().StringArrayVar(&factory.CosignAnnotations, "cosign-annotation", []string{}, "annotation to add to the cosign signatures of built images as KEY=VALUE")
+	cmd.Flags().StringArrayVar(&factory.DeleteCosignAnnotations, "delete-cosign-annotation", []string{}, "keys of the cosign signature annotations to remove")
+	setBuildPodFlags(cmd, &factory, &podFlags, true)
 	cmd.Flags().StringVar(&factory.ServiceAccount, "service-account", "", "service account name to use")
 	cmd.Flags().BoolP("wait", "w", false, "wait for image create to be reconciled and tail resulting build logs")
+	setSpecFileFlags(cmd, &specFile, &overlay)
 	commands.SetImgUploadDryRunOutputFlags(cmd)
 	commands.SetTLSFlags(cmd, &tlsCfg)
 	return cmd
@@ -7,11 +7,17 @@ import (
 	"testing"
 
 	"github.com/sclevine/spec"
+	"github.com/spf13/cobra"
 
+	cmdFakes "github.com/vmware-tanzu/kpack-cli/pkg/commands/fakes"
 	imgcmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/image"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+	"github.com/vmware-tanzu/kpack-cli/pkg/registry"
 )
 
 func TestImageSaveCommand(t *testing.T) {
-	spec.Run(t, "TestImageSaveCommandCreate", testCreateCommand(imgcmds.NewSaveCommand))
+	spec.Run(t, "TestImageSaveCommandCreate", testCreateCommand(func(clientSetProvider k8s.ClientSetProvider, rup registry.UtilProvider, newImageWaiter func(k8s.ClientSet) imgcmds.ImageWaiter) *cobra.Command {
+		return imgcmds.NewSaveCommand(clientSetProvider, rup, newImageWaiter, cmdFakes.NewFakeConfirmationProvider(false, nil))
+	}))
 	spec.Run(t, "TestImageSaveCommandPatch", testPatchCommand(imgcmds.NewSaveCommand))
 }
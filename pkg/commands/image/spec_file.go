@@ -0,0 +1,141 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/image"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+const (
+	specFileFlag   = "file"
+	envOverlayFlag = "env-overlay"
+)
+
+// specFileConflicts are the flags that configure the image resource and cannot be combined with a spec file
+var specFileConflicts = []string{
+	"tag", "additional-tag", "replace-additional-tag", "delete-additional-tag",
+	"git", "git-revision", "blob", "local-path", "local-path-destination-image", "sub-path",
+	"builder", "cluster-builder", "env", "env-file", "env-from-secret", "env-from-configmap", "delete-env", "service-binding", "delete-service-binding",
+	"cache-size", "cache-storage-class", "cache-image", "no-cache", "success-build-history-limit", "failed-build-history-limit", "service-account",
+	"request", "limit", "delete-request", "delete-limit", "node-selector", "delete-node-selector",
+	"toleration", "delete-toleration", "affinity-file", "runtime-class", "scheduler-name", "build-timeout",
+	"cosign-annotation", "delete-cosign-annotation",
+}
+
+type specFileInput struct {
+	name      string
+	namespace string
+	tag       string
+	factory   image.Factory
+}
+
+func setSpecFileFlags(cmd *cobra.Command, file, overlay *string) {
+	cmd.Flags().StringVarP(file, specFileFlag, "f", "", "path to an image spec file such as kp-image.yaml declaring the image resource")
+	cmd.Flags().StringVar(overlay, envOverlayFlag, "", "name of the overlay of the image spec file to apply, such as dev or prod")
+}
+
+// nameArgs requires the image resource name unless it is declared in a spec file
+func nameArgs(cmd *cobra.Command, args []string) error {
+	if file, _ := cmd.Flags().GetString(specFileFlag); file != "" {
+		return commands.OptionalArgsWithUsage(1)(cmd, args)
+	}
+	return commands.ExactArgsWithUsage(1)(cmd, args)
+}
+
+// readSpecFile reads the spec file and returns the image resource it declares
+// with the overlay applied. The name argument is optional with a spec file but
+// must match the file when provided, the namespace flag overrides the file.
+func readSpecFile(cmd *cobra.Command, path, overlay string, args []string, namespace string) (specFileInput, error) {
+	var conflicts []string
+	for _, name := range specFileConflicts {
+		if f := cmd.Flags().Lookup(name); f != nil && f.Changed {
+			conflicts = append(conflicts, "--"+name)
+		}
+	}
+	if len(conflicts) > 0 {
+		return specFileInput{}, errors.Errorf("--%s cannot be used with %s", specFileFlag, strings.Join(conflicts, ", "))
+	}
+
+	file, err := image.ReadSpecFile(path)
+	if err != nil {
+		return specFileInput{}, err
+	}
+
+	if len(args) > 0 && args[0] != file.Name {
+		return specFileInput{}, errors.Errorf("image resource name %q does not match the name %q declared in %s", args[0], file.Name, path)
+	}
+
+	settings, err := file.Settings(overlay)
+	if err != nil {
+		return specFileInput{}, err
+	}
+
+	if namespace == "" {
+		namespace = file.Namespace
+	}
+
+	return specFileInput{
+		name:      file.Name,
+		namespace: namespace,
+		tag:       settings.Tag,
+		factory:   settings.Factory(),
+	}, nil
+}
+
+// reconcile updates the image resource to match the spec file and prints the
+// difference with the existing resource, including the settings that are
+// removed because the file does not declare them
+func reconcile(ctx context.Context, img *v1alpha2.Image, tag string, factory *image.Factory, ch *commands.CommandHelper, cs k8s.ClientSet) (bool, *v1alpha2.Image, error) {
+	if err := ch.PrintStatus("Reconciling Image Resource..."); err != nil {
+		return false, nil, err
+	}
+
+	reconciled, err := factory.ReconcileImage(img, tag)
+	if err != nil {
+		return false, nil, err
+	}
+
+	diff, err := commands.Differ{}.Diff(img.Spec, reconciled.Spec)
+	if err != nil {
+		return false, nil, err
+	}
+
+	if diff != "" {
+		if err := ch.Printlnf("Changes to Image Resource %q:\n%s", img.Name, strings.TrimSuffix(diff, "\n")); err != nil {
+			return false, nil, err
+		}
+	}
+
+	p, err := k8s.CreatePatch(img, reconciled)
+	if err != nil {
+		return false, nil, err
+	}
+
+	hasPatch := len(p) > 0
+	if hasPatch && !ch.IsDryRun() {
+		reconciled, err = cs.KpackClient.KpackV1alpha2().Images(cs.Namespace).Patch(ctx, img.Name, types.MergePatchType, p, metav1.PatchOptions{})
+		if err != nil {
+			return hasPatch, nil, err
+		}
+	}
+
+	if err = ch.PrintObjs([]runtime.Object{reconciled}); err != nil {
+		return hasPatch, nil, err
+	}
+
+	return hasPatch, reconciled, ch.PrintChangeResult(hasPatch, fmt.Sprintf("Image Resource %q patched", img.Name))
+}
@@ -0,0 +1,283 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	"github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/sclevine/spec"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	cmdFakes "github.com/vmware-tanzu/kpack-cli/pkg/commands/fakes"
+	imgcmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/image"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+	registryfakes "github.com/vmware-tanzu/kpack-cli/pkg/registry/fakes"
+	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
+)
+
+func TestImageSpecFile(t *testing.T) {
+	spec.Run(t, "TestImageSpecFile", testImageSpecFile)
+}
+
+func testImageSpecFile(t *testing.T, when spec.G, it spec.S) {
+	const (
+		defaultNamespace = "some-default-namespace"
+		fileNamespace    = "file-namespace"
+	)
+
+	var specFile string
+
+	it.Before(func() {
+		specFile = filepath.Join(t.TempDir(), "kp-image.yaml")
+		require.NoError(t, os.WriteFile(specFile, []byte(`name: some-image
+namespace: file-namespace
+tag: some-registry.io/some-repo
+source:
+  git: https://github.com/some/app
+  gitRevision: main
+env:
+  LOG_LEVEL: info
+  FEATURE: enabled
+serviceBindings:
+- some-secret
+overlays:
+  prod:
+    source:
+      git: https://github.com/some/app
+      gitRevision: v1.0.0
+    clusterBuilder: prod-builder
+    env:
+      LOG_LEVEL: warn
+    successBuildHistoryLimit: 5
+    resources:
+      requests:
+        memory: 4Gi
+    nodeSelector:
+      pool: builds
+    tolerations:
+    - dedicated=builds:NoSchedule
+    runtimeClassName: gvisor
+    schedulerName: some-scheduler
+    buildTimeout: 90m
+`), 0644))
+	})
+
+	saveCmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
+		return imgcmds.NewSaveCommand(testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace), registryfakes.UtilProvider{}, func(k8s.ClientSet) imgcmds.ImageWaiter {
+			return &cmdFakes.FakeImageWaiter{}
+		}, cmdFakes.NewFakeConfirmationProvider(false, nil))
+	}
+
+	createCmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
+		return imgcmds.NewCreateCommand(testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace), registryfakes.UtilProvider{}, func(k8s.ClientSet) imgcmds.ImageWaiter {
+			return &cmdFakes.FakeImageWaiter{}
+		})
+	}
+
+	successLimit := int64(5)
+	runtimeClassName := "gvisor"
+	buildTimeout := int64(5400)
+	prodImage := &v1alpha2.Image{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Image",
+			APIVersion: "kpack.io/v1alpha2",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "some-image",
+			Namespace:   fileNamespace,
+			Annotations: map[string]string{},
+		},
+		Spec: v1alpha2.ImageSpec{
+			Tag: "some-registry.io/some-repo",
+			Builder: corev1.ObjectReference{
+				Kind: v1alpha2.ClusterBuilderKind,
+				Name: "prod-builder",
+			},
+			ServiceAccountName: "default",
+			Source: corev1alpha1.SourceConfig{
+				Git: &corev1alpha1.Git{
+					URL:      "https://github.com/some/app",
+					Revision: "v1.0.0",
+				},
+			},
+			Build: &v1alpha2.ImageBuild{
+				Env: []corev1.EnvVar{
+					{Name: "FEATURE", Value: "enabled"},
+					{Name: "LOG_LEVEL", Value: "warn"},
+				},
+				Services: v1alpha2.Services{
+					{Kind: "Secret", Name: "some-secret"},
+				},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+				},
+				NodeSelector: map[string]string{"pool": "builds"},
+				Tolerations: []corev1.Toleration{
+					{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "builds", Effect: corev1.TaintEffectNoSchedule},
+				},
+				RuntimeClassName: &runtimeClassName,
+				SchedulerName:    "some-scheduler",
+				BuildTimeout:     &buildTimeout,
+			},
+			SuccessBuildHistoryLimit: &successLimit,
+		},
+	}
+
+	when("the image resource does not exist", func() {
+		it("creates it from the file with the overlay applied", func() {
+			require.NoError(t, setLastAppliedAnnotation(prodImage))
+
+			for _, cmdFunc := range []func(*fake.Clientset) *cobra.Command{saveCmdFunc, createCmdFunc} {
+				testhelpers.CommandTest{
+					Args: []string{"-f", specFile, "--env-overlay", "prod"},
+					ExpectedOutput: `Creating Image Resource...
+Image Resource "some-image" created
+`,
+					ExpectCreates: []runtime.Object{prodImage},
+				}.TestKpack(t, cmdFunc)
+			}
+		})
+
+		it("errors for an unknown overlay", func() {
+			testhelpers.CommandTest{
+				Args:                []string{"-f", specFile, "--env-overlay", "staging"},
+				ExpectErr:           true,
+				ExpectedErrorOutput: "Error: overlay \"staging\" not found, available overlays: prod\n",
+			}.TestKpack(t, saveCmdFunc)
+		})
+	})
+
+	when("the image resource exists", func() {
+		existingImage := &v1alpha2.Image{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "some-image",
+				Namespace: fileNamespace,
+			},
+			Spec: v1alpha2.ImageSpec{
+				Tag: "some-registry.io/some-repo",
+				Builder: corev1.ObjectReference{
+					Kind: v1alpha2.ClusterBuilderKind,
+					Name: "default",
+				},
+				ServiceAccountName: "some-service-account",
+				Source: corev1alpha1.SourceConfig{
+					Git: &corev1alpha1.Git{
+						URL:      "https://github.com/some/app",
+						Revision: "main",
+					},
+				},
+				Build: &v1alpha2.ImageBuild{
+					Env: []corev1.EnvVar{
+						{Name: "LOG_LEVEL", Value: "info"},
+						{Name: "UNDECLARED", Value: "some-value"},
+					},
+				},
+			},
+		}
+
+		it("reconciles it to the file and prints the changes", func() {
+			reconciled := existingImage.DeepCopy()
+			reconciled.Spec.Build.Env = []corev1.EnvVar{
+				{Name: "FEATURE", Value: "enabled"},
+				{Name: "LOG_LEVEL", Value: "info"},
+			}
+			reconciled.Spec.Build.Services = v1alpha2.Services{{Kind: "Secret", Name: "some-secret"}}
+
+			diff, err := commands.Differ{}.Diff(existingImage.Spec, reconciled.Spec)
+			require.NoError(t, err)
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{existingImage},
+				Args:    []string{"some-image", "-f", specFile},
+				ExpectedOutput: "Reconciling Image Resource...\n" +
+					"Changes to Image Resource \"some-image\":\n" + diff +
+					"Image Resource \"some-image\" patched\n",
+				ExpectPatches: []string{
+					`{"spec":{"build":{"env":[{"name":"FEATURE","value":"enabled"},{"name":"LOG_LEVEL","value":"info"}],"services":[{"kind":"Secret","name":"some-secret"}]}}}`,
+				},
+			}.TestKpack(t, saveCmdFunc)
+		})
+
+		it("removes the build pod settings the file does not declare", func() {
+			podImage := existingImage.DeepCopy()
+			podImage.Spec.Build.Env = []corev1.EnvVar{
+				{Name: "FEATURE", Value: "enabled"},
+				{Name: "LOG_LEVEL", Value: "info"},
+			}
+			podImage.Spec.Build.Services = v1alpha2.Services{{Kind: "Secret", Name: "some-secret"}}
+			podImage.Spec.Build.NodeSelector = map[string]string{"disk": "ssd"}
+			podImage.Spec.Build.SchedulerName = "some-scheduler"
+			podImage.Spec.Build.BuildTimeout = &buildTimeout
+
+			reconciled := podImage.DeepCopy()
+			reconciled.Spec.Build.NodeSelector = nil
+			reconciled.Spec.Build.SchedulerName = ""
+			reconciled.Spec.Build.BuildTimeout = nil
+
+			diff, err := commands.Differ{}.Diff(podImage.Spec, reconciled.Spec)
+			require.NoError(t, err)
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{podImage},
+				Args:    []string{"some-image", "-f", specFile},
+				ExpectedOutput: "Reconciling Image Resource...\n" +
+					"Changes to Image Resource \"some-image\":\n" + diff +
+					"Image Resource \"some-image\" patched\n",
+				ExpectPatches: []string{
+					`{"spec":{"build":{"buildTimeout":null,"nodeSelector":null,"schedulerName":null}}}`,
+				},
+			}.TestKpack(t, saveCmdFunc)
+		})
+
+		it("errors when the file changes the tag", func() {
+			taggedImage := existingImage.DeepCopy()
+			taggedImage.Spec.Tag = "some-registry.io/other-repo"
+
+			testhelpers.CommandTest{
+				Objects:             []runtime.Object{taggedImage},
+				Args:                []string{"-f", specFile},
+				ExpectErr:           true,
+				ExpectedOutput:      "Reconciling Image Resource...\n",
+				ExpectedErrorOutput: "Error: tag is immutable, the image resource uses \"some-registry.io/other-repo\" but \"some-registry.io/some-repo\" was provided\n",
+			}.TestKpack(t, saveCmdFunc)
+		})
+	})
+
+	it("errors when flags configuring the image are combined with the file", func() {
+		testhelpers.CommandTest{
+			Args:                []string{"-f", specFile, "--git", "some-git-url", "--env", "foo=bar"},
+			ExpectErr:           true,
+			ExpectedErrorOutput: "Error: --file cannot be used with --git, --env\n",
+		}.TestKpack(t, saveCmdFunc)
+	})
+
+	it("errors when the name does not match the file", func() {
+		testhelpers.CommandTest{
+			Args:                []string{"other-image", "-f", specFile},
+			ExpectErr:           true,
+			ExpectedErrorOutput: "Error: image resource name \"other-image\" does not match the name \"some-image\" declared in " + specFile + "\n",
+		}.TestKpack(t, saveCmdFunc)
+	})
+
+	it("errors for fields that are not part of the file format", func() {
+		require.NoError(t, os.WriteFile(specFile, []byte("name: some-image\ntags: some-registry.io/some-repo\n"), 0644))
+
+		testhelpers.CommandTest{
+			Args:                []string{"-f", specFile},
+			ExpectErr:           true,
+			ExpectedErrorOutput: "Error: invalid image spec file \"" + specFile + "\": error unmarshaling JSON: while decoding JSON: json: unknown field \"tags\"\n",
+		}.TestKpack(t, saveCmdFunc)
+	})
+}
@@ -6,6 +6,8 @@ package image
 import (
 	"fmt"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
 	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
@@ -95,6 +97,26 @@ func displayImageStatus(cmd *cobra.Command, image *v1alpha2.Image, builds []v1al
 		return err
 	}
 
+	if buildPod := getBuildPod(image); len(buildPod) > 0 {
+		err = statusWriter.AddBlock(
+			"Build Pod",
+			buildPod...,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if image.Spec.Cosign != nil && len(image.Spec.Cosign.Annotations) > 0 {
+		err = statusWriter.AddBlock(
+			"Cosign",
+			"Annotations", formatCosignAnnotations(image.Spec.Cosign.Annotations),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = statusWriter.AddBlock(
 		"Last Successful Build",
 		buildStatus(successfulBuild)...,
@@ -246,3 +268,90 @@ func getReason(build *v1alpha2.Build) string {
 	}
 	return ""
 }
+
+func getBuildPod(image *v1alpha2.Image) []string {
+	build := image.Spec.Build
+	if build == nil {
+		return nil
+	}
+
+	var items []string
+	add := func(name, value string) {
+		if value != "" {
+			items = append(items, name, value)
+		}
+	}
+
+	add("Requests", formatResourceList(build.Resources.Requests))
+	add("Limits", formatResourceList(build.Resources.Limits))
+	add("Node Selector", formatNodeSelector(build.NodeSelector))
+	add("Tolerations", formatTolerations(build.Tolerations))
+	add("Affinity", formatAffinity(build.Affinity))
+	if build.RuntimeClassName != nil {
+		add("Runtime Class", *build.RuntimeClassName)
+	}
+	add("Scheduler", build.SchedulerName)
+	if build.BuildTimeout != nil {
+		add("Build Timeout", (time.Duration(*build.BuildTimeout) * time.Second).String())
+	}
+	return items
+}
+
+func formatCosignAnnotations(annotations []v1alpha2.CosignAnnotation) string {
+	var parts []string
+	for _, a := range annotations {
+		parts = append(parts, a.Name+"="+a.Value)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatResourceList(list corev1.ResourceList) string {
+	var parts []string
+	for name, quantity := range list {
+		parts = append(parts, string(name)+"="+quantity.String())
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+func formatNodeSelector(selector map[string]string) string {
+	var parts []string
+	for k, v := range selector {
+		parts = append(parts, k+"="+v)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+func formatTolerations(tolerations []corev1.Toleration) string {
+	var parts []string
+	for _, t := range tolerations {
+		s := t.Key
+		if t.Operator == corev1.TolerationOpEqual || t.Value != "" {
+			s += "=" + t.Value
+		}
+		if t.Effect != "" {
+			s += ":" + string(t.Effect)
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatAffinity(affinity *corev1.Affinity) string {
+	if affinity == nil {
+		return ""
+	}
+
+	var parts []string
+	if affinity.NodeAffinity != nil {
+		parts = append(parts, "node affinity")
+	}
+	if affinity.PodAffinity != nil {
+		parts = append(parts, "pod affinity")
+	}
+	if affinity.PodAntiAffinity != nil {
+		parts = append(parts, "pod anti-affinity")
+	}
+	return strings.Join(parts, ", ")
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/sclevine/spec"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
@@ -378,4 +379,128 @@ Build Reason:    --
 			}.TestKpack(t, cmdFunc)
 		})
 	})
+
+	when("an image has build pod settings", func() {
+		it("displays them", func() {
+			runtimeClass := "gvisor"
+			buildTimeout := int64(5400)
+			image := &v1alpha2.Image{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      imageName,
+					Namespace: defaultNamespace,
+				},
+				Spec: v1alpha2.ImageSpec{
+					Builder: corev1.ObjectReference{
+						Kind: "ClusterBuilder",
+						Name: "some-cluster-builder",
+					},
+					Build: &v1alpha2.ImageBuild{
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceMemory: resource.MustParse("4Gi"),
+								corev1.ResourceCPU:    resource.MustParse("2"),
+							},
+							Limits: corev1.ResourceList{
+								corev1.ResourceMemory: resource.MustParse("8Gi"),
+							},
+						},
+						NodeSelector: map[string]string{"pool": "java-builds"},
+						Tolerations: []corev1.Toleration{
+							{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "builds", Effect: corev1.TaintEffectNoSchedule},
+						},
+						Affinity:         &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{}},
+						RuntimeClassName: &runtimeClass,
+						BuildTimeout:     &buildTimeout,
+					},
+				},
+			}
+
+			const expectedOutput = `Status:         Unknown
+Message:        --
+LatestImage:    --
+
+Source
+Type:    Local Source
+
+Builder Ref
+Name:    some-cluster-builder
+Kind:    ClusterBuilder
+
+Build Pod
+Requests:         cpu=2, memory=4Gi
+Limits:           memory=8Gi
+Node Selector:    pool=java-builds
+Tolerations:      dedicated=builds:NoSchedule
+Affinity:         node affinity
+Runtime Class:    gvisor
+Build Timeout:    1h30m0s
+
+Last Successful Build
+Id:              --
+Build Reason:    --
+
+Last Failed Build
+Id:              --
+Build Reason:    --
+
+`
+			testhelpers.CommandTest{
+				Objects:        []runtime.Object{image},
+				Args:           []string{imageName},
+				ExpectedOutput: expectedOutput,
+			}.TestKpack(t, cmdFunc)
+		})
+	})
+
+	when("an image has cosign annotations", func() {
+		it("displays them", func() {
+			image := &v1alpha2.Image{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      imageName,
+					Namespace: defaultNamespace,
+				},
+				Spec: v1alpha2.ImageSpec{
+					Builder: corev1.ObjectReference{
+						Kind: "ClusterBuilder",
+						Name: "some-cluster-builder",
+					},
+					Cosign: &v1alpha2.CosignConfig{
+						Annotations: []v1alpha2.CosignAnnotation{
+							{Name: "team", Value: "payments"},
+							{Name: "env", Value: "prod"},
+						},
+					},
+				},
+			}
+
+			const expectedOutput = `Status:         Unknown
+Message:        --
+LatestImage:    --
+
+Source
+Type:    Local Source
+
+Builder Ref
+Name:    some-cluster-builder
+Kind:    ClusterBuilder
+
+Cosign
+Annotations:    team=payments, env=prod
+
+Last Successful Build
+Id:              --
+Build Reason:    --
+
+Last Failed Build
+Id:              --
+Build Reason:    --
+
+`
+			testhelpers.CommandTest{
+				Objects:        []runtime.Object{image},
+				Args:           []string{imageName},
+				ExpectedOutput: expectedOutput,
+			}.TestKpack(t, cmdFunc)
+		})
+	})
 }
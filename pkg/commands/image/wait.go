@@ -0,0 +1,157 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/build"
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+const (
+	// WaitBuildFailedExitCode is the exit code of kp image wait when the build failed
+	WaitBuildFailedExitCode = 2
+	// WaitTimeoutExitCode is the exit code of kp image wait when no build finished in time
+	WaitTimeoutExitCode = 3
+
+	waitPollInterval = 2 * time.Second
+)
+
+func NewWaitCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+	var (
+		namespace    string
+		revision     string
+		buildAfter   int
+		startedAfter string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "wait <name>",
+		Short: "Wait for a build of an image resource to finish",
+		Long: `Blocks until the latest build of an image resource matching the conditions finishes and prints the resulting image.
+
+This lets pipelines that push to git and let kpack poll for changes wait for the resulting build.
+A build matches when it satisfies every provided condition, without conditions the latest build is waited on:
+  --revision       the build is of the commit sha, either the full sha or a prefix of at least seven characters,
+                   or the build is newer than the wait when it is the branch or tag the image resource builds from
+  --build-after    the build number is greater than the provided number
+  --started-after  the build was created after the provided RFC3339 timestamp
+
+The wait is bounded by --wait-timeout.
+
+The exit code is 0 when the build succeeded, 2 when it failed, 3 when no matching build finished before the timeout and 1 for any other error.
+
+The namespace defaults to the kubernetes current-context namespace.`,
+		Example:      "kp image wait my-image --revision 4f3c2a1\nkp image wait my-image --build-after 12 --wait-timeout 30m\nkp image wait my-image --started-after 2024-01-01T12:00:00Z",
+		Args:         commands.ExactArgsWithUsage(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cs, err := clientSetProvider.GetClientSet(namespace)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			name := args[0]
+
+			img, err := cs.KpackClient.KpackV1alpha2().Images(cs.Namespace).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+
+			var (
+				conditions  []build.Condition
+				description []string
+			)
+			if revision != "" {
+				condition, err := revisionCondition(img, revision)
+				if err != nil {
+					return err
+				}
+				conditions = append(conditions, condition)
+				description = append(description, fmt.Sprintf("revision %q", revision))
+			}
+			if cmd.Flags().Changed("build-after") {
+				conditions = append(conditions, build.NumberedAfter(buildAfter))
+				description = append(description, fmt.Sprintf("build number greater than %d", buildAfter))
+			}
+			if startedAfter != "" {
+				t, err := time.Parse(time.RFC3339, startedAfter)
+				if err != nil {
+					return errors.Errorf("invalid --started-after %q, it must be an RFC3339 timestamp", startedAfter)
+				}
+				conditions = append(conditions, build.StartedAfter(t))
+				description = append(description, fmt.Sprintf("started after %s", t.Format(time.RFC3339)))
+			}
+
+			msg := fmt.Sprintf("Waiting for a build of image resource %q", name)
+			if len(description) > 0 {
+				msg += " with " + strings.Join(description, " and ")
+			}
+			if _, err = fmt.Fprintln(cmd.ErrOrStderr(), msg+"..."); err != nil {
+				return err
+			}
+
+			timeout := commands.GetWaitTimeout(cmd)
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			bld, err := build.WaitForBuild(ctx, cs.KpackClient, cs.Namespace, name, build.AllOf(conditions...), waitPollInterval)
+			if errors.Is(err, context.DeadlineExceeded) {
+				return commands.ExitError{
+					Code: WaitTimeoutExitCode,
+					Err:  errors.Errorf("timed out after %s waiting for a build of image resource %q", timeout, name),
+				}
+			} else if err != nil {
+				return err
+			}
+
+			number := bld.Labels[v1alpha2.BuildNumberLabel]
+			if build.Status(bld) != "SUCCESS" {
+				return commands.ExitError{
+					Code: WaitBuildFailedExitCode,
+					Err:  errors.Errorf("build %s of image resource %q failed", number, name),
+				}
+			}
+
+			if _, err = fmt.Fprintf(cmd.ErrOrStderr(), "Build %s succeeded\n", number); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), bld.Status.LatestImage)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
+	cmd.Flags().StringVar(&revision, "revision", "", "commit sha the build must be of, or the branch or tag the image resource builds from")
+	cmd.Flags().IntVar(&buildAfter, "build-after", 0, "build number the build must be greater than")
+	cmd.Flags().StringVar(&startedAfter, "started-after", "", "RFC3339 timestamp the build must be created after")
+
+	return cmd
+}
+
+// revisionCondition matches the builds of a commit sha. Builds record the
+// commit sha a branch or tag resolved to, so for the branch or tag the image
+// resource builds from it matches the builds scheduled after the wait starts.
+func revisionCondition(img *v1alpha2.Image, revision string) (build.Condition, error) {
+	if build.IsCommitSha(revision) {
+		return build.HasRevision(revision), nil
+	}
+
+	if img.Spec.Source.Git != nil && img.Spec.Source.Git.Revision == revision {
+		return build.NumberedAfter(int(img.Status.BuildCounter)), nil
+	}
+
+	return nil, errors.Errorf("revision %q is not a commit sha of at least seven characters nor the git revision of image resource %q", revision, img.Name)
+}
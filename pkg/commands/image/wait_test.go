@@ -0,0 +1,183 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	"github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/sclevine/spec"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands/image"
+	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
+)
+
+func TestImageWaitCommand(t *testing.T) {
+	spec.Run(t, "TestImageWaitCommand", testImageWaitCommand)
+}
+
+func testImageWaitCommand(t *testing.T, when spec.G, it spec.S) {
+	const (
+		imageName        = "some-image"
+		defaultNamespace = "some-default-namespace"
+	)
+
+	created := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// cmdFunc attaches the command to a parent with the global --wait-timeout flag
+	cmdFunc := func(clientSet *fake.Clientset) *cobra.Command {
+		parent := &cobra.Command{Use: "kp", SilenceErrors: true}
+		parent.PersistentFlags().Duration(commands.WaitTimeoutFlag, commands.DefaultWaitTimeout, "")
+		parent.AddCommand(image.NewWaitCommand(testhelpers.GetFakeKpackProvider(clientSet, defaultNamespace)))
+		return parent
+	}
+
+	img := &v1alpha2.Image{
+		ObjectMeta: metav1.ObjectMeta{Name: imageName, Namespace: defaultNamespace},
+		Spec: v1alpha2.ImageSpec{
+			Source: corev1alpha1.SourceConfig{
+				Git: &corev1alpha1.Git{URL: "https://github.com/some/repo", Revision: "main"},
+			},
+		},
+		Status: v1alpha2.ImageStatus{
+			BuildCounter: 2,
+		},
+	}
+
+	makeBuild := func(number string, revision string, age time.Duration, status corev1.ConditionStatus) *v1alpha2.Build {
+		return &v1alpha2.Build{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              imageName + "-build-" + number,
+				Namespace:         defaultNamespace,
+				CreationTimestamp: metav1.NewTime(created.Add(age)),
+				Labels: map[string]string{
+					v1alpha2.ImageLabel:       imageName,
+					v1alpha2.BuildNumberLabel: number,
+				},
+			},
+			Spec: v1alpha2.BuildSpec{
+				Source: corev1alpha1.SourceConfig{
+					Git: &corev1alpha1.Git{URL: "https://github.com/some/repo", Revision: revision},
+				},
+			},
+			Status: v1alpha2.BuildStatus{
+				Status: corev1alpha1.Status{
+					Conditions: corev1alpha1.Conditions{
+						{Type: corev1alpha1.ConditionSucceeded, Status: status},
+					},
+				},
+				LatestImage: "some-registry.io/some-repo@sha256:build-" + number,
+			},
+		}
+	}
+
+	objects := []runtime.Object{
+		img,
+		makeBuild("1", "1111111aaaaaaa", 0, corev1.ConditionTrue),
+		makeBuild("2", "2222222bbbbbbb", time.Hour, corev1.ConditionFalse),
+		makeBuild("3", "3333333ccccccc", 2*time.Hour, corev1.ConditionTrue),
+	}
+
+	exitCode := func(args ...string) (int, string) {
+		cmd := cmdFunc(fake.NewSimpleClientset(objects...))
+		cmd.SetArgs(append([]string{"wait"}, args...))
+		cmd.SetOut(&bytes.Buffer{})
+		errOut := &bytes.Buffer{}
+		cmd.SetErr(errOut)
+
+		err := cmd.Execute()
+		if err == nil {
+			return 0, ""
+		}
+
+		var exitErr commands.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.Code, err.Error()
+		}
+		return 1, err.Error()
+	}
+
+	it("prints the image of the build of a revision", func() {
+		testhelpers.CommandTest{
+			Objects:             objects,
+			Args:                []string{"wait", imageName, "--revision", "1111111"},
+			ExpectedOutput:      "some-registry.io/some-repo@sha256:build-1\n",
+			ExpectedErrorOutput: "Waiting for a build of image resource \"some-image\" with revision \"1111111\"...\nBuild 1 succeeded\n",
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("waits on a new build for the branch the image resource builds from", func() {
+		testhelpers.CommandTest{
+			Objects:             objects,
+			Args:                []string{"wait", imageName, "--revision", "main"},
+			ExpectedOutput:      "some-registry.io/some-repo@sha256:build-3\n",
+			ExpectedErrorOutput: "Waiting for a build of image resource \"some-image\" with revision \"main\"...\nBuild 3 succeeded\n",
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("exits with 1 for a branch the image resource does not build from", func() {
+		code, msg := exitCode(imageName, "--revision", "release")
+		require.Equal(t, 1, code)
+		require.Equal(t, `revision "release" is not a commit sha of at least seven characters nor the git revision of image resource "some-image"`, msg)
+	})
+
+	it("waits on the latest build when there are no conditions", func() {
+		testhelpers.CommandTest{
+			Objects:             objects,
+			Args:                []string{"wait", imageName},
+			ExpectedOutput:      "some-registry.io/some-repo@sha256:build-3\n",
+			ExpectedErrorOutput: "Waiting for a build of image resource \"some-image\"...\nBuild 3 succeeded\n",
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("matches builds on every condition", func() {
+		testhelpers.CommandTest{
+			Objects:        objects,
+			Args:           []string{"wait", imageName, "--build-after", "0", "--started-after", "2024-01-01T12:30:00Z", "--revision", "3333333ccccccc"},
+			ExpectedOutput: "some-registry.io/some-repo@sha256:build-3\n",
+			ExpectedErrorOutput: "Waiting for a build of image resource \"some-image\" with revision \"3333333ccccccc\" and build number greater than 0 " +
+				"and started after 2024-01-01T12:30:00Z...\nBuild 3 succeeded\n",
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("exits with 0 when the build succeeded", func() {
+		code, _ := exitCode(imageName, "--build-after", "2")
+		require.Equal(t, 0, code)
+	})
+
+	it("exits with 2 when the build failed", func() {
+		code, msg := exitCode(imageName, "--revision", "2222222")
+		require.Equal(t, image.WaitBuildFailedExitCode, code)
+		require.Equal(t, `build 2 of image resource "some-image" failed`, msg)
+	})
+
+	it("exits with 3 when no build matches in time", func() {
+		code, msg := exitCode(imageName, "--build-after", "3", "--wait-timeout", "50ms")
+		require.Equal(t, image.WaitTimeoutExitCode, code)
+		require.Equal(t, `timed out after 50ms waiting for a build of image resource "some-image"`, msg)
+	})
+
+	it("exits with 1 when the image does not exist", func() {
+		code, msg := exitCode("missing-image")
+		require.Equal(t, 1, code)
+		require.Equal(t, `images.kpack.io "missing-image" not found`, msg)
+	})
+
+	it("exits with 1 for an invalid timestamp", func() {
+		code, msg := exitCode(imageName, "--started-after", "yesterday")
+		require.Equal(t, 1, code)
+		require.Equal(t, `invalid --started-after "yesterday", it must be an RFC3339 timestamp`, msg)
+	})
+}
@@ -0,0 +1,93 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	"github.com/spf13/cobra"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/build"
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+func NewWatchCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+	var (
+		namespace     string
+		allNamespaces bool
+		plain         bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "watch [name]",
+		Short: "Watch image resources and their builds",
+		Long: `Shows a live table of the image resources in the provided namespace along with their latest build.
+Running builds show their current step and elapsed time, which makes it easy to follow a stack or buildpack rollout.
+
+With --plain, or when the output is not a terminal, one line is printed each time a build starts, moves to the next step or finishes, which is suited to CI logs.
+
+The command runs until interrupted.
+
+The namespace defaults to the kubernetes current-context namespace.`,
+		Example:      "kp image watch\nkp image watch my-image\nkp image watch -A\nkp image watch -A --plain",
+		Args:         commands.OptionalArgsWithUsage(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cs, err := clientSetProvider.GetClientSet(namespace)
+			if err != nil {
+				return err
+			}
+
+			watchNamespace := cs.Namespace
+			if allNamespaces {
+				watchNamespace = ""
+			}
+
+			var name string
+			if len(args) > 0 {
+				name = args[0]
+			}
+
+			watcher := build.NewWatcher(cs.DynamicClient, watchNamespace, name)
+			live := !plain && commands.IsTerminal(cmd.OutOrStdout())
+
+			return commands.Watch(cmd.Context(), watcher, cmd.OutOrStdout(), live, commands.WatchTable{
+				Headers: []string{"Name", "Ready", "Build", "Status", "Step", "Elapsed", "Reason", "Namespace"},
+				Rows: func(now time.Time) [][]string {
+					var rows [][]string
+					for _, img := range watcher.Images() {
+						rows = append(rows, imageWatchRow(img, watcher, now))
+					}
+					return rows
+				},
+				Empty: "No image resources found",
+			})
+		},
+	}
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
+	cmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "Watch image resources in all namespaces")
+	cmd.Flags().BoolVar(&plain, "plain", false, "print a line per build transition instead of a live table")
+	return cmd
+}
+
+func imageWatchRow(img v1alpha2.Image, watcher *build.Watcher, now time.Time) []string {
+	bld, ok := watcher.LatestBuild(img)
+	if !ok {
+		return []string{img.Name, getReadyText(img), "", "", "", "", "", img.Namespace}
+	}
+
+	return []string{
+		img.Name,
+		getReadyText(img),
+		bld.Labels[v1alpha2.BuildNumberLabel],
+		build.Status(bld),
+		build.CurrentStep(bld),
+		build.Elapsed(bld, now).String(),
+		strings.Join(build.Reasons(bld), ","),
+		img.Namespace,
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands/image"
+	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
+)
+
+func TestImageWatchCommand(t *testing.T) {
+	spec.Run(t, "TestImageWatchCommand", testImageWatchCommand)
+}
+
+func testImageWatchCommand(t *testing.T, when spec.G, it spec.S) {
+	const namespace = "some-namespace"
+
+	makeBuild := func(image, number string, status corev1.ConditionStatus) *v1alpha2.Build {
+		return &v1alpha2.Build{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      image + "-build-" + number,
+				Namespace: namespace,
+				Labels: map[string]string{
+					v1alpha2.ImageLabel:       image,
+					v1alpha2.BuildNumberLabel: number,
+				},
+				Annotations: map[string]string{
+					v1alpha2.BuildReasonAnnotation: "STACK",
+				},
+			},
+			Status: v1alpha2.BuildStatus{
+				Status: corev1alpha1.Status{
+					Conditions: corev1alpha1.Conditions{
+						{Type: corev1alpha1.ConditionSucceeded, Status: status},
+					},
+				},
+				StepStates:     make([]corev1.ContainerState, 2),
+				StepsCompleted: []string{"rebase"},
+			},
+		}
+	}
+
+	it("prints the transitions of the builds of the watched image", func() {
+		dynamicClient := testhelpers.NewFakeDynamicClient(
+			&v1alpha2.Image{ObjectMeta: metav1.ObjectMeta{Name: "some-image", Namespace: namespace}},
+			&v1alpha2.Image{ObjectMeta: metav1.ObjectMeta{Name: "other-image", Namespace: namespace}},
+			makeBuild("some-image", "1", corev1.ConditionUnknown),
+			makeBuild("other-image", "1", corev1.ConditionUnknown),
+		)
+
+		out := &testhelpers.SyncBuffer{}
+		cmd := image.NewWatchCommand(testhelpers.GetFakeDynamicProvider(dynamicClient, namespace))
+		cmd.SetArgs([]string{"some-image", "--plain"})
+		cmd.SetOut(out)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- cmd.ExecuteContext(ctx) }()
+
+		require.Eventually(t, func() bool {
+			return out.String() == "some-namespace/some-image build 1: BUILDING (step: completion, reason: STACK)\n"
+		}, 5*time.Second, 10*time.Millisecond, out.String())
+
+		cancel()
+		require.NoError(t, <-done)
+	})
+}
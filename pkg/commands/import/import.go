@@ -4,9 +4,13 @@
 package _import
 
 import (
+	"encoding/json"
 	"io"
 	"os"
+	"strings"
 
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/dynamic"
@@ -35,6 +39,10 @@ func NewImportCommand(
 		filename    string
 		showChanges bool
 		force       bool
+		plan        bool
+		prune       bool
+		applyPlan   string
+		resume      bool
 		tlsConfig   registry.TLSConfig
 	)
 
@@ -50,15 +58,52 @@ func NewImportCommand(
 
 	cmd := &cobra.Command{
 		Use:   "import -f <filename>",
-		Short: "Import dependencies for stores, stacks, and cluster builders",
-		Long: `This operation will create or update clusterstores, clusterstacks, and clusterbuilders defined in the dependency descriptor.
+		Short: "Import dependencies for stores, buildpacks, stacks, and builders",
+		Long: `This operation will create or update clusterstores, clusterbuildpacks, buildpacks, clusterstacks, clusterbuilders,
+and builders defined in the dependency descriptor.
+
+Dependency descriptors with apiVersion kp.kpack.io/v1alpha4 may declare namespaced "buildpacks" and "builders", and
+"clusterBuildpacks" next to the clusterstores. Builders may set a "serviceAccountRef" (cluster builders) or a
+"serviceAccountName" (builders) and every resource may set a "repository" that its images are relocated to instead of
+the repository of the kp-config. Descriptors with apiVersion kp.kpack.io/v1alpha1 and kp.kpack.io/v1alpha3 are still supported.
+
+The lifecycle, buildpackage and stack images are relocated to the "lifecycle.repository", "buildpackage.repository" and
+"stack.repository" keys of the "kp-config" ConfigMap within "kpack" namespace when they are set, and to the default repository
+otherwise. Use "kp config repository" to set them.
 
 kp import will always attempt to upload the stack, store, and builder images, even if the resources have not changed.
 This can be used as a way to repair resources when registry images have been unexpectedly removed.
 
+The lifecycle, buildpackage and stack images of the dependency descriptor may declare an expected "digest" and a PEM encoded
+cosign "publicKey" next to their "image". Every source image that declares them is verified before anything is relocated
+and the import fails with a report of all source images that did not match.
+
+When a lock file created with "kp import lock" is next to the dependency descriptor, such as dependencies.lock.yaml
+for dependencies.yaml, the images are imported at the digests pinned in the lock file instead of the digests their tags
+currently resolve to. The import fails when the lock file does not pin every image of the descriptor that has no digest.
+
+Use "kp import validate" to check a dependency descriptor for unknown fields and unresolved references before importing it.
+
+Use --plan with --output json or yaml to print the import plan instead of importing. The plan lists every resource that
+would be created, updated, left unchanged or, with --prune, deleted because it was imported before but is no longer in the
+dependency descriptor. Each resource lists its source and relocated images with the bytes to upload and the fields that
+would change. No images are uploaded and no resources are changed while planning.
+
+Use --apply-plan to import exactly the resources of a plan. The import fails before anything is uploaded or changed
+when a resource of the plan was changed in the cluster after the plan was made, or when a source image no longer
+has the digest it had when the plan was made.
+
+Every import records the resources it saves or prunes together with their previous annotations and spec in the
+kp-import-journal config map in the kpack namespace. When an import fails, use --resume to import the same dependency
+descriptor again without saving the resources that were already saved, or use "kp import rollback" to restore the
+resources to what they were before the import. A new import cannot start until the failed import is resumed or rolled back.
+
 Env vars can be used for registry auth as described in https://github.com/vmware-tanzu/kpack-cli/blob/main/docs/auth.md`,
 		Example: `kp import -f dependencies.yaml
-cat dependencies.yaml | kp import -f -`,
+cat dependencies.yaml | kp import -f -
+kp import -f dependencies.yaml --plan --prune --output json > plan.json
+kp import --apply-plan plan.json
+kp import -f dependencies.yaml --resume`,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cs, err := clientSetProvider.GetClientSet("")
@@ -71,12 +116,34 @@ cat dependencies.yaml | kp import -f -`,
 				return err
 			}
 
+			output, err := commands.GetStringFlag(commands.OutputFlag, cmd)
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case applyPlan != "" && (filename != "" || plan):
+				return errors.New("--apply-plan cannot be used with --filename or --plan")
+			case applyPlan != "" && ch.IsDryRun():
+				return errors.New("--apply-plan cannot be used with --dry-run")
+			case applyPlan == "" && filename == "":
+				return errors.New("--filename or --apply-plan is required")
+			case plan && output != "json" && output != "yaml":
+				return errors.New("--plan requires --output json or yaml")
+			case plan && showChanges:
+				return errors.New("--plan cannot be used with --show-changes")
+			case prune && !plan:
+				return errors.New("--prune can only be used with --plan")
+			case resume && (plan || applyPlan != "" || ch.IsDryRun()):
+				return errors.New("--resume cannot be used with --plan, --apply-plan or --dry-run")
+			}
+
 			ctx := cmd.Context()
 
 			kpConfig := config.NewKpConfigProvider(cs.K8sClient).GetKpConfig(ctx)
 
 			imgFetcher := rup.Fetcher(tlsConfig)
-			imgRelocator := rup.Relocator(ch.Writer(), tlsConfig, ch.CanChangeState())
+			imgRelocator := rup.Relocator(ch.Writer(), tlsConfig, ch.CanChangeState() && !plan)
 
 			importer := importpkg.NewImporter(
 				ch,
@@ -88,6 +155,31 @@ cat dependencies.yaml | kp import -f -`,
 				timestampProvider,
 			)
 
+			keychain := dockercreds.DefaultKeychain
+
+			if applyPlan != "" {
+				rawPlan, err := readDescriptor(cmd, applyPlan)
+				if err != nil {
+					return err
+				}
+
+				importPlan, err := importpkg.ReadPlan(rawPlan)
+				if err != nil {
+					return err
+				}
+
+				objs, err := importer.ApplyPlan(ctx, keychain, importPlan)
+				if err != nil {
+					return err
+				}
+
+				if err := ch.PrintObjs(objs); err != nil {
+					return err
+				}
+
+				return ch.PrintResult("Imported resources")
+			}
+
 			rawDescriptor, err := readDescriptor(cmd, filename)
 			if err != nil {
 				return err
@@ -98,7 +190,33 @@ cat dependencies.yaml | kp import -f -`,
 				return err
 			}
 
-			keychain := dockercreds.DefaultKeychain
+			if filename != "-" {
+				lockPath := importpkg.LockPath(filename)
+				lock, found, err := importpkg.ReadLock(lockPath)
+				if err != nil {
+					return err
+				}
+
+				if found {
+					if err := ch.PrintStatus("Using lock file %s", lockPath); err != nil {
+						return err
+					}
+					var unlocked []string
+					descriptor, unlocked = descriptor.ApplyLock(lock)
+					if len(unlocked) > 0 {
+						return errors.Errorf("lock file %s is out of date, it does not pin %s, run \"kp import lock -f %s\" to update it", lockPath, strings.Join(unlocked, ", "), filename)
+					}
+				}
+			}
+
+			if plan {
+				importPlan, err := importer.Plan(ctx, keychain, kpConfig, descriptor, prune)
+				if err != nil {
+					return err
+				}
+
+				return printPlan(cmd.OutOrStdout(), output, importPlan)
+			}
 
 			if showChanges {
 				hasChanges, summary, err := importpkg.SummarizeChange(ctx, keychain, descriptor, kpConfig, importpkg.NewDefaultRelocatedImageProvider(imgFetcher), differ, cs)
@@ -129,7 +247,17 @@ cat dependencies.yaml | kp import -f -`,
 					ctx,
 					keychain,
 					kpConfig,
-					rawDescriptor,
+					descriptor,
+				)
+				if err != nil {
+					return err
+				}
+			} else if resume {
+				objs, err = importer.ResumeImportDescriptor(
+					ctx,
+					keychain,
+					kpConfig,
+					descriptor,
 				)
 				if err != nil {
 					return err
@@ -139,7 +267,7 @@ cat dependencies.yaml | kp import -f -`,
 					ctx,
 					keychain,
 					kpConfig,
-					rawDescriptor,
+					descriptor,
 				)
 				if err != nil {
 					return err
@@ -156,12 +284,34 @@ cat dependencies.yaml | kp import -f -`,
 	cmd.Flags().StringVarP(&filename, "filename", "f", "", "dependency descriptor filename")
 	cmd.Flags().BoolVar(&showChanges, "show-changes", false, "show a summary of resource changes before importing")
 	cmd.Flags().BoolVar(&force, "force", false, "import without confirmation when showing changes")
+	cmd.Flags().BoolVar(&plan, "plan", false, "print the import plan instead of importing, requires --output json or yaml")
+	cmd.Flags().BoolVar(&prune, "prune", false, "plan to delete imported resources that are no longer in the dependency descriptor")
+	cmd.Flags().StringVar(&applyPlan, "apply-plan", "", "import plan filename to apply instead of a dependency descriptor")
+	cmd.Flags().BoolVar(&resume, "resume", false, "resume the failed import of the dependency descriptor")
 	commands.SetImgUploadDryRunOutputFlags(cmd)
 	commands.SetTLSFlags(cmd, &tlsConfig)
-	_ = cmd.MarkFlagRequired("filename")
 	return cmd
 }
 
+func printPlan(writer io.Writer, output string, plan importpkg.ImportPlan) error {
+	var (
+		buf []byte
+		err error
+	)
+	if output == "json" {
+		buf, err = json.MarshalIndent(plan, "", "  ")
+		buf = append(buf, '\n')
+	} else {
+		buf, err = yaml.Marshal(plan)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(buf)
+	return err
+}
+
 func readDescriptor(cmd *cobra.Command, filename string) (string, error) {
 	var (
 		reader io.ReadCloser
@@ -4,18 +4,22 @@
 package _import_test
 
 import (
+	"fmt"
 	"io/ioutil"
+	"path/filepath"
 	"testing"
 
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
 	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
 	kpackfakes "github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/pkg/errors"
 	"github.com/sclevine/spec"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	commandsfakes "github.com/vmware-tanzu/kpack-cli/pkg/commands/fakes"
 	importcmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/import"
+	importpkg "github.com/vmware-tanzu/kpack-cli/pkg/import"
 	registryfakes "github.com/vmware-tanzu/kpack-cli/pkg/registry/fakes"
 	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
 	corev1 "k8s.io/api/core/v1"
@@ -23,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/dynamic"
 	k8sfakes "k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
 )
 
 func TestImportCommand(t *testing.T) {
@@ -244,6 +249,42 @@ func testImportCommand(t *testing.T, when spec.G, it spec.S) {
 		)
 	}
 
+	journal := func(digest, status, resources string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      importpkg.JournalName,
+				Namespace: importpkg.JournalNamespace,
+			},
+			Data: map[string]string{
+				"status":    status,
+				"timestamp": timestampProvider.timestamp,
+				"digest":    digest,
+				"resources": resources,
+			},
+		}
+	}
+
+	importJournal := func(descriptorPath, status, resources string) *corev1.ConfigMap {
+		rawDescriptor, err := ioutil.ReadFile(descriptorPath)
+		require.NoError(t, err)
+
+		descriptor, err := importpkg.ReadDescriptor(string(rawDescriptor))
+		require.NoError(t, err)
+
+		digest, err := descriptor.Digest()
+		require.NoError(t, err)
+
+		return journal(digest, status, resources)
+	}
+
+	const (
+		depsJournal            = `[{"kind":"ConfigMap","name":"lifecycle-image","namespace":"kpack","previous":{}},{"kind":"ClusterStore","name":"store-name"},{"kind":"ClusterStack","name":"stack-name"},{"kind":"ClusterStack","name":"default"},{"kind":"ClusterBuilder","name":"clusterbuilder-name"},{"kind":"ClusterBuilder","name":"default"}]`
+		v1DepsJournal          = `[{"kind":"ClusterStore","name":"store-name"},{"kind":"ClusterStack","name":"stack-name"},{"kind":"ClusterStack","name":"default"},{"kind":"ClusterBuilder","name":"clusterbuilder-name"},{"kind":"ClusterBuilder","name":"default"}]`
+		existingDepsJournal    = `[{"kind":"ConfigMap","name":"lifecycle-image","namespace":"kpack","previous":{}},{"kind":"ClusterStore","name":"store-name","previous":{"annotations":{"kpack.io/import-timestamp":"2006-01-02T15:04:05Z","kubectl.kubernetes.io/last-applied-configuration":"{\"kind\":\"ClusterStore\",\"apiVersion\":\"kpack.io/v1alpha2\",\"metadata\":{\"name\":\"store-name\",\"creationTimestamp\":null},\"spec\":{\"sources\":[{\"image\":\"default-registry.io/default-repo@sha256:buildpack-image-digest\"}],\"serviceAccountRef\":{\"namespace\":\"some-namespace\",\"name\":\"some-serviceaccount\"}},\"status\":{}}"},"spec":{"serviceAccountRef":{"name":"some-serviceaccount","namespace":"some-namespace"},"sources":[{"image":"default-registry.io/default-repo@sha256:buildpack-image-digest"}]}}},{"kind":"ClusterStack","name":"stack-name","previous":{"annotations":{"kpack.io/import-timestamp":"2006-01-02T15:04:05Z"},"spec":{"buildImage":{"image":"some-uploaded-build-image@build-image-digest"},"id":"stack-id","runImage":{"image":"some-uploaded-run-image@build-image-digest"},"serviceAccountRef":{"name":"some-serviceaccount","namespace":"some-namespace"}}}},{"kind":"ClusterStack","name":"default","previous":{"annotations":{"kpack.io/import-timestamp":"2006-01-02T15:04:05Z"},"spec":{"buildImage":{"image":"some-uploaded-build-image@build-image-digest"},"id":"stack-id","runImage":{"image":"some-uploaded-run-image@build-image-digest"},"serviceAccountRef":{"name":"some-serviceaccount","namespace":"some-namespace"}}}},{"kind":"ClusterBuilder","name":"clusterbuilder-name","previous":{"annotations":{"kpack.io/import-timestamp":"2006-01-02T15:04:05Z"},"spec":{"order":[{"group":[{"id":"buildpack-id"}]}],"serviceAccountRef":{"name":"some-serviceaccount","namespace":"some-namespace"},"stack":{"kind":"ClusterStack","name":"stack-name"},"store":{"kind":"ClusterStore","name":"store-name"},"tag":"default-registry.io/default-repo:clusterbuilder-clusterbuilder-name"}}},{"kind":"ClusterBuilder","name":"default","previous":{"annotations":{"kpack.io/import-timestamp":"2006-01-02T15:04:05Z"},"spec":{"order":[{"group":[{"id":"buildpack-id"}]}],"serviceAccountRef":{"name":"some-serviceaccount","namespace":"some-namespace"},"stack":{"kind":"ClusterStack","name":"stack-name"},"store":{"kind":"ClusterStore","name":"store-name"},"tag":"default-registry.io/default-repo:clusterbuilder-default"}}}]`
+		unannotatedDepsJournal = `[{"kind":"ConfigMap","name":"lifecycle-image","namespace":"kpack","previous":{}},{"kind":"ClusterStore","name":"store-name","previous":{"spec":{"serviceAccountRef":{"name":"some-serviceaccount","namespace":"some-namespace"},"sources":[{"image":"default-registry.io/default-repo@sha256:buildpack-image-digest"}]}}},{"kind":"ClusterStack","name":"stack-name","previous":{"spec":{"buildImage":{"image":"default-registry.io/default-repo@sha256:build-image-digest"},"id":"stack-id","runImage":{"image":"default-registry.io/default-repo@sha256:build-image-digest"},"serviceAccountRef":{"name":"some-serviceaccount","namespace":"some-namespace"}}}},{"kind":"ClusterStack","name":"default","previous":{"spec":{"buildImage":{"image":"default-registry.io/default-repo@sha256:build-image-digest"},"id":"stack-id","runImage":{"image":"default-registry.io/default-repo@sha256:build-image-digest"},"serviceAccountRef":{"name":"some-serviceaccount","namespace":"some-namespace"}}}},{"kind":"ClusterBuilder","name":"clusterbuilder-name","previous":{"spec":{"order":[{"group":[{"id":"buildpack-id"}]}],"serviceAccountRef":{"name":"some-serviceaccount","namespace":"some-namespace"},"stack":{"kind":"ClusterStack","name":"stack-name"},"store":{"kind":"ClusterStore","name":"store-name"},"tag":"default-registry.io/default-repo:clusterbuilder-clusterbuilder-name"}}},{"kind":"ClusterBuilder","name":"default","previous":{"spec":{"order":[{"group":[{"id":"buildpack-id"}]}],"serviceAccountRef":{"name":"some-serviceaccount","namespace":"some-namespace"},"stack":{"kind":"ClusterStack","name":"stack-name"},"store":{"kind":"ClusterStore","name":"store-name"},"tag":"default-registry.io/default-repo:clusterbuilder-default"}}}]`
+		updatedDepsJournal     = `[{"kind":"ConfigMap","name":"lifecycle-image","namespace":"kpack","previous":{}},{"kind":"ClusterStore","name":"store-name","previous":{"annotations":{"kpack.io/import-timestamp":"2006-01-02T15:04:05Z","kubectl.kubernetes.io/last-applied-configuration":"{\"kind\":\"ClusterStore\",\"apiVersion\":\"kpack.io/v1alpha2\",\"metadata\":{\"name\":\"store-name\",\"creationTimestamp\":null},\"spec\":{\"sources\":[{\"image\":\"default-registry.io/default-repo@sha256:buildpack-image-digest\"}],\"serviceAccountRef\":{\"namespace\":\"some-namespace\",\"name\":\"some-serviceaccount\"}},\"status\":{}}"},"spec":{"serviceAccountRef":{"name":"some-serviceaccount","namespace":"some-namespace"},"sources":[{"image":"default-registry.io/default-repo@sha256:buildpack-image-digest"}]}}},{"kind":"ClusterStack","name":"stack-name","previous":{"annotations":{"kpack.io/import-timestamp":"2006-01-02T15:04:05Z"},"spec":{"buildImage":{"image":"default-registry.io/default-repo@sha256:build-image-digest"},"id":"stack-id","runImage":{"image":"default-registry.io/default-repo@sha256:build-image-digest"},"serviceAccountRef":{"name":"some-serviceaccount","namespace":"some-namespace"}}}},{"kind":"ClusterStack","name":"default","previous":{"annotations":{"kpack.io/import-timestamp":"2006-01-02T15:04:05Z"},"spec":{"buildImage":{"image":"default-registry.io/default-repo@sha256:build-image-digest"},"id":"stack-id","runImage":{"image":"default-registry.io/default-repo@sha256:build-image-digest"},"serviceAccountRef":{"name":"some-serviceaccount","namespace":"some-namespace"}}}},{"kind":"ClusterBuilder","name":"clusterbuilder-name","previous":{"annotations":{"kpack.io/import-timestamp":"2006-01-02T15:04:05Z"},"spec":{"order":[{"group":[{"id":"buildpack-id"}]}],"serviceAccountRef":{"name":"some-serviceaccount","namespace":"some-namespace"},"stack":{"kind":"ClusterStack","name":"stack-name"},"store":{"kind":"ClusterStore","name":"store-name"},"tag":"default-registry.io/default-repo:clusterbuilder-clusterbuilder-name"}}},{"kind":"ClusterBuilder","name":"default","previous":{"annotations":{"kpack.io/import-timestamp":"2006-01-02T15:04:05Z"},"spec":{"order":[{"group":[{"id":"buildpack-id"}]}],"serviceAccountRef":{"name":"some-serviceaccount","namespace":"some-namespace"},"stack":{"kind":"ClusterStack","name":"stack-name"},"store":{"kind":"ClusterStore","name":"store-name"},"tag":"default-registry.io/default-repo:clusterbuilder-default"}}}]`
+	)
+
 	it.Before(func() {
 		fakeConfirmationProvider = commandsfakes.NewFakeConfirmationProvider(true, nil)
 	})
@@ -277,9 +318,12 @@ Uploading to 'default-registry.io/default-repo'...
 	Uploading 'default-registry.io/default-repo@sha256:build-image-digest'
 Importing ClusterBuilder 'clusterbuilder-name'...
 Importing ClusterBuilder 'default'...
+Ready: ClusterStore 'store-name', ClusterStack 'stack-name', ClusterStack 'default'
+Ready: ClusterBuilder 'clusterbuilder-name', ClusterBuilder 'default'
 Imported resources
 `,
 				ExpectCreates: []runtime.Object{
+					importJournal("./testdata/deps.yaml", importpkg.JournalInProgress, depsJournal),
 					store,
 					stack,
 					defaultStack,
@@ -289,12 +333,60 @@ Imported resources
 				ExpectPatches: []string{
 					`{"data":{"image":"default-registry.io/default-repo@sha256:lifecycle-image-digest"},"metadata":{"annotations":{"kpack.io/import-timestamp":"2006-01-02T15:04:05Z"}}}`,
 				},
+				ExpectUpdates: []clientgotesting.UpdateActionImpl{
+					{Object: importJournal("./testdata/deps.yaml", importpkg.JournalCompleted, depsJournal)},
+				},
 			}.TestK8sAndKpack(t, cmdFunc)
 			require.Len(t, fakeWaiter.WaitCalls, 5)
 			require.Len(t, fakeWaiter.WaitCalls[3].ExtraChecks, 1) // ClusterBuilder has extra check
 			require.Len(t, fakeWaiter.WaitCalls[4].ExtraChecks, 1) // ClusterBuilder has extra check
 		})
 
+		it("reports the resources that did not become ready and does not save builders", func() {
+			fakeWaiter.Errors = map[string]error{
+				"default": errors.New("some-stack-condition-message"),
+			}
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					kpConfig,
+					lifecycleImageConfig,
+				},
+				Args: []string{
+					"-f", "./testdata/deps.yaml",
+				},
+				ExpectErr: true,
+				ExpectedOutput: `Importing Lifecycle...
+	Uploading 'default-registry.io/default-repo@sha256:lifecycle-image-digest'
+Importing ClusterStore 'store-name'...
+	Uploading 'default-registry.io/default-repo@sha256:buildpack-image-digest'
+Importing ClusterStack 'stack-name'...
+Uploading to 'default-registry.io/default-repo'...
+	Uploading 'default-registry.io/default-repo@sha256:build-image-digest'
+	Uploading 'default-registry.io/default-repo@sha256:build-image-digest'
+Importing ClusterStack 'default'...
+Uploading to 'default-registry.io/default-repo'...
+	Uploading 'default-registry.io/default-repo@sha256:build-image-digest'
+	Uploading 'default-registry.io/default-repo@sha256:build-image-digest'
+Importing ClusterBuilder 'clusterbuilder-name'...
+Importing ClusterBuilder 'default'...
+Ready: ClusterStore 'store-name', ClusterStack 'stack-name'
+Failed: ClusterStack 'default': some-stack-condition-message
+`,
+				ExpectedErrorOutput: "Error: 1 of 3 resources did not become ready\n",
+				ExpectCreates: []runtime.Object{
+					importJournal("./testdata/deps.yaml", importpkg.JournalInProgress, depsJournal),
+					store,
+					stack,
+					defaultStack,
+				},
+				ExpectPatches: []string{
+					`{"data":{"image":"default-registry.io/default-repo@sha256:lifecycle-image-digest"},"metadata":{"annotations":{"kpack.io/import-timestamp":"2006-01-02T15:04:05Z"}}}`,
+				},
+			}.TestK8sAndKpack(t, cmdFunc)
+			require.Len(t, fakeWaiter.WaitCalls, 3)
+		})
+
 		it("creates stores, stacks, and cbs defined in the dependency descriptor provided by stdin", func() {
 			builder.Annotations["kubectl.kubernetes.io/last-applied-configuration"] = `{"kind":"ClusterBuilder","apiVersion":"kpack.io/v1alpha2","metadata":{"name":"clusterbuilder-name","creationTimestamp":null},"spec":{"tag":"default-registry.io/default-repo:clusterbuilder-clusterbuilder-name","stack":{"kind":"ClusterStack","name":"stack-name"},"store":{"kind":"ClusterStore","name":"store-name"},"order":[{"group":[{"id":"buildpack-id"}]}],"serviceAccountRef":{"namespace":"some-namespace","name":"some-serviceaccount"}},"status":{"stack":{}}}`
 			defaultBuilder.Annotations["kubectl.kubernetes.io/last-applied-configuration"] = `{"kind":"ClusterBuilder","apiVersion":"kpack.io/v1alpha2","metadata":{"name":"default","creationTimestamp":null},"spec":{"tag":"default-registry.io/default-repo:clusterbuilder-default","stack":{"kind":"ClusterStack","name":"stack-name"},"store":{"kind":"ClusterStore","name":"store-name"},"order":[{"group":[{"id":"buildpack-id"}]}],"serviceAccountRef":{"namespace":"some-namespace","name":"some-serviceaccount"}},"status":{"stack":{}}}`
@@ -327,9 +419,12 @@ Uploading to 'default-registry.io/default-repo'...
 	Uploading 'default-registry.io/default-repo@sha256:build-image-digest'
 Importing ClusterBuilder 'clusterbuilder-name'...
 Importing ClusterBuilder 'default'...
+Ready: ClusterStore 'store-name', ClusterStack 'stack-name', ClusterStack 'default'
+Ready: ClusterBuilder 'clusterbuilder-name', ClusterBuilder 'default'
 Imported resources
 `,
 				ExpectCreates: []runtime.Object{
+					importJournal("./testdata/deps.yaml", importpkg.JournalInProgress, depsJournal),
 					store,
 					stack,
 					defaultStack,
@@ -339,6 +434,9 @@ Imported resources
 				ExpectPatches: []string{
 					`{"data":{"image":"default-registry.io/default-repo@sha256:lifecycle-image-digest"},"metadata":{"annotations":{"kpack.io/import-timestamp":"2006-01-02T15:04:05Z"}}}`,
 				},
+				ExpectUpdates: []clientgotesting.UpdateActionImpl{
+					{Object: importJournal("./testdata/deps.yaml", importpkg.JournalCompleted, depsJournal)},
+				},
 			}.TestK8sAndKpack(t, cmdFunc)
 			require.Len(t, fakeWaiter.WaitCalls, 5)
 			require.Len(t, fakeWaiter.WaitCalls[3].ExtraChecks, 1) // ClusterBuilder has extra check
@@ -371,15 +469,21 @@ Uploading to 'default-registry.io/default-repo'...
 	Uploading 'default-registry.io/default-repo@sha256:build-image-digest'
 Importing ClusterBuilder 'clusterbuilder-name'...
 Importing ClusterBuilder 'default'...
+Ready: ClusterStore 'store-name', ClusterStack 'stack-name', ClusterStack 'default'
+Ready: ClusterBuilder 'clusterbuilder-name', ClusterBuilder 'default'
 Imported resources
 `,
 				ExpectCreates: []runtime.Object{
+					importJournal("./testdata/v1-deps.yaml", importpkg.JournalInProgress, v1DepsJournal),
 					store,
 					stack,
 					defaultStack,
 					builder,
 					defaultBuilder,
 				},
+				ExpectUpdates: []clientgotesting.UpdateActionImpl{
+					{Object: importJournal("./testdata/v1-deps.yaml", importpkg.JournalCompleted, v1DepsJournal)},
+				},
 			}.TestK8sAndKpack(t, cmdFunc)
 		})
 
@@ -434,9 +538,12 @@ Uploading to 'default-registry.io/default-repo'...
 	Uploading 'default-registry.io/default-repo@sha256:build-image-digest'
 Importing ClusterBuilder 'clusterbuilder-name'...
 Importing ClusterBuilder 'default'...
+Ready: ClusterStore 'store-name', ClusterStack 'stack-name', ClusterStack 'default'
+Ready: ClusterBuilder 'clusterbuilder-name', ClusterBuilder 'default'
 Imported resources
 `,
 					ExpectCreates: []runtime.Object{
+						importJournal("./testdata/deps.yaml", importpkg.JournalInProgress, depsJournal),
 						store,
 						stack,
 						defaultStack,
@@ -446,6 +553,9 @@ Imported resources
 					ExpectPatches: []string{
 						`{"data":{"image":"default-registry.io/default-repo@sha256:lifecycle-image-digest"},"metadata":{"annotations":{"kpack.io/import-timestamp":"2006-01-02T15:04:05Z"}}}`,
 					},
+					ExpectUpdates: []clientgotesting.UpdateActionImpl{
+						{Object: importJournal("./testdata/deps.yaml", importpkg.JournalCompleted, depsJournal)},
+					},
 				}.TestK8sAndKpack(t, cmdFunc)
 				require.NoError(t, fakeConfirmationProvider.WasRequestedWithMsg("Confirm with y:"))
 			})
@@ -501,9 +611,12 @@ Uploading to 'default-registry.io/default-repo'...
 	Uploading 'default-registry.io/default-repo@sha256:build-image-digest'
 Importing ClusterBuilder 'clusterbuilder-name'...
 Importing ClusterBuilder 'default'...
+Ready: ClusterStore 'store-name', ClusterStack 'stack-name', ClusterStack 'default'
+Ready: ClusterBuilder 'clusterbuilder-name', ClusterBuilder 'default'
 Imported resources
 `,
 					ExpectCreates: []runtime.Object{
+						importJournal("./testdata/deps.yaml", importpkg.JournalInProgress, depsJournal),
 						store,
 						stack,
 						defaultStack,
@@ -513,6 +626,9 @@ Imported resources
 					ExpectPatches: []string{
 						`{"data":{"image":"default-registry.io/default-repo@sha256:lifecycle-image-digest"},"metadata":{"annotations":{"kpack.io/import-timestamp":"2006-01-02T15:04:05Z"}}}`,
 					},
+					ExpectUpdates: []clientgotesting.UpdateActionImpl{
+						{Object: importJournal("./testdata/deps.yaml", importpkg.JournalCompleted, depsJournal)},
+					},
 				}.TestK8sAndKpack(t, cmdFunc)
 				require.Equal(t, false, fakeConfirmationProvider.WasRequested())
 			})
@@ -583,6 +699,8 @@ Uploading to 'default-registry.io/default-repo'...
 	Uploading 'default-registry.io/default-repo@sha256:build-image-digest'
 Importing ClusterBuilder 'clusterbuilder-name'...
 Importing ClusterBuilder 'default'...
+Ready: ClusterStore 'store-name', ClusterStack 'stack-name', ClusterStack 'default'
+Ready: ClusterBuilder 'clusterbuilder-name', ClusterBuilder 'default'
 Imported resources
 `,
 					ExpectPatches: []string{
@@ -592,6 +710,12 @@ Imported resources
 						`{"metadata":{"annotations":{"kpack.io/import-timestamp":"new-timestamp","kubectl.kubernetes.io/last-applied-configuration":"{\"kind\":\"ClusterBuilder\",\"apiVersion\":\"kpack.io/v1alpha2\",\"metadata\":{\"name\":\"clusterbuilder-name\",\"creationTimestamp\":null},\"spec\":{\"tag\":\"default-registry.io/default-repo:clusterbuilder-clusterbuilder-name\",\"stack\":{\"kind\":\"ClusterStack\",\"name\":\"stack-name\"},\"store\":{\"kind\":\"ClusterStore\",\"name\":\"store-name\"},\"order\":[{\"group\":[{\"id\":\"buildpack-id\"}]}],\"serviceAccountRef\":{\"namespace\":\"some-namespace\",\"name\":\"some-serviceaccount\"}},\"status\":{\"stack\":{}}}"}}}`,
 						`{"metadata":{"annotations":{"kpack.io/import-timestamp":"new-timestamp","kubectl.kubernetes.io/last-applied-configuration":"{\"kind\":\"ClusterBuilder\",\"apiVersion\":\"kpack.io/v1alpha2\",\"metadata\":{\"name\":\"default\",\"creationTimestamp\":null},\"spec\":{\"tag\":\"default-registry.io/default-repo:clusterbuilder-default\",\"stack\":{\"kind\":\"ClusterStack\",\"name\":\"stack-name\"},\"store\":{\"kind\":\"ClusterStore\",\"name\":\"store-name\"},\"order\":[{\"group\":[{\"id\":\"buildpack-id\"}]}],\"serviceAccountRef\":{\"namespace\":\"some-namespace\",\"name\":\"some-serviceaccount\"}},\"status\":{\"stack\":{}}}"}}}`,
 					},
+					ExpectCreates: []runtime.Object{
+						importJournal("./testdata/deps.yaml", importpkg.JournalInProgress, existingDepsJournal),
+					},
+					ExpectUpdates: []clientgotesting.UpdateActionImpl{
+						{Object: importJournal("./testdata/deps.yaml", importpkg.JournalCompleted, existingDepsJournal)},
+					},
 				}.TestK8sAndKpack(t, cmdFunc)
 				require.Len(t, fakeWaiter.WaitCalls, 5)
 				require.Len(t, fakeWaiter.WaitCalls[3].ExtraChecks, 1) // ClusterBuilder has extra check
@@ -639,6 +763,8 @@ Uploading to 'default-registry.io/default-repo'...
 	Uploading 'default-registry.io/default-repo@sha256:build-image-digest'
 Importing ClusterBuilder 'clusterbuilder-name'...
 Importing ClusterBuilder 'default'...
+Ready: ClusterStore 'store-name', ClusterStack 'stack-name', ClusterStack 'default'
+Ready: ClusterBuilder 'clusterbuilder-name', ClusterBuilder 'default'
 Imported resources
 `,
 					ExpectPatches: []string{
@@ -647,6 +773,12 @@ Imported resources
 						`{"metadata":{"annotations":{"kpack.io/import-timestamp":"new-timestamp","kubectl.kubernetes.io/last-applied-configuration":"{\"kind\":\"ClusterBuilder\",\"apiVersion\":\"kpack.io/v1alpha2\",\"metadata\":{\"name\":\"clusterbuilder-name\",\"creationTimestamp\":null},\"spec\":{\"tag\":\"default-registry.io/default-repo:clusterbuilder-clusterbuilder-name\",\"stack\":{\"kind\":\"ClusterStack\",\"name\":\"stack-name\"},\"store\":{\"kind\":\"ClusterStore\",\"name\":\"store-name\"},\"order\":[{\"group\":[{\"id\":\"buildpack-id\"}]}],\"serviceAccountRef\":{\"namespace\":\"some-namespace\",\"name\":\"some-serviceaccount\"}},\"status\":{\"stack\":{}}}"}}}`,
 						`{"metadata":{"annotations":{"kpack.io/import-timestamp":"new-timestamp","kubectl.kubernetes.io/last-applied-configuration":"{\"kind\":\"ClusterBuilder\",\"apiVersion\":\"kpack.io/v1alpha2\",\"metadata\":{\"name\":\"default\",\"creationTimestamp\":null},\"spec\":{\"tag\":\"default-registry.io/default-repo:clusterbuilder-default\",\"stack\":{\"kind\":\"ClusterStack\",\"name\":\"stack-name\"},\"store\":{\"kind\":\"ClusterStore\",\"name\":\"store-name\"},\"order\":[{\"group\":[{\"id\":\"buildpack-id\"}]}],\"serviceAccountRef\":{\"namespace\":\"some-namespace\",\"name\":\"some-serviceaccount\"}},\"status\":{\"stack\":{}}}"}}}`,
 					},
+					ExpectCreates: []runtime.Object{
+						importJournal("./testdata/deps.yaml", importpkg.JournalInProgress, unannotatedDepsJournal),
+					},
+					ExpectUpdates: []clientgotesting.UpdateActionImpl{
+						{Object: importJournal("./testdata/deps.yaml", importpkg.JournalCompleted, unannotatedDepsJournal)},
+					},
 				}.TestK8sAndKpack(t, cmdFunc)
 			})
 		})
@@ -740,6 +872,8 @@ Uploading to 'default-registry.io/default-repo'...
 	Uploading 'default-registry.io/default-repo@sha256:another-run-image-digest'
 Importing ClusterBuilder 'clusterbuilder-name'...
 Importing ClusterBuilder 'default'...
+Ready: ClusterStore 'store-name', ClusterStack 'stack-name', ClusterStack 'default'
+Ready: ClusterBuilder 'clusterbuilder-name', ClusterBuilder 'default'
 Imported resources
 `,
 					ExpectPatches: []string{
@@ -749,6 +883,12 @@ Imported resources
 						`{"metadata":{"annotations":{"kpack.io/import-timestamp":"new-timestamp"}},"spec":{"buildImage":{"image":"default-registry.io/default-repo@sha256:another-build-image-digest"},"id":"another-stack-id","runImage":{"image":"default-registry.io/default-repo@sha256:another-run-image-digest"}}}`,
 						`{"metadata":{"annotations":{"kpack.io/import-timestamp":"new-timestamp","kubectl.kubernetes.io/last-applied-configuration":"{\"kind\":\"ClusterBuilder\",\"apiVersion\":\"kpack.io/v1alpha2\",\"metadata\":{\"name\":\"clusterbuilder-name\",\"creationTimestamp\":null},\"spec\":{\"tag\":\"default-registry.io/default-repo:clusterbuilder-clusterbuilder-name\",\"stack\":{\"kind\":\"ClusterStack\",\"name\":\"stack-name\"},\"store\":{\"kind\":\"ClusterStore\",\"name\":\"store-name\"},\"order\":[{\"group\":[{\"id\":\"another-buildpack-id\"}]}],\"serviceAccountRef\":{\"namespace\":\"some-namespace\",\"name\":\"some-serviceaccount\"}},\"status\":{\"stack\":{}}}"}},"spec":{"order":[{"group":[{"id":"another-buildpack-id"}]}]}}`,
 					},
+					ExpectCreates: []runtime.Object{
+						importJournal("./testdata/updated-deps.yaml", importpkg.JournalInProgress, updatedDepsJournal),
+					},
+					ExpectUpdates: []clientgotesting.UpdateActionImpl{
+						{Object: importJournal("./testdata/updated-deps.yaml", importpkg.JournalCompleted, updatedDepsJournal)},
+					},
 				}.TestK8sAndKpack(t, cmdFunc)
 			})
 		})
@@ -760,7 +900,7 @@ Imported resources
 			Args: []string{
 				"-f", "./testdata/invalid-deps.yaml",
 			},
-			ExpectedErrorOutput: "Error: did not find expected apiVersion, must be one of: [kp.kpack.io/v1alpha1 kp.kpack.io/v1alpha3]\n",
+			ExpectedErrorOutput: "Error: did not find expected apiVersion, must be one of: [kp.kpack.io/v1alpha1 kp.kpack.io/v1alpha3 kp.kpack.io/v1alpha4]\n",
 			ExpectErr:           true,
 		}.TestK8sAndKpack(t, cmdFunc)
 	})
@@ -780,6 +920,8 @@ Uploading to 'default-registry.io/default-repo'...
 	Uploading 'default-registry.io/default-repo@sha256:build-image-digest'
 Importing ClusterBuilder 'clusterbuilder-name'...
 Importing ClusterBuilder 'default'...
+Ready: ClusterStore 'store-name', ClusterStack 'stack-name', ClusterStack 'default'
+Ready: ClusterBuilder 'clusterbuilder-name', ClusterBuilder 'default'
 `
 
 		builder.Annotations["kubectl.kubernetes.io/last-applied-configuration"] = `{"kind":"ClusterBuilder","apiVersion":"kpack.io/v1alpha2","metadata":{"name":"clusterbuilder-name","creationTimestamp":null},"spec":{"tag":"default-registry.io/default-repo:clusterbuilder-clusterbuilder-name","stack":{"kind":"ClusterStack","name":"stack-name"},"store":{"kind":"ClusterStore","name":"store-name"},"order":[{"group":[{"id":"buildpack-id"}]}],"serviceAccountRef":{"namespace":"some-namespace","name":"some-serviceaccount"}},"status":{"stack":{}}}`
@@ -917,6 +1059,7 @@ status:
 					ExpectedOutput:      resourceYAML,
 					ExpectedErrorOutput: expectedOutput,
 					ExpectCreates: []runtime.Object{
+						importJournal("./testdata/deps.yaml", importpkg.JournalInProgress, depsJournal),
 						store,
 						stack,
 						defaultStack,
@@ -926,6 +1069,9 @@ status:
 					ExpectPatches: []string{
 						`{"data":{"image":"default-registry.io/default-repo@sha256:lifecycle-image-digest"},"metadata":{"annotations":{"kpack.io/import-timestamp":"2006-01-02T15:04:05Z"}}}`,
 					},
+					ExpectUpdates: []clientgotesting.UpdateActionImpl{
+						{Object: importJournal("./testdata/deps.yaml", importpkg.JournalCompleted, depsJournal)},
+					},
 				}.TestK8sAndKpack(t, cmdFunc)
 			})
 
@@ -1181,6 +1327,7 @@ Importing ClusterBuilder 'default'... (dry run with image upload)
 				ExpectedOutput:      resourceJSON,
 				ExpectedErrorOutput: expectedOutput,
 				ExpectCreates: []runtime.Object{
+					importJournal("./testdata/deps.yaml", importpkg.JournalInProgress, depsJournal),
 					store,
 					stack,
 					defaultStack,
@@ -1190,6 +1337,306 @@ Importing ClusterBuilder 'default'... (dry run with image upload)
 				ExpectPatches: []string{
 					`{"data":{"image":"default-registry.io/default-repo@sha256:lifecycle-image-digest"},"metadata":{"annotations":{"kpack.io/import-timestamp":"2006-01-02T15:04:05Z"}}}`,
 				},
+				ExpectUpdates: []clientgotesting.UpdateActionImpl{
+					{Object: importJournal("./testdata/deps.yaml", importpkg.JournalCompleted, depsJournal)},
+				},
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+	})
+
+	when("the plan flag is used", func() {
+		var descriptorPath string
+
+		importedStack := &v1alpha2.ClusterStack{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "old-stack",
+				Annotations: map[string]string{importTimestampKey: "some-timestamp"},
+			},
+		}
+
+		it.Before(func() {
+			descriptorPath = filepath.Join(t.TempDir(), "deps.yaml")
+			require.NoError(t, ioutil.WriteFile(descriptorPath, []byte(`apiVersion: kp.kpack.io/v1alpha4
+kind: DependencyDescriptor
+lifecycle:
+  image: some-registry.io/repo/lifecycle-image
+`), 0644))
+		})
+
+		it("prints the import plan without uploading images or changing resources", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					kpConfig,
+					lifecycleImageConfig,
+					importedStack,
+				},
+				Args: []string{
+					"-f", descriptorPath,
+					"--plan",
+					"--prune",
+					"--output", "yaml",
+				},
+				ExpectedOutput: `apiVersion: kp.kpack.io/v1alpha1
+kind: ImportPlan
+resources:
+- action: update
+  changes:
+  - field: data.image
+    new: default-registry.io/default-repo@sha256:lifecycle-image-digest
+  images:
+  - relocated: default-registry.io/default-repo@sha256:lifecycle-image-digest
+    size: 0
+    source: some-registry.io/repo/lifecycle-image
+  kind: ConfigMap
+  name: lifecycle-image
+  namespace: kpack
+  object:
+    data:
+      image: default-registry.io/default-repo@sha256:lifecycle-image-digest
+    metadata:
+      annotations:
+        kpack.io/import-timestamp: "2006-01-02T15:04:05Z"
+      creationTimestamp: null
+      name: lifecycle-image
+      namespace: kpack
+- action: prune
+  kind: ClusterStack
+  name: old-stack
+`,
+				ExpectedErrorOutput: `Importing Lifecycle...
+	Skipping 'default-registry.io/default-repo@sha256:lifecycle-image-digest'
+`,
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+
+		it("applies an import plan", func() {
+			planPath := filepath.Join(t.TempDir(), "plan.yaml")
+			require.NoError(t, ioutil.WriteFile(planPath, []byte(`apiVersion: kp.kpack.io/v1alpha1
+kind: ImportPlan
+resources:
+- action: unchanged
+  kind: ConfigMap
+  name: lifecycle-image
+  namespace: kpack
+- action: prune
+  kind: ClusterStack
+  name: old-stack
+`), 0644))
+
+			rawPlan, err := ioutil.ReadFile(planPath)
+			require.NoError(t, err)
+
+			plan, err := importpkg.ReadPlan(string(rawPlan))
+			require.NoError(t, err)
+
+			digest, err := plan.Digest()
+			require.NoError(t, err)
+
+			const planJournal = `[{"kind":"ClusterStack","name":"old-stack","pruned":true,"previous":{"annotations":{"kpack.io/import-timestamp":"some-timestamp"},"spec":{"buildImage":{},"runImage":{}}}}]`
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					kpConfig,
+					lifecycleImageConfig,
+					importedStack,
+				},
+				Args: []string{
+					"--apply-plan", planPath,
+				},
+				ExpectedOutput: `Pruning ClusterStack 'old-stack'...
+Imported resources
+`,
+				ExpectCreates: []runtime.Object{
+					journal(digest, importpkg.JournalInProgress, planJournal),
+				},
+				ExpectUpdates: []clientgotesting.UpdateActionImpl{
+					{Object: journal(digest, importpkg.JournalCompleted, planJournal)},
+				},
+				ExpectDeletes: []clientgotesting.DeleteActionImpl{
+					{
+						Name: importedStack.Name,
+					},
+				},
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+
+		it("errors when the output format is not json or yaml", func() {
+			testhelpers.CommandTest{
+				Objects:             []runtime.Object{kpConfig},
+				Args:                []string{"-f", descriptorPath, "--plan"},
+				ExpectErr:           true,
+				ExpectedErrorOutput: "Error: --plan requires --output json or yaml\n",
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+
+		it("errors when prune is used without plan", func() {
+			testhelpers.CommandTest{
+				Objects:             []runtime.Object{kpConfig},
+				Args:                []string{"-f", descriptorPath, "--prune"},
+				ExpectErr:           true,
+				ExpectedErrorOutput: "Error: --prune can only be used with --plan\n",
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+
+		it("errors when a plan is applied together with a dependency descriptor", func() {
+			testhelpers.CommandTest{
+				Objects:             []runtime.Object{kpConfig},
+				Args:                []string{"-f", descriptorPath, "--apply-plan", "plan.json"},
+				ExpectErr:           true,
+				ExpectedErrorOutput: "Error: --apply-plan cannot be used with --filename or --plan\n",
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+	})
+
+	when("the last import did not complete", func() {
+		it("resumes the import without saving the resources that were saved before", func() {
+			builder.Annotations["kubectl.kubernetes.io/last-applied-configuration"] = `{"kind":"ClusterBuilder","apiVersion":"kpack.io/v1alpha2","metadata":{"name":"clusterbuilder-name","creationTimestamp":null},"spec":{"tag":"default-registry.io/default-repo:clusterbuilder-clusterbuilder-name","stack":{"kind":"ClusterStack","name":"stack-name"},"store":{"kind":"ClusterStore","name":"store-name"},"order":[{"group":[{"id":"buildpack-id"}]}],"serviceAccountRef":{"namespace":"some-namespace","name":"some-serviceaccount"}},"status":{"stack":{}}}`
+			defaultBuilder.Annotations["kubectl.kubernetes.io/last-applied-configuration"] = `{"kind":"ClusterBuilder","apiVersion":"kpack.io/v1alpha2","metadata":{"name":"default","creationTimestamp":null},"spec":{"tag":"default-registry.io/default-repo:clusterbuilder-default","stack":{"kind":"ClusterStack","name":"stack-name"},"store":{"kind":"ClusterStore","name":"store-name"},"order":[{"group":[{"id":"buildpack-id"}]}],"serviceAccountRef":{"namespace":"some-namespace","name":"some-serviceaccount"}},"status":{"stack":{}}}`
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					kpConfig,
+					expectedLifecycleImageConfig,
+					importJournal("./testdata/deps.yaml", importpkg.JournalInProgress, depsJournal),
+				},
+				Args: []string{
+					"-f", "./testdata/deps.yaml",
+					"--resume",
+				},
+				ExpectedOutput: `Resuming import from 2006-01-02T15:04:05Z
+Importing Lifecycle...
+	Uploading 'default-registry.io/default-repo@sha256:lifecycle-image-digest'
+Importing ClusterStore 'store-name'...
+	Uploading 'default-registry.io/default-repo@sha256:buildpack-image-digest'
+Importing ClusterStack 'stack-name'...
+Uploading to 'default-registry.io/default-repo'...
+	Uploading 'default-registry.io/default-repo@sha256:build-image-digest'
+	Uploading 'default-registry.io/default-repo@sha256:build-image-digest'
+Importing ClusterStack 'default'...
+Uploading to 'default-registry.io/default-repo'...
+	Uploading 'default-registry.io/default-repo@sha256:build-image-digest'
+	Uploading 'default-registry.io/default-repo@sha256:build-image-digest'
+Importing ClusterBuilder 'clusterbuilder-name'...
+Importing ClusterBuilder 'default'...
+Skipping Lifecycle, it was saved before the import failed
+Ready: ClusterStore 'store-name', ClusterStack 'stack-name', ClusterStack 'default'
+Ready: ClusterBuilder 'clusterbuilder-name', ClusterBuilder 'default'
+Imported resources
+`,
+				ExpectCreates: []runtime.Object{
+					store,
+					stack,
+					defaultStack,
+					builder,
+					defaultBuilder,
+				},
+				ExpectUpdates: []clientgotesting.UpdateActionImpl{
+					{Object: importJournal("./testdata/deps.yaml", importpkg.JournalCompleted, depsJournal)},
+				},
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+
+		it("errors when a new import is started", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					kpConfig,
+					lifecycleImageConfig,
+					importJournal("./testdata/deps.yaml", importpkg.JournalInProgress, depsJournal),
+				},
+				Args: []string{
+					"-f", "./testdata/deps.yaml",
+				},
+				ExpectErr:           true,
+				ExpectedErrorOutput: "Error: the last import from 2006-01-02T15:04:05Z did not complete, resume it with --resume or restore the previous resources with \"kp import rollback\"\n",
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+
+		it("errors when resume is used with plan", func() {
+			testhelpers.CommandTest{
+				Objects:             []runtime.Object{kpConfig},
+				Args:                []string{"-f", "./testdata/deps.yaml", "--resume", "--plan", "--output", "json"},
+				ExpectErr:           true,
+				ExpectedErrorOutput: "Error: --resume cannot be used with --plan, --apply-plan or --dry-run\n",
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+	})
+
+	when("a lock file is next to the dependency descriptor", func() {
+		var descriptorPath string
+
+		it.Before(func() {
+			descriptor, err := ioutil.ReadFile("./testdata/deps.yaml")
+			require.NoError(t, err)
+
+			descriptorPath = filepath.Join(t.TempDir(), "deps.yaml")
+			require.NoError(t, ioutil.WriteFile(descriptorPath, descriptor, 0644))
+		})
+
+		it("fetches the source images at their locked digests", func() {
+			require.NoError(t, ioutil.WriteFile(importpkg.LockPath(descriptorPath), []byte(`apiVersion: kp.kpack.io/v1alpha1
+kind: DependencyLock
+lifecycle:
+  image: some-registry.io/repo/lifecycle-image
+  digest: sha256:locked-lifecycle-image-digest
+clusterStores:
+- name: store-name
+  sources:
+  - image: some-registry.io/repo/buildpack-image
+    digest: sha256:locked-buildpack-image-digest
+clusterStacks:
+- name: stack-name
+  buildImage:
+    image: some-registry.io/repo/build-image
+    digest: sha256:locked-build-image-digest
+  runImage:
+    image: some-registry.io/repo/run-image
+    digest: sha256:locked-run-image-digest
+`), 0644))
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					kpConfig,
+					lifecycleImageConfig,
+				},
+				Args: []string{
+					"-f", descriptorPath,
+					"--dry-run",
+				},
+				ExpectErr: true,
+				ExpectedOutput: fmt.Sprintf(`Using lock file %s (dry run)
+Verifying 4 source images... (dry run)
+`, importpkg.LockPath(descriptorPath)),
+				ExpectedErrorOutput: `Error: verification failed for 4 source image(s), no images were relocated:
+	some-registry.io/repo/build-image@sha256:locked-build-image-digest: image not found: "some-registry.io/repo/build-image@sha256:locked-build-image-digest"
+	some-registry.io/repo/buildpack-image@sha256:locked-buildpack-image-digest: image not found: "some-registry.io/repo/buildpack-image@sha256:locked-buildpack-image-digest"
+	some-registry.io/repo/lifecycle-image@sha256:locked-lifecycle-image-digest: image not found: "some-registry.io/repo/lifecycle-image@sha256:locked-lifecycle-image-digest"
+	some-registry.io/repo/run-image@sha256:locked-run-image-digest: image not found: "some-registry.io/repo/run-image@sha256:locked-run-image-digest"
+`,
+			}.TestK8sAndKpack(t, cmdFunc)
+		})
+
+		it("fails when the lock file does not pin every image", func() {
+			require.NoError(t, ioutil.WriteFile(importpkg.LockPath(descriptorPath), []byte(`apiVersion: kp.kpack.io/v1alpha1
+kind: DependencyLock
+lifecycle:
+  image: some-registry.io/repo/lifecycle-image
+  digest: sha256:locked-lifecycle-image-digest
+`), 0644))
+
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					kpConfig,
+					lifecycleImageConfig,
+				},
+				Args: []string{
+					"-f", descriptorPath,
+					"--dry-run",
+				},
+				ExpectErr:      true,
+				ExpectedOutput: fmt.Sprintf("Using lock file %s (dry run)\n", importpkg.LockPath(descriptorPath)),
+				ExpectedErrorOutput: fmt.Sprintf("Error: lock file %s is out of date, it does not pin some-registry.io/repo/buildpack-image, "+
+					"some-registry.io/repo/build-image, some-registry.io/repo/run-image, run \"kp import lock -f %s\" to update it\n",
+					importpkg.LockPath(descriptorPath), descriptorPath),
 			}.TestK8sAndKpack(t, cmdFunc)
 		})
 	})
@@ -0,0 +1,121 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package _import
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/dockercreds"
+	importpkg "github.com/vmware-tanzu/kpack-cli/pkg/import"
+	"github.com/vmware-tanzu/kpack-cli/pkg/registry"
+)
+
+func NewLockCommand(differ importpkg.Differ, rup registry.UtilProvider) *cobra.Command {
+	var (
+		filename  string
+		update    bool
+		only      []string
+		tlsConfig registry.TLSConfig
+	)
+
+	cmd := &cobra.Command{
+		Use:   "lock -f <filename>",
+		Short: "Pin the images of a dependency descriptor to their digests",
+		Long: `Resolves every image of the dependency descriptor to a digest and writes them to a lock file next to it,
+such as dependencies.lock.yaml for dependencies.yaml. kp import uses the digests of the lock file when it is present.
+
+Images already in the lock file keep their digest and only new images are resolved.
+Use --update to resolve every image again, or --update with --only to resolve the images of the selected resources.
+Resources are selected with lifecycle, clusterstore=<name>, clusterbuildpack=<name>, buildpack=<namespace>/<name>
+or clusterstack=<name>.
+
+Env vars can be used for registry auth as described in https://github.com/vmware-tanzu/kpack-cli/blob/main/docs/auth.md`,
+		Example: `kp import lock -f dependencies.yaml
+kp import lock -f dependencies.yaml --update
+kp import lock -f dependencies.yaml --update --only clusterstack=base --only lifecycle`,
+		Args:         commands.ExactArgsWithUsage(0),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if filename == "-" {
+				return errors.New("the dependency descriptor must be a file to be locked")
+			}
+
+			if len(only) > 0 && !update {
+				return errors.New("--only can only be used with --update")
+			}
+
+			var selectors []importpkg.LockSelector
+			for _, o := range only {
+				s, err := importpkg.ParseLockSelector(o)
+				if err != nil {
+					return err
+				}
+				selectors = append(selectors, s)
+			}
+
+			ch, err := commands.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			rawDescriptor, err := readDescriptor(cmd, filename)
+			if err != nil {
+				return err
+			}
+
+			descriptor, err := importpkg.ReadDescriptor(rawDescriptor)
+			if err != nil {
+				return err
+			}
+
+			lockPath := importpkg.LockPath(filename)
+			existing, found, err := importpkg.ReadLock(lockPath)
+			if err != nil {
+				return err
+			}
+
+			if err := ch.PrintStatus("Resolving image digests..."); err != nil {
+				return err
+			}
+
+			locker := importpkg.Locker{Fetcher: rup.Fetcher(tlsConfig)}
+			lock, err := locker.Lock(dockercreds.DefaultKeychain, descriptor, existing, update, selectors...)
+			if err != nil {
+				return err
+			}
+
+			var old interface{}
+			if found {
+				old = existing
+			}
+
+			diff, err := differ.Diff(old, lock)
+			if err != nil {
+				return err
+			}
+
+			if diff == "" {
+				return ch.PrintResult("Lock file %s is up to date", lockPath)
+			}
+
+			if err := ch.Printlnf(diff); err != nil {
+				return err
+			}
+
+			if err := lock.Write(lockPath); err != nil {
+				return err
+			}
+
+			return ch.PrintResult("Wrote lock file %s", lockPath)
+		},
+	}
+	cmd.Flags().StringVarP(&filename, "filename", "f", "", "dependency descriptor filename")
+	cmd.Flags().BoolVar(&update, "update", false, "resolve the digests of images already in the lock file again")
+	cmd.Flags().StringArrayVar(&only, "only", []string{}, "resource to update such as clusterstack=<name> (can be set more than once)")
+	commands.SetTLSFlags(cmd, &tlsConfig)
+	_ = cmd.MarkFlagRequired("filename")
+	return cmd
+}
@@ -0,0 +1,169 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package _import_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	kpackfakes "github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/sclevine/spec"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	commandsfakes "github.com/vmware-tanzu/kpack-cli/pkg/commands/fakes"
+	importcmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/import"
+	importpkg "github.com/vmware-tanzu/kpack-cli/pkg/import"
+	registryfakes "github.com/vmware-tanzu/kpack-cli/pkg/registry/fakes"
+	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
+)
+
+func TestLockCommand(t *testing.T) {
+	spec.Run(t, "TestLockCommand", testLockCommand)
+}
+
+func testLockCommand(t *testing.T, when spec.G, it spec.S) {
+	var (
+		fakeFetcher    *registryfakes.Fetcher
+		fakeDiffer     *commandsfakes.FakeDiffer
+		descriptorPath string
+		lockPath       string
+	)
+
+	addImages := func(suffix string) {
+		fakeFetcher.AddLifecycleImages(registryfakes.LifecycleInfo{
+			ImageInfo: registryfakes.ImageInfo{Ref: "some-registry.io/repo/lifecycle-image", Digest: "lifecycle-image-digest" + suffix},
+		})
+		fakeFetcher.AddBuildpackImages(registryfakes.BuildpackImgInfo{
+			Id:        "buildpack-id",
+			ImageInfo: registryfakes.ImageInfo{Ref: "some-registry.io/repo/buildpack-image", Digest: "buildpack-image-digest" + suffix},
+		})
+		fakeFetcher.AddStackImages(registryfakes.StackInfo{
+			StackID:  "stack-id",
+			BuildImg: registryfakes.ImageInfo{Ref: "some-registry.io/repo/build-image", Digest: "build-image-digest" + suffix},
+			RunImg:   registryfakes.ImageInfo{Ref: "some-registry.io/repo/run-image", Digest: "run-image-digest" + suffix},
+		})
+	}
+
+	it.Before(func() {
+		fakeFetcher = &registryfakes.Fetcher{}
+		fakeDiffer = &commandsfakes.FakeDiffer{DiffResult: "some-diff"}
+		addImages("")
+
+		descriptor, err := ioutil.ReadFile("./testdata/deps.yaml")
+		require.NoError(t, err)
+
+		descriptorPath = filepath.Join(t.TempDir(), "deps.yaml")
+		lockPath = filepath.Join(filepath.Dir(descriptorPath), "deps.lock.yaml")
+		require.NoError(t, ioutil.WriteFile(descriptorPath, descriptor, 0644))
+	})
+
+	cmdFunc := func(*kpackfakes.Clientset) *cobra.Command {
+		return importcmds.NewLockCommand(fakeDiffer, &registryfakes.UtilProvider{FakeFetcher: fakeFetcher})
+	}
+
+	expectedLock := func(suffix, stackSuffix string) importpkg.DependencyLock {
+		return importpkg.DependencyLock{
+			APIVersion: importpkg.LockAPIVersion,
+			Kind:       importpkg.LockKind,
+			Lifecycle:  &importpkg.LockedImage{Image: "some-registry.io/repo/lifecycle-image", Digest: "sha256:lifecycle-image-digest" + suffix},
+			ClusterStores: []importpkg.LockedClusterStore{
+				{
+					Name:    "store-name",
+					Sources: []importpkg.LockedImage{{Image: "some-registry.io/repo/buildpack-image", Digest: "sha256:buildpack-image-digest" + suffix}},
+				},
+			},
+			ClusterStacks: []importpkg.LockedClusterStack{
+				{
+					Name:       "stack-name",
+					BuildImage: importpkg.LockedImage{Image: "some-registry.io/repo/build-image", Digest: "sha256:build-image-digest" + stackSuffix},
+					RunImage:   importpkg.LockedImage{Image: "some-registry.io/repo/run-image", Digest: "sha256:run-image-digest" + stackSuffix},
+				},
+			},
+		}
+	}
+
+	readLock := func() importpkg.DependencyLock {
+		lock, found, err := importpkg.ReadLock(lockPath)
+		require.NoError(t, err)
+		require.True(t, found)
+		return lock
+	}
+
+	it("writes the lock file next to the dependency descriptor", func() {
+		testhelpers.CommandTest{
+			Args: []string{"-f", descriptorPath},
+			ExpectedOutput: fmt.Sprintf(`Resolving image digests...
+some-diff
+Wrote lock file %s
+`, lockPath),
+		}.TestKpack(t, cmdFunc)
+
+		require.Equal(t, expectedLock("", ""), readLock())
+
+		old, _ := fakeDiffer.Args()
+		require.Nil(t, old)
+	})
+
+	it("keeps the lock file when nothing changed", func() {
+		require.NoError(t, expectedLock("", "").Write(lockPath))
+		addImages("-new")
+		fakeDiffer.DiffResult = ""
+
+		testhelpers.CommandTest{
+			Args: []string{"-f", descriptorPath},
+			ExpectedOutput: fmt.Sprintf(`Resolving image digests...
+Lock file %s is up to date
+`, lockPath),
+		}.TestKpack(t, cmdFunc)
+
+		require.Equal(t, expectedLock("", ""), readLock())
+	})
+
+	it("refreshes the digests of the selected resources on update", func() {
+		require.NoError(t, expectedLock("", "").Write(lockPath))
+		addImages("-new")
+
+		testhelpers.CommandTest{
+			Args: []string{"-f", descriptorPath, "--update", "--only", "clusterstack=stack-name"},
+			ExpectedOutput: fmt.Sprintf(`Resolving image digests...
+some-diff
+Wrote lock file %s
+`, lockPath),
+		}.TestKpack(t, cmdFunc)
+
+		require.Equal(t, expectedLock("", "-new"), readLock())
+
+		old, updated := fakeDiffer.Args()
+		require.Equal(t, expectedLock("", ""), old)
+		require.Equal(t, expectedLock("", "-new"), updated)
+	})
+
+	it("errors when a selected resource is not in the dependency descriptor", func() {
+		testhelpers.CommandTest{
+			Args:                []string{"-f", descriptorPath, "--update", "--only", "clusterstack=missing-stack"},
+			ExpectErr:           true,
+			ExpectedOutput:      "Resolving image digests...\n",
+			ExpectedErrorOutput: "Error: clusterstack 'missing-stack' not found in the dependency descriptor\n",
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("errors when only is used without update", func() {
+		testhelpers.CommandTest{
+			Args:                []string{"-f", descriptorPath, "--only", "lifecycle"},
+			ExpectErr:           true,
+			ExpectedErrorOutput: "Error: --only can only be used with --update\n",
+		}.TestKpack(t, cmdFunc)
+	})
+
+	it("errors when the dependency descriptor is read from stdin", func() {
+		testhelpers.CommandTest{
+			Args:                []string{"-f", "-"},
+			ExpectErr:           true,
+			ExpectedErrorOutput: "Error: the dependency descriptor must be a file to be locked\n",
+		}.TestKpack(t, cmdFunc)
+	})
+}
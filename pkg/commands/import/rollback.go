@@ -0,0 +1,48 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package _import
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	importpkg "github.com/vmware-tanzu/kpack-cli/pkg/import"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+)
+
+func NewRollbackCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Restore the resources of the last import",
+		Long: `Restores the resources saved or pruned by the last import to what they were before it, using the previous
+annotations and spec recorded in the kp-import-journal config map in the kpack namespace.
+
+Resources the import created are deleted, resources it updated get their previous annotations and spec back and
+resources it pruned are recreated. Resources the import did not get to save before it failed are left as they are.`,
+		Example:      "kp import rollback",
+		Args:         commands.ExactArgsWithUsage(0),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cs, err := clientSetProvider.GetClientSet("")
+			if err != nil {
+				return err
+			}
+
+			ch, err := commands.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			importer := importpkg.NewImporter(ch, cs.K8sClient, cs.KpackClient, nil, nil, nil, nil)
+
+			timestamp, err := importer.Rollback(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			return ch.PrintResult("Rolled back import from %s", timestamp)
+		},
+	}
+	return cmd
+}
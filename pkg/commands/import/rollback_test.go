@@ -0,0 +1,119 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package _import_test
+
+import (
+	"testing"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	kpackfakes "github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/sclevine/spec"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfakes "k8s.io/client-go/kubernetes/fake"
+	clientgotesting "k8s.io/client-go/testing"
+
+	importcmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/import"
+	importpkg "github.com/vmware-tanzu/kpack-cli/pkg/import"
+	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
+)
+
+func TestRollbackCommand(t *testing.T) {
+	spec.Run(t, "TestRollbackCommand", testRollbackCommand)
+}
+
+func testRollbackCommand(t *testing.T, when spec.G, it spec.S) {
+	const (
+		importTimestamp = "2006-01-02T15:04:05Z"
+		resources       = `[{"kind":"ClusterStack","name":"updated-stack","previous":{"spec":{"id":"old-stack-id"}}},{"kind":"ClusterStack","name":"created-stack"}]`
+	)
+
+	cmdFunc := func(k8sClientSet *k8sfakes.Clientset, kpackClientSet *kpackfakes.Clientset) *cobra.Command {
+		clientSetProvider := testhelpers.GetFakeClusterProvider(k8sClientSet, kpackClientSet)
+		return importcmds.NewRollbackCommand(clientSetProvider)
+	}
+
+	journal := func(status string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      importpkg.JournalName,
+				Namespace: importpkg.JournalNamespace,
+			},
+			Data: map[string]string{
+				"status":    status,
+				"timestamp": importTimestamp,
+				"digest":    "some-digest",
+				"resources": resources,
+			},
+		}
+	}
+
+	importedStack := func(name, id string) *v1alpha2.ClusterStack {
+		return &v1alpha2.ClusterStack{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{"kpack.io/import-timestamp": importTimestamp},
+			},
+			Spec: v1alpha2.ClusterStackSpec{
+				Id: id,
+			},
+		}
+	}
+
+	it("restores the resources of the last import", func() {
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{
+				journal(importpkg.JournalCompleted),
+				importedStack("updated-stack", "new-stack-id"),
+				importedStack("created-stack", "new-stack-id"),
+			},
+			Args: []string{},
+			ExpectedOutput: `Deleting ClusterStack 'created-stack'...
+Restoring ClusterStack 'updated-stack'...
+Rolled back import from 2006-01-02T15:04:05Z
+`,
+			ExpectUpdates: []clientgotesting.UpdateActionImpl{
+				{
+					Object: journal(importpkg.JournalRolledBack),
+				},
+				{
+					Object: &v1alpha2.ClusterStack{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "updated-stack",
+						},
+						Spec: v1alpha2.ClusterStackSpec{
+							Id: "old-stack-id",
+						},
+					},
+				},
+			},
+			ExpectDeletes: []clientgotesting.DeleteActionImpl{
+				{
+					Name: "created-stack",
+				},
+			},
+		}.TestK8sAndKpack(t, cmdFunc)
+	})
+
+	it("errors when there is no import", func() {
+		testhelpers.CommandTest{
+			Args:                []string{},
+			ExpectErr:           true,
+			ExpectedErrorOutput: "Error: there is no import to roll back\n",
+		}.TestK8sAndKpack(t, cmdFunc)
+	})
+
+	it("errors when the last import was already rolled back", func() {
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{
+				journal(importpkg.JournalRolledBack),
+			},
+			Args:                []string{},
+			ExpectErr:           true,
+			ExpectedErrorOutput: "Error: the import from 2006-01-02T15:04:05Z was already rolled back\n",
+		}.TestK8sAndKpack(t, cmdFunc)
+	})
+}
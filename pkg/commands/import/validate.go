@@ -0,0 +1,109 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package _import
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/dockercreds"
+	importpkg "github.com/vmware-tanzu/kpack-cli/pkg/import"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+	"github.com/vmware-tanzu/kpack-cli/pkg/registry"
+)
+
+func NewValidateCommand(clientSetProvider k8s.ClientSetProvider, rup registry.UtilProvider) *cobra.Command {
+	var (
+		filename  string
+		offline   bool
+		schema    bool
+		tlsConfig registry.TLSConfig
+	)
+
+	cmd := &cobra.Command{
+		Use:   "validate -f <filename>",
+		Short: "Validate a dependency descriptor",
+		Long: `Validates a dependency descriptor without importing it and reports every problem found with its line.
+
+Fields that are not part of the apiVersion of the descriptor are reported instead of being ignored.
+The cluster stacks, cluster stores and buildpacks referenced by builders must be defined in the descriptor or the cluster,
+the buildpack ids of builder orders must be provided by the cluster store of the builder and the lifecycle image must be set.
+
+Use --offline to validate without the cluster and registries, references must then be defined in the descriptor
+and buildpack ids are not checked.
+
+Use --schema to print the JSON Schema of the current dependency descriptor version for editors,
+such as with the yaml-language-server comment "# yaml-language-server: $schema=<path to schema>".
+
+Env vars can be used for registry auth as described in https://github.com/vmware-tanzu/kpack-cli/blob/main/docs/auth.md`,
+		Example: `kp import validate -f dependencies.yaml
+kp import validate -f dependencies.yaml --offline
+kp import validate --schema > dependency-descriptor.schema.json`,
+		Args:         commands.ExactArgsWithUsage(0),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ch, err := commands.NewCommandHelper(cmd)
+			if err != nil {
+				return err
+			}
+
+			if schema {
+				s, err := importpkg.Schema()
+				if err != nil {
+					return err
+				}
+				return ch.Printlnf("%s", s)
+			}
+
+			if filename == "" {
+				return errors.New("--filename is required unless --schema is set")
+			}
+
+			rawDescriptor, err := readDescriptor(cmd, filename)
+			if err != nil {
+				return err
+			}
+
+			var validator importpkg.Validator
+			if !offline {
+				cs, err := clientSetProvider.GetClientSet("")
+				if err != nil {
+					return err
+				}
+
+				validator = importpkg.Validator{
+					Fetcher: rup.Fetcher(tlsConfig),
+					Client:  cs.KpackClient,
+				}
+			}
+
+			if err := ch.PrintStatus("Validating dependency descriptor..."); err != nil {
+				return err
+			}
+
+			problems, err := validator.Validate(cmd.Context(), dockercreds.DefaultKeychain, rawDescriptor)
+			if err != nil {
+				return err
+			}
+
+			if len(problems) == 0 {
+				return ch.PrintResult("Dependency descriptor is valid")
+			}
+
+			for _, p := range problems {
+				if err := ch.Printlnf("%s", p); err != nil {
+					return err
+				}
+			}
+
+			return errors.New("invalid dependency descriptor")
+		},
+	}
+	cmd.Flags().StringVarP(&filename, "filename", "f", "", "dependency descriptor filename")
+	cmd.Flags().BoolVar(&offline, "offline", false, "validate without looking up the cluster and registries")
+	cmd.Flags().BoolVar(&schema, "schema", false, "print the JSON Schema of the dependency descriptor")
+	commands.SetTLSFlags(cmd, &tlsConfig)
+	return cmd
+}
@@ -0,0 +1,140 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package _import_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	kpackfakes "github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/sclevine/spec"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfakes "k8s.io/client-go/kubernetes/fake"
+
+	importcmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/import"
+	importpkg "github.com/vmware-tanzu/kpack-cli/pkg/import"
+	registryfakes "github.com/vmware-tanzu/kpack-cli/pkg/registry/fakes"
+	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
+)
+
+func TestValidateCommand(t *testing.T) {
+	spec.Run(t, "TestValidateCommand", testValidateCommand)
+}
+
+func testValidateCommand(t *testing.T, when spec.G, it spec.S) {
+	var fakeFetcher *registryfakes.Fetcher
+
+	it.Before(func() {
+		fakeFetcher = registryfakes.NewBuildpackImagesFetcher(registryfakes.BuildpackImgInfo{
+			Id:        "buildpack-id",
+			ImageInfo: registryfakes.ImageInfo{Ref: "some-registry.io/repo/buildpack-image", Digest: "buildpack-image-digest"},
+		})
+	})
+
+	cmdFunc := func(k8sClientSet *k8sfakes.Clientset, kpackClientSet *kpackfakes.Clientset) *cobra.Command {
+		clientSetProvider := testhelpers.GetFakeClusterProvider(k8sClientSet, kpackClientSet)
+		return importcmds.NewValidateCommand(clientSetProvider, &registryfakes.UtilProvider{FakeFetcher: fakeFetcher})
+	}
+
+	writeDescriptor := func(descriptor string) string {
+		path := filepath.Join(t.TempDir(), "deps.yaml")
+		require.NoError(t, ioutil.WriteFile(path, []byte(descriptor), 0644))
+		return path
+	}
+
+	it("validates a dependency descriptor", func() {
+		testhelpers.CommandTest{
+			Args: []string{"-f", "./testdata/deps.yaml"},
+			ExpectedOutput: `Validating dependency descriptor...
+Dependency descriptor is valid
+`,
+		}.TestK8sAndKpack(t, cmdFunc)
+	})
+
+	it("reports every problem of the dependency descriptor", func() {
+		path := writeDescriptor(`apiVersion: kp.kpack.io/v1alpha4
+kind: DependencyDescriptor
+lifecycle:
+  image: ""
+clusterBuilders:
+- name: some-builder
+  clusterStack: some-stack
+  clusterStore: some-store
+  serviceAccount: some-sa
+  order:
+  - group:
+    - id: buildpack-id
+    - id: other-buildpack-id
+`)
+
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{
+				&v1alpha2.ClusterStore{ObjectMeta: metav1.ObjectMeta{Name: "some-store"}},
+			},
+			Args:      []string{"-f", path},
+			ExpectErr: true,
+			ExpectedOutput: `Validating dependency descriptor...
+line 3: lifecycle image is empty
+line 7: cluster builder 'some-builder' references cluster stack 'some-stack' which is not defined in the descriptor or the cluster
+line 9: unknown field 'serviceAccount' in clusterBuilders[0]
+line 12: cluster builder 'some-builder' references buildpack id 'buildpack-id' which is not provided by cluster store 'some-store'
+line 13: cluster builder 'some-builder' references buildpack id 'other-buildpack-id' which is not provided by cluster store 'some-store'
+`,
+			ExpectedErrorOutput: "Error: invalid dependency descriptor\n",
+		}.TestK8sAndKpack(t, cmdFunc)
+	})
+
+	it("does not look up the cluster or registries when offline", func() {
+		path := writeDescriptor(`apiVersion: kp.kpack.io/v1alpha4
+kind: DependencyDescriptor
+lifecycle:
+  image: some-registry.io/repo/lifecycle-image
+clusterStores:
+- name: some-store
+  sources:
+  - image: some-registry.io/repo/buildpack-image
+clusterBuilders:
+- name: some-builder
+  clusterStack: some-stack
+  clusterStore: some-store
+  order:
+  - group:
+    - id: other-buildpack-id
+`)
+
+		testhelpers.CommandTest{
+			Args:      []string{"-f", path, "--offline"},
+			ExpectErr: true,
+			ExpectedOutput: `Validating dependency descriptor...
+line 11: cluster builder 'some-builder' references cluster stack 'some-stack' which is not defined in the descriptor
+`,
+			ExpectedErrorOutput: "Error: invalid dependency descriptor\n",
+		}.TestK8sAndKpack(t, cmdFunc)
+
+		require.Zero(t, fakeFetcher.CallCount())
+	})
+
+	it("prints the JSON Schema of the dependency descriptor", func() {
+		schema, err := importpkg.Schema()
+		require.NoError(t, err)
+
+		testhelpers.CommandTest{
+			Args:           []string{"--schema"},
+			ExpectedOutput: string(schema) + "\n",
+		}.TestK8sAndKpack(t, cmdFunc)
+	})
+
+	it("errors when the filename is not set", func() {
+		testhelpers.CommandTest{
+			Args:                []string{},
+			ExpectErr:           true,
+			ExpectedErrorOutput: "Error: --filename is required unless --schema is set\n",
+		}.TestK8sAndKpack(t, cmdFunc)
+	})
+}
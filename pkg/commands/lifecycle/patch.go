@@ -5,21 +5,27 @@ package lifecycle
 
 import (
 	"fmt"
-	"k8s.io/apimachinery/pkg/runtime"
+	"time"
 
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	"github.com/vmware-tanzu/kpack-cli/pkg/dependents"
 	"github.com/vmware-tanzu/kpack-cli/pkg/dockercreds"
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
 	"github.com/vmware-tanzu/kpack-cli/pkg/lifecycle"
 	"github.com/vmware-tanzu/kpack-cli/pkg/registry"
 )
 
-func NewUpdateCommand(clientSetProvider k8s.ClientSetProvider, rup registry.UtilProvider) *cobra.Command {
+func NewUpdateCommand(clientSetProvider k8s.ClientSetProvider, rup registry.UtilProvider, newWaiter func(dynamic.Interface) commands.ResourceWaiter) *cobra.Command {
 	var (
-		image  string
-		tlsCfg registry.TLSConfig
+		image             string
+		waitForDependents bool
+		tlsCfg            registry.TLSConfig
+		publicKey         string
+		scheduleTimeout   time.Duration
 	)
 
 	cmd := &cobra.Command{
@@ -28,14 +34,23 @@ func NewUpdateCommand(clientSetProvider k8s.ClientSetProvider, rup registry.Util
 		Short:   "Patch lifecycle image used by kpack",
 		Long: `Patch lifecycle image used by kpack
 
-The Lifecycle image will be uploaded to the default repository.
+The Lifecycle image will be uploaded to the lifecycle repository, or to the "lifecycle" repository within the default repository
+when it is not set.
 Therefore, you must have credentials to access the registry on your machine.
 
+The lifecycle image is verified before it is uploaded. Pin the expected digest with <image>@sha256:<digest>
+and provide --public-key to require a cosign signature made with the key.
+
 Env vars can be used for registry auth as described in https://github.com/vmware-tanzu/kpack-cli/blob/main/docs/auth.md
 
 The default repository is read from the "default.repository" key of the "kp-config" ConfigMap within "kpack" namespace.
+The lifecycle repository is read from the "lifecycle.repository" key of the "kp-config" ConfigMap within "kpack" namespace.
+
+With --wait-for-dependents, kp waits for every builder to be rebuilt with the new lifecycle
+and follows the builds of the images using those builders until they finish.
+Images without a new build within --schedule-timeout are reported as such.
 `,
-		Example:      "kp lifecycle patch --image my-registry.com/lifecycle",
+		Example:      "kp lifecycle patch --image my-registry.com/lifecycle\nkp lifecycle patch --image my-registry.com/lifecycle --wait-for-dependents",
 		Args:         commands.ExactArgsWithUsage(0),
 		SilenceUsage: true,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
@@ -55,20 +70,45 @@ The default repository is read from the "default.repository" key of the "kp-conf
 				return err
 			}
 
+			ctx := cmd.Context()
+
+			trackDependents := waitForDependents && !ch.IsDryRun()
+
+			var (
+				deps          dependents.Dependents
+				previousImage string
+			)
+			if trackDependents {
+				deps, err = dependents.Find(ctx, cs.KpackClient, dependents.AnyBuilder)
+				if err != nil {
+					return err
+				}
+
+				previousImage, err = lifecycle.GetImage(ctx, cs.K8sClient)
+				if err != nil {
+					return err
+				}
+			}
+
 			if err = ch.PrintStatus("Patching lifecycle config..."); err != nil {
 				return err
 			}
 
+			fetcher, err := commands.VerifySources(ch, dockercreds.DefaultKeychain, rup.Fetcher(tlsCfg), publicKey, image)
+			if err != nil {
+				return err
+			}
+
 			cfg := lifecycle.ImageUpdaterConfig{
 				DryRun:       ch.IsDryRun(),
 				IOWriter:     ch.Writer(),
-				ImgFetcher:   rup.Fetcher(tlsCfg),
+				ImgFetcher:   fetcher,
 				ImgRelocator: rup.Relocator(ch.Writer(), tlsCfg, ch.CanChangeState()),
 				ClientSet:    cs,
 				TLSConfig:    tlsCfg,
 			}
 
-			configMap, err := lifecycle.UpdateImage(cmd.Context(), dockercreds.DefaultKeychain, image, cfg)
+			configMap, err := lifecycle.UpdateImage(ctx, dockercreds.DefaultKeychain, image, cfg)
 			if err != nil {
 				return err
 			}
@@ -79,11 +119,29 @@ The default repository is read from the "default.repository" key of the "kp-conf
 				return err
 			}
 
-			return ch.PrintResult("Patched lifecycle config")
+			if err = ch.PrintResult("Patched lifecycle config"); err != nil || !trackDependents {
+				return err
+			}
+
+			updatedImage, err := lifecycle.GetImage(ctx, cs.K8sClient)
+			if err != nil {
+				return err
+			}
+
+			if updatedImage == previousImage {
+				return nil
+			}
+
+			tracker := dependents.NewTracker(cs.KpackClient, newWaiter(cs.DynamicClient), ch, commands.GetWaitTimeout(cmd))
+			tracker.ScheduleTimeout = scheduleTimeout
+			return tracker.Track(ctx, deps, commands.BuilderHasRebuilt(deps.BuilderImages()))
 		},
 	}
 	cmd.Flags().StringVarP(&image, "image", "i", "", "location of the image")
+	cmd.Flags().BoolVar(&waitForDependents, "wait-for-dependents", false, "wait for the builders and images to be rebuilt with the new lifecycle")
+	dependents.SetScheduleTimeoutFlag(cmd, &scheduleTimeout)
 	commands.SetImgUploadDryRunOutputFlags(cmd)
 	commands.SetTLSFlags(cmd, &tlsCfg)
+	commands.SetPublicKeyFlag(cmd, &publicKey)
 	return cmd
 }
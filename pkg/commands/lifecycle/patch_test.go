@@ -6,14 +6,22 @@ package lifecycle_test
 import (
 	"testing"
 
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	kpackfakes "github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
 	"github.com/sclevine/spec"
 	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+	commandsfakes "github.com/vmware-tanzu/kpack-cli/pkg/commands/fakes"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands/lifecycle"
 	registryfakes "github.com/vmware-tanzu/kpack-cli/pkg/registry/fakes"
 	"github.com/vmware-tanzu/kpack-cli/pkg/testhelpers"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes/fake"
 )
 
@@ -34,9 +42,13 @@ func testUpdateCommand(t *testing.T, when spec.G, it spec.S) {
 		),
 	}
 
+	fakeWaiter := &commandsfakes.FakeWaiter{}
+
 	cmdFunc := func(k8sClient *fake.Clientset) *cobra.Command {
 		clientSetProvider := testhelpers.GetFakeK8sProvider(k8sClient, "")
-		return lifecycle.NewUpdateCommand(clientSetProvider, fakeRegistryUtilProvider)
+		return lifecycle.NewUpdateCommand(clientSetProvider, fakeRegistryUtilProvider, func(dynamic.Interface) commands.ResourceWaiter {
+			return fakeWaiter
+		})
 	}
 
 	kpConfig := &corev1.ConfigMap{
@@ -135,6 +147,27 @@ Patched lifecycle config
 		}.TestK8s(t, cmdFunc)
 	})
 
+	it("uploads to the lifecycle repository of the kp-config", func() {
+		kpConfig.Data["lifecycle.repository"] = "lifecycle-registry.io/lifecycle-repo"
+
+		testhelpers.CommandTest{
+			Objects: []runtime.Object{
+				kpConfig,
+				lifecycleImageConfig,
+			},
+			Args: []string{
+				"--image", "some-registry.io/repo/lifecycle-image",
+			},
+			ExpectPatches: []string{
+				`{"data":{"image":"lifecycle-registry.io/lifecycle-repo@sha256:lifecycle-image-digest"}}`,
+			},
+			ExpectedOutput: `Patching lifecycle config...
+	Uploading 'lifecycle-registry.io/lifecycle-repo@sha256:lifecycle-image-digest'
+Patched lifecycle config
+`,
+		}.TestK8s(t, cmdFunc)
+	})
+
 	when("output flag is used", func() {
 		it("can output in yaml format", func() {
 			const resourceYAML = `apiVersion: v1
@@ -298,4 +331,115 @@ metadata:
 			})
 		})
 	})
+
+	when("the wait-for-dependents flag is used", func() {
+		clusterCmdFunc := func(k8sClient *fake.Clientset, kpackClient *kpackfakes.Clientset) *cobra.Command {
+			clientSetProvider := testhelpers.GetFakeClusterProvider(k8sClient, kpackClient)
+			return lifecycle.NewUpdateCommand(clientSetProvider, fakeRegistryUtilProvider, func(dynamic.Interface) commands.ResourceWaiter {
+				return fakeWaiter
+			})
+		}
+
+		clusterBuilder := &v1alpha2.ClusterBuilder{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "some-cb",
+			},
+			Status: v1alpha2.BuilderStatus{
+				LatestImage: "some-registry.io/builder@sha256:old",
+			},
+		}
+
+		img := &v1alpha2.Image{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "some-image",
+				Namespace: "some-namespace",
+			},
+			Spec: v1alpha2.ImageSpec{
+				Builder: corev1.ObjectReference{
+					Kind: v1alpha2.ClusterBuilderKind,
+					Name: "some-cb",
+				},
+			},
+			Status: v1alpha2.ImageStatus{
+				BuildCounter: 1,
+			},
+		}
+
+		newBuild := &v1alpha2.Build{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "some-image-build-2",
+				Namespace: "some-namespace",
+				Labels: map[string]string{
+					v1alpha2.ImageLabel:       "some-image",
+					v1alpha2.BuildNumberLabel: "2",
+				},
+				Annotations: map[string]string{
+					v1alpha2.BuildReasonAnnotation: "BUILDPACK",
+				},
+			},
+			Status: v1alpha2.BuildStatus{
+				Status: corev1alpha1.Status{
+					Conditions: corev1alpha1.Conditions{
+						{
+							Type:   corev1alpha1.ConditionSucceeded,
+							Status: corev1.ConditionTrue,
+						},
+					},
+				},
+			},
+		}
+
+		it("waits for the builders and follows the builds of the images using them", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					kpConfig,
+					lifecycleImageConfig,
+					clusterBuilder,
+					img,
+					newBuild,
+				},
+				Args: []string{
+					"--image", "some-registry.io/repo/lifecycle-image",
+					"--wait-for-dependents",
+				},
+				ExpectPatches: []string{
+					`{"data":{"image":"default-registry.io/default-repo/lifecycle@sha256:lifecycle-image-digest"}}`,
+				},
+				ExpectedOutput: `Patching lifecycle config...
+	Uploading 'default-registry.io/default-repo/lifecycle@sha256:lifecycle-image-digest'
+Patched lifecycle config
+Waiting on 1 builder(s) and 1 image(s) that depend on this change...
+Ready: ClusterBuilder 'some-cb'
+Image 'some-namespace/some-image': build 2 succeeded (BUILDPACK)
+`,
+			}.TestK8sAndKpack(t, clusterCmdFunc)
+			require.Len(t, fakeWaiter.WaitCalls, 1)
+			require.Len(t, fakeWaiter.WaitCalls[0].ExtraChecks, 1)
+
+			rebuilt, err := fakeWaiter.WaitCalls[0].ExtraChecks[0](watch.Event{Object: clusterBuilder})
+			require.NoError(t, err)
+			require.False(t, rebuilt)
+		})
+
+		it("does not wait on a dry run", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{
+					kpConfig,
+					lifecycleImageConfig,
+					clusterBuilder,
+					img,
+				},
+				Args: []string{
+					"--image", "some-registry.io/repo/lifecycle-image",
+					"--wait-for-dependents",
+					"--dry-run",
+				},
+				ExpectedOutput: `Patching lifecycle config... (dry run)
+	Skipping 'default-registry.io/default-repo/lifecycle@sha256:lifecycle-image-digest'
+Patched lifecycle config (dry run)
+`,
+			}.TestK8sAndKpack(t, clusterCmdFunc)
+			require.Empty(t, fakeWaiter.WaitCalls)
+		})
+	})
 }
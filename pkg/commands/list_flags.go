@@ -0,0 +1,93 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ListFlags holds the flags shared by the list commands
+type ListFlags struct {
+	Selector      string
+	FieldSelector string
+	SortBy        string
+	NoHeaders     bool
+	Wide          bool
+}
+
+// SetListFlags registers the selector, sorting and output flags on a list command.
+// sortKeys are the values accepted by --sort-by.
+func SetListFlags(cmd *cobra.Command, flags *ListFlags, sortKeys ...string) {
+	cmd.Flags().StringVarP(&flags.Selector, "selector", "l", "", "label selector to filter on, supports '=', '==', '!=', 'in', 'notin' and 'exists' (e.g. -l key1=value1,key2=value2)")
+	cmd.Flags().StringVar(&flags.FieldSelector, "field-selector", "", "field selector to filter on, passed to the server (e.g. --field-selector metadata.name=my-name)")
+	cmd.Flags().StringVar(&flags.SortBy, "sort-by", "", fmt.Sprintf("sort the table in ascending order by one of: %s", strings.Join(sortKeys, ", ")))
+	cmd.Flags().BoolVar(&flags.NoHeaders, "no-headers", false, "do not print the table headers")
+	cmd.Flags().BoolVar(&flags.Wide, "wide", false, "print additional columns")
+}
+
+// ListOptions returns the list options to send to the server
+func (f ListFlags) ListOptions() metav1.ListOptions {
+	return metav1.ListOptions{
+		LabelSelector: f.Selector,
+		FieldSelector: f.FieldSelector,
+	}
+}
+
+// HasSelectors returns true when a label or field selector was provided
+func (f ListFlags) HasSelectors() bool {
+	return f.Selector != "" || f.FieldSelector != ""
+}
+
+// NewTableWriter returns a table writer honoring the --no-headers flag
+func (f ListFlags) NewTableWriter(out io.Writer, headers ...string) (*TableWriter, error) {
+	if f.NoHeaders {
+		return NewHeaderlessTableWriter(out, len(headers)), nil
+	}
+	return NewTableWriter(out, headers...)
+}
+
+// SortItems stably sorts items using the less function registered for key.
+// An empty key leaves the items in their current order.
+func SortItems[T any](items []T, key string, lessFuncs map[string]func(a, b T) bool) error {
+	if key == "" {
+		return nil
+	}
+
+	less, ok := lessFuncs[key]
+	if !ok {
+		var keys []string
+		for k := range lessFuncs {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return errors.Errorf("invalid sort-by key %q, valid keys are: %s", key, strings.Join(keys, ", "))
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return less(items[i], items[j])
+	})
+	return nil
+}
+
+// SortKeys returns the sorted keys of lessFuncs for use in flag usage
+func SortKeys[T any](lessFuncs map[string]func(a, b T) bool) []string {
+	var keys []string
+	for k := range lessFuncs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ByCreationTimestamp compares two object metas by creation time
+func ByCreationTimestamp(a, b metav1.ObjectMeta) bool {
+	return a.CreationTimestamp.Before(&b.CreationTimestamp)
+}
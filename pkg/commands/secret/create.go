@@ -49,12 +49,17 @@ The flags for this command determine the type of secret that will be created:
 
   "--git-url" and "--git-user" to create Basic Auth based git credentials.
   "--git-url" should not contain the repository path (eg. https://github.com not https://github.com/my/repo) 
-  Use the "GIT_PASSWORD" env var to bypass the password prompt.`,
+  Use the "GIT_PASSWORD" env var to bypass the password prompt.
+
+  "--cosign-key" to create a cosign signing key that kpack uses to sign the images built with the service account.
+  "--cosign-repository" optionally sets the repository the signatures are pushed to instead of the image repository.
+  Use the "COSIGN_PASSWORD" env var to bypass the password prompt.`,
 		Example: `kp secret create my-docker-hub-creds --dockerhub dockerhub-id
 kp secret create my-gcr-creds --gcr /path/to/gcr/service-account.json
 kp secret create my-registry-cred --registry example-registry.io --registry-user my-registry-user
 kp secret create my-git-ssh-cred --git-url git@github.com --git-ssh-key /path/to/git/ssh-private-key.pem
-kp secret create my-git-cred --git-url https://github.com --git-user my-git-user`,
+kp secret create my-git-cred --git-url https://github.com --git-user my-git-user
+kp secret create my-cosign-cred --cosign-key /path/to/cosign.key --cosign-repository my-registry.com/my-signatures`,
 		Args:         commands.ExactArgsWithUsage(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -143,6 +148,8 @@ kp secret create my-git-cred --git-url https://github.com --git-user my-git-user
 	cmd.Flags().StringVarP(&secretFactory.GitUrl, "git-url", "", "", "git url")
 	cmd.Flags().StringVarP(&secretFactory.GitSshKeyFile, "git-ssh-key", "", "", "path to a file containing the GitUrl SSH private key")
 	cmd.Flags().StringVarP(&secretFactory.GitUser, "git-user", "", "", "git user")
+	cmd.Flags().StringVar(&secretFactory.CosignKeyFile, "cosign-key", "", "path to a file containing the cosign private key")
+	cmd.Flags().StringVar(&secretFactory.CosignRepository, "cosign-repository", "", "repository to push the cosign signatures to")
 	cmd.Flags().StringVar(&serviceAccount, "service-account", "default", "service account name to use")
 	commands.SetDryRunOutputFlags(cmd)
 	return cmd
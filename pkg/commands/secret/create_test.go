@@ -5,11 +5,13 @@ package secret_test
 
 import (
 	"fmt"
+	"os"
 	"testing"
 
 	"github.com/pkg/errors"
 	"github.com/sclevine/spec"
 	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -349,6 +351,62 @@ func testSecretCreateCommand(t *testing.T, when spec.G, it spec.S) {
 				}.TestK8s(t, cmdFunc)
 			})
 		})
+		when("creating a cosign secret", func() {
+			var (
+				cosignKeyFile    = "./testdata/cosign.key"
+				cosignPassword   = "my-cosign-password"
+				cosignRepository = "my-registry.io/my-signatures"
+				secretName       = "my-cosign-cred"
+			)
+
+			fetcher.passwords["COSIGN_PASSWORD"] = cosignPassword
+
+			it("creates a secret with the key, password and repository annotation and updates the default service account", func() {
+				key, err := os.ReadFile(cosignKeyFile)
+				require.NoError(t, err)
+
+				expectedCosignSecret := &corev1.Secret{
+					ObjectMeta: v1.ObjectMeta{
+						Name:      secretName,
+						Namespace: namespace,
+						Annotations: map[string]string{
+							secret.CosignRepositoryAnnotation: cosignRepository,
+						},
+					},
+					Data: map[string][]byte{
+						secret.CosignKeyDataKey:      key,
+						secret.CosignPasswordDataKey: []byte(cosignPassword),
+					},
+					Type: corev1.SecretTypeOpaque,
+				}
+
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						defaultNamespacedServiceAccount,
+					},
+					Args: []string{secretName, "--cosign-key", cosignKeyFile, "--cosign-repository", cosignRepository, "-n", namespace},
+					ExpectedOutput: `Secret "my-cosign-cred" created for cosign signing
+`,
+					ExpectCreates: []runtime.Object{
+						expectedCosignSecret,
+					},
+					ExpectPatches: []string{
+						`{"metadata":{"annotations":{"kpack.io/managedSecret":"{\"my-cosign-cred\":\"cosign signing\"}"}},"secrets":[{"name":"my-cosign-cred"}]}`,
+					},
+				}.TestK8s(t, cmdFunc)
+			})
+
+			it("errors when the key is not a private key", func() {
+				testhelpers.CommandTest{
+					Objects: []runtime.Object{
+						defaultNamespacedServiceAccount,
+					},
+					Args:                []string{secretName, "--cosign-key", "./testdata/gcr-service-account.json", "-n", namespace},
+					ExpectErr:           true,
+					ExpectedErrorOutput: "Error: cosign key ./testdata/gcr-service-account.json is not a PEM encoded private key\n",
+				}.TestK8s(t, cmdFunc)
+			})
+		})
 	})
 
 	when("namespace is not provided", func() {
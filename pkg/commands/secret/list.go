@@ -20,8 +20,16 @@ func NewListCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 	var (
 		namespace      string
 		serviceAccount string
+		listFlags      commands.ListFlags
 	)
 
+	sorters := map[string]func(a, b secretInfo) bool{
+		"name":      func(a, b secretInfo) bool { return a.name < b.name },
+		"target":    func(a, b secretInfo) bool { return a.target < b.target },
+		"available": func(a, b secretInfo) bool { return !a.isAvailable && b.isAvailable },
+		"created":   func(a, b secretInfo) bool { return a.created.Before(&b.created) },
+	}
+
 	command := cobra.Command{
 		Use:   "list",
 		Short: "List secrets attached to a service account",
@@ -31,8 +39,10 @@ A secret attached to a service account that does not exist in the specified name
 
 The namespace defaults to the kubernetes current-context namespace.
 
-The service account defaults to "default".`,
-		Example:      "kp secret list\nkp secret list -n my-namespace",
+The service account defaults to "default".
+
+When a label or field selector is provided, only the attached secrets matching the selector are listed.`,
+		Example:      "kp secret list\nkp secret list -n my-namespace\nkp secret list -l team=payments --wide",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cs, err := clientSetProvider.GetClientSet(namespace)
@@ -44,7 +54,7 @@ The service account defaults to "default".`,
 			if err != nil {
 				return err
 			}
-			secretsList, err := cs.K8sClient.CoreV1().Secrets(cs.Namespace).List(cmd.Context(), metav1.ListOptions{})
+			secretsList, err := cs.K8sClient.CoreV1().Secrets(cs.Namespace).List(cmd.Context(), listFlags.ListOptions())
 			if err != nil {
 				return err
 			}
@@ -52,29 +62,45 @@ The service account defaults to "default".`,
 			if len(serviceAccount.Secrets) == 0 && len(serviceAccount.ImagePullSecrets) == 0 {
 				return errors.Errorf("no secrets found in %q namespace for %q service account", cs.Namespace, serviceAccount.Name)
 			} else {
-				return displaySecretsTable(cmd, serviceAccount, secretsList)
+				return displaySecretsTable(cmd, serviceAccount, secretsList, listFlags, sorters)
 			}
 		},
 	}
 
 	command.Flags().StringVarP(&namespace, "namespace", "n", "", "kubernetes namespace")
 	command.Flags().StringVar(&serviceAccount, "service-account", "default", "service account to list secrets for")
+	commands.SetListFlags(&command, &listFlags, commands.SortKeys(sorters)...)
 
 	return &command
 }
 
-func displaySecretsTable(cmd *cobra.Command, sa *corev1.ServiceAccount, secretsList *corev1.SecretList) error {
-	secretNames, err := getServiceAccountSecretsInfo(sa, secretsList)
+func displaySecretsTable(cmd *cobra.Command, sa *corev1.ServiceAccount, secretsList *corev1.SecretList, listFlags commands.ListFlags, sorters map[string]func(a, b secretInfo) bool) error {
+	secretInfos, err := getServiceAccountSecretsInfo(sa, secretsList, !listFlags.HasSelectors())
 	if err != nil {
 		return errors.WithMessage(err, "could not retrieve secrets information from service account.")
 	}
-	writer, err := commands.NewTableWriter(cmd.OutOrStdout(), "NAME", "TARGET", "AVAILABLE")
+
+	if err = commands.SortItems(secretInfos, listFlags.SortBy, sorters); err != nil {
+		return err
+	}
+
+	headers := []string{"NAME", "TARGET", "AVAILABLE"}
+	if listFlags.Wide {
+		headers = append(headers, "TYPE")
+	}
+
+	writer, err := listFlags.NewTableWriter(cmd.OutOrStdout(), headers...)
 	if err != nil {
 		return err
 	}
 
-	for _, secret := range secretNames {
-		err = writer.AddRow(secret.name, secret.target, strconv.FormatBool(secret.isAvailable))
+	for _, secret := range secretInfos {
+		row := []string{secret.name, secret.target, strconv.FormatBool(secret.isAvailable)}
+		if listFlags.Wide {
+			row = append(row, string(secret.secretType))
+		}
+
+		err = writer.AddRow(row...)
 		if err != nil {
 			return err
 		}
@@ -83,11 +109,18 @@ func displaySecretsTable(cmd *cobra.Command, sa *corev1.ServiceAccount, secretsL
 	return writer.Write()
 }
 
-func getServiceAccountSecretsInfo(sa *corev1.ServiceAccount, secretsList *corev1.SecretList) ([]struct {
+type secretInfo struct {
 	name        string
 	target      string
 	isAvailable bool
-}, error) {
+	secretType  corev1.SecretType
+	created     metav1.Time
+}
+
+// getServiceAccountSecretsInfo returns the secrets attached to the service account.
+// Secrets missing from secretsList are only included when includeUnavailable is set,
+// as they would otherwise be listed when filtered out by a selector.
+func getServiceAccountSecretsInfo(sa *corev1.ServiceAccount, secretsList *corev1.SecretList, includeUnavailable bool) ([]secretInfo, error) {
 	managedSecrets, err := readManagedSecrets(sa)
 	if err != nil {
 		return nil, err
@@ -100,27 +133,24 @@ func getServiceAccountSecretsInfo(sa *corev1.ServiceAccount, secretsList *corev1
 		secretNameSet[item.Name] = nil
 	}
 
-	var secretNames []struct {
-		name        string
-		target      string
-		isAvailable bool
-	}
+	var secretInfos []secretInfo
 	for name := range secretNameSet {
-		found := false
+		info := secretInfo{name: name, target: managedSecrets[name]}
 		for _, secret := range secretsList.Items {
 			if secret.Name == name {
-				found = true
+				info.isAvailable = true
+				info.secretType = secret.Type
+				info.created = secret.CreationTimestamp
 				break
 			}
 		}
-		secretNames = append(secretNames, struct {
-			name        string
-			target      string
-			isAvailable bool
-		}{name, managedSecrets[name], found})
+		if !info.isAvailable && !includeUnavailable {
+			continue
+		}
+		secretInfos = append(secretInfos, info)
 	}
-	sort.Slice(secretNames, func(i, j int) bool {
-		return secretNames[i].name < secretNames[j].name
+	sort.Slice(secretInfos, func(i, j int) bool {
+		return secretInfos[i].name < secretInfos[j].name
 	})
-	return secretNames, nil
+	return secretInfos, nil
 }
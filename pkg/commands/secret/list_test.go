@@ -211,4 +211,59 @@ secret-two      some-git-url                   false
 			})
 		})
 	})
+
+	when("list flags are provided", func() {
+		serviceAccount := &corev1.ServiceAccount{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "default",
+				Namespace: defaultNamespace,
+				Annotations: map[string]string{
+					secretcmds.ManagedSecretAnnotationKey: `{"secret-one":"https://index.docker.io/v1/", "secret-two":"some-git-url"}`,
+				},
+			},
+			Secrets: []corev1.ObjectReference{
+				{Name: "secret-one"},
+				{Name: "secret-two"},
+				{Name: "secret-three"},
+			},
+		}
+		secretOne := &corev1.Secret{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "secret-one",
+				Namespace: defaultNamespace,
+				Labels:    map[string]string{"team": "payments"},
+			},
+			Type: corev1.SecretTypeDockerConfigJson,
+		}
+		secretTwo := &corev1.Secret{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "secret-two",
+				Namespace: defaultNamespace,
+				Labels:    map[string]string{"team": "search"},
+			},
+			Type: corev1.SecretTypeBasicAuth,
+		}
+
+		it("only lists the attached secrets matching the selector", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{serviceAccount, secretOne, secretTwo},
+				Args:    []string{"-l", "team=payments", "--wide"},
+				ExpectedOutput: `NAME          TARGET                         AVAILABLE    TYPE
+secret-one    https://index.docker.io/v1/    true         kubernetes.io/dockerconfigjson
+
+`,
+			}.TestK8s(t, cmdFunc)
+		})
+
+		it("sorts the secrets by the provided key", func() {
+			testhelpers.CommandTest{
+				Objects: []runtime.Object{serviceAccount, secretOne, secretTwo},
+				Args:    []string{"--sort-by", "available", "--no-headers"},
+				ExpectedOutput: `secret-three                                   false
+secret-one      https://index.docker.io/v1/    true
+secret-two      some-git-url                   true
+`,
+			}.TestK8s(t, cmdFunc)
+		})
+	})
 }
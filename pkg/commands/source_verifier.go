@@ -0,0 +1,39 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"github.com/google/go-containerregistry/pkg/authn"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/registry"
+)
+
+// VerifySources verifies the source images against their pinned
+// <image>@sha256:<digest> digests and, when a public key is provided, their
+// cosign signatures. It returns a fetcher that verifies the source images
+// again when they are fetched to be relocated.
+func VerifySources(ch *CommandHelper, keychain authn.Keychain, fetcher registry.Fetcher, publicKeyPath string, sources ...string) (registry.Fetcher, error) {
+	verifyingFetcher := registry.NewVerifyingFetcher(fetcher)
+
+	var verification registry.Verification
+	if publicKeyPath != "" {
+		publicKey, err := registry.ReadPublicKey(publicKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		verification.PublicKey = publicKey
+	}
+
+	for _, src := range sources {
+		verifyingFetcher.Expect(src, verification)
+	}
+
+	if publicKeyPath != "" {
+		if err := ch.PrintStatus("Verifying source image signatures..."); err != nil {
+			return nil, err
+		}
+	}
+
+	return verifyingFetcher, verifyingFetcher.VerifyAll(keychain)
+}
@@ -14,6 +14,7 @@ import (
 
 type TableWriter struct {
 	numColumns int
+	noHeaders  bool
 	writer     *tabwriter.Writer
 }
 
@@ -31,6 +32,16 @@ func NewTableWriter(out io.Writer, headers ...string) (*TableWriter, error) {
 	}, nil
 }
 
+// NewHeaderlessTableWriter returns a table writer that prints neither a header
+// row nor the trailing blank line, which is easier to consume from scripts.
+func NewHeaderlessTableWriter(out io.Writer, numColumns int) *TableWriter {
+	return &TableWriter{
+		numColumns: numColumns,
+		noHeaders:  true,
+		writer:     tabwriter.NewWriter(out, 0, 4, 4, ' ', 0),
+	}
+}
+
 func (w *TableWriter) AddRow(columns ...string) error {
 	if len(columns) != w.numColumns {
 		return errors.New("incorrect number of columns for row")
@@ -41,9 +52,11 @@ func (w *TableWriter) AddRow(columns ...string) error {
 }
 
 func (w *TableWriter) Write() error {
-	_, err := fmt.Fprintln(w.writer, "")
-	if err != nil {
-		return err
+	if !w.noHeaders {
+		_, err := fmt.Fprintln(w.writer, "")
+		if err != nil {
+			return err
+		}
 	}
 	return w.writer.Flush()
 }
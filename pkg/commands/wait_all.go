@@ -0,0 +1,122 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	watchTools "k8s.io/client-go/tools/watch"
+	"knative.dev/pkg/kmeta"
+)
+
+// WaitTimeoutFlag is the name of the global flag that bounds how long to wait on resources
+const WaitTimeoutFlag = "wait-timeout"
+
+// GetWaitTimeout returns the value of the global --wait-timeout flag or the
+// default when the command is not attached to the root command
+func GetWaitTimeout(cmd *cobra.Command) time.Duration {
+	if timeout, err := cmd.Flags().GetDuration(WaitTimeoutFlag); err == nil {
+		return timeout
+	}
+	return DefaultWaitTimeout
+}
+
+type WaitPrinter interface {
+	Printlnf(format string, args ...interface{}) error
+}
+
+// WaitTarget is a resource to wait on along with any checks beyond the resource being ready
+type WaitTarget struct {
+	Object      runtime.Object
+	ExtraChecks []watchTools.ConditionFunc
+}
+
+type generationProgress func(observed, expected int64)
+
+type progressWaiter interface {
+	waitWithProgress(ctx context.Context, ob runtime.Object, progress generationProgress, extraConditions ...watchTools.ConditionFunc) error
+}
+
+// WaitAll waits on all targets concurrently and prints a summary of the
+// resources that became ready and the ones that failed. When the waiter
+// supports it, the observed generation of each resource is printed as it changes.
+func WaitAll(ctx context.Context, waiter ResourceWaiter, printer WaitPrinter, targets ...WaitTarget) error {
+	if len(targets) == 0 {
+		return nil
+	}
+
+	var (
+		wg      sync.WaitGroup
+		printMu sync.Mutex
+		errs    = make([]error, len(targets))
+	)
+
+	for idx, target := range targets {
+		wg.Add(1)
+		go func(idx int, target WaitTarget) {
+			defer wg.Done()
+
+			pw, ok := waiter.(progressWaiter)
+			if !ok {
+				errs[idx] = waiter.Wait(ctx, target.Object, target.ExtraChecks...)
+				return
+			}
+
+			lastObserved := int64(-1)
+			errs[idx] = pw.waitWithProgress(ctx, target.Object, func(observed, expected int64) {
+				if observed == lastObserved {
+					return
+				}
+				lastObserved = observed
+
+				printMu.Lock()
+				defer printMu.Unlock()
+				_ = printer.Printlnf("%s: observed generation %d of %d", describeTarget(target.Object), observed, expected)
+			}, target.ExtraChecks...)
+		}(idx, target)
+	}
+	wg.Wait()
+
+	var ready []string
+	var failed []string
+	for idx, target := range targets {
+		if errs[idx] != nil {
+			failed = append(failed, fmt.Sprintf("%s: %s", describeTarget(target.Object), errs[idx]))
+		} else {
+			ready = append(ready, describeTarget(target.Object))
+		}
+	}
+
+	if len(ready) > 0 {
+		if err := printer.Printlnf("Ready: %s", strings.Join(ready, ", ")); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range failed {
+		if err := printer.Printlnf("Failed: %s", f); err != nil {
+			return err
+		}
+	}
+
+	if len(failed) > 0 {
+		return errors.Errorf("%d of %d resources did not become ready", len(failed), len(targets))
+	}
+	return nil
+}
+
+func describeTarget(ob runtime.Object) string {
+	refable, ok := ob.(kmeta.OwnerRefable)
+	if !ok {
+		return fmt.Sprintf("%T", ob)
+	}
+	return fmt.Sprintf("%s '%s'", refable.GetGroupVersionKind().Kind, refable.GetObjectMeta().GetName())
+}
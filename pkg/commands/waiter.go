@@ -24,14 +24,23 @@ import (
 	"knative.dev/pkg/kmeta"
 )
 
-const defaultWaitTimeout = 10 * time.Minute
+// DefaultWaitTimeout is the default time to wait for a resource to become ready
+const DefaultWaitTimeout = 10 * time.Minute
 
 type ResourceWaiter interface {
 	Wait(ctx context.Context, object runtime.Object, extraChecks ...watchTools.ConditionFunc) error
 }
 
 func NewResourceWaiter(dc dynamic.Interface) ResourceWaiter {
-	return NewWaiter(dc, defaultWaitTimeout)
+	return NewWaiter(dc, DefaultWaitTimeout)
+}
+
+// NewResourceWaiterWithTimeout returns a constructor of resource waiters that
+// reads the timeout when a waiter is created, so it can be bound to a flag
+func NewResourceWaiterWithTimeout(timeout *time.Duration) func(dynamic.Interface) ResourceWaiter {
+	return func(dc dynamic.Interface) ResourceWaiter {
+		return NewWaiter(dc, *timeout)
+	}
 }
 
 type Waiter struct {
@@ -45,11 +54,15 @@ func NewWaiter(dc dynamic.Interface, timeout time.Duration) *Waiter {
 }
 
 func (w *Waiter) Wait(ctx context.Context, ob runtime.Object, extraConditions ...watchTools.ConditionFunc) error {
+	return w.waitWithProgress(ctx, ob, nil, extraConditions...)
+}
+
+func (w *Waiter) waitWithProgress(ctx context.Context, ob runtime.Object, progress generationProgress, extraConditions ...watchTools.ConditionFunc) error {
 	m, ok := ob.(kmeta.OwnerRefable)
 	if !ok {
 		return errors.New("unexpected type")
 	}
-	return w.wait(ctx, ob, hasResolved(m.GetObjectMeta().GetGeneration()), extraConditions...)
+	return w.wait(ctx, ob, hasResolved(m.GetObjectMeta().GetGeneration(), progress), extraConditions...)
 }
 
 func (w *Waiter) wait(ctx context.Context, ob runtime.Object, condition watchTools.ConditionFunc, extraConditions ...watchTools.ConditionFunc) error {
@@ -126,13 +139,17 @@ func eventToDuck(e *watch.Event) (*duckv1.KResource, error) {
 	return statusDuck, nil
 }
 
-func hasResolved(expectedGeneration int64) func(event watch.Event) (bool, error) {
+func hasResolved(expectedGeneration int64, progress generationProgress) func(event watch.Event) (bool, error) {
 	return func(event watch.Event) (bool, error) {
 		genObservable, err := eventToDuck(&event)
 		if err != nil {
 			return false, err
 		}
 
+		if progress != nil {
+			progress(genObservable.Status.ObservedGeneration, expectedGeneration)
+		}
+
 		if genObservable.Status.ObservedGeneration < expectedGeneration {
 			return false, nil // still waiting on update
 		}
@@ -6,6 +6,7 @@ package commands
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -158,6 +159,97 @@ func testWaiter(t *testing.T, when spec.G, it spec.S) {
 			require.NoError(t, waiter.Wait(context.Background(), resourceToWatch))
 		})
 	})
+
+	when("WaitAll", func() {
+		var (
+			resourceToWatch *v1alpha2.Builder
+			printer         *fakeWaitPrinter
+		)
+
+		it.Before(func() {
+			resourceToWatch = &v1alpha2.Builder{
+				TypeMeta: v1.TypeMeta{
+					Kind: "Builder",
+				},
+				ObjectMeta: v1.ObjectMeta{
+					Name:            "some-name",
+					Namespace:       "some-namespace",
+					ResourceVersion: "1",
+					Generation:      generation,
+				},
+			}
+			watcher = &TestWatcher{
+				events:           make(chan watch.Event, 100),
+				expectedResource: resourceToWatch,
+			}
+			dynamicClient.PrependWatchReactor("builders", watcher.watchReactor)
+			printer = &fakeWaitPrinter{}
+		})
+
+		it("prints the observed generation and a summary of ready resources", func() {
+			resourceToWatch.Status = v1alpha2.BuilderStatus{
+				Status: conditionReady(corev1.ConditionUnknown, generation-1),
+			}
+
+			builderObj := &v1alpha2.Builder{
+				TypeMeta:   resourceToWatch.TypeMeta,
+				ObjectMeta: resourceToWatch.ObjectMeta,
+				Status:     v1alpha2.BuilderStatus{Status: conditionReady(corev1.ConditionTrue, generation)},
+			}
+			content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(builderObj)
+			require.NoError(t, err)
+			watcher.addEvent(watch.Event{
+				Type:   watch.Modified,
+				Object: &unstructured.Unstructured{Object: content},
+			})
+
+			require.NoError(t, WaitAll(context.Background(), waiter, printer, WaitTarget{Object: resourceToWatch}))
+			require.Equal(t, []string{
+				"Builder 'some-name': observed generation 1 of 2",
+				"Builder 'some-name': observed generation 2 of 2",
+				"Ready: Builder 'some-name'",
+			}, printer.lines)
+		})
+
+		it("waits on every resource and reports the ones that failed", func() {
+			resourceToWatch.Status = v1alpha2.BuilderStatus{
+				Status: conditionReady(corev1.ConditionFalse, generation),
+			}
+
+			readyBuilder := resourceToWatch.DeepCopy()
+			readyBuilder.Name = "other-name"
+			readyBuilder.Status = v1alpha2.BuilderStatus{
+				Status: conditionReady(corev1.ConditionTrue, generation),
+			}
+
+			err := WaitAll(context.Background(), waiter, printer, WaitTarget{Object: resourceToWatch}, WaitTarget{Object: readyBuilder})
+			require.EqualError(t, err, "1 of 2 resources did not become ready")
+
+			require.Len(t, printer.lines, 4)
+			require.ElementsMatch(t, []string{
+				"Builder 'some-name': observed generation 2 of 2",
+				"Builder 'other-name': observed generation 2 of 2",
+			}, printer.lines[:2])
+			require.Equal(t, []string{
+				"Ready: Builder 'other-name'",
+				"Failed: Builder 'some-name': Builder \"some-name\" not ready: some-message",
+			}, printer.lines[2:])
+		})
+
+		it("does not print anything without targets", func() {
+			require.NoError(t, WaitAll(context.Background(), waiter, printer))
+			require.Empty(t, printer.lines)
+		})
+	})
+}
+
+type fakeWaitPrinter struct {
+	lines []string
+}
+
+func (p *fakeWaitPrinter) Printlnf(format string, args ...interface{}) error {
+	p.lines = append(p.lines, fmt.Sprintf(format, args...))
+	return nil
 }
 
 type fakeConditionChecker struct {
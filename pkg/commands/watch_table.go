@@ -0,0 +1,133 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/build"
+)
+
+const watchRefreshInterval = time.Second
+
+// WatchTable describes the table redrawn by the watch commands
+type WatchTable struct {
+	Headers []string
+	Rows    func(now time.Time) [][]string
+	// Empty is printed below the headers when there are no rows
+	Empty string
+}
+
+// LiveTable redraws a table in place on a terminal
+type LiveTable struct {
+	out   io.Writer
+	lines int
+}
+
+func NewLiveTable(out io.Writer) *LiveTable {
+	return &LiveTable{out: out}
+}
+
+// Render clears the previously rendered table and prints the new one in its place
+func (t *LiveTable) Render(headers []string, rows [][]string, empty string) error {
+	var buf bytes.Buffer
+	writer, err := NewTableWriter(&buf, headers...)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if err := writer.AddRow(row...); err != nil {
+			return err
+		}
+	}
+
+	if err := writer.Write(); err != nil {
+		return err
+	}
+
+	if len(rows) == 0 && empty != "" {
+		buf.WriteString(empty + "\n")
+	}
+
+	if t.lines > 0 {
+		// move the cursor to the start of the previous table and clear to the end of the screen
+		if _, err := fmt.Fprintf(t.out, "\x1b[%dA\x1b[J", t.lines); err != nil {
+			return err
+		}
+	}
+
+	t.lines = strings.Count(buf.String(), "\n")
+	_, err = t.out.Write(buf.Bytes())
+	return err
+}
+
+// IsTerminal reports whether the writer is a terminal
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Watch runs the watcher until the context is done. When live is set the
+// table is redrawn on every change and every second to keep elapsed times
+// current, otherwise one line is printed for each build transition.
+func Watch(ctx context.Context, watcher *build.Watcher, out io.Writer, live bool, table WatchTable) error {
+	if !live {
+		err := watcher.Start(ctx, build.WatchHandler{
+			OnTransition: func(t build.Transition) {
+				_, _ = fmt.Fprintln(out, t)
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		<-ctx.Done()
+		return nil
+	}
+
+	changed := make(chan struct{}, 1)
+	err := watcher.Start(ctx, build.WatchHandler{
+		OnChange: func() {
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(watchRefreshInterval)
+	defer ticker.Stop()
+
+	liveTable := NewLiveTable(out)
+	for {
+		if err := liveTable.Render(table.Headers, table.Rows(time.Now()), table.Empty); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changed:
+		case <-ticker.C:
+		}
+	}
+}
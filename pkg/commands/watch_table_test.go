@@ -0,0 +1,43 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+)
+
+func TestLiveTable(t *testing.T) {
+	spec.Run(t, "TestLiveTable", testLiveTable)
+}
+
+func testLiveTable(t *testing.T, when spec.G, it spec.S) {
+	it("clears the previous table before rendering the next one", func() {
+		out := &bytes.Buffer{}
+		table := commands.NewLiveTable(out)
+
+		require.NoError(t, table.Render([]string{"Name", "Step"}, [][]string{{"some-image", "detect"}}, "nothing"))
+		require.Equal(t, "NAME          STEP\nsome-image    detect\n\n", out.String())
+
+		out.Reset()
+		require.NoError(t, table.Render([]string{"Name", "Step"}, [][]string{{"some-image", "build"}}, "nothing"))
+		require.Equal(t, "\x1b[3A\x1b[J"+"NAME          STEP\nsome-image    build\n\n", out.String())
+	})
+
+	it("prints the empty message when there are no rows", func() {
+		out := &bytes.Buffer{}
+
+		require.NoError(t, commands.NewLiveTable(out).Render([]string{"Name", "Step"}, nil, "No builds in progress"))
+		require.Equal(t, "NAME    STEP\n\nNo builds in progress\n", out.String())
+	})
+
+	it("does not treat a buffer as a terminal", func() {
+		require.False(t, commands.IsTerminal(&bytes.Buffer{}))
+	})
+}
@@ -22,11 +22,40 @@ const (
 	canonicalRepositoryKey              = "canonical.repository"                          // historical key
 	canonicalServiceAccountNameKey      = "canonical.repository.serviceaccount"           // historical key
 	canonicalServiceAccountNamespaceKey = "canonical.repository.serviceaccount.namespace" // historical key
+	repositoryKeySuffix                 = ".repository"
 )
 
+// Artifact is a class of images that kp uploads. The images of each artifact
+// are uploaded to the repository of the artifact in the kp-config, or to the
+// default repository when it is not set.
+type Artifact string
+
+const (
+	StackArtifact        Artifact = "stack"
+	BuildpackageArtifact Artifact = "buildpackage"
+	LifecycleArtifact    Artifact = "lifecycle"
+	SourceArtifact       Artifact = "source"
+)
+
+var Artifacts = []Artifact{StackArtifact, BuildpackageArtifact, LifecycleArtifact, SourceArtifact}
+
+func ParseArtifact(artifact string) (Artifact, error) {
+	for _, a := range Artifacts {
+		if string(a) == artifact {
+			return a, nil
+		}
+	}
+	return "", errors.Errorf("unknown artifact '%s', must be one of stack, buildpackage, lifecycle or source", artifact)
+}
+
+func (a Artifact) key() string {
+	return string(a) + repositoryKeySuffix
+}
+
 type KpConfig struct {
 	defaultRepository string
 	serviceAccount    corev1.ObjectReference
+	repositories      map[Artifact]string
 }
 
 func NewKpConfig(defaultRepository string, serviceAccount corev1.ObjectReference) KpConfig {
@@ -44,6 +73,40 @@ func (c KpConfig) DefaultRepository() (string, error) {
 	return sanitize(c.defaultRepository), nil
 }
 
+// Repository returns the repository that the images of the artifact are
+// uploaded to, which is the default repository unless the kp-config sets a
+// repository for the artifact
+func (c KpConfig) Repository(artifact Artifact) (string, error) {
+	if repo := c.ArtifactRepository(artifact); repo != "" {
+		return repo, nil
+	}
+
+	return c.DefaultRepository()
+}
+
+// ArtifactRepository returns the repository that the kp-config sets for the
+// artifact or an empty string when it is not set
+func (c KpConfig) ArtifactRepository(artifact Artifact) string {
+	return sanitize(c.repositories[artifact])
+}
+
+// WithRepository returns the kp-config with the repository of the artifact
+// set to the repository, such as to override it for a single resource. The
+// kp-config is unchanged when the repository is empty.
+func (c KpConfig) WithRepository(artifact Artifact, repository string) KpConfig {
+	if repository == "" {
+		return c
+	}
+
+	repositories := map[Artifact]string{}
+	for a, repo := range c.repositories {
+		repositories[a] = repo
+	}
+	repositories[artifact] = repository
+	c.repositories = repositories
+	return c
+}
+
 func (c KpConfig) ServiceAccount() corev1.ObjectReference {
 	if c.serviceAccount.Name == "" {
 		return corev1.ObjectReference{Name: "default", Namespace: kpConfigNamespace}
@@ -84,12 +147,23 @@ func (d KpConfigProvider) GetKpConfig(ctx context.Context) KpConfig {
 		}
 	}
 
+	var repositories map[Artifact]string
+	for _, artifact := range Artifacts {
+		if artifactRepo := kpConfig.Data[artifact.key()]; artifactRepo != "" {
+			if repositories == nil {
+				repositories = map[Artifact]string{}
+			}
+			repositories[artifact] = artifactRepo
+		}
+	}
+
 	return KpConfig{
 		defaultRepository: repo,
 		serviceAccount: corev1.ObjectReference{
 			Name:      serviceAccountName,
 			Namespace: serviceAccountNamespace,
 		},
+		repositories: repositories,
 	}
 }
 
@@ -109,6 +183,49 @@ func (d KpConfigProvider) SetDefaultRepository(ctx context.Context, defaultRepos
 	return d.updateDefaultRepository(ctx, existingKpConfig, defaultRepository)
 }
 
+// SetRepository sets the repository of the artifact, an empty repository
+// removes it so that the images of the artifact are uploaded to the default
+// repository again
+func (d KpConfigProvider) SetRepository(ctx context.Context, artifact Artifact, repository string) error {
+	existingKpConfig, err := d.getKpConfigMap(ctx)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	if k8serrors.IsNotFound(err) {
+		if repository == "" {
+			return nil
+		}
+
+		return d.createKpConfigMap(ctx, map[string]string{
+			artifact.key(): repository,
+		})
+	}
+
+	updatedConfig := existingKpConfig.DeepCopy()
+	if updatedConfig.Data == nil {
+		updatedConfig.Data = map[string]string{}
+	}
+
+	if repository == "" {
+		delete(updatedConfig.Data, artifact.key())
+	} else {
+		updatedConfig.Data[artifact.key()] = repository
+	}
+
+	patch, err := k8s.CreatePatch(existingKpConfig, updatedConfig)
+	if err != nil {
+		return err
+	}
+
+	if patch == nil {
+		return nil
+	}
+
+	_, err = d.client.CoreV1().ConfigMaps(kpConfigNamespace).Patch(ctx, updatedConfig.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
 func (d KpConfigProvider) SetDefaultServiceAccount(ctx context.Context, serviceAccount corev1.ObjectReference) error {
 	existingKpConfig, err := d.getKpConfigMap(ctx)
 	if err != nil && !k8serrors.IsNotFound(err) {
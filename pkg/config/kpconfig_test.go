@@ -32,6 +32,41 @@ func testKpConfig(t *testing.T, when spec.G, it spec.S) {
 			require.Equal(t, want, got)
 		})
 	})
+
+	when("Repository", func() {
+		it("returns the repository of the artifact", func() {
+			kpConfig := NewKpConfig("some-repo", corev1.ObjectReference{}).
+				WithRepository(StackArtifact, "some-stack-repo/")
+			got, err := kpConfig.Repository(StackArtifact)
+			require.NoError(t, err)
+			require.Equal(t, "some-stack-repo", got)
+		})
+
+		it("falls back to the default repository", func() {
+			kpConfig := NewKpConfig("some-repo", corev1.ObjectReference{}).
+				WithRepository(StackArtifact, "some-stack-repo")
+			got, err := kpConfig.Repository(BuildpackageArtifact)
+			require.NoError(t, err)
+			require.Equal(t, "some-repo", got)
+			require.Empty(t, kpConfig.ArtifactRepository(BuildpackageArtifact))
+		})
+	})
+
+	when("WithRepository", func() {
+		it("does not change the kp-config it was called on", func() {
+			kpConfig := NewKpConfig("some-repo", corev1.ObjectReference{}).
+				WithRepository(StackArtifact, "some-stack-repo")
+			_ = kpConfig.WithRepository(StackArtifact, "some-other-stack-repo")
+			require.Equal(t, "some-stack-repo", kpConfig.ArtifactRepository(StackArtifact))
+		})
+
+		it("keeps the repository when the override is empty", func() {
+			kpConfig := NewKpConfig("some-repo", corev1.ObjectReference{}).
+				WithRepository(StackArtifact, "some-stack-repo").
+				WithRepository(StackArtifact, "")
+			require.Equal(t, "some-stack-repo", kpConfig.ArtifactRepository(StackArtifact))
+		})
+	})
 }
 
 func testKpConfigProvider(t *testing.T, when spec.G, it spec.S) {
@@ -83,6 +118,32 @@ func testKpConfigProvider(t *testing.T, when spec.G, it spec.S) {
 				serviceAccount:    corev1.ObjectReference{Name: "some-canonical-sa", Namespace: "some-canonical-ns"},
 			}, provider.GetKpConfig(ctx))
 		})
+
+		it("reads the repositories of the artifacts", func() {
+			kpConfig := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kp-config",
+					Namespace: "kpack",
+				},
+				Data: map[string]string{
+					"default.repository":      "some-repo",
+					"stack.repository":        "some-stack-repo",
+					"buildpackage.repository": "some-buildpackage-repo",
+				},
+			}
+
+			listers := kpacktesthelpers.NewListers([]runtime.Object{kpConfig})
+			k8sClient := k8sfakes.NewSimpleClientset(listers.GetKubeObjects()...)
+			provider := NewKpConfigProvider(k8sClient)
+			require.Equal(t, KpConfig{
+				defaultRepository: "some-repo",
+				serviceAccount:    corev1.ObjectReference{Namespace: "kpack"},
+				repositories: map[Artifact]string{
+					StackArtifact:        "some-stack-repo",
+					BuildpackageArtifact: "some-buildpackage-repo",
+				},
+			}, provider.GetKpConfig(ctx))
+		})
 	})
 
 	when("SetDefaultRepository", func() {
@@ -99,6 +160,33 @@ func testKpConfigProvider(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("SetRepository", func() {
+		it("writes the key of the artifact to the config map", func() {
+			k8sClient := k8sfakes.NewSimpleClientset()
+			provider := NewKpConfigProvider(k8sClient)
+			require.NoError(t, provider.SetRepository(ctx, SourceArtifact, "some-source-repo"))
+			kpConfig, err := k8sClient.CoreV1().ConfigMaps("kpack").Get(ctx, "kp-config", metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Equal(t, map[string]string{
+				"source.repository": "some-source-repo",
+			}, kpConfig.Data)
+		})
+
+		it("removes the key of the artifact when the repository is empty", func() {
+			k8sClient := k8sfakes.NewSimpleClientset()
+			provider := NewKpConfigProvider(k8sClient)
+			require.NoError(t, provider.SetDefaultRepository(ctx, "some-repo"))
+			require.NoError(t, provider.SetRepository(ctx, SourceArtifact, "some-source-repo"))
+			require.NoError(t, provider.SetRepository(ctx, SourceArtifact, ""))
+			kpConfig, err := k8sClient.CoreV1().ConfigMaps("kpack").Get(ctx, "kp-config", metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Equal(t, map[string]string{
+				"canonical.repository": "some-repo",
+				"default.repository":   "some-repo",
+			}, kpConfig.Data)
+		})
+	})
+
 	when("SetDefaultServiceAccount", func() {
 		it("writes both sets of keys to the config map", func() {
 			k8sClient := k8sfakes.NewSimpleClientset()
@@ -0,0 +1,118 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package dependents
+
+import (
+	"context"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	"github.com/pivotal/kpack/pkg/client/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Matcher reports whether a builder depends on the changed cluster resource
+type Matcher func(spec v1alpha2.BuilderSpec) bool
+
+func UsesClusterStack(name string) Matcher {
+	return func(spec v1alpha2.BuilderSpec) bool {
+		return spec.Stack.Kind == v1alpha2.ClusterStackKind && spec.Stack.Name == name
+	}
+}
+
+func UsesClusterStore(name string) Matcher {
+	return func(spec v1alpha2.BuilderSpec) bool {
+		return spec.Store.Kind == v1alpha2.ClusterStoreKind && spec.Store.Name == name
+	}
+}
+
+// AnyBuilder matches every builder, such as when the lifecycle image changes
+func AnyBuilder(v1alpha2.BuilderSpec) bool {
+	return true
+}
+
+// Dependents are the builders that use a cluster resource and the images that use those builders
+type Dependents struct {
+	ClusterBuilders []v1alpha2.ClusterBuilder
+	Builders        []v1alpha2.Builder
+	// Images are captured before the change so their build counter tells new builds apart
+	Images []v1alpha2.Image
+}
+
+func (d Dependents) IsEmpty() bool {
+	return len(d.ClusterBuilders) == 0 && len(d.Builders) == 0
+}
+
+// BuilderImages returns the latest images of the builders keyed by their
+// namespace and name, as they were before the change
+func (d Dependents) BuilderImages() map[string]string {
+	images := map[string]string{}
+	for _, cb := range d.ClusterBuilders {
+		images[types.NamespacedName{Name: cb.Name}.String()] = cb.Status.LatestImage
+	}
+	for _, b := range d.Builders {
+		images[types.NamespacedName{Namespace: b.Namespace, Name: b.Name}.String()] = b.Status.LatestImage
+	}
+	return images
+}
+
+// Find returns the builders matching the matcher and the images in any namespace that use them
+func Find(ctx context.Context, client versioned.Interface, matches Matcher) (Dependents, error) {
+	var deps Dependents
+
+	cbList, err := client.KpackV1alpha2().ClusterBuilders().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return deps, err
+	}
+
+	clusterBuilders := map[string]bool{}
+	for _, cb := range cbList.Items {
+		if matches(cb.Spec.BuilderSpec) {
+			deps.ClusterBuilders = append(deps.ClusterBuilders, cb)
+			clusterBuilders[cb.Name] = true
+		}
+	}
+
+	builderList, err := client.KpackV1alpha2().Builders("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return deps, err
+	}
+
+	builders := map[string]bool{}
+	for _, b := range builderList.Items {
+		if matches(b.Spec.BuilderSpec) {
+			deps.Builders = append(deps.Builders, b)
+			builders[b.Namespace+"/"+b.Name] = true
+		}
+	}
+
+	if deps.IsEmpty() {
+		return deps, nil
+	}
+
+	imageList, err := client.KpackV1alpha2().Images("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return deps, err
+	}
+
+	for _, img := range imageList.Items {
+		ref := img.Spec.Builder
+		switch ref.Kind {
+		case v1alpha2.ClusterBuilderKind:
+			if clusterBuilders[ref.Name] {
+				deps.Images = append(deps.Images, img)
+			}
+		case v1alpha2.BuilderKind:
+			namespace := ref.Namespace
+			if namespace == "" {
+				namespace = img.Namespace
+			}
+			if builders[namespace+"/"+ref.Name] {
+				deps.Images = append(deps.Images, img)
+			}
+		}
+	}
+
+	return deps, nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package dependents_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	"github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/dependents"
+)
+
+func TestFind(t *testing.T) {
+	spec.Run(t, "TestFind", testFind)
+}
+
+func testFind(t *testing.T, when spec.G, it spec.S) {
+	makeClusterBuilder := func(name, stack, store string) *v1alpha2.ClusterBuilder {
+		return &v1alpha2.ClusterBuilder{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1alpha2.ClusterBuilderSpec{
+				BuilderSpec: v1alpha2.BuilderSpec{
+					Stack: corev1.ObjectReference{Kind: v1alpha2.ClusterStackKind, Name: stack},
+					Store: corev1.ObjectReference{Kind: v1alpha2.ClusterStoreKind, Name: store},
+				},
+			},
+		}
+	}
+
+	makeBuilder := func(namespace, name, stack, store string) *v1alpha2.Builder {
+		return &v1alpha2.Builder{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: v1alpha2.NamespacedBuilderSpec{
+				BuilderSpec: v1alpha2.BuilderSpec{
+					Stack: corev1.ObjectReference{Kind: v1alpha2.ClusterStackKind, Name: stack},
+					Store: corev1.ObjectReference{Kind: v1alpha2.ClusterStoreKind, Name: store},
+				},
+			},
+		}
+	}
+
+	makeImage := func(namespace, name string, builder corev1.ObjectReference) *v1alpha2.Image {
+		return &v1alpha2.Image{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       v1alpha2.ImageSpec{Builder: builder},
+		}
+	}
+
+	client := fake.NewSimpleClientset(
+		makeClusterBuilder("cb-one", "base", "default"),
+		makeClusterBuilder("cb-two", "full", "default"),
+		makeBuilder("ns-one", "builder", "base", "other"),
+		makeBuilder("ns-two", "builder", "full", "other"),
+		makeImage("ns-one", "uses-cb-one", corev1.ObjectReference{Kind: v1alpha2.ClusterBuilderKind, Name: "cb-one"}),
+		makeImage("ns-one", "uses-cb-two", corev1.ObjectReference{Kind: v1alpha2.ClusterBuilderKind, Name: "cb-two"}),
+		makeImage("ns-one", "uses-builder", corev1.ObjectReference{Kind: v1alpha2.BuilderKind, Name: "builder"}),
+		makeImage("ns-two", "uses-builder", corev1.ObjectReference{Kind: v1alpha2.BuilderKind, Name: "builder"}),
+	)
+
+	names := func(deps dependents.Dependents) []string {
+		var result []string
+		for _, cb := range deps.ClusterBuilders {
+			result = append(result, "ClusterBuilder/"+cb.Name)
+		}
+		for _, b := range deps.Builders {
+			result = append(result, "Builder/"+b.Namespace+"/"+b.Name)
+		}
+		for _, img := range deps.Images {
+			result = append(result, "Image/"+img.Namespace+"/"+img.Name)
+		}
+		return result
+	}
+
+	it("finds the builders using a cluster stack and the images using them", func() {
+		deps, err := dependents.Find(context.Background(), client, dependents.UsesClusterStack("base"))
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{
+			"ClusterBuilder/cb-one",
+			"Builder/ns-one/builder",
+			"Image/ns-one/uses-cb-one",
+			"Image/ns-one/uses-builder",
+		}, names(deps))
+	})
+
+	it("finds the builders using a cluster store and the images using them", func() {
+		deps, err := dependents.Find(context.Background(), client, dependents.UsesClusterStore("other"))
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{
+			"Builder/ns-one/builder",
+			"Builder/ns-two/builder",
+			"Image/ns-one/uses-builder",
+			"Image/ns-two/uses-builder",
+		}, names(deps))
+	})
+
+	it("finds every builder and image", func() {
+		deps, err := dependents.Find(context.Background(), client, dependents.AnyBuilder)
+		require.NoError(t, err)
+		require.Len(t, deps.ClusterBuilders, 2)
+		require.Len(t, deps.Builders, 2)
+		require.Len(t, deps.Images, 4)
+	})
+
+	it("returns no dependents when no builder matches", func() {
+		deps, err := dependents.Find(context.Background(), client, dependents.UsesClusterStack("unused"))
+		require.NoError(t, err)
+		require.True(t, deps.IsEmpty())
+		require.Empty(t, deps.Images)
+	})
+}
@@ -0,0 +1,317 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package dependents
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	"github.com/pivotal/kpack/pkg/client/clientset/versioned"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	watchTools "k8s.io/client-go/tools/watch"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/build"
+	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
+)
+
+const (
+	// DefaultScheduleTimeout is how long to wait for kpack to schedule a build
+	// for an image once its builder is ready
+	DefaultScheduleTimeout = 30 * time.Second
+
+	scheduleTimeoutFlag      = "schedule-timeout"
+	scheduleTimeoutFlagUsage = "how long to wait for kpack to schedule a build of an image once its builder is ready, with --wait-for-dependents"
+)
+
+// SetScheduleTimeoutFlag adds the schedule timeout flag to the commands that wait for dependents
+func SetScheduleTimeoutFlag(cmd *cobra.Command, scheduleTimeout *time.Duration) {
+	cmd.Flags().DurationVar(scheduleTimeout, scheduleTimeoutFlag, DefaultScheduleTimeout, scheduleTimeoutFlagUsage)
+}
+
+type Tracker struct {
+	KpackClient versioned.Interface
+	Waiter      commands.ResourceWaiter
+	Printer     commands.WaitPrinter
+	// Timeout bounds how long to follow the builds of the images
+	Timeout time.Duration
+	// ScheduleTimeout is how long to wait for kpack to schedule a new build
+	// of an image, images without one by then are reported as such
+	ScheduleTimeout time.Duration
+}
+
+func NewTracker(client versioned.Interface, waiter commands.ResourceWaiter, printer commands.WaitPrinter, timeout time.Duration) Tracker {
+	return Tracker{
+		KpackClient:     client,
+		Waiter:          waiter,
+		Printer:         printer,
+		Timeout:         timeout,
+		ScheduleTimeout: DefaultScheduleTimeout,
+	}
+}
+
+// ImageResult is the outcome of the rebuild of an image
+type ImageResult struct {
+	Image     string
+	Namespace string
+	// Build is the number of the new build, it is empty when no build was scheduled
+	Build  string
+	Status string
+	Reason string
+	Err    error
+}
+
+func (r ImageResult) failed() bool {
+	return r.Err != nil || r.Status == "FAILURE"
+}
+
+func (r ImageResult) String() string {
+	name := fmt.Sprintf("Image '%s/%s'", r.Namespace, r.Image)
+	switch {
+	case r.Err != nil:
+		return fmt.Sprintf("%s: %s", name, r.Err)
+	case r.Build == "":
+		return fmt.Sprintf("%s: no build scheduled", name)
+	}
+
+	outcome := "succeeded"
+	if r.Status == "FAILURE" {
+		outcome = "failed"
+	}
+
+	if r.Reason == "" {
+		return fmt.Sprintf("%s: build %s %s", name, r.Build, outcome)
+	}
+	return fmt.Sprintf("%s: build %s %s (%s)", name, r.Build, outcome, r.Reason)
+}
+
+// Track waits for the dependent builders to become ready, with the extra
+// builder checks, then follows the builds of the dependent images until
+// they finish and prints the outcome for each image.
+func (t Tracker) Track(ctx context.Context, deps Dependents, builderChecks ...watchTools.ConditionFunc) error {
+	if deps.IsEmpty() {
+		return t.Printer.Printlnf("No builders depend on this change")
+	}
+
+	err := t.Printer.Printlnf("Waiting on %d builder(s) and %d image(s) that depend on this change...", len(deps.ClusterBuilders)+len(deps.Builders), len(deps.Images))
+	if err != nil {
+		return err
+	}
+
+	var targets []commands.WaitTarget
+	for i := range deps.ClusterBuilders {
+		targets = append(targets, commands.WaitTarget{Object: &deps.ClusterBuilders[i], ExtraChecks: builderChecks})
+	}
+	for i := range deps.Builders {
+		targets = append(targets, commands.WaitTarget{Object: &deps.Builders[i], ExtraChecks: builderChecks})
+	}
+
+	if err := commands.WaitAll(ctx, t.Waiter, t.Printer, targets...); err != nil {
+		return err
+	}
+
+	if len(deps.Images) == 0 {
+		return nil
+	}
+
+	if t.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.Timeout)
+		defer cancel()
+	}
+
+	namespaces := map[string][]int{}
+	for i, img := range deps.Images {
+		namespaces[img.Namespace] = append(namespaces[img.Namespace], i)
+	}
+
+	results := make([]ImageResult, len(deps.Images))
+	var wg sync.WaitGroup
+	for namespace, indexes := range namespaces {
+		images := make([]v1alpha2.Image, 0, len(indexes))
+		for _, i := range indexes {
+			images = append(images, deps.Images[i])
+		}
+
+		wg.Add(1)
+		go func(namespace string, indexes []int, images []v1alpha2.Image) {
+			defer wg.Done()
+			for i, result := range t.trackNamespace(ctx, namespace, images) {
+				results[indexes[i]] = result
+			}
+		}(namespace, indexes, images)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, r := range results {
+		if r.failed() {
+			failed++
+		}
+		if err := t.Printer.Printlnf("%s", r); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return errors.Errorf("%d of %d images did not rebuild successfully", failed, len(results))
+	}
+	return nil
+}
+
+// trackNamespace follows the builds of the images of a namespace with a single
+// watch on their builds until every image has a finished build, or has no new
+// build once the schedule timeout passed
+func (t Tracker) trackNamespace(ctx context.Context, namespace string, images []v1alpha2.Image) []ImageResult {
+	trackers := make([]*imageTracker, len(images))
+	byName := map[string]*imageTracker{}
+	names := make([]string, len(images))
+	for i, img := range images {
+		trackers[i] = &imageTracker{
+			result:      ImageResult{Image: img.Name, Namespace: img.Namespace},
+			latestBuild: img.Status.BuildCounter,
+		}
+		byName[img.Name] = trackers[i]
+		names[i] = img.Name
+	}
+
+	results := func() []ImageResult {
+		results := make([]ImageResult, len(trackers))
+		for i, it := range trackers {
+			results[i] = it.result
+		}
+		return results
+	}
+
+	fail := func(err error) []ImageResult {
+		for _, it := range trackers {
+			if !it.done {
+				it.result.Err = err
+			}
+		}
+		return results()
+	}
+
+	selector := fmt.Sprintf("%s in (%s)", v1alpha2.ImageLabel, strings.Join(names, ","))
+	scheduleTimer := time.NewTimer(t.ScheduleTimeout)
+	defer scheduleTimer.Stop()
+	scheduleTimedOut := false
+
+	for {
+		buildList, err := t.KpackClient.KpackV1alpha2().Builds(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return fail(err)
+		}
+
+		for i := range buildList.Items {
+			bld := buildList.Items[i]
+			if it, ok := byName[bld.Labels[v1alpha2.ImageLabel]]; ok {
+				it.observe(&bld)
+			}
+		}
+
+		if allDone(trackers) {
+			return results()
+		}
+
+		watcher, err := t.KpackClient.KpackV1alpha2().Builds(namespace).Watch(ctx, metav1.ListOptions{
+			LabelSelector:   selector,
+			ResourceVersion: buildList.ResourceVersion,
+		})
+		if err != nil {
+			return fail(err)
+		}
+
+		for open := true; open; {
+			select {
+			case <-ctx.Done():
+				watcher.Stop()
+				for _, it := range trackers {
+					it.timedOut()
+				}
+				return results()
+			case <-scheduleTimer.C:
+				scheduleTimedOut = true
+				for _, it := range trackers {
+					it.scheduleTimedOut()
+				}
+			case event, ok := <-watcher.ResultChan():
+				if !ok {
+					// the watch expired, list the builds again and start a new watch
+					open = false
+					continue
+				}
+
+				if bld, isBuild := event.Object.(*v1alpha2.Build); isBuild && event.Type != watch.Deleted {
+					if it, ok := byName[bld.Labels[v1alpha2.ImageLabel]]; ok {
+						it.observe(bld)
+						if scheduleTimedOut {
+							it.scheduleTimedOut()
+						}
+					}
+				}
+			}
+
+			if allDone(trackers) {
+				watcher.Stop()
+				return results()
+			}
+		}
+	}
+}
+
+// imageTracker is the progress of the rebuild of a single image
+type imageTracker struct {
+	result      ImageResult
+	latestBuild int64
+	done        bool
+}
+
+// observe records a build of the image when it is newer than the builds seen so far
+func (it *imageTracker) observe(bld *v1alpha2.Build) {
+	number, err := strconv.ParseInt(bld.Labels[v1alpha2.BuildNumberLabel], 10, 64)
+	if err != nil || number < it.latestBuild || (number == it.latestBuild && it.result.Build == "") {
+		return
+	}
+
+	it.latestBuild = number
+	it.result.Build = bld.Labels[v1alpha2.BuildNumberLabel]
+	it.result.Status = build.Status(*bld)
+	it.result.Reason = strings.Join(build.Reasons(*bld), ",")
+	it.done = it.result.Status == "SUCCESS" || it.result.Status == "FAILURE"
+}
+
+// scheduleTimedOut stops waiting on the image when no build was scheduled for it
+func (it *imageTracker) scheduleTimedOut() {
+	if it.result.Build == "" {
+		it.done = true
+	}
+}
+
+func (it *imageTracker) timedOut() {
+	if it.done {
+		return
+	}
+
+	it.result.Err = errors.New("timed out waiting for the build to finish")
+	if it.result.Build == "" {
+		it.result.Err = errors.New("timed out waiting for a build to be scheduled")
+	}
+}
+
+func allDone(trackers []*imageTracker) bool {
+	for _, it := range trackers {
+		if !it.done {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,196 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package dependents_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	"github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/pkg/errors"
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	commandsfakes "github.com/vmware-tanzu/kpack-cli/pkg/commands/fakes"
+	"github.com/vmware-tanzu/kpack-cli/pkg/dependents"
+)
+
+func TestTracker(t *testing.T) {
+	spec.Run(t, "TestTracker", testTracker)
+}
+
+func testTracker(t *testing.T, when spec.G, it spec.S) {
+	var (
+		printer *fakePrinter
+		waiter  *commandsfakes.FakeWaiter
+	)
+
+	clusterBuilder := v1alpha2.ClusterBuilder{
+		ObjectMeta: metav1.ObjectMeta{Name: "some-cb"},
+	}
+
+	makeImage := func(name string) v1alpha2.Image {
+		return v1alpha2.Image{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "some-namespace"},
+			Status:     v1alpha2.ImageStatus{BuildCounter: 1},
+		}
+	}
+
+	makeBuild := func(image, number string, status corev1.ConditionStatus) *v1alpha2.Build {
+		return &v1alpha2.Build{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      image + "-build-" + number,
+				Namespace: "some-namespace",
+				Labels: map[string]string{
+					v1alpha2.ImageLabel:       image,
+					v1alpha2.BuildNumberLabel: number,
+				},
+				Annotations: map[string]string{
+					v1alpha2.BuildReasonAnnotation: "STACK",
+				},
+			},
+			Status: v1alpha2.BuildStatus{
+				Status: corev1alpha1.Status{
+					Conditions: corev1alpha1.Conditions{
+						{Type: corev1alpha1.ConditionSucceeded, Status: status},
+					},
+				},
+			},
+		}
+	}
+
+	newTracker := func(objs ...runtime.Object) dependents.Tracker {
+		tracker := dependents.NewTracker(fake.NewSimpleClientset(objs...), waiter, printer, time.Second)
+		tracker.ScheduleTimeout = 0
+		return tracker
+	}
+
+	it.Before(func() {
+		printer = &fakePrinter{}
+		waiter = &commandsfakes.FakeWaiter{}
+	})
+
+	it("reports the outcome of the new build of each image", func() {
+		tracker := newTracker(
+			makeBuild("image-one", "1", corev1.ConditionTrue),
+			makeBuild("image-one", "2", corev1.ConditionTrue),
+			makeBuild("image-two", "2", corev1.ConditionFalse),
+			makeBuild("image-three", "1", corev1.ConditionTrue),
+		)
+
+		err := tracker.Track(context.Background(), dependents.Dependents{
+			ClusterBuilders: []v1alpha2.ClusterBuilder{clusterBuilder},
+			Images:          []v1alpha2.Image{makeImage("image-one"), makeImage("image-two"), makeImage("image-three")},
+		})
+		require.EqualError(t, err, "1 of 3 images did not rebuild successfully")
+		require.Equal(t, []string{
+			"Waiting on 1 builder(s) and 3 image(s) that depend on this change...",
+			"Ready: ClusterBuilder 'some-cb'",
+			"Image 'some-namespace/image-one': build 2 succeeded (STACK)",
+			"Image 'some-namespace/image-two': build 2 failed (STACK)",
+			"Image 'some-namespace/image-three': no build scheduled",
+		}, printer.lines)
+	})
+
+	it("follows the builds of the images of a namespace with a single watch", func() {
+		client := fake.NewSimpleClientset(makeBuild("image-one", "1", corev1.ConditionTrue))
+		tracker := dependents.NewTracker(client, waiter, printer, time.Second)
+
+		go func() {
+			for countWatches(client) == 0 {
+				time.Sleep(5 * time.Millisecond)
+			}
+
+			_, err := client.KpackV1alpha2().Builds("some-namespace").Create(context.Background(), makeBuild("image-one", "2", corev1.ConditionTrue), metav1.CreateOptions{})
+			require.NoError(t, err)
+			_, err = client.KpackV1alpha2().Builds("some-namespace").Create(context.Background(), makeBuild("image-two", "2", corev1.ConditionTrue), metav1.CreateOptions{})
+			require.NoError(t, err)
+		}()
+
+		err := tracker.Track(context.Background(), dependents.Dependents{
+			ClusterBuilders: []v1alpha2.ClusterBuilder{clusterBuilder},
+			Images:          []v1alpha2.Image{makeImage("image-one"), makeImage("image-two")},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"Waiting on 1 builder(s) and 2 image(s) that depend on this change...",
+			"Ready: ClusterBuilder 'some-cb'",
+			"Image 'some-namespace/image-one': build 2 succeeded (STACK)",
+			"Image 'some-namespace/image-two': build 2 succeeded (STACK)",
+		}, printer.lines)
+
+		require.Equal(t, 1, countWatches(client))
+	})
+
+	it("times out when no build is scheduled within the timeout", func() {
+		tracker := newTracker()
+		tracker.Timeout = 50 * time.Millisecond
+		tracker.ScheduleTimeout = time.Minute
+
+		err := tracker.Track(context.Background(), dependents.Dependents{
+			ClusterBuilders: []v1alpha2.ClusterBuilder{clusterBuilder},
+			Images:          []v1alpha2.Image{makeImage("image-one")},
+		})
+		require.EqualError(t, err, "1 of 1 images did not rebuild successfully")
+		require.Contains(t, printer.lines, "Image 'some-namespace/image-one': timed out waiting for a build to be scheduled")
+	})
+
+	it("times out when a build does not finish", func() {
+		tracker := newTracker(makeBuild("image-one", "2", corev1.ConditionUnknown))
+		tracker.Timeout = 50 * time.Millisecond
+
+		err := tracker.Track(context.Background(), dependents.Dependents{
+			ClusterBuilders: []v1alpha2.ClusterBuilder{clusterBuilder},
+			Images:          []v1alpha2.Image{makeImage("image-one")},
+		})
+		require.EqualError(t, err, "1 of 1 images did not rebuild successfully")
+		require.Contains(t, printer.lines, "Image 'some-namespace/image-one': timed out waiting for the build to finish")
+	})
+
+	it("does not follow images when a builder does not become ready", func() {
+		waiter.Errors = map[string]error{"some-cb": errors.New("some-message")}
+		tracker := newTracker()
+
+		err := tracker.Track(context.Background(), dependents.Dependents{
+			ClusterBuilders: []v1alpha2.ClusterBuilder{clusterBuilder},
+			Images:          []v1alpha2.Image{makeImage("image-one")},
+		})
+		require.EqualError(t, err, "1 of 1 resources did not become ready")
+		require.Equal(t, []string{
+			"Waiting on 1 builder(s) and 1 image(s) that depend on this change...",
+			"Failed: ClusterBuilder 'some-cb': some-message",
+		}, printer.lines)
+	})
+
+	it("reports when nothing depends on the change", func() {
+		require.NoError(t, newTracker().Track(context.Background(), dependents.Dependents{}))
+		require.Equal(t, []string{"No builders depend on this change"}, printer.lines)
+	})
+}
+
+type fakePrinter struct {
+	lines []string
+}
+
+func (p *fakePrinter) Printlnf(format string, args ...interface{}) error {
+	p.lines = append(p.lines, fmt.Sprintf(format, args...))
+	return nil
+}
+
+func countWatches(client *fake.Clientset) int {
+	count := 0
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "watch" {
+			count++
+		}
+	}
+	return count
+}
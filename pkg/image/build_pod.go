@@ -0,0 +1,279 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+)
+
+var buildPodResources = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourceEphemeralStorage}
+
+var tolerationEffects = []corev1.TaintEffect{corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute}
+
+// setBuildPod applies the resources, scheduling and timeout settings of the
+// build pod. Settings are added or replaced by name and deleting a setting
+// that the image resource does not have is an error. Settings provided as
+// pointers are only changed when set, an empty value removes them. An affinity
+// file takes precedence over an affinity declared inline.
+func (f *Factory) setBuildPod(build *v1alpha2.ImageBuild) error {
+	var err error
+	build.Resources.Requests, err = updateResourceList("request", build.Resources.Requests, f.ResourceRequests, f.DeleteResourceRequests)
+	if err != nil {
+		return err
+	}
+
+	build.Resources.Limits, err = updateResourceList("limit", build.Resources.Limits, f.ResourceLimits, f.DeleteResourceLimits)
+	if err != nil {
+		return err
+	}
+
+	if err := validateResources(build.Resources); err != nil {
+		return err
+	}
+
+	build.NodeSelector, err = updateNodeSelector(build.NodeSelector, f.NodeSelector, f.DeleteNodeSelector)
+	if err != nil {
+		return err
+	}
+
+	build.Tolerations, err = updateTolerations(build.Tolerations, f.Tolerations, f.DeleteTolerations)
+	if err != nil {
+		return err
+	}
+
+	if f.AffinityFile != nil {
+		build.Affinity, err = readAffinityFile(*f.AffinityFile)
+		if err != nil {
+			return err
+		}
+	} else if f.Affinity != nil {
+		build.Affinity = f.Affinity.DeepCopy()
+	}
+
+	if f.RuntimeClassName != nil {
+		build.RuntimeClassName = nil
+		if *f.RuntimeClassName != "" {
+			runtimeClassName := *f.RuntimeClassName
+			build.RuntimeClassName = &runtimeClassName
+		}
+	}
+
+	if f.SchedulerName != nil {
+		build.SchedulerName = *f.SchedulerName
+	}
+
+	if f.BuildTimeout != nil {
+		build.BuildTimeout, err = parseBuildTimeout(*f.BuildTimeout)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func updateResourceList(kind string, list corev1.ResourceList, add, remove []string) (corev1.ResourceList, error) {
+	updated := corev1.ResourceList{}
+	for name, quantity := range list {
+		updated[name] = quantity
+	}
+
+	for _, name := range remove {
+		if _, ok := updated[corev1.ResourceName(name)]; !ok {
+			return nil, errors.Errorf("delete-%s parameter '%s' not found in existing image configuration", kind, name)
+		}
+		delete(updated, corev1.ResourceName(name))
+	}
+
+	for _, r := range add {
+		idx := strings.Index(r, "=")
+		if idx == -1 {
+			return nil, errors.Errorf("%s %q is improperly formatted, expected NAME=QUANTITY such as memory=4Gi", kind, r)
+		}
+
+		name := corev1.ResourceName(r[:idx])
+		if !containsResourceName(buildPodResources, name) {
+			return nil, errors.Errorf("%s %q is not supported, must be one of cpu, memory or ephemeral-storage", kind, name)
+		}
+
+		quantity, err := resource.ParseQuantity(r[idx+1:])
+		if err != nil {
+			return nil, errors.Errorf("invalid %s %s %q, must be valid quantity ex. 4Gi", name, kind, r[idx+1:])
+		}
+		updated[name] = quantity
+	}
+
+	if len(updated) == 0 {
+		return nil, nil
+	}
+	return updated, nil
+}
+
+func containsResourceName(names []corev1.ResourceName, name corev1.ResourceName) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func validateResources(resources corev1.ResourceRequirements) error {
+	for _, name := range buildPodResources {
+		request, hasRequest := resources.Requests[name]
+		limit, hasLimit := resources.Limits[name]
+		if hasRequest && hasLimit && request.Cmp(limit) > 0 {
+			return errors.Errorf("%s request %s must be less than or equal to the %s limit %s", name, request.String(), name, limit.String())
+		}
+	}
+	return nil
+}
+
+func updateNodeSelector(selector map[string]string, add, remove []string) (map[string]string, error) {
+	updated := map[string]string{}
+	for k, v := range selector {
+		updated[k] = v
+	}
+
+	for _, key := range remove {
+		if _, ok := updated[key]; !ok {
+			return nil, errors.Errorf("delete-node-selector parameter '%s' not found in existing image configuration", key)
+		}
+		delete(updated, key)
+	}
+
+	for _, s := range add {
+		idx := strings.Index(s, "=")
+		if idx <= 0 {
+			return nil, errors.Errorf("node selector %q is improperly formatted, expected KEY=VALUE", s)
+		}
+		updated[s[:idx]] = s[idx+1:]
+	}
+
+	if len(updated) == 0 {
+		return nil, nil
+	}
+	return updated, nil
+}
+
+func updateTolerations(tolerations []corev1.Toleration, add, remove []string) ([]corev1.Toleration, error) {
+	updated := append([]corev1.Toleration{}, tolerations...)
+
+	for _, key := range remove {
+		var kept []corev1.Toleration
+		for _, t := range updated {
+			if t.Key != key {
+				kept = append(kept, t)
+			}
+		}
+		if len(kept) == len(updated) {
+			return nil, errors.Errorf("delete-toleration parameter '%s' not found in existing image configuration", key)
+		}
+		updated = kept
+	}
+
+	for _, t := range add {
+		toleration, err := parseToleration(t)
+		if err != nil {
+			return nil, err
+		}
+
+		replaced := false
+		for i, existing := range updated {
+			if existing.Key == toleration.Key && existing.Effect == toleration.Effect {
+				updated[i] = toleration
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			updated = append(updated, toleration)
+		}
+	}
+
+	if len(updated) == 0 {
+		return nil, nil
+	}
+	return updated, nil
+}
+
+// parseToleration parses a KEY[=VALUE][:EFFECT] toleration, the taint syntax
+// of kubectl. A toleration without a value tolerates any value of the key and
+// a toleration without an effect tolerates all effects.
+func parseToleration(s string) (corev1.Toleration, error) {
+	toleration := corev1.Toleration{Operator: corev1.TolerationOpExists}
+
+	keyValue := s
+	if idx := strings.LastIndex(s, ":"); idx != -1 {
+		keyValue = s[:idx]
+		toleration.Effect = corev1.TaintEffect(s[idx+1:])
+		if !containsTaintEffect(tolerationEffects, toleration.Effect) {
+			return corev1.Toleration{}, errors.Errorf("toleration %q has an invalid effect, must be one of NoSchedule, PreferNoSchedule or NoExecute", s)
+		}
+	}
+
+	toleration.Key = keyValue
+	if idx := strings.Index(keyValue, "="); idx != -1 {
+		toleration.Key = keyValue[:idx]
+		toleration.Value = keyValue[idx+1:]
+		toleration.Operator = corev1.TolerationOpEqual
+	}
+
+	if toleration.Key == "" {
+		return corev1.Toleration{}, errors.Errorf("toleration %q is improperly formatted, expected KEY[=VALUE][:EFFECT]", s)
+	}
+	return toleration, nil
+}
+
+func containsTaintEffect(effects []corev1.TaintEffect, effect corev1.TaintEffect) bool {
+	for _, e := range effects {
+		if e == effect {
+			return true
+		}
+	}
+	return false
+}
+
+// readAffinityFile reads a build pod affinity from a yaml or json file, an
+// empty path removes the affinity
+func readAffinityFile(path string) (*corev1.Affinity, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	affinity := &corev1.Affinity{}
+	if err := yaml.UnmarshalStrict(buf, affinity); err != nil {
+		return nil, errors.Wrapf(err, "invalid affinity file %q", path)
+	}
+	return affinity, nil
+}
+
+// parseBuildTimeout parses a duration such as 90m into the build timeout in
+// seconds, an empty value removes the timeout
+func parseBuildTimeout(timeout string) (*int64, error) {
+	if timeout == "" {
+		return nil, nil
+	}
+
+	d, err := time.ParseDuration(timeout)
+	if err != nil || d < time.Second {
+		return nil, errors.Errorf("invalid build timeout %q, must be a duration of at least one second ex. 90m", timeout)
+	}
+
+	seconds := int64(d / time.Second)
+	return &seconds, nil
+}
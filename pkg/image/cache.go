@@ -0,0 +1,144 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const defaultCacheSize = "2G"
+
+func (f *Factory) validateCache() error {
+	volumeSet := f.CacheSize != "" || f.CacheStorageClass != ""
+
+	if f.NoCache && (volumeSet || f.CacheImage != "") {
+		return errors.New("no-cache is incompatible with cache-size, cache-storage-class and cache-image")
+	}
+
+	if f.CacheImage != "" && volumeSet {
+		return errors.New("cache-image is incompatible with cache-size and cache-storage-class")
+	}
+
+	if f.CacheImage != "" {
+		if _, err := name.NewTag(f.CacheImage, name.WeakValidation); err != nil {
+			return errors.Wrapf(err, "invalid cache image %q", f.CacheImage)
+		}
+	}
+
+	return nil
+}
+
+// makeCache returns the cache of a new image resource, nil leaves the
+// default volume cache of the cluster and an empty config disables caching
+func (f *Factory) makeCache() (*v1alpha2.ImageCacheConfig, error) {
+	switch {
+	case f.NoCache:
+		return &v1alpha2.ImageCacheConfig{}, nil
+	case f.CacheImage != "":
+		return &v1alpha2.ImageCacheConfig{Registry: &v1alpha2.RegistryCache{Tag: f.CacheImage}}, nil
+	case f.CacheSize != "" || f.CacheStorageClass != "":
+		volume, err := f.makeVolumeCache(nil)
+		if err != nil {
+			return nil, err
+		}
+		return &v1alpha2.ImageCacheConfig{Volume: volume}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// setCache switches the image resource to the requested cache. A volume cache
+// can only grow and keep its storage class unless the factory recreates it.
+func (f *Factory) setCache(image *v1alpha2.Image) error {
+	switch {
+	case f.NoCache:
+		image.Spec.Cache = &v1alpha2.ImageCacheConfig{}
+	case f.CacheImage != "":
+		image.Spec.Cache = &v1alpha2.ImageCacheConfig{Registry: &v1alpha2.RegistryCache{Tag: f.CacheImage}}
+	case f.CacheSize != "" || f.CacheStorageClass != "":
+		current := volumeCache(image)
+		volume, err := f.makeVolumeCache(current)
+		if err != nil {
+			return err
+		}
+
+		if current != nil && !f.RecreateCache {
+			if volume.Size.Cmp(*current.Size) < 0 {
+				return errors.Errorf("cache size cannot be decreased, current: %v, requested: %v", current.Size, volume.Size)
+			}
+			if volume.StorageClassName != current.StorageClassName {
+				return errors.Errorf("cache storage class cannot be changed, current: %q, requested: %q", current.StorageClassName, volume.StorageClassName)
+			}
+		}
+
+		image.Spec.Cache = &v1alpha2.ImageCacheConfig{Volume: volume}
+	}
+	return nil
+}
+
+// CacheRecreateRequired returns whether the requested cache shrinks the
+// volume cache or changes its storage class. The volume cache of the image
+// resource then has to be removed before it is recreated with RecreateCache.
+func (f *Factory) CacheRecreateRequired(image *v1alpha2.Image) (bool, error) {
+	current := volumeCache(image)
+	if current == nil || f.NoCache || f.CacheImage != "" || (f.CacheSize == "" && f.CacheStorageClass == "") {
+		return false, nil
+	}
+
+	volume, err := f.makeVolumeCache(current)
+	if err != nil {
+		return false, err
+	}
+
+	return volume.Size.Cmp(*current.Size) < 0 || volume.StorageClassName != current.StorageClassName, nil
+}
+
+// makeVolumeCache returns the requested volume cache, settings that are not
+// provided are kept from the current volume cache or defaulted
+func (f *Factory) makeVolumeCache(current *v1alpha2.ImagePersistentVolumeCache) (*v1alpha2.ImagePersistentVolumeCache, error) {
+	volume := &v1alpha2.ImagePersistentVolumeCache{StorageClassName: f.CacheStorageClass}
+	if current != nil {
+		size := current.Size.DeepCopy()
+		volume.Size = &size
+		if volume.StorageClassName == "" {
+			volume.StorageClassName = current.StorageClassName
+		}
+	} else {
+		size := resource.MustParse(defaultCacheSize)
+		volume.Size = &size
+	}
+
+	if f.CacheSize != "" {
+		size, err := f.getCacheSize()
+		if err != nil {
+			return nil, err
+		}
+		volume.Size = size
+	}
+
+	return volume, nil
+}
+
+func (f *Factory) getCacheSize() (*resource.Quantity, error) {
+	c, err := resource.ParseQuantity(f.CacheSize)
+	if err != nil {
+		return nil, errors.New("invalid cache size, must be valid quantity ex. 2G")
+	}
+
+	if c.Sign() <= 0 {
+		return nil, errors.New("cache size must be greater than 0")
+	}
+
+	return &c, nil
+}
+
+func volumeCache(image *v1alpha2.Image) *v1alpha2.ImagePersistentVolumeCache {
+	if !image.Spec.NeedVolumeCache() {
+		return nil
+	}
+	return image.Spec.Cache.Volume
+}
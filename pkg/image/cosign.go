@@ -0,0 +1,64 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"strings"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	"github.com/pkg/errors"
+)
+
+// setCosign applies the annotations that kpack adds to the cosign signatures
+// of built images. Annotations are added or replaced by name and the cosign
+// config is removed when no annotations are left.
+func (f *Factory) setCosign(image *v1alpha2.Image) error {
+	if len(f.CosignAnnotations) == 0 && len(f.DeleteCosignAnnotations) == 0 {
+		return nil
+	}
+
+	var annotations []v1alpha2.CosignAnnotation
+	if image.Spec.Cosign != nil {
+		annotations = append(annotations, image.Spec.Cosign.Annotations...)
+	}
+
+	for _, name := range f.DeleteCosignAnnotations {
+		var kept []v1alpha2.CosignAnnotation
+		for _, a := range annotations {
+			if a.Name != name {
+				kept = append(kept, a)
+			}
+		}
+		if len(kept) == len(annotations) {
+			return errors.Errorf("delete-cosign-annotation parameter '%s' not found in existing image configuration", name)
+		}
+		annotations = kept
+	}
+
+	for _, a := range f.CosignAnnotations {
+		idx := strings.Index(a, "=")
+		if idx <= 0 {
+			return errors.Errorf("cosign annotation %q is improperly formatted, expected KEY=VALUE", a)
+		}
+		annotations = setCosignAnnotation(annotations, v1alpha2.CosignAnnotation{Name: a[:idx], Value: a[idx+1:]})
+	}
+
+	if len(annotations) == 0 {
+		image.Spec.Cosign = nil
+		return nil
+	}
+
+	image.Spec.Cosign = &v1alpha2.CosignConfig{Annotations: annotations}
+	return nil
+}
+
+func setCosignAnnotation(annotations []v1alpha2.CosignAnnotation, annotation v1alpha2.CosignAnnotation) []v1alpha2.CosignAnnotation {
+	for i, a := range annotations {
+		if a.Name == annotation.Name {
+			annotations[i] = annotation
+			return annotations
+		}
+	}
+	return append(annotations, annotation)
+}
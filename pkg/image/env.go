@@ -0,0 +1,153 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// makeEnvVars returns the build env vars from the env files, the env flags
+// and the secret and config map references, in that order. A variable
+// declared more than once takes the last value provided.
+func (f *Factory) makeEnvVars() ([]corev1.EnvVar, error) {
+	var envVars []corev1.EnvVar
+	for _, path := range f.EnvFile {
+		fileVars, err := readEnvFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range fileVars {
+			envVars = setEnvVar(envVars, e)
+		}
+	}
+
+	for _, e := range f.Env {
+		idx := strings.Index(e, "=")
+		if idx == -1 {
+			return nil, errors.Errorf("env vars are improperly formatted")
+		}
+		envVars = setEnvVar(envVars, corev1.EnvVar{
+			Name:  e[:idx],
+			Value: e[idx+1:],
+		})
+	}
+
+	for _, e := range f.EnvFromSecret {
+		name, ref, key, err := parseEnvFrom("env-from-secret", e)
+		if err != nil {
+			return nil, err
+		}
+		envVars = setEnvVar(envVars, corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: ref},
+					Key:                  key,
+				},
+			},
+		})
+	}
+
+	for _, e := range f.EnvFromConfigMap {
+		name, ref, key, err := parseEnvFrom("env-from-configmap", e)
+		if err != nil {
+			return nil, err
+		}
+		envVars = setEnvVar(envVars, corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: ref},
+					Key:                  key,
+				},
+			},
+		})
+	}
+	return envVars, nil
+}
+
+func setEnvVar(envVars []corev1.EnvVar, envVar corev1.EnvVar) []corev1.EnvVar {
+	for i, e := range envVars {
+		if e.Name == envVar.Name {
+			envVars[i] = envVar
+			return envVars
+		}
+	}
+	return append(envVars, envVar)
+}
+
+// parseEnvFrom parses a [ENV_NAME=]NAME:KEY reference to the key of a secret
+// or config map. The env var is named after the key unless ENV_NAME is provided.
+func parseEnvFrom(flag, value string) (envName, name, key string, err error) {
+	ref := value
+	if idx := strings.Index(value, "="); idx != -1 {
+		envName, ref = value[:idx], value[idx+1:]
+	}
+
+	parts := strings.Split(ref, ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" || (envName == "" && strings.Contains(value, "=")) {
+		return "", "", "", errors.Errorf("%s value %q is improperly formatted, expected [ENV_NAME=]NAME:KEY", flag, value)
+	}
+
+	name, key = parts[0], parts[1]
+	if envName == "" {
+		envName = key
+	}
+	return envName, name, key, nil
+}
+
+// readEnvFile reads a dotenv file of NAME=value lines. Blank lines and lines
+// starting with # are ignored, an "export " prefix is allowed and values may
+// be single or double quoted.
+func readEnvFile(path string) ([]corev1.EnvVar, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var envVars []corev1.EnvVar
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		idx := strings.Index(line, "=")
+		if idx <= 0 {
+			return nil, errors.Errorf("env file %s is improperly formatted on line %d, expected NAME=value", path, lineNumber)
+		}
+
+		name := strings.TrimSpace(line[:idx])
+		value, err := unquoteEnvValue(strings.TrimSpace(line[idx+1:]))
+		if err != nil {
+			return nil, errors.Wrapf(err, "env file %s is improperly formatted on line %d", path, lineNumber)
+		}
+		envVars = setEnvVar(envVars, corev1.EnvVar{Name: name, Value: value})
+	}
+	return envVars, scanner.Err()
+}
+
+func unquoteEnvValue(value string) (string, error) {
+	if len(value) < 2 {
+		return value, nil
+	}
+
+	switch {
+	case value[0] == '"' && value[len(value)-1] == '"':
+		return strconv.Unquote(value)
+	case value[0] == '\'' && value[len(value)-1] == '\'':
+		return value[1 : len(value)-1], nil
+	default:
+		return value, nil
+	}
+}
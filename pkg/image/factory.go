@@ -15,7 +15,6 @@ import (
 	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/dockercreds"
@@ -51,8 +50,15 @@ type Factory struct {
 	Builder                   string
 	ClusterBuilder            string
 	Env                       []string
+	EnvFile                   []string
+	EnvFromSecret             []string
+	EnvFromConfigMap          []string
 	ServiceBinding            []string
 	CacheSize                 string
+	CacheStorageClass         string
+	CacheImage                string
+	NoCache                   bool
+	RecreateCache             bool
 	SuccessBuildHistoryLimit  string
 	FailedBuildHistoryLimit   string
 	DeleteEnv                 []string
@@ -61,6 +67,21 @@ type Factory struct {
 	Printer                   Printer
 	ServiceAccount            string
 	ReplaceAdditionalTags     []string
+	ResourceRequests          []string
+	ResourceLimits            []string
+	DeleteResourceRequests    []string
+	DeleteResourceLimits      []string
+	NodeSelector              []string
+	DeleteNodeSelector        []string
+	Tolerations               []string
+	DeleteTolerations         []string
+	AffinityFile              *string
+	Affinity                  *corev1.Affinity
+	RuntimeClassName          *string
+	SchedulerName             *string
+	BuildTimeout              *string
+	CosignAnnotations         []string
+	DeleteCosignAnnotations   []string
 }
 
 func (f *Factory) MakeImage(name, namespace, tag string) (*v1alpha2.Image, error) {
@@ -84,7 +105,7 @@ func (f *Factory) MakeImage(name, namespace, tag string) (*v1alpha2.Image, error
 		return nil, err
 	}
 
-	cacheSize, err := f.makeCacheSize()
+	cache, err := f.makeCache()
 	if err != nil {
 		return nil, err
 	}
@@ -117,12 +138,14 @@ func (f *Factory) MakeImage(name, namespace, tag string) (*v1alpha2.Image, error
 		},
 	}
 
-	if cacheSize != nil {
-		image.Spec.Cache = &v1alpha2.ImageCacheConfig{
-			Volume: &v1alpha2.ImagePersistentVolumeCache{
-				Size: cacheSize,
-			},
-		}
+	if err := f.setBuildPod(image.Spec.Build); err != nil {
+		return nil, err
+	}
+
+	image.Spec.Cache = cache
+
+	if err := f.setCosign(image); err != nil {
+		return nil, err
 	}
 
 	image.Spec.SuccessBuildHistoryLimit, err = f.makeBuildHistoryLimit(f.SuccessBuildHistoryLimit)
@@ -150,7 +173,7 @@ func (f *Factory) validateCreate(tag string) error {
 		return err
 	}
 
-	return nil
+	return f.validateCache()
 }
 
 func (f *Factory) validateSourceCreate() error {
@@ -196,46 +219,10 @@ func (f *Factory) validateTagsSameRegistry(tag string) error {
 	return nil
 }
 
-func (f *Factory) makeEnvVars() ([]corev1.EnvVar, error) {
-	var envVars []corev1.EnvVar
-	for _, e := range f.Env {
-		idx := strings.Index(e, "=")
-		if idx == -1 {
-			return nil, errors.Errorf("env vars are improperly formatted")
-		}
-		envVars = append(envVars, corev1.EnvVar{
-			Name:  e[:idx],
-			Value: e[idx+1:],
-		})
-	}
-	return envVars, nil
-}
-
 func (f *Factory) makeServiceBindings() (v1alpha2.Services, error) {
 	return parseServiceBindings(f.ServiceBinding)
 }
 
-func (f *Factory) makeCacheSize() (*resource.Quantity, error) {
-	if f.CacheSize == "" {
-		return nil, nil
-	}
-
-	return f.getCacheSize()
-}
-
-func (f *Factory) getCacheSize() (*resource.Quantity, error) {
-	c, err := resource.ParseQuantity(f.CacheSize)
-	if err != nil {
-		return nil, errors.New("invalid cache size, must be valid quantity ex. 2G")
-	}
-
-	if c.Sign() <= 0 {
-		return nil, errors.New("cache size must be greater than 0")
-	}
-
-	return &c, nil
-}
-
 func (f *Factory) makeBuildHistoryLimit(buildHistoryLimit string) (*int64, error) {
 	if buildHistoryLimit == "" {
 		return nil, nil
@@ -5,10 +5,14 @@ package image_test
 
 import (
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
 	"github.com/sclevine/spec"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/image"
@@ -80,6 +84,66 @@ func testImageFactory(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("env vars are read from files, secrets and config maps", func() {
+		it("adds plain values from the files and references for the secret and config map keys", func() {
+			envFile := filepath.Join(t.TempDir(), "build.env")
+			require.NoError(t, os.WriteFile(envFile, []byte(`# maven settings
+export MAVEN_OPTS="-Xmx1g -Dfoo=bar"
+LOG_LEVEL='debug'
+
+OVERRIDDEN=from-file
+`), 0644))
+
+			factory.Blob = "some-blob"
+			factory.EnvFile = []string{envFile}
+			factory.Env = []string{"OVERRIDDEN=from-flag"}
+			factory.EnvFromSecret = []string{"maven-creds:MAVEN_TOKEN"}
+			factory.EnvFromConfigMap = []string{"MAVEN_MIRROR=maven-settings:mirror-url"}
+			img, err := factory.MakeImage("test-name", "test-namespace", "test-registry.io/test-image")
+			require.NoError(t, err)
+			require.Equal(t, []corev1.EnvVar{
+				{Name: "MAVEN_OPTS", Value: "-Xmx1g -Dfoo=bar"},
+				{Name: "LOG_LEVEL", Value: "debug"},
+				{Name: "OVERRIDDEN", Value: "from-flag"},
+				{
+					Name: "MAVEN_TOKEN",
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "maven-creds"},
+							Key:                  "MAVEN_TOKEN",
+						},
+					},
+				},
+				{
+					Name: "MAVEN_MIRROR",
+					ValueFrom: &corev1.EnvVarSource{
+						ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "maven-settings"},
+							Key:                  "mirror-url",
+						},
+					},
+				},
+			}, img.Env())
+		})
+
+		it("returns an error for an improperly formatted env file", func() {
+			envFile := filepath.Join(t.TempDir(), "build.env")
+			require.NoError(t, os.WriteFile(envFile, []byte("FOO=bar\nnot-an-env-var\n"), 0644))
+
+			factory.Blob = "some-blob"
+			factory.EnvFile = []string{envFile}
+			_, err := factory.MakeImage("test-name", "test-namespace", "test-registry.io/test-image")
+			require.EqualError(t, err, "env file "+envFile+" is improperly formatted on line 2, expected NAME=value")
+		})
+
+		it("returns an error for an improperly formatted reference", func() {
+			factory.Blob = "some-blob"
+			factory.EnvFromSecret = []string{"maven-creds"}
+			_, err := factory.MakeImage("test-name", "test-namespace", "test-registry.io/test-image")
+			require.EqualError(t, err, `env-from-secret value "maven-creds" is improperly formatted, expected [ENV_NAME=]NAME:KEY`)
+		})
+	})
+
 	when("an invalid build history limit is provided", func() {
 		it("returns an error message", func() {
 			factory.SuccessBuildHistoryLimit = "-1"
@@ -122,6 +186,32 @@ func testImageFactory(t *testing.T, when spec.G, it spec.S) {
 			_, err = factory.MakeImage("test-name", "test-namespace", "test-registry.io/test-image")
 			require.EqualError(t, err, "cache size must be greater than 0")
 		})
+
+		it("can use a storage class with the default size", func() {
+			factory.CacheStorageClass = "some-storage-class"
+			expectedCache := resource.MustParse("2G")
+			img, err := factory.MakeImage("test-name", "test-namespace", "test-registry.io/test-image")
+			require.NoError(t, err)
+			require.Equal(t, &v1alpha2.ImageCacheConfig{
+				Volume: &v1alpha2.ImagePersistentVolumeCache{Size: &expectedCache, StorageClassName: "some-storage-class"},
+			}, img.Spec.Cache)
+		})
+
+		it("can use a registry cache", func() {
+			factory.CacheImage = "test-registry.io/test-cache"
+			img, err := factory.MakeImage("test-name", "test-namespace", "test-registry.io/test-image")
+			require.NoError(t, err)
+			require.Equal(t, &v1alpha2.ImageCacheConfig{
+				Registry: &v1alpha2.RegistryCache{Tag: "test-registry.io/test-cache"},
+			}, img.Spec.Cache)
+		})
+
+		it("can be disabled", func() {
+			factory.NoCache = true
+			img, err := factory.MakeImage("test-name", "test-namespace", "test-registry.io/test-image")
+			require.NoError(t, err)
+			require.Equal(t, &v1alpha2.ImageCacheConfig{}, img.Spec.Cache)
+		})
 	})
 
 	when("additional tags", func() {
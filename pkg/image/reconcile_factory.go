@@ -0,0 +1,57 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	"github.com/pkg/errors"
+)
+
+// ReconcileImage returns the existing image updated to match the factory as
+// a whole, settings the factory does not declare are removed from the image.
+// The service account and cache are kept when they are not declared, the
+// cache cannot be removed, and build settings the factory does not manage
+// are left as they are.
+func (f *Factory) ReconcileImage(img *v1alpha2.Image, tag string) (*v1alpha2.Image, error) {
+	if tag != "" && tag != img.Spec.Tag {
+		return nil, errors.Errorf("tag is immutable, the image resource uses %q but %q was provided", img.Spec.Tag, tag)
+	}
+
+	if f.ServiceAccount == "" {
+		f.ServiceAccount = img.Spec.ServiceAccountName
+	}
+
+	desired, err := f.MakeImage(img.Name, img.Namespace, img.Spec.Tag)
+	if err != nil {
+		return nil, err
+	}
+
+	reconciled := img.DeepCopy()
+	reconciled.Spec.AdditionalTags = desired.Spec.AdditionalTags
+	reconciled.Spec.Builder = desired.Spec.Builder
+	reconciled.Spec.ServiceAccountName = desired.Spec.ServiceAccountName
+	reconciled.Spec.Source = desired.Spec.Source
+	reconciled.Spec.SuccessBuildHistoryLimit = desired.Spec.SuccessBuildHistoryLimit
+	reconciled.Spec.FailedBuildHistoryLimit = desired.Spec.FailedBuildHistoryLimit
+	reconciled.Spec.Cosign = desired.Spec.Cosign
+
+	if reconciled.Spec.Build == nil {
+		reconciled.Spec.Build = &v1alpha2.ImageBuild{}
+	}
+	reconciled.Spec.Build.Env = desired.Spec.Build.Env
+	reconciled.Spec.Build.Services = desired.Spec.Build.Services
+	reconciled.Spec.Build.Resources = desired.Spec.Build.Resources
+	reconciled.Spec.Build.NodeSelector = desired.Spec.Build.NodeSelector
+	reconciled.Spec.Build.Tolerations = desired.Spec.Build.Tolerations
+	reconciled.Spec.Build.Affinity = desired.Spec.Build.Affinity
+	reconciled.Spec.Build.RuntimeClassName = desired.Spec.Build.RuntimeClassName
+	reconciled.Spec.Build.SchedulerName = desired.Spec.Build.SchedulerName
+	reconciled.Spec.Build.BuildTimeout = desired.Spec.Build.BuildTimeout
+
+	if err := f.setCache(reconciled); err != nil {
+		return nil, err
+	}
+
+	return reconciled, nil
+}
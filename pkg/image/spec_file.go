@@ -0,0 +1,286 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// SpecFile is a declarative image resource configuration kept in the
+// application repository, such as a kp-image.yaml file
+type SpecFile struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	ImageSettings
+	// Overlays are per environment settings applied on top of the base settings
+	Overlays map[string]ImageSettings `json:"overlays,omitempty"`
+}
+
+// ImageSettings are the image resource settings that can be declared in a
+// spec file, they match the flags of kp image save
+type ImageSettings struct {
+	Tag                      string            `json:"tag,omitempty"`
+	AdditionalTags           []string          `json:"additionalTags,omitempty"`
+	Source                   *SourceSettings   `json:"source,omitempty"`
+	Builder                  string            `json:"builder,omitempty"`
+	ClusterBuilder           string            `json:"clusterBuilder,omitempty"`
+	Env                      map[string]string `json:"env,omitempty"`
+	EnvFromSecret            map[string]string `json:"envFromSecret,omitempty"`
+	EnvFromConfigMap         map[string]string `json:"envFromConfigMap,omitempty"`
+	ServiceBindings          []string          `json:"serviceBindings,omitempty"`
+	CacheSize                string            `json:"cacheSize,omitempty"`
+	CacheStorageClass        string            `json:"cacheStorageClass,omitempty"`
+	CacheImage               string            `json:"cacheImage,omitempty"`
+	SuccessBuildHistoryLimit *int64            `json:"successBuildHistoryLimit,omitempty"`
+	FailedBuildHistoryLimit  *int64            `json:"failedBuildHistoryLimit,omitempty"`
+	ServiceAccount           string            `json:"serviceAccount,omitempty"`
+	CosignAnnotations        map[string]string `json:"cosignAnnotations,omitempty"`
+	Resources                *ResourceSettings `json:"resources,omitempty"`
+	NodeSelector             map[string]string `json:"nodeSelector,omitempty"`
+	Tolerations              []string          `json:"tolerations,omitempty"`
+	Affinity                 *corev1.Affinity  `json:"affinity,omitempty"`
+	RuntimeClassName         string            `json:"runtimeClassName,omitempty"`
+	SchedulerName            string            `json:"schedulerName,omitempty"`
+	BuildTimeout             string            `json:"buildTimeout,omitempty"`
+}
+
+// ResourceSettings are the resource requests and limits of the build pod by
+// resource name, such as memory: 4Gi
+type ResourceSettings struct {
+	Requests map[string]string `json:"requests,omitempty"`
+	Limits   map[string]string `json:"limits,omitempty"`
+}
+
+type SourceSettings struct {
+	Git                       string `json:"git,omitempty"`
+	GitRevision               string `json:"gitRevision,omitempty"`
+	Blob                      string `json:"blob,omitempty"`
+	LocalPath                 string `json:"localPath,omitempty"`
+	LocalPathDestinationImage string `json:"localPathDestinationImage,omitempty"`
+	SubPath                   string `json:"subPath,omitempty"`
+}
+
+// ReadSpecFile reads a spec file, fields that are not part of the format are an error.
+// A relative local path source is resolved against the directory of the file.
+func ReadSpecFile(path string) (SpecFile, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return SpecFile{}, err
+	}
+
+	var file SpecFile
+	if err := yaml.UnmarshalStrict(buf, &file); err != nil {
+		return SpecFile{}, errors.Wrapf(err, "invalid image spec file %q", path)
+	}
+
+	if file.Name == "" {
+		return SpecFile{}, errors.Errorf("invalid image spec file %q: name is required", path)
+	}
+
+	dir := filepath.Dir(path)
+	file.Source.resolveLocalPath(dir)
+	for _, overlay := range file.Overlays {
+		overlay.Source.resolveLocalPath(dir)
+	}
+
+	return file, nil
+}
+
+func (s *SourceSettings) resolveLocalPath(dir string) {
+	if s != nil && s.LocalPath != "" && !filepath.IsAbs(s.LocalPath) {
+		s.LocalPath = filepath.Join(dir, s.LocalPath)
+	}
+}
+
+// Settings returns the base settings with the named overlay applied, an
+// empty overlay name returns the base settings
+func (f SpecFile) Settings(overlay string) (ImageSettings, error) {
+	if overlay == "" {
+		return f.ImageSettings, nil
+	}
+
+	o, ok := f.Overlays[overlay]
+	if !ok {
+		var names []string
+		for name := range f.Overlays {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return ImageSettings{}, errors.Errorf("overlay %q not found, the file declares no overlays", overlay)
+		}
+		return ImageSettings{}, errors.Errorf("overlay %q not found, available overlays: %s", overlay, strings.Join(names, ", "))
+	}
+
+	return f.ImageSettings.merge(o), nil
+}
+
+// merge returns the settings overridden by every field set in the overlay.
+// Env vars, env var references, cosign annotations, resources and node selectors are merged by name,
+// other fields are replaced as a whole.
+func (s ImageSettings) merge(o ImageSettings) ImageSettings {
+	merged := s
+
+	if o.Tag != "" {
+		merged.Tag = o.Tag
+	}
+	if o.AdditionalTags != nil {
+		merged.AdditionalTags = o.AdditionalTags
+	}
+	if o.Source != nil {
+		merged.Source = o.Source
+	}
+	if o.Builder != "" || o.ClusterBuilder != "" {
+		merged.Builder = o.Builder
+		merged.ClusterBuilder = o.ClusterBuilder
+	}
+	merged.Env = mergeEnv(s.Env, o.Env)
+	merged.EnvFromSecret = mergeEnv(s.EnvFromSecret, o.EnvFromSecret)
+	merged.EnvFromConfigMap = mergeEnv(s.EnvFromConfigMap, o.EnvFromConfigMap)
+	if o.ServiceBindings != nil {
+		merged.ServiceBindings = o.ServiceBindings
+	}
+	if o.CacheSize != "" || o.CacheStorageClass != "" || o.CacheImage != "" {
+		merged.CacheSize = o.CacheSize
+		merged.CacheStorageClass = o.CacheStorageClass
+		merged.CacheImage = o.CacheImage
+	}
+	if o.SuccessBuildHistoryLimit != nil {
+		merged.SuccessBuildHistoryLimit = o.SuccessBuildHistoryLimit
+	}
+	if o.FailedBuildHistoryLimit != nil {
+		merged.FailedBuildHistoryLimit = o.FailedBuildHistoryLimit
+	}
+	if o.ServiceAccount != "" {
+		merged.ServiceAccount = o.ServiceAccount
+	}
+	merged.CosignAnnotations = mergeEnv(s.CosignAnnotations, o.CosignAnnotations)
+	merged.Resources = s.Resources.merge(o.Resources)
+	merged.NodeSelector = mergeEnv(s.NodeSelector, o.NodeSelector)
+	if o.Tolerations != nil {
+		merged.Tolerations = o.Tolerations
+	}
+	if o.Affinity != nil {
+		merged.Affinity = o.Affinity
+	}
+	if o.RuntimeClassName != "" {
+		merged.RuntimeClassName = o.RuntimeClassName
+	}
+	if o.SchedulerName != "" {
+		merged.SchedulerName = o.SchedulerName
+	}
+	if o.BuildTimeout != "" {
+		merged.BuildTimeout = o.BuildTimeout
+	}
+	return merged
+}
+
+func (r *ResourceSettings) merge(o *ResourceSettings) *ResourceSettings {
+	if o == nil {
+		return r
+	} else if r == nil {
+		return o
+	}
+
+	return &ResourceSettings{
+		Requests: mergeEnv(r.Requests, o.Requests),
+		Limits:   mergeEnv(r.Limits, o.Limits),
+	}
+}
+
+func mergeEnv(base, overlay map[string]string) map[string]string {
+	if overlay == nil {
+		return base
+	}
+
+	merged := map[string]string{}
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overlay {
+		merged[k] = v
+	}
+	return merged
+}
+
+// Factory returns a factory configured with the settings, the source
+// uploader and printer still have to be set
+func (s ImageSettings) Factory() Factory {
+	factory := Factory{
+		AdditionalTags:    s.AdditionalTags,
+		Builder:           s.Builder,
+		ClusterBuilder:    s.ClusterBuilder,
+		ServiceBinding:    s.ServiceBindings,
+		CacheSize:         s.CacheSize,
+		CacheStorageClass: s.CacheStorageClass,
+		CacheImage:        s.CacheImage,
+		ServiceAccount:    s.ServiceAccount,
+	}
+
+	if s.Source != nil {
+		factory.GitRepo = s.Source.Git
+		factory.GitRevision = s.Source.GitRevision
+		factory.Blob = s.Source.Blob
+		factory.LocalPath = s.Source.LocalPath
+		factory.LocalPathDestinationImage = s.Source.LocalPathDestinationImage
+		subPath := s.Source.SubPath
+		factory.SubPath = &subPath
+	}
+
+	factory.Env = envFlags(s.Env)
+	factory.EnvFromSecret = envFlags(s.EnvFromSecret)
+	factory.EnvFromConfigMap = envFlags(s.EnvFromConfigMap)
+	factory.CosignAnnotations = envFlags(s.CosignAnnotations)
+
+	if s.SuccessBuildHistoryLimit != nil {
+		factory.SuccessBuildHistoryLimit = strconv.FormatInt(*s.SuccessBuildHistoryLimit, 10)
+	}
+	if s.FailedBuildHistoryLimit != nil {
+		factory.FailedBuildHistoryLimit = strconv.FormatInt(*s.FailedBuildHistoryLimit, 10)
+	}
+
+	if s.Resources != nil {
+		factory.ResourceRequests = envFlags(s.Resources.Requests)
+		factory.ResourceLimits = envFlags(s.Resources.Limits)
+	}
+	factory.NodeSelector = envFlags(s.NodeSelector)
+	factory.Tolerations = s.Tolerations
+	factory.Affinity = s.Affinity
+	factory.RuntimeClassName = optionalFlag(s.RuntimeClassName)
+	factory.SchedulerName = optionalFlag(s.SchedulerName)
+	factory.BuildTimeout = optionalFlag(s.BuildTimeout)
+
+	return factory
+}
+
+// optionalFlag returns the value as a flag that is only applied when set
+func optionalFlag(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// envFlags returns the env vars as NAME=value flag values sorted by name
+func envFlags(env map[string]string) []string {
+	var names []string
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var flags []string
+	for _, name := range names {
+		flags = append(flags, name+"="+env[name])
+	}
+	return flags
+}
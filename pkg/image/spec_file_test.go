@@ -0,0 +1,171 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package image_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/image"
+)
+
+func TestSpecFile(t *testing.T) {
+	spec.Run(t, "TestSpecFile", testSpecFile)
+}
+
+func testSpecFile(t *testing.T, when spec.G, it spec.S) {
+	var (
+		dir  string
+		path string
+	)
+
+	it.Before(func() {
+		dir = t.TempDir()
+		path = filepath.Join(dir, "kp-image.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`name: some-image
+tag: some-registry.io/some-repo
+additionalTags:
+- some-registry.io/some-repo:base
+source:
+  localPath: app
+  subPath: some-sub-path
+builder: some-builder
+env:
+  A: base-a
+  B: base-b
+envFromSecret:
+  MAVEN_TOKEN: maven-creds:token
+cacheSize: 2G
+overlays:
+  dev:
+    env:
+      B: dev-b
+      C: dev-c
+    envFromConfigMap:
+      MAVEN_MIRROR: maven-settings:dev-mirror
+    clusterBuilder: some-cluster-builder
+    failedBuildHistoryLimit: 3
+  empty: {}
+`), 0644))
+	})
+
+	it("applies the overlay on top of the base settings", func() {
+		file, err := image.ReadSpecFile(path)
+		require.NoError(t, err)
+
+		settings, err := file.Settings("dev")
+		require.NoError(t, err)
+
+		factory := settings.Factory()
+		require.Equal(t, "some-registry.io/some-repo", settings.Tag)
+		require.Equal(t, []string{"some-registry.io/some-repo:base"}, factory.AdditionalTags)
+		require.Equal(t, []string{"A=base-a", "B=dev-b", "C=dev-c"}, factory.Env)
+		require.Equal(t, []string{"MAVEN_TOKEN=maven-creds:token"}, factory.EnvFromSecret)
+		require.Equal(t, []string{"MAVEN_MIRROR=maven-settings:dev-mirror"}, factory.EnvFromConfigMap)
+		require.Equal(t, "", factory.Builder)
+		require.Equal(t, "some-cluster-builder", factory.ClusterBuilder)
+		require.Equal(t, "2G", factory.CacheSize)
+		require.Equal(t, "3", factory.FailedBuildHistoryLimit)
+		require.Equal(t, "", factory.SuccessBuildHistoryLimit)
+	})
+
+	it("merges the build pod settings of the overlay", func() {
+		require.NoError(t, os.WriteFile(path, []byte(`name: some-image
+resources:
+  requests:
+    cpu: "1"
+    memory: 2Gi
+nodeSelector:
+  kubernetes.io/arch: amd64
+tolerations:
+- dedicated=builds:NoSchedule
+buildTimeout: 30m
+overlays:
+  prod:
+    resources:
+      requests:
+        memory: 4Gi
+      limits:
+        memory: 8Gi
+    nodeSelector:
+      pool: builds
+    tolerations:
+    - dedicated=prod-builds:NoSchedule
+    affinity:
+      nodeAffinity:
+        requiredDuringSchedulingIgnoredDuringExecution:
+          nodeSelectorTerms:
+          - matchExpressions:
+            - key: zone
+              operator: In
+              values:
+              - us-east-1a
+    runtimeClassName: gvisor
+    schedulerName: some-scheduler
+`), 0644))
+
+		file, err := image.ReadSpecFile(path)
+		require.NoError(t, err)
+
+		settings, err := file.Settings("prod")
+		require.NoError(t, err)
+
+		factory := settings.Factory()
+		require.Equal(t, []string{"cpu=1", "memory=4Gi"}, factory.ResourceRequests)
+		require.Equal(t, []string{"memory=8Gi"}, factory.ResourceLimits)
+		require.Equal(t, []string{"kubernetes.io/arch=amd64", "pool=builds"}, factory.NodeSelector)
+		require.Equal(t, []string{"dedicated=prod-builds:NoSchedule"}, factory.Tolerations)
+		require.Equal(t, "zone", factory.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions[0].Key)
+		require.Equal(t, "gvisor", *factory.RuntimeClassName)
+		require.Equal(t, "some-scheduler", *factory.SchedulerName)
+		require.Equal(t, "30m", *factory.BuildTimeout)
+
+		base := file.ImageSettings.Factory()
+		require.Nil(t, base.Affinity)
+		require.Nil(t, base.RuntimeClassName)
+		require.Nil(t, base.SchedulerName)
+	})
+
+	it("returns the base settings without an overlay or with an empty one", func() {
+		file, err := image.ReadSpecFile(path)
+		require.NoError(t, err)
+
+		for _, overlay := range []string{"", "empty"} {
+			settings, err := file.Settings(overlay)
+			require.NoError(t, err)
+
+			factory := settings.Factory()
+			require.Equal(t, []string{"A=base-a", "B=base-b"}, factory.Env)
+			require.Equal(t, "some-builder", factory.Builder)
+		}
+	})
+
+	it("resolves a relative local path against the directory of the file", func() {
+		file, err := image.ReadSpecFile(path)
+		require.NoError(t, err)
+
+		factory := file.ImageSettings.Factory()
+		require.Equal(t, filepath.Join(dir, "app"), factory.LocalPath)
+		require.Equal(t, "some-sub-path", *factory.SubPath)
+	})
+
+	it("errors for an unknown overlay", func() {
+		file, err := image.ReadSpecFile(path)
+		require.NoError(t, err)
+
+		_, err = file.Settings("prod")
+		require.EqualError(t, err, `overlay "prod" not found, available overlays: dev, empty`)
+	})
+
+	it("requires a name", func() {
+		require.NoError(t, os.WriteFile(path, []byte("tag: some-registry.io/some-repo\n"), 0644))
+
+		_, err := image.ReadSpecFile(path)
+		require.EqualError(t, err, `invalid image spec file "`+path+`": name is required`)
+	})
+}
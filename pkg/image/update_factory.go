@@ -32,7 +32,7 @@ func (f *Factory) UpdateImage(img *v1alpha2.Image) (*v1alpha2.Image, error) {
 
 	f.setAdditionalTags(updatedImage)
 
-	err = f.setCacheSize(updatedImage)
+	err = f.setCache(updatedImage)
 	if err != nil {
 		return nil, err
 	}
@@ -42,6 +42,16 @@ func (f *Factory) UpdateImage(img *v1alpha2.Image) (*v1alpha2.Image, error) {
 		return nil, err
 	}
 
+	err = f.setBuildPod(updatedImage.Spec.Build)
+	if err != nil {
+		return nil, err
+	}
+
+	err = f.setCosign(updatedImage)
+	if err != nil {
+		return nil, err
+	}
+
 	f.setBuilder(updatedImage)
 
 	f.setServiceAccount(updatedImage)
@@ -66,6 +76,10 @@ func (f *Factory) validateUpdate(img *v1alpha2.Image) error {
 		return err
 	}
 
+	if err := f.validateCache(); err != nil {
+		return err
+	}
+
 	return f.validateAdditionalTags(img)
 }
 
@@ -208,36 +222,6 @@ func (f *Factory) setSource(image *v1alpha2.Image) error {
 	return nil
 }
 
-func (f *Factory) setCacheSize(image *v1alpha2.Image) error {
-	if f.CacheSize == "" {
-		return nil
-	}
-
-	c, err := f.getCacheSize()
-	if err != nil {
-		return err
-	}
-
-	if image.Spec.Cache == nil {
-		image.Spec.Cache = &v1alpha2.ImageCacheConfig{
-			Volume: &v1alpha2.ImagePersistentVolumeCache{
-				Size: c,
-			},
-		}
-	} else if image.Spec.Cache.Volume == nil {
-		image.Spec.Cache.Volume = &v1alpha2.ImagePersistentVolumeCache{
-			Size: c,
-		}
-	}
-	if c.Cmp(*image.Spec.Cache.Volume.Size) < 0 {
-		return errors.Errorf("cache size cannot be decreased, current: %v, requested: %v", image.Spec.Cache.Volume.Size, c)
-	} else {
-		image.Spec.Cache.Volume.Size = c
-	}
-
-	return nil
-}
-
 func (f *Factory) setAdditionalTags(image *v1alpha2.Image) {
 	if len(f.ReplaceAdditionalTags) != 0 {
 		image.Spec.AdditionalTags = f.ReplaceAdditionalTags
@@ -286,19 +270,7 @@ func (f *Factory) setBuild(image *v1alpha2.Image) error {
 	}
 
 	for _, env := range envsToSave {
-		updated := false
-
-		for i, e := range image.Spec.Build.Env {
-			if e.Name == env.Name {
-				image.Spec.Build.Env[i].Value = env.Value
-				updated = true
-				break
-			}
-		}
-
-		if !updated {
-			image.Spec.Build.Env = append(image.Spec.Build.Env, corev1.EnvVar{Name: env.Name, Value: env.Value})
-		}
+		image.Spec.Build.Env = setEnvVar(image.Spec.Build.Env, env)
 	}
 
 	svcsToDelete, err := parseServiceBindings(f.DeleteServiceBinding)
@@ -145,6 +145,139 @@ func testPatchFactory(t *testing.T, when spec.G, it spec.S) {
 		})
 	})
 
+	when("env vars reference secrets and config maps", func() {
+		it("replaces a plain value with a reference", func() {
+			factory.EnvFromSecret = []string{"foo=some-secret:some-key"}
+
+			expectedImg.Spec.Build.Env = []corev1.EnvVar{
+				{
+					Name: "foo",
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "some-secret"},
+							Key:                  "some-key",
+						},
+					},
+				},
+			}
+
+			updatedImage, err := factory.UpdateImage(img)
+			require.NoError(t, err)
+			require.Equal(t, expectedImg, updatedImage)
+		})
+
+		it("deletes a reference by name", func() {
+			withRef := img.DeepCopy()
+			withRef.Spec.Build.Env = append(withRef.Spec.Build.Env, corev1.EnvVar{
+				Name: "bar",
+				ValueFrom: &corev1.EnvVarSource{
+					ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "some-config-map"},
+						Key:                  "bar",
+					},
+				},
+			})
+			factory.DeleteEnv = []string{"bar"}
+
+			updatedImage, err := factory.UpdateImage(withRef)
+			require.NoError(t, err)
+			require.Equal(t, expectedImg, updatedImage)
+		})
+	})
+
+	when("build pod settings are provided", func() {
+		it("adds and replaces them", func() {
+			withPod := img.DeepCopy()
+			withPod.Spec.Build.Resources.Limits = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")}
+			withPod.Spec.Build.Tolerations = []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}}
+
+			runtimeClass := "gvisor"
+			buildTimeout := "90m"
+			factory.ResourceRequests = []string{"memory=4Gi", "cpu=2"}
+			factory.ResourceLimits = []string{"memory=8Gi"}
+			factory.NodeSelector = []string{"pool=java-builds"}
+			factory.Tolerations = []string{"dedicated=builds:NoSchedule", "spot"}
+			factory.RuntimeClassName = &runtimeClass
+			factory.BuildTimeout = &buildTimeout
+
+			expectedTimeout := int64(5400)
+			expectedImg.Spec.Build.Resources = corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("4Gi"),
+					corev1.ResourceCPU:    resource.MustParse("2"),
+				},
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")},
+			}
+			expectedImg.Spec.Build.NodeSelector = map[string]string{"pool": "java-builds"}
+			expectedImg.Spec.Build.Tolerations = []corev1.Toleration{
+				{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "builds", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "spot", Operator: corev1.TolerationOpExists},
+			}
+			expectedImg.Spec.Build.RuntimeClassName = &runtimeClass
+			expectedImg.Spec.Build.BuildTimeout = &expectedTimeout
+
+			updatedImage, err := factory.UpdateImage(withPod)
+			require.NoError(t, err)
+			require.Equal(t, expectedImg, updatedImage)
+		})
+
+		it("removes them", func() {
+			runtimeClass := "gvisor"
+			buildTimeout := int64(5400)
+			withPod := img.DeepCopy()
+			withPod.Spec.Build.Resources.Requests = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")}
+			withPod.Spec.Build.NodeSelector = map[string]string{"pool": "java-builds"}
+			withPod.Spec.Build.Tolerations = []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}}
+			withPod.Spec.Build.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{}}
+			withPod.Spec.Build.RuntimeClassName = &runtimeClass
+			withPod.Spec.Build.BuildTimeout = &buildTimeout
+
+			empty := ""
+			factory.DeleteResourceRequests = []string{"memory"}
+			factory.DeleteNodeSelector = []string{"pool"}
+			factory.DeleteTolerations = []string{"dedicated"}
+			factory.AffinityFile = &empty
+			factory.RuntimeClassName = &empty
+			factory.BuildTimeout = &empty
+
+			updatedImage, err := factory.UpdateImage(withPod)
+			require.NoError(t, err)
+			require.Equal(t, expectedImg, updatedImage)
+		})
+
+		it("returns an error for a request above the limit", func() {
+			factory.ResourceRequests = []string{"memory=8Gi"}
+			factory.ResourceLimits = []string{"memory=4Gi"}
+			_, err := factory.UpdateImage(img)
+			require.EqualError(t, err, "memory request 8Gi must be less than or equal to the memory limit 4Gi")
+		})
+
+		it("returns an error for an invalid quantity", func() {
+			factory.ResourceLimits = []string{"memory=lots"}
+			_, err := factory.UpdateImage(img)
+			require.EqualError(t, err, `invalid memory limit "lots", must be valid quantity ex. 4Gi`)
+		})
+
+		it("returns an error for an invalid toleration effect", func() {
+			factory.Tolerations = []string{"dedicated=builds:Never"}
+			_, err := factory.UpdateImage(img)
+			require.EqualError(t, err, `toleration "dedicated=builds:Never" has an invalid effect, must be one of NoSchedule, PreferNoSchedule or NoExecute`)
+		})
+
+		it("returns an error for an invalid build timeout", func() {
+			buildTimeout := "90"
+			factory.BuildTimeout = &buildTimeout
+			_, err := factory.UpdateImage(img)
+			require.EqualError(t, err, `invalid build timeout "90", must be a duration of at least one second ex. 90m`)
+		})
+
+		it("returns an error when deleting a setting the image does not have", func() {
+			factory.DeleteNodeSelector = []string{"pool"}
+			_, err := factory.UpdateImage(img)
+			require.EqualError(t, err, "delete-node-selector parameter 'pool' not found in existing image configuration")
+		})
+	})
+
 	when("delete-env does not exist in the current image", func() {
 		it("returns an error message", func() {
 			factory.DeleteEnv = []string{"bar"}
@@ -265,4 +398,171 @@ func testPatchFactory(t *testing.T, when spec.G, it spec.S) {
 			require.EqualError(t, err, "invalid cache size, must be valid quantity ex. 2G")
 		})
 	})
+
+	when("patching the cache type", func() {
+		var withVolume *v1alpha2.Image
+
+		it.Before(func() {
+			cacheSize := resource.MustParse("2G")
+			withVolume = img.DeepCopy()
+			withVolume.Spec.Cache = &v1alpha2.ImageCacheConfig{
+				Volume: &v1alpha2.ImagePersistentVolumeCache{
+					Size:             &cacheSize,
+					StorageClassName: "some-storage-class",
+				},
+			}
+		})
+
+		it("switches from a volume to a registry cache", func() {
+			factory.CacheImage = "some-registry.io/some-cache"
+
+			expectedImg.Spec.Cache = &v1alpha2.ImageCacheConfig{
+				Registry: &v1alpha2.RegistryCache{Tag: "some-registry.io/some-cache"},
+			}
+
+			updatedImg, err := factory.UpdateImage(withVolume)
+			require.NoError(t, err)
+			require.Equal(t, expectedImg, updatedImg)
+
+			required, err := factory.CacheRecreateRequired(withVolume)
+			require.NoError(t, err)
+			require.False(t, required)
+		})
+
+		it("disables the cache", func() {
+			factory.NoCache = true
+
+			expectedImg.Spec.Cache = &v1alpha2.ImageCacheConfig{}
+
+			updatedImg, err := factory.UpdateImage(withVolume)
+			require.NoError(t, err)
+			require.Equal(t, expectedImg, updatedImg)
+		})
+
+		it("keeps the storage class when growing the volume", func() {
+			factory.CacheSize = "3G"
+
+			s := resource.MustParse("3G")
+			expectedImg.Spec.Cache = &v1alpha2.ImageCacheConfig{
+				Volume: &v1alpha2.ImagePersistentVolumeCache{
+					Size:             &s,
+					StorageClassName: "some-storage-class",
+				},
+			}
+
+			updatedImg, err := factory.UpdateImage(withVolume)
+			require.NoError(t, err)
+			require.Equal(t, expectedImg, updatedImg)
+		})
+
+		it("switches from a registry cache to a volume with the default size", func() {
+			withRegistry := img.DeepCopy()
+			withRegistry.Spec.Cache = &v1alpha2.ImageCacheConfig{
+				Registry: &v1alpha2.RegistryCache{Tag: "some-registry.io/some-cache"},
+			}
+			factory.CacheStorageClass = "some-storage-class"
+
+			s := resource.MustParse("2G")
+			expectedImg.Spec.Cache = &v1alpha2.ImageCacheConfig{
+				Volume: &v1alpha2.ImagePersistentVolumeCache{
+					Size:             &s,
+					StorageClassName: "some-storage-class",
+				},
+			}
+
+			updatedImg, err := factory.UpdateImage(withRegistry)
+			require.NoError(t, err)
+			require.Equal(t, expectedImg, updatedImg)
+		})
+
+		it("requires recreating the volume to shrink it or change its storage class", func() {
+			factory.CacheSize = "1G"
+			factory.CacheStorageClass = "other-storage-class"
+
+			required, err := factory.CacheRecreateRequired(withVolume)
+			require.NoError(t, err)
+			require.True(t, required)
+
+			_, err = factory.UpdateImage(withVolume)
+			require.EqualError(t, err, "cache size cannot be decreased, current: 2G, requested: 1G")
+
+			factory.CacheSize = ""
+			_, err = factory.UpdateImage(withVolume)
+			require.EqualError(t, err, `cache storage class cannot be changed, current: "some-storage-class", requested: "other-storage-class"`)
+
+			factory.CacheSize = "1G"
+			factory.RecreateCache = true
+
+			s := resource.MustParse("1G")
+			expectedImg.Spec.Cache = &v1alpha2.ImageCacheConfig{
+				Volume: &v1alpha2.ImagePersistentVolumeCache{
+					Size:             &s,
+					StorageClassName: "other-storage-class",
+				},
+			}
+
+			updatedImg, err := factory.UpdateImage(withVolume)
+			require.NoError(t, err)
+			require.Equal(t, expectedImg, updatedImg)
+		})
+
+		it("errors when cache types are combined", func() {
+			factory.CacheImage = "some-registry.io/some-cache"
+			factory.CacheSize = "3G"
+			_, err := factory.UpdateImage(withVolume)
+			require.EqualError(t, err, "cache-image is incompatible with cache-size and cache-storage-class")
+
+			factory.NoCache = true
+			_, err = factory.UpdateImage(withVolume)
+			require.EqualError(t, err, "no-cache is incompatible with cache-size, cache-storage-class and cache-image")
+		})
+	})
+
+	when("patching cosign annotations", func() {
+		it.Before(func() {
+			img.Spec.Cosign = &v1alpha2.CosignConfig{
+				Annotations: []v1alpha2.CosignAnnotation{
+					{Name: "team", Value: "payments"},
+					{Name: "env", Value: "dev"},
+				},
+			}
+			expectedImg = img.DeepCopy()
+		})
+
+		it("adds, replaces and deletes annotations by name", func() {
+			factory.CosignAnnotations = []string{"env=prod", "commit=abc123"}
+			factory.DeleteCosignAnnotations = []string{"team"}
+
+			expectedImg.Spec.Cosign.Annotations = []v1alpha2.CosignAnnotation{
+				{Name: "env", Value: "prod"},
+				{Name: "commit", Value: "abc123"},
+			}
+
+			updatedImg, err := factory.UpdateImage(img)
+			require.NoError(t, err)
+			require.Equal(t, expectedImg, updatedImg)
+		})
+
+		it("removes the cosign config when no annotations are left", func() {
+			factory.DeleteCosignAnnotations = []string{"team", "env"}
+
+			expectedImg.Spec.Cosign = nil
+
+			updatedImg, err := factory.UpdateImage(img)
+			require.NoError(t, err)
+			require.Equal(t, expectedImg, updatedImg)
+		})
+
+		it("errors when deleting an annotation that does not exist", func() {
+			factory.DeleteCosignAnnotations = []string{"missing"}
+			_, err := factory.UpdateImage(img)
+			require.EqualError(t, err, "delete-cosign-annotation parameter 'missing' not found in existing image configuration")
+		})
+
+		it("errors when an annotation is improperly formatted", func() {
+			factory.CosignAnnotations = []string{"team"}
+			_, err := factory.UpdateImage(img)
+			require.EqualError(t, err, `cosign annotation "team" is improperly formatted, expected KEY=VALUE`)
+		})
+	})
 }
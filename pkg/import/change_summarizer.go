@@ -38,6 +38,20 @@ func SummarizeChange(
 		return
 	}
 
+	if len(desc.ClusterBuildpacks) > 0 {
+		err = writeClusterBuildpacksChange(ctx, keychain, kpConfig, desc.ClusterBuildpacks, iDiffer, cs, &summarizer)
+		if err != nil {
+			return
+		}
+	}
+
+	if len(desc.Buildpacks) > 0 {
+		err = writeBuildpacksChange(ctx, keychain, kpConfig, desc.Buildpacks, iDiffer, cs, &summarizer)
+		if err != nil {
+			return
+		}
+	}
+
 	err = writeClusterStacksChange(ctx, keychain, kpConfig, desc.GetClusterStacks(), iDiffer, cs, &summarizer)
 	if err != nil {
 		return
@@ -48,6 +62,13 @@ func SummarizeChange(
 		return
 	}
 
+	if len(desc.Builders) > 0 {
+		err = writeBuildersChange(ctx, desc.Builders, iDiffer, cs, &summarizer)
+		if err != nil {
+			return
+		}
+	}
+
 	return summarizer.hasChanges, summarizer.changes.String(), nil
 }
 
@@ -51,7 +51,7 @@ func writeClusterStoresChange(ctx context.Context, keychain authn.Keychain, kpCo
 			oldStore = nil
 		}
 
-		diff, err := differ.DiffClusterStore(keychain, kpConfig, oldStore, store)
+		diff, err := differ.DiffClusterStore(keychain, repositoryConfig(kpConfig, store.Repository), oldStore, store)
 		if err != nil {
 			return err
 		}
@@ -64,6 +64,52 @@ func writeClusterStoresChange(ctx context.Context, keychain authn.Keychain, kpCo
 	return nil
 }
 
+func writeClusterBuildpacksChange(ctx context.Context, keychain authn.Keychain, kpConfig config.KpConfig, buildpacks []ClusterBuildpack, differ *ImportDiffer, cs buildk8s.ClientSet, cw changeWriter) error {
+	for _, bp := range buildpacks {
+		oldBuildpack, err := cs.KpackClient.KpackV1alpha2().ClusterBuildpacks().Get(ctx, bp.Name, metav1.GetOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+		if k8serrors.IsNotFound(err) {
+			oldBuildpack = nil
+		}
+
+		diff, err := differ.DiffClusterBuildpack(keychain, repositoryConfig(kpConfig, bp.Repository), oldBuildpack, bp)
+		if err != nil {
+			return err
+		}
+		if err = cw.writeDiff(diff); err != nil {
+			return err
+		}
+	}
+
+	cw.writeChange("ClusterBuildpacks")
+	return nil
+}
+
+func writeBuildpacksChange(ctx context.Context, keychain authn.Keychain, kpConfig config.KpConfig, buildpacks []Buildpack, differ *ImportDiffer, cs buildk8s.ClientSet, cw changeWriter) error {
+	for _, bp := range buildpacks {
+		oldBuildpack, err := cs.KpackClient.KpackV1alpha2().Buildpacks(bp.Namespace).Get(ctx, bp.Name, metav1.GetOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+		if k8serrors.IsNotFound(err) {
+			oldBuildpack = nil
+		}
+
+		diff, err := differ.DiffBuildpack(keychain, repositoryConfig(kpConfig, bp.Repository), oldBuildpack, bp)
+		if err != nil {
+			return err
+		}
+		if err = cw.writeDiff(diff); err != nil {
+			return err
+		}
+	}
+
+	cw.writeChange("Buildpacks")
+	return nil
+}
+
 func writeClusterStacksChange(ctx context.Context, keychain authn.Keychain, kpConfig config.KpConfig, stacks []ClusterStack, differ *ImportDiffer, cs buildk8s.ClientSet, cw changeWriter) error {
 	for _, stack := range stacks {
 		oldStack, err := cs.KpackClient.KpackV1alpha2().ClusterStacks().Get(ctx, stack.Name, metav1.GetOptions{})
@@ -74,7 +120,7 @@ func writeClusterStacksChange(ctx context.Context, keychain authn.Keychain, kpCo
 			oldStack = nil
 		}
 
-		diff, err := differ.DiffClusterStack(keychain, kpConfig, oldStack, stack)
+		diff, err := differ.DiffClusterStack(keychain, repositoryConfig(kpConfig, stack.Repository), oldStack, stack)
 		if err != nil {
 			return err
 		}
@@ -109,3 +155,26 @@ func writeClusterBuildersChange(ctx context.Context, builders []ClusterBuilder,
 	cw.writeChange("ClusterBuilders")
 	return nil
 }
+
+func writeBuildersChange(ctx context.Context, builders []Builder, differ *ImportDiffer, cs buildk8s.ClientSet, cw changeWriter) error {
+	for _, builder := range builders {
+		oldBuilder, err := cs.KpackClient.KpackV1alpha2().Builders(builder.Namespace).Get(ctx, builder.Name, metav1.GetOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+		if k8serrors.IsNotFound(err) {
+			oldBuilder = nil
+		}
+
+		diff, err := differ.DiffBuilder(oldBuilder, builder)
+		if err != nil {
+			return err
+		}
+		if err = cw.writeDiff(diff); err != nil {
+			return err
+		}
+	}
+
+	cw.writeChange("Builders")
+	return nil
+}
@@ -4,57 +4,105 @@
 package _import
 
 import (
+	"fmt"
+
 	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/registry"
 )
 
-const CurrentAPIVersion = "kp.kpack.io/v1alpha3"
+const CurrentAPIVersion = "kp.kpack.io/v1alpha4"
 
 type API struct {
 	Version string `yaml:"apiVersion" json:"apiVersion"`
 }
 
+// DependencyDescriptor is the current descriptor version, the resources it
+// declares may set a repository to relocate their images to instead of the
+// default repository of the kp-config
 type DependencyDescriptor struct {
-	APIVersion            string           `yaml:"apiVersion" json:"apiVersion"`
-	Kind                  string           `yaml:"kind" json:"kind"`
-	DefaultClusterStack   string           `yaml:"defaultClusterStack" json:"defaultClusterStack"`
-	DefaultClusterBuilder string           `yaml:"defaultClusterBuilder" json:"defaultClusterBuilder"`
-	Lifecycle             Lifecycle        `yaml:"lifecycle" json:"lifecycle"`
-	ClusterStores         []ClusterStore   `yaml:"clusterStores" json:"clusterStores"`
-	ClusterStacks         []ClusterStack   `yaml:"clusterStacks" json:"clusterStacks"`
-	ClusterBuilders       []ClusterBuilder `yaml:"clusterBuilders" json:"clusterBuilders"`
+	APIVersion            string             `yaml:"apiVersion" json:"apiVersion"`
+	Kind                  string             `yaml:"kind" json:"kind"`
+	DefaultClusterStack   string             `yaml:"defaultClusterStack" json:"defaultClusterStack"`
+	DefaultClusterBuilder string             `yaml:"defaultClusterBuilder" json:"defaultClusterBuilder"`
+	Lifecycle             Lifecycle          `yaml:"lifecycle" json:"lifecycle"`
+	ClusterStores         []ClusterStore     `yaml:"clusterStores" json:"clusterStores"`
+	ClusterBuildpacks     []ClusterBuildpack `yaml:"clusterBuildpacks,omitempty" json:"clusterBuildpacks,omitempty"`
+	Buildpacks            []Buildpack        `yaml:"buildpacks,omitempty" json:"buildpacks,omitempty"`
+	ClusterStacks         []ClusterStack     `yaml:"clusterStacks" json:"clusterStacks"`
+	ClusterBuilders       []ClusterBuilder   `yaml:"clusterBuilders" json:"clusterBuilders"`
+	Builders              []Builder          `yaml:"builders,omitempty" json:"builders,omitempty"`
 }
 
+// Source is a source image, the optional digest and PEM encoded cosign
+// public key are verified before the image is relocated
 type Source struct {
-	Image string `yaml:"image"`
+	Image     string `yaml:"image"`
+	Digest    string `yaml:"digest,omitempty" json:"digest,omitempty"`
+	PublicKey string `yaml:"publicKey,omitempty" json:"publicKey,omitempty"`
 }
 
 type Lifecycle Source
 
 type ClusterStore struct {
-	Name    string   `yaml:"name" json:"name"`
-	Sources []Source `yaml:"sources" json:"sources"`
+	Name       string   `yaml:"name" json:"name"`
+	Sources    []Source `yaml:"sources" json:"sources"`
+	Repository string   `yaml:"repository,omitempty" json:"repository,omitempty"`
+}
+
+type ClusterBuildpack struct {
+	Name              string `yaml:"name" json:"name"`
+	Source            `yaml:",inline"`
+	ServiceAccountRef *corev1.ObjectReference `yaml:"serviceAccountRef,omitempty" json:"serviceAccountRef,omitempty"`
+	Repository        string                  `yaml:"repository,omitempty" json:"repository,omitempty"`
+}
+
+type Buildpack struct {
+	Name               string `yaml:"name" json:"name"`
+	Namespace          string `yaml:"namespace" json:"namespace"`
+	Source             `yaml:",inline"`
+	ServiceAccountName string `yaml:"serviceAccountName,omitempty" json:"serviceAccountName,omitempty"`
+	Repository         string `yaml:"repository,omitempty" json:"repository,omitempty"`
 }
 
 type ClusterStack struct {
 	Name       string `yaml:"name" json:"name"`
 	BuildImage Source `yaml:"buildImage" json:"buildImage"`
 	RunImage   Source `yaml:"runImage" json:"runImage"`
+	Repository string `yaml:"repository,omitempty" json:"repository,omitempty"`
 }
 
 type ClusterBuilder struct {
-	Name         string                       `yaml:"name" json:"name"`
-	ClusterStack string                       `yaml:"clusterStack" json:"clusterStack"`
-	ClusterStore string                       `yaml:"clusterStore" json:"clusterStore"`
-	Order        []v1alpha2.BuilderOrderEntry `yaml:"order" json:"order"`
+	Name              string                       `yaml:"name" json:"name"`
+	ClusterStack      string                       `yaml:"clusterStack" json:"clusterStack"`
+	ClusterStore      string                       `yaml:"clusterStore" json:"clusterStore"`
+	Order             []v1alpha2.BuilderOrderEntry `yaml:"order" json:"order"`
+	ServiceAccountRef *corev1.ObjectReference      `yaml:"serviceAccountRef,omitempty" json:"serviceAccountRef,omitempty"`
+	Repository        string                       `yaml:"repository,omitempty" json:"repository,omitempty"`
 }
 
+type Builder struct {
+	Name               string                       `yaml:"name" json:"name"`
+	Namespace          string                       `yaml:"namespace" json:"namespace"`
+	ClusterStack       string                       `yaml:"clusterStack" json:"clusterStack"`
+	ClusterStore       string                       `yaml:"clusterStore,omitempty" json:"clusterStore,omitempty"`
+	Order              []v1alpha2.BuilderOrderEntry `yaml:"order" json:"order"`
+	ServiceAccountName string                       `yaml:"serviceAccountName,omitempty" json:"serviceAccountName,omitempty"`
+	Repository         string                       `yaml:"repository,omitempty" json:"repository,omitempty"`
+}
+
+// Validate checks the descriptor on its own. Builders may use stacks, stores
+// and buildpacks that are already in the cluster, so whether the resources they
+// reference exist is left to the importer which resolves the references.
 func (d DependencyDescriptor) Validate() error {
 	storeSet := map[string]interface{}{}
 	for _, store := range d.ClusterStores {
-		if name, ok := storeSet[store.Name]; ok {
-			return errors.Errorf("duplicate store name '%s'", name)
+		if _, ok := storeSet[store.Name]; ok {
+			return errors.Errorf("duplicate store name '%s'", store.Name)
 		}
 		storeSet[store.Name] = nil
 
@@ -63,13 +111,58 @@ func (d DependencyDescriptor) Validate() error {
 			if err != nil {
 				return err
 			}
+
+			if err := src.validate(); err != nil {
+				return err
+			}
+		}
+
+		if err := validateRepository(store.Repository); err != nil {
+			return err
+		}
+	}
+
+	clusterBuildpackSet := map[string]interface{}{}
+	for _, bp := range d.ClusterBuildpacks {
+		if _, ok := clusterBuildpackSet[bp.Name]; ok {
+			return errors.Errorf("duplicate cluster buildpack name '%s'", bp.Name)
+		}
+		clusterBuildpackSet[bp.Name] = nil
+
+		if err := bp.Source.validateImage(); err != nil {
+			return err
+		}
+
+		if err := validateRepository(bp.Repository); err != nil {
+			return err
+		}
+	}
+
+	buildpackSet := map[string]interface{}{}
+	for _, bp := range d.Buildpacks {
+		if bp.Namespace == "" {
+			return errors.Errorf("buildpack '%s' must have a namespace", bp.Name)
+		}
+
+		key := bp.Namespace + "/" + bp.Name
+		if _, ok := buildpackSet[key]; ok {
+			return errors.Errorf("duplicate buildpack name '%s' in namespace '%s'", bp.Name, bp.Namespace)
+		}
+		buildpackSet[key] = nil
+
+		if err := bp.Source.validateImage(); err != nil {
+			return err
+		}
+
+		if err := validateRepository(bp.Repository); err != nil {
+			return err
 		}
 	}
 
 	stackSet := map[string]interface{}{}
 	for _, stack := range d.ClusterStacks {
-		if name, ok := stackSet[stack.Name]; ok {
-			return errors.Errorf("duplicate stack name '%s'", name)
+		if _, ok := stackSet[stack.Name]; ok {
+			return errors.Errorf("duplicate stack name '%s'", stack.Name)
 		}
 		stackSet[stack.Name] = nil
 
@@ -82,6 +175,22 @@ func (d DependencyDescriptor) Validate() error {
 		if err != nil {
 			return err
 		}
+
+		if err := stack.BuildImage.validate(); err != nil {
+			return err
+		}
+
+		if err := stack.RunImage.validate(); err != nil {
+			return err
+		}
+
+		if err := validateRepository(stack.Repository); err != nil {
+			return err
+		}
+	}
+
+	if err := Source(d.Lifecycle).validate(); err != nil {
+		return err
 	}
 
 	if _, ok := stackSet[d.DefaultClusterStack]; !ok && d.DefaultClusterStack != "" {
@@ -90,19 +199,131 @@ func (d DependencyDescriptor) Validate() error {
 
 	ccbSet := map[string]interface{}{}
 	for _, ccb := range d.ClusterBuilders {
-		if name, ok := ccbSet[ccb.Name]; ok {
-			return errors.Errorf("duplicate cluster builder name '%s'", name)
+		if _, ok := ccbSet[ccb.Name]; ok {
+			return errors.Errorf("duplicate cluster builder name '%s'", ccb.Name)
 		}
 		ccbSet[ccb.Name] = nil
+
+		if err := validateBuilderOrder(fmt.Sprintf("cluster builder '%s'", ccb.Name), "", ccb.Order); err != nil {
+			return err
+		}
+
+		if err := validateRepository(ccb.Repository); err != nil {
+			return err
+		}
 	}
 
 	if _, ok := ccbSet[d.DefaultClusterBuilder]; !ok && d.DefaultClusterBuilder != "" {
 		return errors.Errorf("default cluster builder '%s' not found", d.DefaultClusterBuilder)
 	}
 
+	builderSet := map[string]interface{}{}
+	for _, b := range d.Builders {
+		if b.Namespace == "" {
+			return errors.Errorf("builder '%s' must have a namespace", b.Name)
+		}
+
+		key := b.Namespace + "/" + b.Name
+		if _, ok := builderSet[key]; ok {
+			return errors.Errorf("duplicate builder name '%s' in namespace '%s'", b.Name, b.Namespace)
+		}
+		builderSet[key] = nil
+
+		if err := validateBuilderOrder(fmt.Sprintf("builder '%s' in namespace '%s'", b.Name, b.Namespace), b.Namespace, b.Order); err != nil {
+			return err
+		}
+
+		if err := validateRepository(b.Repository); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateBuilderOrder validates the kinds of the buildpacks a builder
+// references. Cluster builders have no namespace and can only reference
+// cluster buildpacks. Whether the referenced resources exist is checked when
+// the references are resolved.
+func validateBuilderOrder(builder, namespace string, order []v1alpha2.BuilderOrderEntry) error {
+	for _, entry := range order {
+		for _, ref := range entry.Group {
+			switch ref.Kind {
+			case "", v1alpha2.ClusterBuildpackKind:
+			case v1alpha2.BuildpackKind:
+				if namespace == "" {
+					return errors.Errorf("%s cannot reference buildpack '%s', only cluster buildpacks can be referenced", builder, ref.Name)
+				}
+			default:
+				return errors.Errorf("%s references unsupported kind '%s'", builder, ref.Kind)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateRepository(repository string) error {
+	if repository == "" {
+		return nil
+	}
+
+	_, err := name.NewRepository(repository, name.WeakValidation)
+	return errors.Wrapf(err, "invalid repository '%s'", repository)
+}
+
+func (s Source) validateImage() error {
+	if _, err := name.ParseReference(s.Image, name.WeakValidation); err != nil {
+		return err
+	}
+	return s.validate()
+}
+
+func (s Source) validate() error {
+	if s.Digest != "" {
+		if _, err := v1.NewHash(s.Digest); err != nil {
+			return errors.Wrapf(err, "invalid digest for '%s'", s.Image)
+		}
+	}
+
+	if s.PublicKey != "" {
+		if err := registry.ValidatePublicKey([]byte(s.PublicKey)); err != nil {
+			return errors.Wrapf(err, "invalid public key for '%s'", s.Image)
+		}
+	}
+
 	return nil
 }
 
+// Verification returns what the source image is verified against before it is relocated
+func (s Source) Verification() registry.Verification {
+	return registry.Verification{
+		Digest:    s.Digest,
+		PublicKey: []byte(s.PublicKey),
+	}
+}
+
+// GetSources returns every source image of the descriptor
+func (d DependencyDescriptor) GetSources() []Source {
+	var sources []Source
+	if d.HasLifecycleImage() {
+		sources = append(sources, Source(d.Lifecycle))
+	}
+	for _, store := range d.ClusterStores {
+		sources = append(sources, store.Sources...)
+	}
+	for _, bp := range d.ClusterBuildpacks {
+		sources = append(sources, bp.Source)
+	}
+	for _, bp := range d.Buildpacks {
+		sources = append(sources, bp.Source)
+	}
+	for _, stack := range d.ClusterStacks {
+		sources = append(sources, stack.BuildImage, stack.RunImage)
+	}
+	return sources
+}
+
 func (d DependencyDescriptor) GetLifecycleImage() string {
 	return d.Lifecycle.Image
 }
@@ -118,6 +339,7 @@ func (d DependencyDescriptor) GetClusterStacks() []ClusterStack {
 				Name:       "default",
 				BuildImage: stack.BuildImage,
 				RunImage:   stack.RunImage,
+				Repository: stack.Repository,
 			})
 			break
 		}
@@ -129,10 +351,12 @@ func (d DependencyDescriptor) GetClusterBuilders() []ClusterBuilder {
 	for _, cb := range d.ClusterBuilders {
 		if cb.Name == d.DefaultClusterBuilder {
 			d.ClusterBuilders = append(d.ClusterBuilders, ClusterBuilder{
-				Name:         "default",
-				ClusterStack: cb.ClusterStack,
-				ClusterStore: cb.ClusterStore,
-				Order:        cb.Order,
+				Name:              "default",
+				ClusterStack:      cb.ClusterStack,
+				ClusterStore:      cb.ClusterStore,
+				Order:             cb.Order,
+				ServiceAccountRef: cb.ServiceAccountRef,
+				Repository:        cb.Repository,
 			})
 			break
 		}
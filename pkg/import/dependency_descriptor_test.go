@@ -10,6 +10,7 @@ import (
 	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
 	"github.com/sclevine/spec"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 
 	importpkg "github.com/vmware-tanzu/kpack-cli/pkg/import"
 )
@@ -135,6 +136,146 @@ func testDescriptor(t *testing.T, when spec.G, it spec.S) {
 				require.NoError(t, desc.Validate())
 			})
 		})
+
+		when("a cluster builder references a stack that is not defined", func() {
+			desc.ClusterBuilders[0].ClusterStack = "does-not-exist"
+
+			it("validates successfully as the stack may be in the cluster", func() {
+				require.NoError(t, desc.Validate())
+			})
+		})
+
+		when("a cluster builder references the default stack", func() {
+			desc.ClusterBuilders[0].ClusterStack = "default"
+
+			it("validates successfully", func() {
+				require.NoError(t, desc.Validate())
+			})
+		})
+
+		when("there are cluster buildpacks, buildpacks and builders", func() {
+			buildpackOrder := func(kind string) []v1alpha2.BuilderOrderEntry {
+				return []v1alpha2.BuilderOrderEntry{{
+					Group: []v1alpha2.BuilderBuildpackRef{{
+						ObjectReference: corev1.ObjectReference{Kind: kind, Name: "some-buildpack"},
+					}},
+				}}
+			}
+
+			desc.ClusterBuildpacks = []importpkg.ClusterBuildpack{
+				{Name: "some-buildpack", Source: importpkg.Source{Image: "some-buildpack-image"}},
+			}
+			desc.Buildpacks = []importpkg.Buildpack{
+				{Name: "some-buildpack", Namespace: "some-namespace", Source: importpkg.Source{Image: "some-buildpack-image"}},
+			}
+			desc.ClusterBuilders = append(desc.ClusterBuilders, importpkg.ClusterBuilder{
+				Name:         "some-buildpacks-cb",
+				ClusterStack: "some-stack",
+				Order:        buildpackOrder("ClusterBuildpack"),
+			})
+			desc.Builders = []importpkg.Builder{
+				{
+					Name:         "some-builder",
+					Namespace:    "some-namespace",
+					ClusterStack: "some-stack",
+					Order:        append(buildpackOrder("Buildpack"), buildpackOrder("ClusterBuildpack")...),
+					Repository:   "some-registry.io/some-builders",
+				},
+			}
+
+			it("validates successfully", func() {
+				require.NoError(t, desc.Validate())
+			})
+
+			when("a cluster builder references a namespaced buildpack", func() {
+				desc.ClusterBuilders[1].Order = buildpackOrder("Buildpack")
+
+				it("fails validation", func() {
+					require.EqualError(t, desc.Validate(), "cluster builder 'some-buildpacks-cb' cannot reference buildpack 'some-buildpack', only cluster buildpacks can be referenced")
+				})
+			})
+
+			when("a builder references an unsupported kind", func() {
+				desc.Builders[0].Order = buildpackOrder("Image")
+
+				it("fails validation", func() {
+					require.EqualError(t, desc.Validate(), "builder 'some-builder' in namespace 'some-namespace' references unsupported kind 'Image'")
+				})
+			})
+
+			when("there is a duplicate buildpack in a namespace", func() {
+				desc.Buildpacks = append(desc.Buildpacks, desc.Buildpacks[0])
+
+				it("fails validation", func() {
+					require.EqualError(t, desc.Validate(), "duplicate buildpack name 'some-buildpack' in namespace 'some-namespace'")
+				})
+			})
+
+			when("a builder has no namespace", func() {
+				desc.Builders[0].Namespace = ""
+
+				it("fails validation", func() {
+					require.EqualError(t, desc.Validate(), "builder 'some-builder' must have a namespace")
+				})
+			})
+
+			when("a repository is invalid", func() {
+				desc.Builders[0].Repository = "Invalid Repository"
+
+				it("fails validation", func() {
+					require.ErrorContains(t, desc.Validate(), "invalid repository 'Invalid Repository'")
+				})
+			})
+		})
+	})
+
+	when("#References", func() {
+		it("returns the stacks, stores and buildpacks referenced by builders", func() {
+			desc.Builders = []importpkg.Builder{
+				{
+					Name:         "some-builder",
+					Namespace:    "some-namespace",
+					ClusterStack: "default",
+					Order: []v1alpha2.BuilderOrderEntry{{
+						Group: []v1alpha2.BuilderBuildpackRef{
+							{ObjectReference: corev1.ObjectReference{Kind: "Buildpack", Name: "some-buildpack"}},
+							{ObjectReference: corev1.ObjectReference{Kind: "ClusterBuildpack", Name: "some-cluster-buildpack"}},
+						},
+					}},
+				},
+			}
+
+			require.Equal(t, []importpkg.Reference{
+				{Path: "clusterBuilders[0].clusterStack", Referrer: "cluster builder 'some-cb'", Kind: "ClusterStack", Name: "some-stack"},
+				{Path: "clusterBuilders[0].clusterStore", Referrer: "cluster builder 'some-cb'", Kind: "ClusterStore", Name: "some-store"},
+				{Path: "builders[0].clusterStack", Referrer: "builder 'some-builder' in namespace 'some-namespace'", Kind: "ClusterStack", Name: "default"},
+				{Path: "builders[0].order[0].group[0]", Referrer: "builder 'some-builder' in namespace 'some-namespace'", Kind: "Buildpack", Namespace: "some-namespace", Name: "some-buildpack"},
+				{Path: "builders[0].order[0].group[1]", Referrer: "builder 'some-builder' in namespace 'some-namespace'", Kind: "ClusterBuildpack", Name: "some-cluster-buildpack"},
+			}, desc.References())
+		})
+	})
+
+	when("#ReadDescriptor", func() {
+		it("reads a v1alpha3 descriptor with cluster builders using the stacks and stores of the cluster", func() {
+			descriptor, err := importpkg.ReadDescriptor(`
+apiVersion: kp.kpack.io/v1alpha3
+kind: DependencyDescriptor
+lifecycle:
+  image: some-lifecycle-image
+clusterBuilders:
+- name: some-cb
+  clusterStack: cluster-stack
+  clusterStore: cluster-store
+  order:
+  - group:
+    - id: some-buildpack
+`)
+			require.NoError(t, err)
+			require.Equal(t, []importpkg.Reference{
+				{Path: "clusterBuilders[0].clusterStack", Referrer: "cluster builder 'some-cb'", Kind: "ClusterStack", Name: "cluster-stack"},
+				{Path: "clusterBuilders[0].clusterStore", Referrer: "cluster builder 'some-cb'", Kind: "ClusterStore", Name: "cluster-store"},
+			}, descriptor.References())
+		})
 	})
 
 	when("#GetClusterStacks", func() {
@@ -15,8 +15,8 @@ type DependencyDescriptorV1 struct {
 	Kind                  string             `yaml:"kind"`
 	DefaultStack          string             `yaml:"defaultStack"`
 	DefaultClusterBuilder string             `yaml:"defaultClusterBuilder"`
-	Stores                []ClusterStore     `yaml:"stores"`
-	Stacks                []ClusterStack     `yaml:"stacks"`
+	Stores                []ClusterStoreV3   `yaml:"stores"`
+	Stacks                []ClusterStackV3   `yaml:"stacks"`
 	ClusterBuilders       []ClusterBuilderV1 `yaml:"clusterBuilders"`
 }
 
@@ -27,8 +27,8 @@ type ClusterBuilderV1 struct {
 	Order []corev1alpha1.OrderEntry `yaml:"order"`
 }
 
-func (d1 DependencyDescriptorV1) ToNextVersion() DependencyDescriptor {
-	var d DependencyDescriptor
+func (d1 DependencyDescriptorV1) ToNextVersion() DependencyDescriptorV3 {
+	var d DependencyDescriptorV3
 	d.APIVersion = d1.APIVersion
 	d.Kind = d1.Kind
 	d.DefaultClusterStack = d1.DefaultStack
@@ -36,7 +36,7 @@ func (d1 DependencyDescriptorV1) ToNextVersion() DependencyDescriptor {
 	d.ClusterStores = d1.Stores
 	d.ClusterStacks = d1.Stacks
 	for _, cb := range d1.ClusterBuilders {
-		d.ClusterBuilders = append(d.ClusterBuilders, ClusterBuilder{
+		d.ClusterBuilders = append(d.ClusterBuilders, ClusterBuilderV3{
 			Name:         cb.Name,
 			ClusterStack: cb.Stack,
 			ClusterStore: cb.Store,
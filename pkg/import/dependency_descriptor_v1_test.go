@@ -22,7 +22,7 @@ func testDescriptorV1(t *testing.T, when spec.G, it spec.S) {
 		descV1 := importpkg.DependencyDescriptorV1{
 			DefaultStack:          "some-stack",
 			DefaultClusterBuilder: "some-ccb",
-			Stores: []importpkg.ClusterStore{
+			Stores: []importpkg.ClusterStoreV3{
 				{
 					Name: "some-store",
 					Sources: []importpkg.Source{
@@ -32,7 +32,7 @@ func testDescriptorV1(t *testing.T, when spec.G, it spec.S) {
 					},
 				},
 			},
-			Stacks: []importpkg.ClusterStack{
+			Stacks: []importpkg.ClusterStackV3{
 				{
 					Name: "some-stack",
 					BuildImage: importpkg.Source{
@@ -66,7 +66,7 @@ func testDescriptorV1(t *testing.T, when spec.G, it spec.S) {
 		}
 
 		it("converts successfully", func() {
-			d := descV1.ToNextVersion()
+			d := descV1.ToNextVersion().ToNextVersion()
 			require.NoError(t, d.Validate())
 		})
 	})
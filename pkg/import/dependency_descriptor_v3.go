@@ -0,0 +1,70 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package _import
+
+import (
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+)
+
+const APIVersionV3 = "kp.kpack.io/v1alpha3"
+
+type DependencyDescriptorV3 struct {
+	APIVersion            string             `yaml:"apiVersion" json:"apiVersion"`
+	Kind                  string             `yaml:"kind" json:"kind"`
+	DefaultClusterStack   string             `yaml:"defaultClusterStack" json:"defaultClusterStack"`
+	DefaultClusterBuilder string             `yaml:"defaultClusterBuilder" json:"defaultClusterBuilder"`
+	Lifecycle             Lifecycle          `yaml:"lifecycle" json:"lifecycle"`
+	ClusterStores         []ClusterStoreV3   `yaml:"clusterStores" json:"clusterStores"`
+	ClusterStacks         []ClusterStackV3   `yaml:"clusterStacks" json:"clusterStacks"`
+	ClusterBuilders       []ClusterBuilderV3 `yaml:"clusterBuilders" json:"clusterBuilders"`
+}
+
+type ClusterStoreV3 struct {
+	Name    string   `yaml:"name" json:"name"`
+	Sources []Source `yaml:"sources" json:"sources"`
+}
+
+type ClusterStackV3 struct {
+	Name       string `yaml:"name" json:"name"`
+	BuildImage Source `yaml:"buildImage" json:"buildImage"`
+	RunImage   Source `yaml:"runImage" json:"runImage"`
+}
+
+type ClusterBuilderV3 struct {
+	Name         string                       `yaml:"name" json:"name"`
+	ClusterStack string                       `yaml:"clusterStack" json:"clusterStack"`
+	ClusterStore string                       `yaml:"clusterStore" json:"clusterStore"`
+	Order        []v1alpha2.BuilderOrderEntry `yaml:"order" json:"order"`
+}
+
+func (d3 DependencyDescriptorV3) ToNextVersion() DependencyDescriptor {
+	var d DependencyDescriptor
+	d.APIVersion = d3.APIVersion
+	d.Kind = d3.Kind
+	d.DefaultClusterStack = d3.DefaultClusterStack
+	d.DefaultClusterBuilder = d3.DefaultClusterBuilder
+	d.Lifecycle = d3.Lifecycle
+	for _, cs := range d3.ClusterStores {
+		d.ClusterStores = append(d.ClusterStores, ClusterStore{
+			Name:    cs.Name,
+			Sources: cs.Sources,
+		})
+	}
+	for _, cs := range d3.ClusterStacks {
+		d.ClusterStacks = append(d.ClusterStacks, ClusterStack{
+			Name:       cs.Name,
+			BuildImage: cs.BuildImage,
+			RunImage:   cs.RunImage,
+		})
+	}
+	for _, cb := range d3.ClusterBuilders {
+		d.ClusterBuilders = append(d.ClusterBuilders, ClusterBuilder{
+			Name:         cb.Name,
+			ClusterStack: cb.ClusterStack,
+			ClusterStore: cb.ClusterStore,
+			Order:        cb.Order,
+		})
+	}
+	return d
+}
@@ -0,0 +1,100 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package _import_test
+
+import (
+	"testing"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/require"
+
+	importpkg "github.com/vmware-tanzu/kpack-cli/pkg/import"
+)
+
+func TestDescriptorV3(t *testing.T) {
+	spec.Run(t, "TestDescriptorV3", testDescriptorV3)
+}
+
+func testDescriptorV3(t *testing.T, when spec.G, it spec.S) {
+	when("#ToNextVersion", func() {
+		order := []v1alpha2.BuilderOrderEntry{
+			{
+				Group: []v1alpha2.BuilderBuildpackRef{
+					{
+						BuildpackRef: corev1alpha1.BuildpackRef{
+							BuildpackInfo: corev1alpha1.BuildpackInfo{
+								Id: "some-buildpack",
+							},
+						},
+					},
+				},
+			},
+		}
+
+		descV3 := importpkg.DependencyDescriptorV3{
+			APIVersion:            importpkg.APIVersionV3,
+			Kind:                  "DependencyDescriptor",
+			DefaultClusterStack:   "some-stack",
+			DefaultClusterBuilder: "some-cb",
+			Lifecycle:             importpkg.Lifecycle{Image: "some-lifecycle-image"},
+			ClusterStores: []importpkg.ClusterStoreV3{
+				{
+					Name:    "some-store",
+					Sources: []importpkg.Source{{Image: "some-store-image"}},
+				},
+			},
+			ClusterStacks: []importpkg.ClusterStackV3{
+				{
+					Name:       "some-stack",
+					BuildImage: importpkg.Source{Image: "build-image"},
+					RunImage:   importpkg.Source{Image: "run-image"},
+				},
+			},
+			ClusterBuilders: []importpkg.ClusterBuilderV3{
+				{
+					Name:         "some-cb",
+					ClusterStack: "some-stack",
+					ClusterStore: "some-store",
+					Order:        order,
+				},
+			},
+		}
+
+		it("converts successfully", func() {
+			d := descV3.ToNextVersion()
+			require.NoError(t, d.Validate())
+
+			require.Equal(t, importpkg.DependencyDescriptor{
+				APIVersion:            importpkg.APIVersionV3,
+				Kind:                  "DependencyDescriptor",
+				DefaultClusterStack:   "some-stack",
+				DefaultClusterBuilder: "some-cb",
+				Lifecycle:             importpkg.Lifecycle{Image: "some-lifecycle-image"},
+				ClusterStores: []importpkg.ClusterStore{
+					{
+						Name:    "some-store",
+						Sources: []importpkg.Source{{Image: "some-store-image"}},
+					},
+				},
+				ClusterStacks: []importpkg.ClusterStack{
+					{
+						Name:       "some-stack",
+						BuildImage: importpkg.Source{Image: "build-image"},
+						RunImage:   importpkg.Source{Image: "run-image"},
+					},
+				},
+				ClusterBuilders: []importpkg.ClusterBuilder{
+					{
+						Name:         "some-cb",
+						ClusterStack: "some-stack",
+						ClusterStore: "some-store",
+						Order:        order,
+					},
+				},
+			}, d)
+		})
+	})
+}
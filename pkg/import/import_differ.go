@@ -16,7 +16,7 @@ import (
 )
 
 type RelocatedImageProvider interface {
-	RelocatedImage(authn.Keychain, config.KpConfig, string) (string, error)
+	RelocatedImage(authn.Keychain, config.KpConfig, config.Artifact, string) (string, error)
 }
 
 type Differ interface {
@@ -29,7 +29,7 @@ type ImportDiffer struct {
 }
 
 func (id *ImportDiffer) DiffLifecycle(keychain authn.Keychain, kpConfig config.KpConfig, oldImg string, newImg string) (string, error) {
-	relocatedLifecycle, err := id.RelocatedImageProvider.RelocatedImage(keychain, kpConfig, newImg)
+	relocatedLifecycle, err := id.RelocatedImageProvider.RelocatedImage(keychain, kpConfig, config.LifecycleArtifact, newImg)
 	if err != nil {
 		return "", err
 	}
@@ -46,7 +46,7 @@ func (id *ImportDiffer) DiffClusterStore(keychain authn.Keychain, kpConfig confi
 	for _, bp := range newCS.Sources {
 		image := bp.Image
 		errs.Go(func() error {
-			relocatedBP, err := id.RelocatedImageProvider.RelocatedImage(keychain, kpConfig, image)
+			relocatedBP, err := id.RelocatedImageProvider.RelocatedImage(keychain, kpConfig, config.BuildpackageArtifact, image)
 			if err != nil {
 				return err
 			}
@@ -83,14 +83,16 @@ Sources:`, oldCS.Name)
 }
 
 func (id *ImportDiffer) DiffClusterStack(keychain authn.Keychain, kpConfig config.KpConfig, oldCS *v1alpha2.ClusterStack, newCS ClusterStack) (diff string, err error) {
-	newCS.BuildImage.Image, err = id.RelocatedImageProvider.RelocatedImage(keychain, kpConfig, newCS.BuildImage.Image)
+	buildImage, err := id.RelocatedImageProvider.RelocatedImage(keychain, kpConfig, config.StackArtifact, newCS.BuildImage.Image)
 	if err != nil {
 		return "", err
 	}
-	newCS.RunImage.Image, err = id.RelocatedImageProvider.RelocatedImage(keychain, kpConfig, newCS.RunImage.Image)
+	runImage, err := id.RelocatedImageProvider.RelocatedImage(keychain, kpConfig, config.StackArtifact, newCS.RunImage.Image)
 	if err != nil {
 		return "", err
 	}
+	newCS.BuildImage = Source{Image: buildImage}
+	newCS.RunImage = Source{Image: runImage}
 
 	var oldDiffableStack interface{}
 	if oldCS != nil {
@@ -104,16 +106,91 @@ func (id *ImportDiffer) DiffClusterStack(keychain authn.Keychain, kpConfig confi
 	return id.Differ.Diff(oldDiffableStack, newCS)
 }
 
+func (id *ImportDiffer) DiffClusterBuildpack(keychain authn.Keychain, kpConfig config.KpConfig, oldBP *v1alpha2.ClusterBuildpack, newBP ClusterBuildpack) (string, error) {
+	image, err := id.RelocatedImageProvider.RelocatedImage(keychain, kpConfig, config.BuildpackageArtifact, newBP.Image)
+	if err != nil {
+		return "", err
+	}
+	newBP.Source = Source{Image: image}
+	newBP.Repository = ""
+
+	var oldDiffableBP interface{}
+	if oldBP != nil {
+		old := ClusterBuildpack{
+			Name:   oldBP.Name,
+			Source: Source{Image: oldBP.Spec.Image},
+		}
+		if newBP.ServiceAccountRef != nil {
+			old.ServiceAccountRef = oldBP.Spec.ServiceAccountRef
+		}
+		oldDiffableBP = old
+	}
+
+	return id.Differ.Diff(oldDiffableBP, newBP)
+}
+
+func (id *ImportDiffer) DiffBuildpack(keychain authn.Keychain, kpConfig config.KpConfig, oldBP *v1alpha2.Buildpack, newBP Buildpack) (string, error) {
+	image, err := id.RelocatedImageProvider.RelocatedImage(keychain, kpConfig, config.BuildpackageArtifact, newBP.Image)
+	if err != nil {
+		return "", err
+	}
+	newBP.Source = Source{Image: image}
+	newBP.Repository = ""
+
+	var oldDiffableBP interface{}
+	if oldBP != nil {
+		old := Buildpack{
+			Name:      oldBP.Name,
+			Namespace: oldBP.Namespace,
+			Source:    Source{Image: oldBP.Spec.Image},
+		}
+		if newBP.ServiceAccountName != "" {
+			old.ServiceAccountName = oldBP.Spec.ServiceAccountName
+		}
+		oldDiffableBP = old
+	}
+
+	return id.Differ.Diff(oldDiffableBP, newBP)
+}
+
 func (id *ImportDiffer) DiffClusterBuilder(oldCB *v1alpha2.ClusterBuilder, newCB ClusterBuilder) (string, error) {
+	newCB.Repository = ""
+
 	var oldDiffableCB interface{}
 	if oldCB != nil {
-		oldDiffableCB = ClusterBuilder{
+		old := ClusterBuilder{
 			Name:         oldCB.Name,
 			ClusterStack: oldCB.Spec.Stack.Name,
 			ClusterStore: oldCB.Spec.Store.Name,
 			Order:        oldCB.Spec.Order,
 		}
+		if newCB.ServiceAccountRef != nil {
+			sa := oldCB.Spec.ServiceAccountRef
+			old.ServiceAccountRef = &sa
+		}
+		oldDiffableCB = old
 	}
 
 	return id.Differ.Diff(oldDiffableCB, newCB)
 }
+
+func (id *ImportDiffer) DiffBuilder(oldB *v1alpha2.Builder, newB Builder) (string, error) {
+	newB.Repository = ""
+
+	var oldDiffableB interface{}
+	if oldB != nil {
+		old := Builder{
+			Name:         oldB.Name,
+			Namespace:    oldB.Namespace,
+			ClusterStack: oldB.Spec.Stack.Name,
+			ClusterStore: oldB.Spec.Store.Name,
+			Order:        oldB.Spec.Order,
+		}
+		if newB.ServiceAccountName != "" {
+			old.ServiceAccountName = oldB.Spec.ServiceAccountName
+		}
+		oldDiffableB = old
+	}
+
+	return id.Differ.Diff(oldDiffableB, newB)
+}
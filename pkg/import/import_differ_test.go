@@ -33,7 +33,7 @@ func NewFakeRelocatedImageProvider() *FakeRelocatedImageProvider {
 	return &FakeRelocatedImageProvider{}
 }
 
-func (rg *FakeRelocatedImageProvider) RelocatedImage(keychain authn.Keychain, kpConfig config.KpConfig, image string) (string, error) {
+func (rg *FakeRelocatedImageProvider) RelocatedImage(keychain authn.Keychain, kpConfig config.KpConfig, artifact config.Artifact, image string) (string, error) {
 	return image, nil
 }
 
@@ -231,4 +231,86 @@ func testImportDiffer(t *testing.T, when spec.G, it spec.S) {
 			require.Equal(t, nil, diffArg0)
 		})
 	})
+
+	when("DiffClusterBuildpack", func() {
+		it("returns a diff of old and new cluster buildpack", func() {
+			oldBuildpack := &v1alpha2.ClusterBuildpack{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "some-buildpack",
+				},
+				Spec: v1alpha2.ClusterBuildpackSpec{
+					ImageSource: corev1alpha1.ImageSource{Image: "some-old-buildpackage"},
+					ServiceAccountRef: &corev1.ObjectReference{
+						Namespace: "some-namespace",
+						Name:      "some-serviceaccount",
+					},
+				},
+			}
+			newBuildpack := importpkg.ClusterBuildpack{
+				Name:       "some-buildpack",
+				Source:     importpkg.Source{Image: "some-new-buildpackage", Digest: "sha256:some-digest"},
+				Repository: "some-repo",
+			}
+
+			diff, err := importDiffer.DiffClusterBuildpack(fakeKeychain, kpConfig, oldBuildpack, newBuildpack)
+			require.NoError(t, err)
+			require.Equal(t, "some-diff", diff)
+			diffArg0, diffArg1 := fakeDiffer.Args()
+			require.Equal(t, importpkg.ClusterBuildpack{
+				Name:   "some-buildpack",
+				Source: importpkg.Source{Image: "some-old-buildpackage"},
+			}, diffArg0)
+			require.Equal(t, importpkg.ClusterBuildpack{
+				Name:   "some-buildpack",
+				Source: importpkg.Source{Image: "some-new-buildpackage"},
+			}, diffArg1)
+		})
+	})
+
+	when("DiffBuilder", func() {
+		it("returns a diff of old and new builder", func() {
+			oldBuilder := &v1alpha2.Builder{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "some-builder",
+					Namespace: "some-namespace",
+				},
+				Spec: v1alpha2.NamespacedBuilderSpec{
+					BuilderSpec: v1alpha2.BuilderSpec{
+						Stack: corev1.ObjectReference{
+							Name: "some-stack",
+						},
+					},
+					ServiceAccountName: "some-serviceaccount",
+				},
+			}
+			newBuilder := importpkg.Builder{
+				Name:               "some-builder",
+				Namespace:          "some-namespace",
+				ClusterStack:       "some-new-stack",
+				ServiceAccountName: "some-new-serviceaccount",
+				Repository:         "some-repo",
+			}
+
+			diff, err := importDiffer.DiffBuilder(oldBuilder, newBuilder)
+			require.NoError(t, err)
+			require.Equal(t, "some-diff", diff)
+			diffArg0, diffArg1 := fakeDiffer.Args()
+			require.Equal(t, importpkg.Builder{
+				Name:               "some-builder",
+				Namespace:          "some-namespace",
+				ClusterStack:       "some-stack",
+				ServiceAccountName: "some-serviceaccount",
+			}, diffArg0)
+			newBuilder.Repository = ""
+			require.Equal(t, newBuilder, diffArg1)
+		})
+
+		it("diffs against nil when old builder does not exist", func() {
+			diff, err := importDiffer.DiffBuilder(nil, importpkg.Builder{})
+			require.NoError(t, err)
+			require.Equal(t, "some-diff", diff)
+			diffArg0, _ := fakeDiffer.Args()
+			require.Equal(t, nil, diffArg0)
+		})
+	})
 }
@@ -16,7 +16,9 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
+	watchTools "k8s.io/client-go/tools/watch"
 
+	"github.com/vmware-tanzu/kpack-cli/pkg/buildpackage"
 	"github.com/vmware-tanzu/kpack-cli/pkg/clusterstack"
 	"github.com/vmware-tanzu/kpack-cli/pkg/clusterstore"
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
@@ -39,35 +41,52 @@ type Importer struct {
 	k8sClient           kubernetes.Interface
 	printer             Printer
 	imageRelocator      registry.Relocator
-	imageFetcher        registry.Fetcher
+	imageFetcher        *registry.VerifyingFetcher
 	waiter              commands.ResourceWaiter
 	clusterStoreFactory *clusterstore.Factory
 	clusterStackFactory *clusterstack.Factory
+	buildpackUploader   clusterstore.BuildpackageUploader
 	timestampProvider   TimestampProvider
+	relocations         *relocationRecorder
 }
 
 type relocatedDescriptor struct {
-	lifecycle       *corev1.ConfigMap
-	clusterStores   []*v1alpha2.ClusterStore
-	clusterStacks   []*v1alpha2.ClusterStack
-	clusterBuilders []*v1alpha2.ClusterBuilder
+	lifecycle         *corev1.ConfigMap
+	clusterStores     []*v1alpha2.ClusterStore
+	clusterBuildpacks []*v1alpha2.ClusterBuildpack
+	buildpacks        []*v1alpha2.Buildpack
+	clusterStacks     []*v1alpha2.ClusterStack
+	clusterBuilders   []*v1alpha2.ClusterBuilder
+	builders          []*v1alpha2.Builder
 }
 
 func NewImporter(printer Printer, k8sClient kubernetes.Interface, client versioned.Interface, fetcher registry.Fetcher, relocator registry.Relocator, waiter commands.ResourceWaiter, timestampProvider TimestampProvider) *Importer {
+	relocations := newRelocationRecorder(fetcher, relocator)
+	verifyingFetcher := registry.NewVerifyingFetcher(relocations)
 	return &Importer{
-		imageRelocator:      relocator,
+		imageRelocator:      relocations,
 		client:              client,
 		k8sClient:           k8sClient,
 		printer:             printer,
 		waiter:              waiter,
-		imageFetcher:        fetcher,
+		imageFetcher:        verifyingFetcher,
 		timestampProvider:   timestampProvider,
-		clusterStackFactory: clusterstack.NewFactory(printer, relocator, fetcher),
-		clusterStoreFactory: clusterstore.NewFactory(printer, relocator, fetcher),
+		relocations:         relocations,
+		clusterStackFactory: clusterstack.NewFactory(printer, relocations, verifyingFetcher),
+		clusterStoreFactory: clusterstore.NewFactory(printer, relocations, verifyingFetcher),
+		buildpackUploader: &buildpackage.Uploader{
+			Fetcher:   verifyingFetcher,
+			Relocator: relocations,
+		},
 	}
 }
 
 func (i *Importer) ReadDescriptor(rawDescriptor string) (DependencyDescriptor, error) {
+	return ReadDescriptor(rawDescriptor)
+}
+
+// ReadDescriptor parses and validates a dependency descriptor of any supported apiVersion
+func ReadDescriptor(rawDescriptor string) (DependencyDescriptor, error) {
 	var api API
 	if err := yaml.Unmarshal([]byte(rawDescriptor), &api); err != nil {
 		return DependencyDescriptor{}, err
@@ -80,13 +99,19 @@ func (i *Importer) ReadDescriptor(rawDescriptor string) (DependencyDescriptor, e
 		if err := yaml.Unmarshal([]byte(rawDescriptor), &d1); err != nil {
 			return DependencyDescriptor{}, err
 		}
-		descriptor = d1.ToNextVersion()
+		descriptor = d1.ToNextVersion().ToNextVersion()
+	case APIVersionV3:
+		var d3 DependencyDescriptorV3
+		if err := yaml.Unmarshal([]byte(rawDescriptor), &d3); err != nil {
+			return DependencyDescriptor{}, err
+		}
+		descriptor = d3.ToNextVersion()
 	case CurrentAPIVersion:
 		if err := yaml.Unmarshal([]byte(rawDescriptor), &descriptor); err != nil {
 			return DependencyDescriptor{}, err
 		}
 	default:
-		return DependencyDescriptor{}, errors.Errorf("did not find expected apiVersion, must be one of: %s", []string{APIVersionV1, CurrentAPIVersion})
+		return DependencyDescriptor{}, errors.Errorf("did not find expected apiVersion, must be one of: %s", []string{APIVersionV1, APIVersionV3, CurrentAPIVersion})
 	}
 
 	if err := descriptor.Validate(); err != nil {
@@ -96,55 +121,180 @@ func (i *Importer) ReadDescriptor(rawDescriptor string) (DependencyDescriptor, e
 	return descriptor, nil
 }
 
-func (i *Importer) ImportDescriptor(ctx context.Context, keychain authn.Keychain, kpConfig config.KpConfig, rawDescriptor string) ([]runtime.Object, error) {
-	descriptor, err := i.ReadDescriptor(rawDescriptor)
+func (i *Importer) ImportDescriptor(ctx context.Context, keychain authn.Keychain, kpConfig config.KpConfig, descriptor DependencyDescriptor) ([]runtime.Object, error) {
+	return i.importDescriptor(ctx, keychain, kpConfig, descriptor, false)
+}
+
+// ResumeImportDescriptor resumes the failed import of the dependency
+// descriptor, the resources that were saved before it failed are not saved
+// again and the journal keeps the resources they replaced for a rollback
+func (i *Importer) ResumeImportDescriptor(ctx context.Context, keychain authn.Keychain, kpConfig config.KpConfig, descriptor DependencyDescriptor) ([]runtime.Object, error) {
+	return i.importDescriptor(ctx, keychain, kpConfig, descriptor, true)
+}
+
+func (i *Importer) importDescriptor(ctx context.Context, keychain authn.Keychain, kpConfig config.KpConfig, descriptor DependencyDescriptor, resume bool) ([]runtime.Object, error) {
+	digest, err := descriptor.Digest()
 	if err != nil {
 		return nil, err
 	}
 
-	rDescriptor, objects, err := i.relocateDescriptor(ctx, keychain, kpConfig, i.timestampProvider.GetTimestamp(), descriptor)
+	var journal *ImportJournal
+	ts := i.timestampProvider.GetTimestamp()
+	if resume {
+		journal, err = i.resumeJournal(ctx, digest)
+		if err != nil {
+			return nil, err
+		}
+		ts = journal.Timestamp
+	} else if err := i.checkLastImport(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := i.resolveReferences(ctx, descriptor); err != nil {
+		return nil, err
+	}
+
+	if err := i.verifySources(keychain, descriptor); err != nil {
+		return nil, err
+	}
+
+	rDescriptor, objects, err := i.relocateDescriptor(ctx, keychain, kpConfig, ts, descriptor)
 	if err != nil {
 		return nil, err
 	}
 
-	if rDescriptor.lifecycle != nil {
-		if err := i.patchLifecycleConfigMap(ctx, rDescriptor.lifecycle); err != nil {
+	if journal == nil {
+		journal, err = i.startJournal(ctx, ts, digest, objects, nil)
+		if err != nil {
 			return nil, err
 		}
 	}
 
+	if err := i.saveDescriptor(ctx, rDescriptor, journal); err != nil {
+		return nil, err
+	}
+
+	return objects, i.completeJournal(ctx, journal)
+}
+
+// saveDescriptor creates or updates the relocated resources and waits for
+// them to become ready, the builders are saved once their stores and stacks
+// are ready. Resources that a resumed import already saved are not saved again.
+func (i *Importer) saveDescriptor(ctx context.Context, rDescriptor relocatedDescriptor, journal *ImportJournal) error {
+	if rDescriptor.lifecycle != nil {
+		_, err := i.save(ctx, journal, rDescriptor.lifecycle, func() (runtime.Object, error) {
+			return rDescriptor.lifecycle, i.patchLifecycleConfigMap(ctx, rDescriptor.lifecycle)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	var targets []commands.WaitTarget
 	storeToGeneration := map[string]int64{}
 	for _, store := range rDescriptor.clusterStores {
-		gen, err := i.saveClusterStore(ctx, store)
+		store := store
+		savedStore, err := i.save(ctx, journal, store, func() (runtime.Object, error) {
+			return i.saveClusterStore(ctx, store)
+		})
 		if err != nil {
-			return nil, err
+			return err
+		}
+
+		storeToGeneration[store.Name] = savedStore.(*v1alpha2.ClusterStore).Generation
+		targets = append(targets, commands.WaitTarget{Object: savedStore})
+	}
+
+	for _, bp := range rDescriptor.clusterBuildpacks {
+		bp := bp
+		savedBuildpack, err := i.save(ctx, journal, bp, func() (runtime.Object, error) {
+			return i.saveClusterBuildpack(ctx, bp)
+		})
+		if err != nil {
+			return err
 		}
+		targets = append(targets, commands.WaitTarget{Object: savedBuildpack})
+	}
 
-		storeToGeneration[store.Name] = gen
+	for _, bp := range rDescriptor.buildpacks {
+		bp := bp
+		savedBuildpack, err := i.save(ctx, journal, bp, func() (runtime.Object, error) {
+			return i.saveBuildpack(ctx, bp)
+		})
+		if err != nil {
+			return err
+		}
+		targets = append(targets, commands.WaitTarget{Object: savedBuildpack})
 	}
 
 	stackToGeneration := map[string]int64{}
 	for _, stack := range rDescriptor.clusterStacks {
-		gen, err := i.saveClusterStack(ctx, stack)
+		stack := stack
+		savedStack, err := i.save(ctx, journal, stack, func() (runtime.Object, error) {
+			return i.saveClusterStack(ctx, stack)
+		})
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		stackToGeneration[stack.Name] = gen
+		stackToGeneration[stack.Name] = savedStack.(*v1alpha2.ClusterStack).Generation
+		targets = append(targets, commands.WaitTarget{Object: savedStack})
+	}
+
+	if err := commands.WaitAll(ctx, i.waiter, i.printer, targets...); err != nil {
+		return err
 	}
 
+	targets = nil
 	for _, builder := range rDescriptor.clusterBuilders {
-		if err := i.saveClusterBuilder(ctx, storeToGeneration, stackToGeneration, builder); err != nil {
-			return nil, err
+		builder := builder
+		savedBuilder, err := i.save(ctx, journal, builder, func() (runtime.Object, error) {
+			return i.saveClusterBuilder(ctx, builder)
+		})
+		if err != nil {
+			return err
 		}
+
+		targets = append(targets, commands.WaitTarget{
+			Object:      savedBuilder,
+			ExtraChecks: []watchTools.ConditionFunc{commands.BuilderHasResolved(storeToGeneration[builder.Spec.Store.Name], stackToGeneration[builder.Spec.Stack.Name])},
+		})
 	}
 
-	return objects, nil
+	for _, builder := range rDescriptor.builders {
+		builder := builder
+		savedBuilder, err := i.save(ctx, journal, builder, func() (runtime.Object, error) {
+			return i.saveBuilder(ctx, builder)
+		})
+		if err != nil {
+			return err
+		}
+
+		targets = append(targets, commands.WaitTarget{
+			Object:      savedBuilder,
+			ExtraChecks: []watchTools.ConditionFunc{commands.BuilderHasResolved(storeToGeneration[builder.Spec.Store.Name], stackToGeneration[builder.Spec.Stack.Name])},
+		})
+	}
+
+	return commands.WaitAll(ctx, i.waiter, i.printer, targets...)
 }
 
-func (i *Importer) ImportDescriptorDryRun(ctx context.Context, keychain authn.Keychain, kpConfig config.KpConfig, rawDescriptor string) ([]runtime.Object, error) {
-	descriptor, err := i.ReadDescriptor(rawDescriptor)
-	if err != nil {
+// save saves a resource unless the resumed import already saved it
+func (i *Importer) save(ctx context.Context, journal *ImportJournal, obj runtime.Object, save func() (runtime.Object, error)) (runtime.Object, error) {
+	saved, err := i.savedByJournal(ctx, journal, obj)
+	if err != nil || saved != nil {
+		return saved, err
+	}
+
+	return save()
+}
+
+func (i *Importer) ImportDescriptorDryRun(ctx context.Context, keychain authn.Keychain, kpConfig config.KpConfig, descriptor DependencyDescriptor) ([]runtime.Object, error) {
+	if err := i.resolveReferences(ctx, descriptor); err != nil {
+		return nil, err
+	}
+
+	if err := i.verifySources(keychain, descriptor); err != nil {
 		return nil, err
 	}
 
@@ -156,6 +306,46 @@ func (i *Importer) ImportDescriptorDryRun(ctx context.Context, keychain authn.Ke
 	return objects, nil
 }
 
+// resolveReferences fails the import when a builder references a stack, store
+// or buildpack that is neither in the descriptor nor in the cluster
+func (i *Importer) resolveReferences(ctx context.Context, descriptor DependencyDescriptor) error {
+	unresolved, err := unresolvedReferences(ctx, i.client, descriptor)
+	if err != nil {
+		return err
+	}
+
+	if len(unresolved) > 0 {
+		return errors.New(unresolved[0].unresolved(true))
+	}
+
+	return nil
+}
+
+// verifySources verifies the digests and signatures of the source images that
+// declare them before anything is relocated, the source images are verified
+// again when they are fetched to be relocated
+func (i *Importer) verifySources(keychain authn.Keychain, descriptor DependencyDescriptor) error {
+	count := 0
+	for _, src := range descriptor.GetSources() {
+		verification := src.Verification()
+		if verification.Digest == "" && len(verification.PublicKey) == 0 {
+			continue
+		}
+		i.imageFetcher.Expect(src.Image, verification)
+		count++
+	}
+
+	if count == 0 {
+		return nil
+	}
+
+	if err := i.printer.PrintStatus("Verifying %d source images...", count); err != nil {
+		return err
+	}
+
+	return i.imageFetcher.VerifyAll(keychain)
+}
+
 func (i *Importer) relocateDescriptor(ctx context.Context, keychain authn.Keychain, kpConfig config.KpConfig, ts string, descriptor DependencyDescriptor) (relocatedDescriptor, []runtime.Object, error) {
 	var (
 		updatedLifecycle *corev1.ConfigMap
@@ -173,7 +363,7 @@ func (i *Importer) relocateDescriptor(ctx context.Context, keychain authn.Keycha
 
 	clusterstores := make([]*v1alpha2.ClusterStore, 0)
 	for _, clusterStore := range descriptor.ClusterStores {
-		rStore, err := i.constructClusterStore(ctx, keychain, kpConfig, clusterStore)
+		rStore, err := i.constructClusterStore(ctx, keychain, repositoryConfig(kpConfig, clusterStore.Repository), clusterStore)
 		if err != nil {
 			return relocatedDescriptor{}, nil, err
 		}
@@ -183,9 +373,33 @@ func (i *Importer) relocateDescriptor(ctx context.Context, keychain authn.Keycha
 		objs = append(objs, rStore)
 	}
 
+	clusterBuildpacks := make([]*v1alpha2.ClusterBuildpack, 0)
+	for _, clusterBuildpack := range descriptor.ClusterBuildpacks {
+		rBuildpack, err := i.constructClusterBuildpack(keychain, repositoryConfig(kpConfig, clusterBuildpack.Repository), clusterBuildpack)
+		if err != nil {
+			return relocatedDescriptor{}, nil, err
+		}
+		rBuildpack.Annotations = k8s.MergeAnnotations(rBuildpack.Annotations, map[string]string{"kpack.io/import-timestamp": ts})
+
+		clusterBuildpacks = append(clusterBuildpacks, rBuildpack)
+		objs = append(objs, rBuildpack)
+	}
+
+	buildpacks := make([]*v1alpha2.Buildpack, 0)
+	for _, buildpack := range descriptor.Buildpacks {
+		rBuildpack, err := i.constructBuildpack(keychain, repositoryConfig(kpConfig, buildpack.Repository), buildpack)
+		if err != nil {
+			return relocatedDescriptor{}, nil, err
+		}
+		rBuildpack.Annotations = k8s.MergeAnnotations(rBuildpack.Annotations, map[string]string{"kpack.io/import-timestamp": ts})
+
+		buildpacks = append(buildpacks, rBuildpack)
+		objs = append(objs, rBuildpack)
+	}
+
 	clusterstacks := make([]*v1alpha2.ClusterStack, 0)
 	for _, clusterStack := range descriptor.GetClusterStacks() {
-		rStack, err := i.constructClusterStack(keychain, kpConfig, clusterStack)
+		rStack, err := i.constructClusterStack(keychain, repositoryConfig(kpConfig, clusterStack.Repository), clusterStack)
 		if err != nil {
 			return relocatedDescriptor{}, nil, err
 		}
@@ -197,7 +411,7 @@ func (i *Importer) relocateDescriptor(ctx context.Context, keychain authn.Keycha
 
 	clusterBuilders := make([]*v1alpha2.ClusterBuilder, 0)
 	for _, clusterBuilder := range descriptor.GetClusterBuilders() {
-		rBuilder, err := i.constructClusterBuilder(kpConfig, clusterBuilder)
+		rBuilder, err := i.constructClusterBuilder(repositoryConfig(kpConfig, clusterBuilder.Repository), clusterBuilder)
 		if err != nil {
 			return relocatedDescriptor{}, nil, err
 		}
@@ -207,11 +421,26 @@ func (i *Importer) relocateDescriptor(ctx context.Context, keychain authn.Keycha
 		objs = append(objs, rBuilder)
 	}
 
+	builders := make([]*v1alpha2.Builder, 0)
+	for _, builder := range descriptor.Builders {
+		rBuilder, err := i.constructBuilder(repositoryConfig(kpConfig, builder.Repository), builder)
+		if err != nil {
+			return relocatedDescriptor{}, nil, err
+		}
+		rBuilder.Annotations = k8s.MergeAnnotations(rBuilder.Annotations, map[string]string{"kpack.io/import-timestamp": ts})
+
+		builders = append(builders, rBuilder)
+		objs = append(objs, rBuilder)
+	}
+
 	return relocatedDescriptor{
-		lifecycle:       updatedLifecycle,
-		clusterStores:   clusterstores,
-		clusterStacks:   clusterstacks,
-		clusterBuilders: clusterBuilders,
+		lifecycle:         updatedLifecycle,
+		clusterStores:     clusterstores,
+		clusterBuildpacks: clusterBuildpacks,
+		buildpacks:        buildpacks,
+		clusterStacks:     clusterstacks,
+		clusterBuilders:   clusterBuilders,
+		builders:          builders,
 	}, objs, nil
 }
 
@@ -225,12 +454,12 @@ func (i *Importer) relocateLifecycle(ctx context.Context, keychain authn.Keychai
 		return nil, err
 	}
 
-	defaultRepo, err := kpConfig.DefaultRepository()
+	repository, err := kpConfig.Repository(config.LifecycleArtifact)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get default repository")
+		return nil, errors.Wrap(err, "failed to get lifecycle repository")
 	}
 
-	relocatedLifecycle, err := i.imageRelocator.Relocate(keychain, lifecycleImage, defaultRepo)
+	relocatedLifecycle, err := i.imageRelocator.Relocate(keychain, lifecycleImage, repository)
 	if err != nil {
 		return nil, err
 	}
@@ -293,6 +522,12 @@ func (i *Importer) constructClusterBuilder(kpConfig config.KpConfig, builder Clu
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get default repository")
 	}
+
+	serviceAccount := kpConfig.ServiceAccount()
+	if builder.ServiceAccountRef != nil {
+		serviceAccount = *builder.ServiceAccountRef
+	}
+
 	newCB := &v1alpha2.ClusterBuilder{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       v1alpha2.ClusterBuilderKind,
@@ -309,13 +544,10 @@ func (i *Importer) constructClusterBuilder(kpConfig config.KpConfig, builder Clu
 					Name: builder.ClusterStack,
 					Kind: v1alpha2.ClusterStackKind,
 				},
-				Store: corev1.ObjectReference{
-					Name: builder.ClusterStore,
-					Kind: v1alpha2.ClusterStoreKind,
-				},
+				Store: storeRef(builder.ClusterStore),
 				Order: builder.Order,
 			},
-			ServiceAccountRef: kpConfig.ServiceAccount(),
+			ServiceAccountRef: serviceAccount,
 		},
 	}
 
@@ -327,6 +559,115 @@ func (i *Importer) constructClusterBuilder(kpConfig config.KpConfig, builder Clu
 	return newCB, nil
 }
 
+func (i *Importer) constructClusterBuildpack(keychain authn.Keychain, kpConfig config.KpConfig, buildpack ClusterBuildpack) (*v1alpha2.ClusterBuildpack, error) {
+	if err := i.printer.PrintStatus("Importing ClusterBuildpack '%s'...", buildpack.Name); err != nil {
+		return nil, err
+	}
+
+	repository, err := kpConfig.Repository(config.BuildpackageArtifact)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get buildpackage repository")
+	}
+
+	image, err := i.buildpackUploader.UploadBuildpackage(keychain, buildpack.Image, repository)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceAccount := kpConfig.ServiceAccount()
+	if buildpack.ServiceAccountRef != nil {
+		serviceAccount = *buildpack.ServiceAccountRef
+	}
+
+	newBuildpack := &v1alpha2.ClusterBuildpack{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       v1alpha2.ClusterBuildpackKind,
+			APIVersion: "kpack.io/v1alpha2",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        buildpack.Name,
+			Annotations: map[string]string{},
+		},
+		Spec: v1alpha2.ClusterBuildpackSpec{
+			ImageSource:       corev1alpha1.ImageSource{Image: image},
+			ServiceAccountRef: &serviceAccount,
+		},
+	}
+
+	return newBuildpack, k8s.SetLastAppliedCfg(newBuildpack)
+}
+
+func (i *Importer) constructBuildpack(keychain authn.Keychain, kpConfig config.KpConfig, buildpack Buildpack) (*v1alpha2.Buildpack, error) {
+	if err := i.printer.PrintStatus("Importing Buildpack '%s' in namespace '%s'...", buildpack.Name, buildpack.Namespace); err != nil {
+		return nil, err
+	}
+
+	repository, err := kpConfig.Repository(config.BuildpackageArtifact)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get buildpackage repository")
+	}
+
+	image, err := i.buildpackUploader.UploadBuildpackage(keychain, buildpack.Image, repository)
+	if err != nil {
+		return nil, err
+	}
+
+	newBuildpack := &v1alpha2.Buildpack{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       v1alpha2.BuildpackKind,
+			APIVersion: "kpack.io/v1alpha2",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        buildpack.Name,
+			Namespace:   buildpack.Namespace,
+			Annotations: map[string]string{},
+		},
+		Spec: v1alpha2.BuildpackSpec{
+			ImageSource:        corev1alpha1.ImageSource{Image: image},
+			ServiceAccountName: buildpack.ServiceAccountName,
+		},
+	}
+
+	return newBuildpack, k8s.SetLastAppliedCfg(newBuildpack)
+}
+
+func (i *Importer) constructBuilder(kpConfig config.KpConfig, builder Builder) (*v1alpha2.Builder, error) {
+	if err := i.printer.PrintStatus("Importing Builder '%s' in namespace '%s'...", builder.Name, builder.Namespace); err != nil {
+		return nil, err
+	}
+
+	defaultRepo, err := kpConfig.DefaultRepository()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get default repository")
+	}
+
+	newBuilder := &v1alpha2.Builder{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       v1alpha2.BuilderKind,
+			APIVersion: "kpack.io/v1alpha2",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        builder.Name,
+			Namespace:   builder.Namespace,
+			Annotations: map[string]string{},
+		},
+		Spec: v1alpha2.NamespacedBuilderSpec{
+			BuilderSpec: v1alpha2.BuilderSpec{
+				Tag: fmt.Sprintf("%s:builder-%s-%s", defaultRepo, builder.Namespace, builder.Name),
+				Stack: corev1.ObjectReference{
+					Name: builder.ClusterStack,
+					Kind: v1alpha2.ClusterStackKind,
+				},
+				Store: storeRef(builder.ClusterStore),
+				Order: builder.Order,
+			},
+			ServiceAccountName: builder.ServiceAccountName,
+		},
+	}
+
+	return newBuilder, k8s.SetLastAppliedCfg(newBuilder)
+}
+
 func (i *Importer) patchLifecycleConfigMap(ctx context.Context, updatedLifecycle *corev1.ConfigMap) error {
 	existingLifecycle, err := i.k8sClient.CoreV1().ConfigMaps("kpack").Get(ctx, updatedLifecycle.Name, metav1.GetOptions{})
 	if err != nil {
@@ -343,17 +684,17 @@ func (i *Importer) patchLifecycleConfigMap(ctx context.Context, updatedLifecycle
 	return err
 }
 
-func (i *Importer) saveClusterStore(ctx context.Context, relocatedStore *v1alpha2.ClusterStore) (int64, error) {
+func (i *Importer) saveClusterStore(ctx context.Context, relocatedStore *v1alpha2.ClusterStore) (*v1alpha2.ClusterStore, error) {
 	existingStore, err := i.client.KpackV1alpha2().ClusterStores().Get(ctx, relocatedStore.Name, metav1.GetOptions{})
 	if err != nil && !k8serrors.IsNotFound(err) {
-		return 0, err
+		return nil, err
 	}
 
 	var store *v1alpha2.ClusterStore
 	if k8serrors.IsNotFound(err) {
 		store, err = i.client.KpackV1alpha2().ClusterStores().Create(ctx, relocatedStore, metav1.CreateOptions{})
 		if err != nil {
-			return 0, err
+			return nil, err
 		}
 	} else {
 		updateStore := existingStore.DeepCopy()
@@ -361,32 +702,28 @@ func (i *Importer) saveClusterStore(ctx context.Context, relocatedStore *v1alpha
 		updateStore.Annotations = k8s.MergeAnnotations(updateStore.Annotations, relocatedStore.Annotations)
 		patch, err := k8s.CreatePatch(existingStore, updateStore)
 		if err != nil {
-			return 0, err
+			return nil, err
 		}
 		store, err = i.client.KpackV1alpha2().ClusterStores().Patch(ctx, updateStore.Name, types.MergePatchType, patch, metav1.PatchOptions{})
 		if err != nil {
-			return 0, err
+			return nil, err
 		}
 	}
 
-	if err := i.waiter.Wait(ctx, store); err != nil {
-		return 0, err
-	}
-
-	return store.Generation, nil
+	return store, nil
 }
 
-func (i *Importer) saveClusterStack(ctx context.Context, relocatedStack *v1alpha2.ClusterStack) (int64, error) {
+func (i *Importer) saveClusterStack(ctx context.Context, relocatedStack *v1alpha2.ClusterStack) (*v1alpha2.ClusterStack, error) {
 	exstingStack, err := i.client.KpackV1alpha2().ClusterStacks().Get(ctx, relocatedStack.Name, metav1.GetOptions{})
 	if err != nil && !k8serrors.IsNotFound(err) {
-		return 0, err
+		return nil, err
 	}
 
 	var stack *v1alpha2.ClusterStack
 	if k8serrors.IsNotFound(err) {
 		stack, err = i.client.KpackV1alpha2().ClusterStacks().Create(ctx, relocatedStack, metav1.CreateOptions{})
 		if err != nil {
-			return 0, err
+			return nil, err
 		}
 	} else {
 		updateStack := exstingStack.DeepCopy()
@@ -394,31 +731,27 @@ func (i *Importer) saveClusterStack(ctx context.Context, relocatedStack *v1alpha
 		updateStack.Annotations = k8s.MergeAnnotations(updateStack.Annotations, relocatedStack.Annotations)
 		patch, err := k8s.CreatePatch(exstingStack, updateStack)
 		if err != nil {
-			return 0, err
+			return nil, err
 		}
 		stack, err = i.client.KpackV1alpha2().ClusterStacks().Patch(ctx, updateStack.Name, types.MergePatchType, patch, metav1.PatchOptions{})
 		if err != nil {
-			return 0, err
+			return nil, err
 		}
 	}
-	if err := i.waiter.Wait(ctx, stack); err != nil {
-		return 0, err
-	}
-
-	return stack.Generation, nil
+	return stack, nil
 }
 
-func (i *Importer) saveClusterBuilder(ctx context.Context, storeToGeneration, stackToGeneration map[string]int64, relocatedBuilder *v1alpha2.ClusterBuilder) error {
+func (i *Importer) saveClusterBuilder(ctx context.Context, relocatedBuilder *v1alpha2.ClusterBuilder) (*v1alpha2.ClusterBuilder, error) {
 	existingBuilder, err := i.client.KpackV1alpha2().ClusterBuilders().Get(ctx, relocatedBuilder.Name, metav1.GetOptions{})
 	if err != nil && !k8serrors.IsNotFound(err) {
-		return err
+		return nil, err
 	}
 
 	var builder *v1alpha2.ClusterBuilder
 	if k8serrors.IsNotFound(err) {
 		builder, err = i.client.KpackV1alpha2().ClusterBuilders().Create(ctx, relocatedBuilder, metav1.CreateOptions{})
 		if err != nil {
-			return err
+			return nil, err
 		}
 	} else {
 		updateBuilder := existingBuilder.DeepCopy()
@@ -426,15 +759,97 @@ func (i *Importer) saveClusterBuilder(ctx context.Context, storeToGeneration, st
 		updateBuilder.Annotations = k8s.MergeAnnotations(updateBuilder.Annotations, relocatedBuilder.Annotations)
 		patch, err := k8s.CreatePatch(existingBuilder, updateBuilder)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		builder, err = i.client.KpackV1alpha2().ClusterBuilders().Patch(ctx, updateBuilder.Name, types.MergePatchType, patch, metav1.PatchOptions{})
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	return i.waiter.Wait(ctx, builder, builderHasResolved(storeToGeneration[relocatedBuilder.Spec.Store.Name], stackToGeneration[relocatedBuilder.Spec.Stack.Name]))
+	return builder, nil
+}
+
+func (i *Importer) saveClusterBuildpack(ctx context.Context, relocatedBuildpack *v1alpha2.ClusterBuildpack) (*v1alpha2.ClusterBuildpack, error) {
+	existingBuildpack, err := i.client.KpackV1alpha2().ClusterBuildpacks().Get(ctx, relocatedBuildpack.Name, metav1.GetOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	if k8serrors.IsNotFound(err) {
+		return i.client.KpackV1alpha2().ClusterBuildpacks().Create(ctx, relocatedBuildpack, metav1.CreateOptions{})
+	}
+
+	updateBuildpack := existingBuildpack.DeepCopy()
+	updateBuildpack.Spec = relocatedBuildpack.Spec
+	updateBuildpack.Annotations = k8s.MergeAnnotations(updateBuildpack.Annotations, relocatedBuildpack.Annotations)
+	patch, err := k8s.CreatePatch(existingBuildpack, updateBuildpack)
+	if err != nil {
+		return nil, err
+	}
+	return i.client.KpackV1alpha2().ClusterBuildpacks().Patch(ctx, updateBuildpack.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+}
+
+func (i *Importer) saveBuildpack(ctx context.Context, relocatedBuildpack *v1alpha2.Buildpack) (*v1alpha2.Buildpack, error) {
+	existingBuildpack, err := i.client.KpackV1alpha2().Buildpacks(relocatedBuildpack.Namespace).Get(ctx, relocatedBuildpack.Name, metav1.GetOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	if k8serrors.IsNotFound(err) {
+		return i.client.KpackV1alpha2().Buildpacks(relocatedBuildpack.Namespace).Create(ctx, relocatedBuildpack, metav1.CreateOptions{})
+	}
+
+	updateBuildpack := existingBuildpack.DeepCopy()
+	updateBuildpack.Spec = relocatedBuildpack.Spec
+	updateBuildpack.Annotations = k8s.MergeAnnotations(updateBuildpack.Annotations, relocatedBuildpack.Annotations)
+	patch, err := k8s.CreatePatch(existingBuildpack, updateBuildpack)
+	if err != nil {
+		return nil, err
+	}
+	return i.client.KpackV1alpha2().Buildpacks(updateBuildpack.Namespace).Patch(ctx, updateBuildpack.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+}
+
+func (i *Importer) saveBuilder(ctx context.Context, relocatedBuilder *v1alpha2.Builder) (*v1alpha2.Builder, error) {
+	existingBuilder, err := i.client.KpackV1alpha2().Builders(relocatedBuilder.Namespace).Get(ctx, relocatedBuilder.Name, metav1.GetOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	if k8serrors.IsNotFound(err) {
+		return i.client.KpackV1alpha2().Builders(relocatedBuilder.Namespace).Create(ctx, relocatedBuilder, metav1.CreateOptions{})
+	}
+
+	updateBuilder := existingBuilder.DeepCopy()
+	updateBuilder.Spec = relocatedBuilder.Spec
+	updateBuilder.Annotations = k8s.MergeAnnotations(updateBuilder.Annotations, relocatedBuilder.Annotations)
+	patch, err := k8s.CreatePatch(existingBuilder, updateBuilder)
+	if err != nil {
+		return nil, err
+	}
+	return i.client.KpackV1alpha2().Builders(updateBuilder.Namespace).Patch(ctx, updateBuilder.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+}
+
+// repositoryConfig returns the kp-config with the repository of a resource as
+// its default repository and without the repositories of the artifacts, the
+// kp-config is unchanged when it is not set
+func repositoryConfig(kpConfig config.KpConfig, repository string) config.KpConfig {
+	if repository == "" {
+		return kpConfig
+	}
+	return config.NewKpConfig(repository, kpConfig.ServiceAccount())
+}
+
+// storeRef returns the reference to the cluster store of a builder, builders
+// that only use cluster buildpacks or buildpacks have no store
+func storeRef(store string) corev1.ObjectReference {
+	if store == "" {
+		return corev1.ObjectReference{}
+	}
+	return corev1.ObjectReference{
+		Name: store,
+		Kind: v1alpha2.ClusterStoreKind,
+	}
 }
 
 func buildpackagesForSource(sources []Source) []string {
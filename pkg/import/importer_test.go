@@ -3,6 +3,7 @@ package _import
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -264,6 +265,127 @@ clusterBuilders:
 				ExpectPatches: []string{
 					`{"data":{"image":"gcr.io/my-cool-repo@sha256:lifecycledigest"},"metadata":{"annotations":{"kpack.io/import-timestamp":"0001-01-01 00:00:00 +0000 UTC"}}}`,
 				},
+				ExpectJournal: []JournalEntry{
+					expectedJournalEntry(t, "ConfigMap", "kpack", "lifecycle-image", existingLifecycle),
+					expectedJournalEntry(t, "ClusterStore", "", "default", nil),
+					expectedJournalEntry(t, "ClusterStack", "", "base", nil),
+					expectedJournalEntry(t, "ClusterStack", "", "default", nil),
+					expectedJournalEntry(t, "ClusterBuilder", "", "base", nil),
+					expectedJournalEntry(t, "ClusterBuilder", "", "default", nil),
+				},
+			}.TestImporter(t)
+		})
+
+		it("uploads to the repositories of the artifacts in the kp-config", func() {
+			TestImport{
+				Images: map[string]v1.Image{
+					"new-image.com/lifecycle":              fakes.NewFakeImage(lifecycleDigest),
+					"new-image.com/buildpacks/dotnet-core": fakes.NewFakeLabeledImage("io.buildpacks.buildpackage.metadata", fmt.Sprintf("{\"id\":%q}", dotnetCoreId), dotnetCoreDigest),
+					"new-image.com/stacks/base/run":        fakes.NewFakeLabeledImage("io.buildpacks.stack.id", stackId, runImageDigest),
+					"new-image.com/stacks/base/build":      fakes.NewFakeLabeledImage("io.buildpacks.stack.id", stackId, buildImageDigest),
+				},
+				Objects: []runtime.Object{
+					existingLifecycle,
+				},
+				KpConfig: kpConfig.
+					WithRepository(config.LifecycleArtifact, "gcr.io/my-lifecycle-repo").
+					WithRepository(config.BuildpackageArtifact, "gcr.io/my-buildpackage-repo").
+					WithRepository(config.StackArtifact, "gcr.io/my-stack-repo"),
+				DependencyDescriptor: `
+apiVersion: kp.kpack.io/v1alpha4
+kind: DependencyDescriptor
+lifecycle:
+  image: new-image.com/lifecycle
+clusterStores:
+- name: default
+  sources:
+  - image: new-image.com/buildpacks/dotnet-core
+clusterStacks:
+- name: base
+  buildImage:
+    image: new-image.com/stacks/base/build
+  runImage:
+    image: new-image.com/stacks/base/run
+- name: other
+  repository: gcr.io/my-other-stack-repo
+  buildImage:
+    image: new-image.com/stacks/base/build
+  runImage:
+    image: new-image.com/stacks/base/run
+`,
+				ExpectCreates: []runtime.Object{
+					annotate(t, &v1alpha2.ClusterStore{
+						TypeMeta: metav1.TypeMeta{
+							Kind:       "ClusterStore",
+							APIVersion: "kpack.io/v1alpha2",
+						},
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "default",
+						},
+						Spec: v1alpha2.ClusterStoreSpec{
+							Sources: []corev1alpha1.ImageSource{
+								{Image: fmt.Sprintf("gcr.io/my-buildpackage-repo@sha256:%s", dotnetCoreDigest)},
+							},
+							ServiceAccountRef: &corev1.ObjectReference{
+								Namespace: "some-namespace",
+								Name:      "some-serviceaccount",
+							},
+						},
+					}, kubectlAnnotation, timestampAnnotation),
+					annotate(t, &v1alpha2.ClusterStack{
+						TypeMeta: metav1.TypeMeta{
+							Kind:       "ClusterStack",
+							APIVersion: "kpack.io/v1alpha2",
+						},
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "base",
+						},
+						Spec: v1alpha2.ClusterStackSpec{
+							Id: stackId,
+							BuildImage: v1alpha2.ClusterStackSpecImage{
+								Image: fmt.Sprintf("gcr.io/my-stack-repo@sha256:%s", buildImageDigest),
+							},
+							RunImage: v1alpha2.ClusterStackSpecImage{
+								Image: fmt.Sprintf("gcr.io/my-stack-repo@sha256:%s", runImageDigest),
+							},
+							ServiceAccountRef: &corev1.ObjectReference{
+								Namespace: "some-namespace",
+								Name:      "some-serviceaccount",
+							},
+						},
+					}, timestampAnnotation),
+					annotate(t, &v1alpha2.ClusterStack{
+						TypeMeta: metav1.TypeMeta{
+							Kind:       "ClusterStack",
+							APIVersion: "kpack.io/v1alpha2",
+						},
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "other",
+						},
+						Spec: v1alpha2.ClusterStackSpec{
+							Id: stackId,
+							BuildImage: v1alpha2.ClusterStackSpecImage{
+								Image: fmt.Sprintf("gcr.io/my-other-stack-repo@sha256:%s", buildImageDigest),
+							},
+							RunImage: v1alpha2.ClusterStackSpecImage{
+								Image: fmt.Sprintf("gcr.io/my-other-stack-repo@sha256:%s", runImageDigest),
+							},
+							ServiceAccountRef: &corev1.ObjectReference{
+								Namespace: "some-namespace",
+								Name:      "some-serviceaccount",
+							},
+						},
+					}, timestampAnnotation),
+				},
+				ExpectPatches: []string{
+					`{"data":{"image":"gcr.io/my-lifecycle-repo@sha256:lifecycledigest"},"metadata":{"annotations":{"kpack.io/import-timestamp":"0001-01-01 00:00:00 +0000 UTC"}}}`,
+				},
+				ExpectJournal: []JournalEntry{
+					expectedJournalEntry(t, "ConfigMap", "kpack", "lifecycle-image", existingLifecycle),
+					expectedJournalEntry(t, "ClusterStore", "", "default", nil),
+					expectedJournalEntry(t, "ClusterStack", "", "base", nil),
+					expectedJournalEntry(t, "ClusterStack", "", "other", nil),
+				},
 			}.TestImporter(t)
 		})
 
@@ -314,6 +436,169 @@ clusterBuilders:
 					expectedClusterBuilder,
 					expectedDefaultClusterBuilder,
 				},
+				ExpectJournal: []JournalEntry{
+					expectedJournalEntry(t, "ClusterStore", "", "default", nil),
+					expectedJournalEntry(t, "ClusterStack", "", "base", nil),
+					expectedJournalEntry(t, "ClusterStack", "", "default", nil),
+					expectedJournalEntry(t, "ClusterBuilder", "", "base", nil),
+					expectedJournalEntry(t, "ClusterBuilder", "", "default", nil),
+				},
+			}.TestImporter(t)
+		})
+
+		it("can import v1alpha4 buildpacks and builders on a new cluster", func() {
+			dotnetCoreRef := func(kind string) []v1alpha2.BuilderOrderEntry {
+				return []v1alpha2.BuilderOrderEntry{
+					{
+						Group: []v1alpha2.BuilderBuildpackRef{
+							{
+								ObjectReference: corev1.ObjectReference{
+									Kind: kind,
+									Name: "dotnet-core",
+								},
+							},
+						},
+					},
+				}
+			}
+
+			TestImport{
+				Images: map[string]v1.Image{
+					"new-image.com/buildpacks/dotnet-core": fakes.NewFakeLabeledImage("io.buildpacks.buildpackage.metadata", fmt.Sprintf("{\"id\":%q}", dotnetCoreId), dotnetCoreDigest),
+					"new-image.com/stacks/base/run":        fakes.NewFakeLabeledImage("io.buildpacks.stack.id", stackId, runImageDigest),
+					"new-image.com/stacks/base/build":      fakes.NewFakeLabeledImage("io.buildpacks.stack.id", stackId, buildImageDigest),
+				},
+				Objects: []runtime.Object{
+					existingLifecycle,
+				},
+				KpConfig: kpConfig,
+				DependencyDescriptor: `
+apiVersion: kp.kpack.io/v1alpha4
+kind: DependencyDescriptor
+clusterBuildpacks:
+- name: dotnet-core
+  image: new-image.com/buildpacks/dotnet-core
+  repository: gcr.io/my-buildpacks-repo
+buildpacks:
+- name: dotnet-core
+  namespace: some-namespace
+  image: new-image.com/buildpacks/dotnet-core
+  serviceAccountName: some-buildpack-serviceaccount
+clusterStacks:
+- name: base
+  buildImage:
+    image: new-image.com/stacks/base/build
+  runImage:
+    image: new-image.com/stacks/base/run
+clusterBuilders:
+- name: base
+  clusterStack: base
+  serviceAccountRef:
+    namespace: builder-namespace
+    name: builder-serviceaccount
+  order:
+  - group:
+    - kind: ClusterBuildpack
+      name: dotnet-core
+builders:
+- name: some-builder
+  namespace: some-namespace
+  clusterStack: base
+  serviceAccountName: some-builder-serviceaccount
+  repository: gcr.io/my-builders-repo
+  order:
+  - group:
+    - kind: Buildpack
+      name: dotnet-core
+`,
+				ExpectCreates: []runtime.Object{
+					annotate(t, &v1alpha2.ClusterBuildpack{
+						TypeMeta: metav1.TypeMeta{
+							Kind:       "ClusterBuildpack",
+							APIVersion: "kpack.io/v1alpha2",
+						},
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "dotnet-core",
+						},
+						Spec: v1alpha2.ClusterBuildpackSpec{
+							ImageSource: corev1alpha1.ImageSource{
+								Image: fmt.Sprintf("gcr.io/my-buildpacks-repo@sha256:%s", dotnetCoreDigest),
+							},
+							ServiceAccountRef: &corev1.ObjectReference{
+								Namespace: "some-namespace",
+								Name:      "some-serviceaccount",
+							},
+						},
+					}, kubectlAnnotation, timestampAnnotation),
+					annotate(t, &v1alpha2.Buildpack{
+						TypeMeta: metav1.TypeMeta{
+							Kind:       "Buildpack",
+							APIVersion: "kpack.io/v1alpha2",
+						},
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "dotnet-core",
+							Namespace: "some-namespace",
+						},
+						Spec: v1alpha2.BuildpackSpec{
+							ImageSource: corev1alpha1.ImageSource{
+								Image: fmt.Sprintf("gcr.io/my-cool-repo@sha256:%s", dotnetCoreDigest),
+							},
+							ServiceAccountName: "some-buildpack-serviceaccount",
+						},
+					}, kubectlAnnotation, timestampAnnotation),
+					expectedClusterStack,
+					annotate(t, &v1alpha2.ClusterBuilder{
+						TypeMeta: metav1.TypeMeta{
+							Kind:       "ClusterBuilder",
+							APIVersion: "kpack.io/v1alpha2",
+						},
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "base",
+						},
+						Spec: v1alpha2.ClusterBuilderSpec{
+							BuilderSpec: v1alpha2.BuilderSpec{
+								Tag: "gcr.io/my-cool-repo:clusterbuilder-base",
+								Stack: corev1.ObjectReference{
+									Kind: "ClusterStack",
+									Name: "base",
+								},
+								Order: dotnetCoreRef("ClusterBuildpack"),
+							},
+							ServiceAccountRef: corev1.ObjectReference{
+								Namespace: "builder-namespace",
+								Name:      "builder-serviceaccount",
+							},
+						},
+					}, kubectlAnnotation, timestampAnnotation),
+					annotate(t, &v1alpha2.Builder{
+						TypeMeta: metav1.TypeMeta{
+							Kind:       "Builder",
+							APIVersion: "kpack.io/v1alpha2",
+						},
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "some-builder",
+							Namespace: "some-namespace",
+						},
+						Spec: v1alpha2.NamespacedBuilderSpec{
+							BuilderSpec: v1alpha2.BuilderSpec{
+								Tag: "gcr.io/my-builders-repo:builder-some-namespace-some-builder",
+								Stack: corev1.ObjectReference{
+									Kind: "ClusterStack",
+									Name: "base",
+								},
+								Order: dotnetCoreRef("Buildpack"),
+							},
+							ServiceAccountName: "some-builder-serviceaccount",
+						},
+					}, kubectlAnnotation, timestampAnnotation),
+				},
+				ExpectJournal: []JournalEntry{
+					expectedJournalEntry(t, "ClusterBuildpack", "", "dotnet-core", nil),
+					expectedJournalEntry(t, "Buildpack", "some-namespace", "dotnet-core", nil),
+					expectedJournalEntry(t, "ClusterStack", "", "base", nil),
+					expectedJournalEntry(t, "ClusterBuilder", "", "base", nil),
+					expectedJournalEntry(t, "Builder", "some-namespace", "some-builder", nil),
+				},
 			}.TestImporter(t)
 		})
 
@@ -557,6 +842,14 @@ clusterBuilders:
 						`{"metadata":{"annotations":{"kubectl.kubernetes.io/last-applied-configuration":"{\"kind\":\"ClusterBuilder\",\"apiVersion\":\"kpack.io/v1alpha2\",\"metadata\":{\"name\":\"default\",\"creationTimestamp\":null},\"spec\":{\"tag\":\"gcr.io/my-cool-repo:clusterbuilder-default\",\"stack\":{\"kind\":\"ClusterStack\",\"name\":\"base\"},\"store\":{\"kind\":\"ClusterStore\",\"name\":\"default\"},\"order\":[{\"group\":[{\"id\":\"tanzu-buildpacks/dotnet-core\"}]},{\"group\":[{\"id\":\"tanzu-buildpacks/nodejs\"}]}],\"serviceAccountRef\":{\"namespace\":\"some-namespace\",\"name\":\"some-serviceaccount\"}},\"status\":{\"stack\":{}}}"}},"spec":{"order":[{"group":[{"id":"tanzu-buildpacks/dotnet-core"}]},{"group":[{"id":"tanzu-buildpacks/nodejs"}]}],"tag":"gcr.io/my-cool-repo:clusterbuilder-default"}}`,
 						`{"data":{"image":"gcr.io/my-cool-repo@sha256:newlifecycledigest"},"metadata":{"annotations":{"kpack.io/import-timestamp":"0001-01-01 00:00:00 +0000 UTC"}}}`,
 					},
+					ExpectJournal: []JournalEntry{
+						expectedJournalEntry(t, "ConfigMap", "kpack", "lifecycle-image", existingLifecycle),
+						expectedJournalEntry(t, "ClusterStore", "", "default", existingClusterStore),
+						expectedJournalEntry(t, "ClusterStack", "", "base", existingClusterStack),
+						expectedJournalEntry(t, "ClusterStack", "", "default", existingDefaultClusterStack),
+						expectedJournalEntry(t, "ClusterBuilder", "", "base", existingClusterBuilder),
+						expectedJournalEntry(t, "ClusterBuilder", "", "default", existingDefaultClusterBuilder),
+					},
 				}.TestImporter(t)
 			})
 
@@ -617,6 +910,13 @@ clusterBuilders:
 						`{"metadata":{"annotations":{"kubectl.kubernetes.io/last-applied-configuration":"{\"kind\":\"ClusterBuilder\",\"apiVersion\":\"kpack.io/v1alpha2\",\"metadata\":{\"name\":\"default\",\"creationTimestamp\":null},\"spec\":{\"tag\":\"gcr.io/my-cool-repo:clusterbuilder-default\",\"stack\":{\"kind\":\"ClusterStack\",\"name\":\"base\"},\"store\":{\"kind\":\"ClusterStore\",\"name\":\"default\"},\"order\":[{\"group\":[{\"id\":\"tanzu-buildpacks/dotnet-core\"}]},{\"group\":[{\"id\":\"tanzu-buildpacks/nodejs\"}]}],\"serviceAccountRef\":{\"namespace\":\"some-namespace\",\"name\":\"some-serviceaccount\"}},\"status\":{\"stack\":{}}}"}},"spec":{"order":[{"group":[{"id":"tanzu-buildpacks/dotnet-core"}]},{"group":[{"id":"tanzu-buildpacks/nodejs"}]}],"tag":"gcr.io/my-cool-repo:clusterbuilder-default"}}`,
 						`{"spec":{"sources":[{"image":"gcr.io/my-cool-repo@sha256:dotnetcoredigest"},{"image":"gcr.io/my-cool-repo@sha256:newdotnetcoredigest"},{"image":"gcr.io/my-cool-repo@sha256:nodejsdigest"}]}}`,
 					},
+					ExpectJournal: []JournalEntry{
+						expectedJournalEntry(t, "ClusterStore", "", "default", existingClusterStore),
+						expectedJournalEntry(t, "ClusterStack", "", "base", existingClusterStack),
+						expectedJournalEntry(t, "ClusterStack", "", "default", existingDefaultClusterStack),
+						expectedJournalEntry(t, "ClusterBuilder", "", "base", existingClusterBuilder),
+						expectedJournalEntry(t, "ClusterBuilder", "", "default", existingDefaultClusterBuilder),
+					},
 				}.TestImporter(t)
 			})
 		})
@@ -661,6 +961,80 @@ clusterBuilders:
 				ExpectErr: errors.New("buddy we don't have your image, check another registry"),
 			}.TestImporter(t)
 		})
+
+		it("does not relocate or create any resources if a source image fails verification", func() {
+			const (
+				buildDigest = "1111111111111111111111111111111111111111111111111111111111111111"
+				runDigest   = "2222222222222222222222222222222222222222222222222222222222222222"
+				otherDigest = "3333333333333333333333333333333333333333333333333333333333333333"
+			)
+
+			TestImport{
+				Images: map[string]v1.Image{
+					"new-image.com/lifecycle":              fakes.NewFakeImage(lifecycleDigest),
+					"new-image.com/buildpacks/dotnet-core": fakes.NewFakeLabeledImage("io.buildpacks.buildpackage.metadata", fmt.Sprintf("{\"id\":%q}", dotnetCoreId), dotnetCoreDigest),
+					"new-image.com/stacks/base/run":        fakes.NewFakeLabeledImage("io.buildpacks.stack.id", stackId, runDigest),
+					"new-image.com/stacks/base/build":      fakes.NewFakeLabeledImage("io.buildpacks.stack.id", stackId, buildDigest),
+				},
+				Objects: []runtime.Object{
+					existingLifecycle,
+				},
+				KpConfig: kpConfig,
+				DependencyDescriptor: `
+apiVersion: kp.kpack.io/v1alpha3
+kind: DependencyDescriptor
+lifecycle:
+  image: new-image.com/lifecycle
+clusterStacks:
+- name: base
+  buildImage:
+    image: new-image.com/stacks/base/build
+    digest: sha256:` + buildDigest + `
+  runImage:
+    image: new-image.com/stacks/base/run
+    digest: sha256:` + otherDigest + `
+`,
+				ExpectErr: errors.New(`verification failed for 1 source image(s), no images were relocated:
+	new-image.com/stacks/base/run: digest sha256:` + runDigest + ` does not match the expected digest sha256:` + otherDigest),
+			}.TestImporter(t)
+		})
+
+		it("rejects a descriptor with an invalid digest", func() {
+			TestImport{
+				Objects: []runtime.Object{
+					existingLifecycle,
+				},
+				KpConfig: kpConfig,
+				DependencyDescriptor: `
+apiVersion: kp.kpack.io/v1alpha3
+kind: DependencyDescriptor
+lifecycle:
+  image: new-image.com/lifecycle
+  digest: not-a-digest
+`,
+				ExpectErr: errors.New("invalid digest for 'new-image.com/lifecycle': cannot parse hash: \"not-a-digest\""),
+			}.TestImporter(t)
+		})
+
+		it("does not relocate or create any resources if a builder references a stack that does not exist", func() {
+			TestImport{
+				Objects: []runtime.Object{
+					existingLifecycle,
+				},
+				KpConfig: kpConfig,
+				DependencyDescriptor: `
+apiVersion: kp.kpack.io/v1alpha4
+kind: DependencyDescriptor
+clusterBuilders:
+- name: base
+  clusterStack: base
+  order:
+  - group:
+    - id: tanzu-buildpacks/dotnet-core
+`,
+				ExpectErr: errors.New("cluster builder 'base' references cluster stack 'base' which is not defined in the descriptor or the cluster"),
+			}.TestImporter(t)
+		})
 	})
 
 	when("importing with the dry run", func() {
@@ -740,6 +1114,7 @@ type TestImport struct {
 	ExpectPatches        []string
 	Images               map[string]v1.Image
 	ExpectCreates        []runtime.Object
+	ExpectJournal        []JournalEntry
 	ExpectErr            error
 }
 
@@ -751,12 +1126,12 @@ func (i TestImport) TestImporter(t *testing.T) {
 	k8sClient := k8sfakes.NewSimpleClientset(listers.GetKubeObjects()...)
 
 	buffer := &bytes.Buffer{}
-	var err error
 	importer := NewImporter(testLogger{writer: buffer}, k8sClient, client, &fakeFetcher{Images: i.Images}, &fakeRelocator{}, &fakeWaiter{}, &fakeTimestampProvider{ts: time.Time{}.String()})
-	if i.DryRun {
-		_, err = importer.ImportDescriptorDryRun(context.Background(), authn.NewMultiKeychain(), i.KpConfig, i.DependencyDescriptor)
-	} else {
-		_, err = importer.ImportDescriptor(context.Background(), authn.NewMultiKeychain(), i.KpConfig, i.DependencyDescriptor)
+	descriptor, err := importer.ReadDescriptor(i.DependencyDescriptor)
+	if err == nil && i.DryRun {
+		_, err = importer.ImportDescriptorDryRun(context.Background(), authn.NewMultiKeychain(), i.KpConfig, descriptor)
+	} else if err == nil {
+		_, err = importer.ImportDescriptor(context.Background(), authn.NewMultiKeychain(), i.KpConfig, descriptor)
 	}
 
 	if i.ExpectErr != nil {
@@ -765,17 +1140,54 @@ func (i TestImport) TestImporter(t *testing.T) {
 		require.NoError(t, err)
 	}
 
+	expectCreates, expectUpdates := i.ExpectCreates, i.ExpectUpdates
+	if i.ExpectJournal != nil {
+		digest, err := descriptor.Digest()
+		require.NoError(t, err)
+
+		expectCreates = append([]runtime.Object{expectedJournal(t, JournalInProgress, digest, i.ExpectJournal)}, expectCreates...)
+		expectUpdates = append(expectUpdates, clientgotesting.UpdateActionImpl{Object: expectedJournal(t, JournalCompleted, digest, i.ExpectJournal)})
+	}
+
 	testhelpers.TestK8sAndKpackActions(
 		t,
 		k8sClient,
 		client,
-		i.ExpectUpdates,
-		i.ExpectCreates,
+		expectUpdates,
+		expectCreates,
 		nil,
 		i.ExpectPatches,
 	)
 }
 
+func expectedJournal(t *testing.T, status, digest string, entries []JournalEntry) *corev1.ConfigMap {
+	resources, err := json.Marshal(entries)
+	require.NoError(t, err)
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      JournalName,
+			Namespace: JournalNamespace,
+		},
+		Data: map[string]string{
+			"status":    status,
+			"timestamp": time.Time{}.String(),
+			"digest":    digest,
+			"resources": string(resources),
+		},
+	}
+}
+
+func expectedJournalEntry(t *testing.T, kind, namespace, name string, previous runtime.Object) JournalEntry {
+	entry := JournalEntry{Kind: kind, Name: name, Namespace: namespace}
+	if previous != nil {
+		var err error
+		entry.Previous, err = snapshot(previous)
+		require.NoError(t, err)
+	}
+	return entry
+}
+
 type testLogger struct {
 	writer io.Writer
 }
@@ -0,0 +1,450 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package _import
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	JournalName      = "kp-import-journal"
+	JournalNamespace = "kpack"
+
+	JournalInProgress = "in-progress"
+	JournalCompleted  = "completed"
+	JournalRolledBack = "rolled-back"
+
+	journalStatusKey    = "status"
+	journalTimestampKey = "timestamp"
+	journalDigestKey    = "digest"
+	journalResourcesKey = "resources"
+)
+
+// ImportJournal records the last import in the kp-import-journal config map
+// so that it can be resumed when it fails and rolled back. The resources the
+// import saved carry its timestamp in their import timestamp annotation and
+// the digest identifies the dependency descriptor or plan that was imported.
+type ImportJournal struct {
+	Status    string
+	Timestamp string
+	Digest    string
+	Resources []JournalEntry
+
+	resumed bool
+}
+
+// JournalEntry is a resource of an import. The previous resource holds the
+// annotations and spec, or data of the lifecycle config map, that the resource
+// had before the import and is empty when the import created the resource.
+type JournalEntry struct {
+	Kind      string          `json:"kind"`
+	Name      string          `json:"name"`
+	Namespace string          `json:"namespace,omitempty"`
+	Pruned    bool            `json:"pruned,omitempty"`
+	Previous  json.RawMessage `json:"previous,omitempty"`
+}
+
+func (e JournalEntry) String() string {
+	return resourceName(e.Kind, e.Namespace, e.Name)
+}
+
+func (i *Importer) readJournal(ctx context.Context) (*ImportJournal, error) {
+	cm, err := i.k8sClient.CoreV1().ConfigMaps(JournalNamespace).Get(ctx, JournalName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	journal := &ImportJournal{
+		Status:    cm.Data[journalStatusKey],
+		Timestamp: cm.Data[journalTimestampKey],
+		Digest:    cm.Data[journalDigestKey],
+	}
+
+	if err := json.Unmarshal([]byte(cm.Data[journalResourcesKey]), &journal.Resources); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s config map", JournalName)
+	}
+	return journal, nil
+}
+
+func (i *Importer) writeJournal(ctx context.Context, journal *ImportJournal) error {
+	resources, err := json.Marshal(journal.Resources)
+	if err != nil {
+		return err
+	}
+
+	data := map[string]string{
+		journalStatusKey:    journal.Status,
+		journalTimestampKey: journal.Timestamp,
+		journalDigestKey:    journal.Digest,
+		journalResourcesKey: string(resources),
+	}
+
+	cm, err := i.k8sClient.CoreV1().ConfigMaps(JournalNamespace).Get(ctx, JournalName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		_, err = i.k8sClient.CoreV1().ConfigMaps(JournalNamespace).Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      JournalName,
+				Namespace: JournalNamespace,
+			},
+			Data: data,
+		}, metav1.CreateOptions{})
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	cm = cm.DeepCopy()
+	cm.Data = data
+	_, err = i.k8sClient.CoreV1().ConfigMaps(JournalNamespace).Update(ctx, cm, metav1.UpdateOptions{})
+	return err
+}
+
+// checkLastImport fails a new import while the last import has not completed
+// so that the previous resources it recorded are not lost
+func (i *Importer) checkLastImport(ctx context.Context) error {
+	journal, err := i.readJournal(ctx)
+	if err != nil {
+		return err
+	}
+
+	if journal != nil && journal.Status == JournalInProgress {
+		return errors.Errorf("the last import from %s did not complete, resume it with --resume or restore the previous resources with \"kp import rollback\"", journal.Timestamp)
+	}
+	return nil
+}
+
+// resumeJournal returns the journal of the failed import of the dependency
+// descriptor with the digest
+func (i *Importer) resumeJournal(ctx context.Context, digest string) (*ImportJournal, error) {
+	journal, err := i.readJournal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if journal == nil || journal.Status != JournalInProgress {
+		return nil, errors.New("there is no failed import to resume")
+	}
+
+	if journal.Digest != digest {
+		return nil, errors.Errorf("the dependency descriptor changed since the import from %s, restore the previous resources with \"kp import rollback\" before importing it", journal.Timestamp)
+	}
+
+	if err := i.printer.PrintStatus("Resuming import from %s", journal.Timestamp); err != nil {
+		return nil, err
+	}
+
+	journal.resumed = true
+	return journal, nil
+}
+
+// startJournal records the resources an import saves or prunes with the
+// resources they replace before anything is saved
+func (i *Importer) startJournal(ctx context.Context, ts, digest string, objects []runtime.Object, pruned []PlannedResource) (*ImportJournal, error) {
+	journal := &ImportJournal{
+		Status:    JournalInProgress,
+		Timestamp: ts,
+		Digest:    digest,
+	}
+
+	for _, obj := range objects {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		entry, err := i.journalEntry(ctx, kindOf(obj), accessor.GetNamespace(), accessor.GetName())
+		if err != nil {
+			return nil, err
+		}
+		journal.Resources = append(journal.Resources, entry)
+	}
+
+	for _, r := range pruned {
+		entry, err := i.journalEntry(ctx, r.Kind, r.Namespace, r.Name)
+		if err != nil {
+			return nil, err
+		}
+		entry.Pruned = true
+		journal.Resources = append(journal.Resources, entry)
+	}
+
+	return journal, i.writeJournal(ctx, journal)
+}
+
+func (i *Importer) journalEntry(ctx context.Context, kind, namespace, name string) (JournalEntry, error) {
+	entry := JournalEntry{Kind: kind, Name: name, Namespace: namespace}
+
+	existing, err := i.getResource(ctx, kind, namespace, name)
+	if err != nil || existing == nil {
+		return entry, err
+	}
+
+	entry.Previous, err = snapshot(existing)
+	return entry, err
+}
+
+func (i *Importer) completeJournal(ctx context.Context, journal *ImportJournal) error {
+	journal.Status = JournalCompleted
+	return i.writeJournal(ctx, journal)
+}
+
+// savedByJournal returns the resource when it was saved by the resumed import
+func (i *Importer) savedByJournal(ctx context.Context, journal *ImportJournal, obj runtime.Object) (runtime.Object, error) {
+	if !journal.resumed {
+		return nil, nil
+	}
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := i.getResource(ctx, kindOf(obj), accessor.GetNamespace(), accessor.GetName())
+	if err != nil || existing == nil {
+		return nil, err
+	}
+
+	existingAccessor, err := meta.Accessor(existing)
+	if err != nil {
+		return nil, err
+	}
+
+	if existingAccessor.GetAnnotations()[importTimestampAnnotation] != journal.Timestamp {
+		return nil, nil
+	}
+
+	return existing, i.printer.PrintStatus("Skipping %s, it was saved before the import failed", resourceName(kindOf(obj), accessor.GetNamespace(), accessor.GetName()))
+}
+
+// Rollback restores the resources of the last import to what they were before
+// it. Resources the import created are deleted, resources it pruned are
+// recreated and the resources it did not get to save are left as they are.
+func (i *Importer) Rollback(ctx context.Context) (string, error) {
+	journal, err := i.readJournal(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if journal == nil {
+		return "", errors.New("there is no import to roll back")
+	}
+
+	if journal.Status == JournalRolledBack {
+		return "", errors.Errorf("the import from %s was already rolled back", journal.Timestamp)
+	}
+
+	for idx := len(journal.Resources) - 1; idx >= 0; idx-- {
+		if err := i.rollbackEntry(ctx, journal.Timestamp, journal.Resources[idx]); err != nil {
+			return "", err
+		}
+	}
+
+	journal.Status = JournalRolledBack
+	return journal.Timestamp, i.writeJournal(ctx, journal)
+}
+
+func (i *Importer) rollbackEntry(ctx context.Context, ts string, entry JournalEntry) error {
+	current, err := i.getResource(ctx, entry.Kind, entry.Namespace, entry.Name)
+	if err != nil {
+		return err
+	}
+
+	if current == nil {
+		if !entry.Pruned || entry.Previous == nil {
+			return nil
+		}
+
+		if err := i.printer.PrintStatus("Restoring %s...", entry); err != nil {
+			return err
+		}
+
+		obj, err := newObject(entry.Kind)
+		if err != nil {
+			return err
+		}
+
+		restored, err := restore(obj, entry)
+		if err != nil {
+			return err
+		}
+		return i.createResource(ctx, restored)
+	}
+
+	accessor, err := meta.Accessor(current)
+	if err != nil {
+		return err
+	}
+
+	if entry.Pruned || accessor.GetAnnotations()[importTimestampAnnotation] != ts {
+		return nil
+	}
+
+	if entry.Previous == nil {
+		if err := i.printer.PrintStatus("Deleting %s...", entry); err != nil {
+			return err
+		}
+		return i.deleteResource(ctx, entry.Kind, entry.Namespace, entry.Name)
+	}
+
+	if err := i.printer.PrintStatus("Restoring %s...", entry); err != nil {
+		return err
+	}
+
+	restored, err := restore(current, entry)
+	if err != nil {
+		return err
+	}
+	return i.updateResource(ctx, restored)
+}
+
+// snapshot returns the annotations and the spec, or data of the lifecycle
+// config map, of a resource
+func snapshot(obj runtime.Object) (json.RawMessage, error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := map[string]interface{}{}
+	if annotations := accessor.GetAnnotations(); len(annotations) > 0 {
+		previous["annotations"] = annotations
+	}
+
+	field := specField(obj)
+	if value, ok := u[field]; ok {
+		previous[field] = value
+	}
+
+	return json.Marshal(previous)
+}
+
+// restore returns the resource with the annotations and spec of its journal entry
+func restore(obj runtime.Object, entry JournalEntry) (runtime.Object, error) {
+	var previous map[string]interface{}
+	if err := json.Unmarshal(entry.Previous, &previous); err != nil {
+		return nil, err
+	}
+
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	field := specField(obj)
+	if value, ok := previous[field]; ok {
+		u[field] = value
+	} else {
+		delete(u, field)
+	}
+
+	metadata, _ := u["metadata"].(map[string]interface{})
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+		u["metadata"] = metadata
+	}
+	metadata["name"] = entry.Name
+	if entry.Namespace != "" {
+		metadata["namespace"] = entry.Namespace
+	}
+	if annotations, ok := previous["annotations"]; ok {
+		metadata["annotations"] = annotations
+	} else {
+		delete(metadata, "annotations")
+	}
+
+	restored, err := newObject(entry.Kind)
+	if err != nil {
+		return nil, err
+	}
+	return restored, runtime.DefaultUnstructuredConverter.FromUnstructured(u, restored)
+}
+
+func specField(obj runtime.Object) string {
+	if _, ok := obj.(*corev1.ConfigMap); ok {
+		return "data"
+	}
+	return "spec"
+}
+
+func (i *Importer) createResource(ctx context.Context, obj runtime.Object) error {
+	var err error
+	switch o := obj.(type) {
+	case *v1alpha2.ClusterStore:
+		_, err = i.client.KpackV1alpha2().ClusterStores().Create(ctx, o, metav1.CreateOptions{})
+	case *v1alpha2.ClusterBuildpack:
+		_, err = i.client.KpackV1alpha2().ClusterBuildpacks().Create(ctx, o, metav1.CreateOptions{})
+	case *v1alpha2.Buildpack:
+		_, err = i.client.KpackV1alpha2().Buildpacks(o.Namespace).Create(ctx, o, metav1.CreateOptions{})
+	case *v1alpha2.ClusterStack:
+		_, err = i.client.KpackV1alpha2().ClusterStacks().Create(ctx, o, metav1.CreateOptions{})
+	case *v1alpha2.ClusterBuilder:
+		_, err = i.client.KpackV1alpha2().ClusterBuilders().Create(ctx, o, metav1.CreateOptions{})
+	case *v1alpha2.Builder:
+		_, err = i.client.KpackV1alpha2().Builders(o.Namespace).Create(ctx, o, metav1.CreateOptions{})
+	default:
+		return errors.Errorf("%s cannot be created", kindOf(obj))
+	}
+	return err
+}
+
+func (i *Importer) updateResource(ctx context.Context, obj runtime.Object) error {
+	var err error
+	switch o := obj.(type) {
+	case *corev1.ConfigMap:
+		_, err = i.k8sClient.CoreV1().ConfigMaps(o.Namespace).Update(ctx, o, metav1.UpdateOptions{})
+	case *v1alpha2.ClusterStore:
+		_, err = i.client.KpackV1alpha2().ClusterStores().Update(ctx, o, metav1.UpdateOptions{})
+	case *v1alpha2.ClusterBuildpack:
+		_, err = i.client.KpackV1alpha2().ClusterBuildpacks().Update(ctx, o, metav1.UpdateOptions{})
+	case *v1alpha2.Buildpack:
+		_, err = i.client.KpackV1alpha2().Buildpacks(o.Namespace).Update(ctx, o, metav1.UpdateOptions{})
+	case *v1alpha2.ClusterStack:
+		_, err = i.client.KpackV1alpha2().ClusterStacks().Update(ctx, o, metav1.UpdateOptions{})
+	case *v1alpha2.ClusterBuilder:
+		_, err = i.client.KpackV1alpha2().ClusterBuilders().Update(ctx, o, metav1.UpdateOptions{})
+	case *v1alpha2.Builder:
+		_, err = i.client.KpackV1alpha2().Builders(o.Namespace).Update(ctx, o, metav1.UpdateOptions{})
+	default:
+		return errors.Errorf("%s cannot be updated", kindOf(obj))
+	}
+	return err
+}
+
+// Digest returns the digest that identifies the dependency descriptor in the
+// import journal
+func (d DependencyDescriptor) Digest() (string, error) {
+	return contentDigest(d)
+}
+
+// Digest returns the digest that identifies the import plan in the import
+// journal
+func (p ImportPlan) Digest() (string, error) {
+	return contentDigest(p)
+}
+
+func contentDigest(v interface{}) (string, error) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(buf)), nil
+}
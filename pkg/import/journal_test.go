@@ -0,0 +1,292 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package _import
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	kpackfakes "github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfakes "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/config"
+	"github.com/vmware-tanzu/kpack-cli/pkg/registry/fakes"
+)
+
+func TestJournal(t *testing.T) {
+	spec.Run(t, "TestJournal", testJournal)
+}
+
+func testJournal(t *testing.T, when spec.G, it spec.S) {
+	const (
+		stackId    = "io.stacks.mycoolstack"
+		descriptor = `
+apiVersion: kp.kpack.io/v1alpha4
+kind: DependencyDescriptor
+lifecycle:
+  image: new-image.com/lifecycle
+clusterStacks:
+- name: base
+  buildImage:
+    image: new-image.com/stacks/base/build
+  runImage:
+    image: new-image.com/stacks/base/run
+`
+	)
+
+	var (
+		ctx      = context.Background()
+		keychain = authn.NewMultiKeychain()
+		ts       = time.Time{}.String()
+		kpConfig = config.NewKpConfig(
+			"gcr.io/my-cool-repo",
+			corev1.ObjectReference{
+				Namespace: "some-namespace",
+				Name:      "some-serviceaccount",
+			},
+		)
+
+		images    map[string]v1.Image
+		client    *kpackfakes.Clientset
+		k8sClient *k8sfakes.Clientset
+		output    *bytes.Buffer
+		importer  *Importer
+	)
+
+	it.Before(func() {
+		images = map[string]v1.Image{
+			"new-image.com/lifecycle":         fakes.NewFakeImage("lifecycledigest"),
+			"new-image.com/stacks/base/build": fakes.NewFakeLabeledImage("io.buildpacks.stack.id", stackId, "buildimagedigest"),
+			"new-image.com/stacks/base/run":   fakes.NewFakeLabeledImage("io.buildpacks.stack.id", stackId, "runimagedigest"),
+		}
+
+		client = kpackfakes.NewSimpleClientset()
+		k8sClient = k8sfakes.NewSimpleClientset(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "lifecycle-image",
+				Namespace: "kpack",
+			},
+			Data: map[string]string{
+				"image": "old/image",
+			},
+		})
+		output = &bytes.Buffer{}
+		importer = NewImporter(testLogger{writer: output}, k8sClient, client, &fakeFetcher{Images: images}, &fakeRelocator{}, &fakeWaiter{}, &fakeTimestampProvider{ts: ts})
+	})
+
+	readDescriptor := func() DependencyDescriptor {
+		d, err := importer.ReadDescriptor(descriptor)
+		require.NoError(t, err)
+		return d
+	}
+
+	importDescriptor := func() {
+		_, err := importer.ImportDescriptor(ctx, keychain, kpConfig, readDescriptor())
+		require.NoError(t, err)
+	}
+
+	createStack := func(annotations map[string]string, buildImage string) {
+		_, err := client.KpackV1alpha2().ClusterStacks().Create(ctx, &v1alpha2.ClusterStack{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "base",
+				Annotations: annotations,
+			},
+			Spec: v1alpha2.ClusterStackSpec{
+				Id:         stackId,
+				BuildImage: v1alpha2.ClusterStackSpecImage{Image: buildImage},
+				RunImage:   v1alpha2.ClusterStackSpecImage{Image: "old/run"},
+			},
+		}, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	getStack := func() *v1alpha2.ClusterStack {
+		stack, err := client.KpackV1alpha2().ClusterStacks().Get(ctx, "base", metav1.GetOptions{})
+		require.NoError(t, err)
+		return stack
+	}
+
+	getLifecycle := func() *corev1.ConfigMap {
+		cm, err := k8sClient.CoreV1().ConfigMaps("kpack").Get(ctx, "lifecycle-image", metav1.GetOptions{})
+		require.NoError(t, err)
+		return cm
+	}
+
+	readJournal := func() *ImportJournal {
+		journal, err := importer.readJournal(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, journal)
+		return journal
+	}
+
+	failedImport := func(digest string, resources ...JournalEntry) {
+		require.NoError(t, importer.writeJournal(ctx, &ImportJournal{
+			Status:    JournalInProgress,
+			Timestamp: "failed-import-timestamp",
+			Digest:    digest,
+			Resources: resources,
+		}))
+	}
+
+	when("importing", func() {
+		it("records the resources of the import with their previous annotations and spec", func() {
+			importDescriptor()
+
+			journal := readJournal()
+			require.Equal(t, JournalCompleted, journal.Status)
+			require.Equal(t, ts, journal.Timestamp)
+
+			digest, err := readDescriptor().Digest()
+			require.NoError(t, err)
+			require.Equal(t, digest, journal.Digest)
+
+			require.Equal(t, []JournalEntry{
+				{Kind: "ConfigMap", Name: "lifecycle-image", Namespace: "kpack", Previous: json.RawMessage(`{"data":{"image":"old/image"}}`)},
+				{Kind: v1alpha2.ClusterStackKind, Name: "base"},
+			}, journal.Resources)
+		})
+
+		it("fails while the last import did not complete", func() {
+			failedImport("some-digest")
+
+			_, err := importer.ImportDescriptor(ctx, keychain, kpConfig, readDescriptor())
+			require.EqualError(t, err, `the last import from failed-import-timestamp did not complete, resume it with --resume or restore the previous resources with "kp import rollback"`)
+		})
+	})
+
+	when("resuming", func() {
+		it("does not save the resources that were saved before the import failed", func() {
+			digest, err := readDescriptor().Digest()
+			require.NoError(t, err)
+
+			createStack(map[string]string{importTimestampAnnotation: "failed-import-timestamp"}, "saved/build")
+			failedImport(digest,
+				JournalEntry{Kind: "ConfigMap", Name: "lifecycle-image", Namespace: "kpack", Previous: json.RawMessage(`{"data":{"image":"old/image"}}`)},
+				JournalEntry{Kind: v1alpha2.ClusterStackKind, Name: "base"},
+			)
+
+			_, err = importer.ResumeImportDescriptor(ctx, keychain, kpConfig, readDescriptor())
+			require.NoError(t, err)
+
+			require.Contains(t, output.String(), "Resuming import from failed-import-timestamp")
+			require.Contains(t, output.String(), "Skipping ClusterStack 'base', it was saved before the import failed")
+			require.Equal(t, "saved/build", getStack().Spec.BuildImage.Image)
+
+			lifecycle := getLifecycle()
+			require.Equal(t, "gcr.io/my-cool-repo@sha256:lifecycledigest", lifecycle.Data["image"])
+			require.Equal(t, "failed-import-timestamp", lifecycle.Annotations[importTimestampAnnotation])
+
+			journal := readJournal()
+			require.Equal(t, JournalCompleted, journal.Status)
+			require.Equal(t, "failed-import-timestamp", journal.Timestamp)
+			require.Len(t, journal.Resources, 2)
+		})
+
+		it("fails when there is no failed import", func() {
+			importDescriptor()
+
+			_, err := importer.ResumeImportDescriptor(ctx, keychain, kpConfig, readDescriptor())
+			require.EqualError(t, err, "there is no failed import to resume")
+		})
+
+		it("fails when the dependency descriptor changed", func() {
+			failedImport("some-other-digest")
+
+			_, err := importer.ResumeImportDescriptor(ctx, keychain, kpConfig, readDescriptor())
+			require.EqualError(t, err, `the dependency descriptor changed since the import from failed-import-timestamp, restore the previous resources with "kp import rollback" before importing it`)
+		})
+	})
+
+	when("Rollback", func() {
+		it("restores the resources the import updated", func() {
+			createStack(map[string]string{"some-annotation": "some-value"}, "old/build")
+			importDescriptor()
+			require.Equal(t, "gcr.io/my-cool-repo@sha256:buildimagedigest", getStack().Spec.BuildImage.Image)
+
+			rolledBack, err := importer.Rollback(ctx)
+			require.NoError(t, err)
+			require.Equal(t, ts, rolledBack)
+
+			stack := getStack()
+			require.Equal(t, "old/build", stack.Spec.BuildImage.Image)
+			require.Equal(t, map[string]string{"some-annotation": "some-value"}, stack.Annotations)
+
+			lifecycle := getLifecycle()
+			require.Equal(t, map[string]string{"image": "old/image"}, lifecycle.Data)
+			require.Empty(t, lifecycle.Annotations)
+
+			require.Equal(t, JournalRolledBack, readJournal().Status)
+			require.Contains(t, output.String(), "Restoring ClusterStack 'base'...")
+			require.Contains(t, output.String(), "Restoring Lifecycle...")
+		})
+
+		it("deletes the resources the import created", func() {
+			importDescriptor()
+
+			_, err := importer.Rollback(ctx)
+			require.NoError(t, err)
+
+			_, err = client.KpackV1alpha2().ClusterStacks().Get(ctx, "base", metav1.GetOptions{})
+			require.True(t, k8serrors.IsNotFound(err))
+			require.Contains(t, output.String(), "Deleting ClusterStack 'base'...")
+		})
+
+		it("recreates the resources the import pruned", func() {
+			failedImport("some-digest", JournalEntry{
+				Kind:     v1alpha2.ClusterStackKind,
+				Name:     "base",
+				Pruned:   true,
+				Previous: json.RawMessage(`{"annotations":{"kpack.io/import-timestamp":"some-timestamp"},"spec":{"id":"io.stacks.mycoolstack","buildImage":{"image":"old/build"},"runImage":{"image":"old/run"}}}`),
+			})
+
+			_, err := importer.Rollback(ctx)
+			require.NoError(t, err)
+
+			stack := getStack()
+			require.Equal(t, "old/build", stack.Spec.BuildImage.Image)
+			require.Equal(t, "some-timestamp", stack.Annotations[importTimestampAnnotation])
+		})
+
+		it("leaves the resources the failed import did not save", func() {
+			createStack(map[string]string{importTimestampAnnotation: "some-earlier-timestamp"}, "current/build")
+			failedImport("some-digest", JournalEntry{
+				Kind:     v1alpha2.ClusterStackKind,
+				Name:     "base",
+				Previous: json.RawMessage(`{"spec":{"buildImage":{"image":"old/build"}}}`),
+			})
+
+			_, err := importer.Rollback(ctx)
+			require.NoError(t, err)
+
+			require.Equal(t, "current/build", getStack().Spec.BuildImage.Image)
+			require.Equal(t, JournalRolledBack, readJournal().Status)
+		})
+
+		it("fails when there is no import", func() {
+			_, err := importer.Rollback(ctx)
+			require.EqualError(t, err, "there is no import to roll back")
+		})
+
+		it("fails when the import was already rolled back", func() {
+			importDescriptor()
+
+			_, err := importer.Rollback(ctx)
+			require.NoError(t, err)
+
+			_, err = importer.Rollback(ctx)
+			require.EqualError(t, err, "the import from "+ts+" was already rolled back")
+		})
+	})
+}
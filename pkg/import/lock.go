@@ -0,0 +1,424 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package _import
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/pkg/errors"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/registry"
+)
+
+const (
+	LockAPIVersion = "kp.kpack.io/v1alpha1"
+	LockKind       = "DependencyLock"
+
+	lifecycleLockKind        = "lifecycle"
+	clusterStoreLockKind     = "clusterstore"
+	clusterBuildpackLockKind = "clusterbuildpack"
+	buildpackLockKind        = "buildpack"
+	clusterStackLockKind     = "clusterstack"
+)
+
+// DependencyLock pins every image of a dependency descriptor to the digest
+// its tag resolved to, so that importing the descriptor is reproducible
+type DependencyLock struct {
+	APIVersion        string               `json:"apiVersion"`
+	Kind              string               `json:"kind"`
+	Lifecycle         *LockedImage         `json:"lifecycle,omitempty"`
+	ClusterStores     []LockedClusterStore `json:"clusterStores,omitempty"`
+	ClusterBuildpacks []LockedBuildpack    `json:"clusterBuildpacks,omitempty"`
+	Buildpacks        []LockedBuildpack    `json:"buildpacks,omitempty"`
+	ClusterStacks     []LockedClusterStack `json:"clusterStacks,omitempty"`
+}
+
+type LockedImage struct {
+	Image  string `json:"image"`
+	Digest string `json:"digest"`
+}
+
+type LockedClusterStore struct {
+	Name    string        `json:"name"`
+	Sources []LockedImage `json:"sources"`
+}
+
+type LockedBuildpack struct {
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace,omitempty"`
+	LockedImage `json:",inline"`
+}
+
+type LockedClusterStack struct {
+	Name       string      `json:"name"`
+	BuildImage LockedImage `json:"buildImage"`
+	RunImage   LockedImage `json:"runImage"`
+}
+
+// LockSelector selects the resources of a descriptor whose digests are
+// refreshed, the name is empty for the lifecycle and is <namespace>/<name>
+// for buildpacks
+type LockSelector struct {
+	Kind string
+	Name string
+}
+
+// ParseLockSelector parses a selector such as clusterstack=base, clusterbuildpack=java,
+// buildpack=some-namespace/go or lifecycle
+func ParseLockSelector(s string) (LockSelector, error) {
+	kind, name, _ := strings.Cut(s, "=")
+	switch kind {
+	case lifecycleLockKind:
+		if name != "" {
+			return LockSelector{}, errors.Errorf("invalid selector %q, the lifecycle is selected without a name", s)
+		}
+	case clusterStoreLockKind, clusterBuildpackLockKind, clusterStackLockKind:
+		if name == "" {
+			return LockSelector{}, errors.Errorf("invalid selector %q, expected %s=<name>", s, kind)
+		}
+	case buildpackLockKind:
+		if namespace, bpName, _ := strings.Cut(name, "/"); namespace == "" || bpName == "" {
+			return LockSelector{}, errors.Errorf("invalid selector %q, expected %s=<namespace>/<name>", s, kind)
+		}
+	default:
+		return LockSelector{}, errors.Errorf("invalid selector %q, must be one of lifecycle, clusterstore=<name>, clusterbuildpack=<name>, buildpack=<namespace>/<name> or clusterstack=<name>", s)
+	}
+	return LockSelector{Kind: kind, Name: name}, nil
+}
+
+// LockPath returns the path of the lock file of a descriptor, such as
+// dependencies.lock.yaml for dependencies.yaml
+func LockPath(descriptorPath string) string {
+	ext := filepath.Ext(descriptorPath)
+	return strings.TrimSuffix(descriptorPath, ext) + ".lock" + ext
+}
+
+// ReadLock reads a lock file, the returned bool is false when it does not exist
+func ReadLock(path string) (DependencyLock, bool, error) {
+	buf, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return DependencyLock{}, false, nil
+	} else if err != nil {
+		return DependencyLock{}, false, err
+	}
+
+	var lock DependencyLock
+	if err := yaml.Unmarshal(buf, &lock); err != nil {
+		return DependencyLock{}, false, errors.Wrapf(err, "invalid lock file %s", path)
+	}
+
+	if lock.APIVersion != LockAPIVersion || lock.Kind != LockKind {
+		return DependencyLock{}, false, errors.Errorf("invalid lock file %s, expected apiVersion %s and kind %s", path, LockAPIVersion, LockKind)
+	}
+
+	return lock, true, nil
+}
+
+func (l DependencyLock) Write(path string) error {
+	buf, err := yaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf, 0644)
+}
+
+// Locker resolves the images of a descriptor to their digests
+type Locker struct {
+	Fetcher registry.Fetcher
+}
+
+// Lock returns the lock of the descriptor. Digests of the existing lock are
+// kept for images that did not change unless update is set. With selectors,
+// update only refreshes the digests of the selected resources.
+func (l Locker) Lock(keychain authn.Keychain, descriptor DependencyDescriptor, existing DependencyLock, update bool, only ...LockSelector) (DependencyLock, error) {
+	if err := validateSelectors(descriptor, only); err != nil {
+		return DependencyLock{}, err
+	}
+
+	refresh := func(kind, name string) bool {
+		if !update {
+			return false
+		}
+		if len(only) == 0 {
+			return true
+		}
+		for _, s := range only {
+			if s.Kind == kind && s.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	lock := DependencyLock{
+		APIVersion: LockAPIVersion,
+		Kind:       LockKind,
+	}
+
+	if descriptor.HasLifecycleImage() {
+		var previous []LockedImage
+		if existing.Lifecycle != nil {
+			previous = []LockedImage{*existing.Lifecycle}
+		}
+
+		locked, err := l.lockImage(keychain, descriptor.Lifecycle.Image, previous, refresh(lifecycleLockKind, ""))
+		if err != nil {
+			return DependencyLock{}, err
+		}
+		lock.Lifecycle = &locked
+	}
+
+	for _, store := range descriptor.ClusterStores {
+		previous := existing.clusterStore(store.Name).Sources
+		lockedStore := LockedClusterStore{Name: store.Name}
+		for _, src := range store.Sources {
+			locked, err := l.lockImage(keychain, src.Image, previous, refresh(clusterStoreLockKind, store.Name))
+			if err != nil {
+				return DependencyLock{}, err
+			}
+			lockedStore.Sources = append(lockedStore.Sources, locked)
+		}
+		lock.ClusterStores = append(lock.ClusterStores, lockedStore)
+	}
+
+	for _, bp := range descriptor.ClusterBuildpacks {
+		previous := []LockedImage{existing.clusterBuildpack(bp.Name).LockedImage}
+		locked, err := l.lockImage(keychain, bp.Image, previous, refresh(clusterBuildpackLockKind, bp.Name))
+		if err != nil {
+			return DependencyLock{}, err
+		}
+		lock.ClusterBuildpacks = append(lock.ClusterBuildpacks, LockedBuildpack{Name: bp.Name, LockedImage: locked})
+	}
+
+	for _, bp := range descriptor.Buildpacks {
+		previous := []LockedImage{existing.buildpack(bp.Namespace, bp.Name).LockedImage}
+		locked, err := l.lockImage(keychain, bp.Image, previous, refresh(buildpackLockKind, bp.Namespace+"/"+bp.Name))
+		if err != nil {
+			return DependencyLock{}, err
+		}
+		lock.Buildpacks = append(lock.Buildpacks, LockedBuildpack{Name: bp.Name, Namespace: bp.Namespace, LockedImage: locked})
+	}
+
+	for _, stack := range descriptor.ClusterStacks {
+		prev := existing.clusterStack(stack.Name)
+		previous := []LockedImage{prev.BuildImage, prev.RunImage}
+		refreshStack := refresh(clusterStackLockKind, stack.Name)
+
+		buildImage, err := l.lockImage(keychain, stack.BuildImage.Image, previous, refreshStack)
+		if err != nil {
+			return DependencyLock{}, err
+		}
+
+		runImage, err := l.lockImage(keychain, stack.RunImage.Image, previous, refreshStack)
+		if err != nil {
+			return DependencyLock{}, err
+		}
+
+		lock.ClusterStacks = append(lock.ClusterStacks, LockedClusterStack{
+			Name:       stack.Name,
+			BuildImage: buildImage,
+			RunImage:   runImage,
+		})
+	}
+
+	return lock, nil
+}
+
+func (l Locker) lockImage(keychain authn.Keychain, image string, previous []LockedImage, refresh bool) (LockedImage, error) {
+	if !refresh {
+		for _, p := range previous {
+			if p.Image == image {
+				return p, nil
+			}
+		}
+	}
+
+	img, err := l.Fetcher.Fetch(keychain, image)
+	if err != nil {
+		return LockedImage{}, err
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		return LockedImage{}, err
+	}
+
+	return LockedImage{Image: image, Digest: digest.String()}, nil
+}
+
+func validateSelectors(descriptor DependencyDescriptor, selectors []LockSelector) error {
+	for _, s := range selectors {
+		found := false
+		switch s.Kind {
+		case lifecycleLockKind:
+			found = descriptor.HasLifecycleImage()
+		case clusterStoreLockKind:
+			for _, store := range descriptor.ClusterStores {
+				found = found || store.Name == s.Name
+			}
+		case clusterBuildpackLockKind:
+			for _, bp := range descriptor.ClusterBuildpacks {
+				found = found || bp.Name == s.Name
+			}
+		case buildpackLockKind:
+			for _, bp := range descriptor.Buildpacks {
+				found = found || bp.Namespace+"/"+bp.Name == s.Name
+			}
+		case clusterStackLockKind:
+			for _, stack := range descriptor.ClusterStacks {
+				found = found || stack.Name == s.Name
+			}
+		}
+
+		if !found && s.Name == "" {
+			return errors.Errorf("%s not found in the dependency descriptor", s.Kind)
+		} else if !found {
+			return errors.Errorf("%s '%s' not found in the dependency descriptor", s.Kind, s.Name)
+		}
+	}
+	return nil
+}
+
+func (l DependencyLock) clusterStore(name string) LockedClusterStore {
+	for _, store := range l.ClusterStores {
+		if store.Name == name {
+			return store
+		}
+	}
+	return LockedClusterStore{}
+}
+
+func (l DependencyLock) clusterBuildpack(name string) LockedBuildpack {
+	for _, bp := range l.ClusterBuildpacks {
+		if bp.Name == name {
+			return bp
+		}
+	}
+	return LockedBuildpack{}
+}
+
+func (l DependencyLock) buildpack(namespace, name string) LockedBuildpack {
+	for _, bp := range l.Buildpacks {
+		if bp.Namespace == namespace && bp.Name == name {
+			return bp
+		}
+	}
+	return LockedBuildpack{}
+}
+
+func (l DependencyLock) clusterStack(name string) LockedClusterStack {
+	for _, stack := range l.ClusterStacks {
+		if stack.Name == name {
+			return stack
+		}
+	}
+	return LockedClusterStack{}
+}
+
+// ApplyLock pins the images of the descriptor to their locked digests.
+// Registry images are fetched by digest and local files are verified against
+// it. Images that are not in the lock keep their tag and are returned, along
+// with the descriptor, unless the descriptor already pins their digest.
+func (d DependencyDescriptor) ApplyLock(lock DependencyLock) (DependencyDescriptor, []string) {
+	locked := d
+	var unlocked []string
+
+	lockSource := func(src Source, previous []LockedImage) Source {
+		pinned, ok := pin(src, previous)
+		if !ok && !hasDigest(src) {
+			unlocked = append(unlocked, src.Image)
+		}
+		return pinned
+	}
+
+	if d.HasLifecycleImage() {
+		var previous []LockedImage
+		if lock.Lifecycle != nil {
+			previous = []LockedImage{*lock.Lifecycle}
+		}
+		locked.Lifecycle = Lifecycle(lockSource(Source(d.Lifecycle), previous))
+	}
+
+	locked.ClusterStores = nil
+	for _, store := range d.ClusterStores {
+		previous := lock.clusterStore(store.Name).Sources
+		lockedStore := store
+		lockedStore.Sources = nil
+		for _, src := range store.Sources {
+			lockedStore.Sources = append(lockedStore.Sources, lockSource(src, previous))
+		}
+		locked.ClusterStores = append(locked.ClusterStores, lockedStore)
+	}
+
+	locked.ClusterBuildpacks = nil
+	for _, bp := range d.ClusterBuildpacks {
+		lockedBuildpack := bp
+		lockedBuildpack.Source = lockSource(bp.Source, []LockedImage{lock.clusterBuildpack(bp.Name).LockedImage})
+		locked.ClusterBuildpacks = append(locked.ClusterBuildpacks, lockedBuildpack)
+	}
+
+	locked.Buildpacks = nil
+	for _, bp := range d.Buildpacks {
+		lockedBuildpack := bp
+		lockedBuildpack.Source = lockSource(bp.Source, []LockedImage{lock.buildpack(bp.Namespace, bp.Name).LockedImage})
+		locked.Buildpacks = append(locked.Buildpacks, lockedBuildpack)
+	}
+
+	locked.ClusterStacks = nil
+	for _, stack := range d.ClusterStacks {
+		l := lock.clusterStack(stack.Name)
+		previous := []LockedImage{l.BuildImage, l.RunImage}
+		lockedStack := stack
+		lockedStack.BuildImage = lockSource(stack.BuildImage, previous)
+		lockedStack.RunImage = lockSource(stack.RunImage, previous)
+		locked.ClusterStacks = append(locked.ClusterStacks, lockedStack)
+	}
+
+	return locked, unlocked
+}
+
+// pin returns the source pinned to the digest of its image in the lock, the
+// returned bool is false when the lock has no digest for the image
+func pin(src Source, lock []LockedImage) (Source, bool) {
+	for _, l := range lock {
+		if l.Image != src.Image || l.Digest == "" {
+			continue
+		}
+
+		if src.Digest == "" {
+			src.Digest = l.Digest
+		}
+
+		if _, err := os.Stat(src.Image); err == nil {
+			return src, true
+		}
+
+		if ref, err := name.ParseReference(src.Image, name.WeakValidation); err == nil {
+			if _, ok := ref.(name.Digest); !ok {
+				src.Image = src.Image + "@" + l.Digest
+			}
+		}
+		return src, true
+	}
+	return src, false
+}
+
+// hasDigest reports whether the descriptor already pins the digest of the source
+func hasDigest(src Source) bool {
+	if src.Digest != "" {
+		return true
+	}
+
+	ref, err := name.ParseReference(src.Image, name.WeakValidation)
+	if err != nil {
+		return false
+	}
+	_, ok := ref.(name.Digest)
+	return ok
+}
@@ -0,0 +1,240 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package _import_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/pivotal/kpack/pkg/registry/registryfakes"
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/require"
+
+	importpkg "github.com/vmware-tanzu/kpack-cli/pkg/import"
+	"github.com/vmware-tanzu/kpack-cli/pkg/registry/fakes"
+)
+
+func TestLock(t *testing.T) {
+	spec.Run(t, "TestLock", testLock)
+}
+
+func testLock(t *testing.T, when spec.G, it spec.S) {
+	var (
+		keychain = &registryfakes.FakeKeychain{}
+		fetcher  *fakes.Fetcher
+		locker   importpkg.Locker
+	)
+
+	descriptor := importpkg.DependencyDescriptor{
+		Lifecycle: importpkg.Lifecycle{Image: "some-registry.io/lifecycle:latest"},
+		ClusterStores: []importpkg.ClusterStore{
+			{
+				Name:    "some-store",
+				Sources: []importpkg.Source{{Image: "some-registry.io/buildpack:latest"}},
+			},
+		},
+		ClusterStacks: []importpkg.ClusterStack{
+			{
+				Name:       "some-stack",
+				BuildImage: importpkg.Source{Image: "some-registry.io/build:latest"},
+				RunImage:   importpkg.Source{Image: "some-registry.io/run:latest"},
+			},
+		},
+	}
+
+	addImages := func(suffix string) {
+		fetcher.AddImage("some-registry.io/lifecycle:latest", fakes.NewFakeImage("lifecycle"+suffix))
+		fetcher.AddImage("some-registry.io/buildpack:latest", fakes.NewFakeImage("buildpack"+suffix))
+		fetcher.AddImage("some-registry.io/build:latest", fakes.NewFakeImage("build"+suffix))
+		fetcher.AddImage("some-registry.io/run:latest", fakes.NewFakeImage("run"+suffix))
+	}
+
+	it.Before(func() {
+		fetcher = &fakes.Fetcher{}
+		locker = importpkg.Locker{Fetcher: fetcher}
+		addImages("")
+	})
+
+	expectedLock := func(lifecycle, buildpack, build, run string) importpkg.DependencyLock {
+		return importpkg.DependencyLock{
+			APIVersion: importpkg.LockAPIVersion,
+			Kind:       importpkg.LockKind,
+			Lifecycle:  &importpkg.LockedImage{Image: "some-registry.io/lifecycle:latest", Digest: "sha256:" + lifecycle},
+			ClusterStores: []importpkg.LockedClusterStore{
+				{
+					Name:    "some-store",
+					Sources: []importpkg.LockedImage{{Image: "some-registry.io/buildpack:latest", Digest: "sha256:" + buildpack}},
+				},
+			},
+			ClusterStacks: []importpkg.LockedClusterStack{
+				{
+					Name:       "some-stack",
+					BuildImage: importpkg.LockedImage{Image: "some-registry.io/build:latest", Digest: "sha256:" + build},
+					RunImage:   importpkg.LockedImage{Image: "some-registry.io/run:latest", Digest: "sha256:" + run},
+				},
+			},
+		}
+	}
+
+	when("Lock", func() {
+		it("resolves every image to its digest", func() {
+			lock, err := locker.Lock(keychain, descriptor, importpkg.DependencyLock{}, false)
+			require.NoError(t, err)
+			require.Equal(t, expectedLock("lifecycle", "buildpack", "build", "run"), lock)
+		})
+
+		it("keeps the digests of the existing lock", func() {
+			existing := expectedLock("lifecycle", "buildpack", "build", "run")
+			addImages("-new")
+
+			lock, err := locker.Lock(keychain, descriptor, existing, false)
+			require.NoError(t, err)
+			require.Equal(t, existing, lock)
+		})
+
+		it("resolves every image again on update", func() {
+			existing := expectedLock("lifecycle", "buildpack", "build", "run")
+			addImages("-new")
+
+			lock, err := locker.Lock(keychain, descriptor, existing, true)
+			require.NoError(t, err)
+			require.Equal(t, expectedLock("lifecycle-new", "buildpack-new", "build-new", "run-new"), lock)
+		})
+
+		it("only resolves the selected resources again on update", func() {
+			existing := expectedLock("lifecycle", "buildpack", "build", "run")
+			addImages("-new")
+
+			lock, err := locker.Lock(keychain, descriptor, existing, true,
+				importpkg.LockSelector{Kind: "clusterstack", Name: "some-stack"},
+				importpkg.LockSelector{Kind: "lifecycle"},
+			)
+			require.NoError(t, err)
+			require.Equal(t, expectedLock("lifecycle-new", "buildpack", "build-new", "run-new"), lock)
+		})
+
+		it("locks cluster buildpacks and buildpacks", func() {
+			withBuildpacks := descriptor
+			withBuildpacks.ClusterBuildpacks = []importpkg.ClusterBuildpack{
+				{Name: "some-buildpack", Source: importpkg.Source{Image: "some-registry.io/buildpack:latest"}},
+			}
+			withBuildpacks.Buildpacks = []importpkg.Buildpack{
+				{Name: "some-buildpack", Namespace: "some-namespace", Source: importpkg.Source{Image: "some-registry.io/buildpack:latest"}},
+			}
+
+			existing, err := locker.Lock(keychain, withBuildpacks, importpkg.DependencyLock{}, false)
+			require.NoError(t, err)
+			addImages("-new")
+
+			lock, err := locker.Lock(keychain, withBuildpacks, existing, true,
+				importpkg.LockSelector{Kind: "buildpack", Name: "some-namespace/some-buildpack"},
+			)
+			require.NoError(t, err)
+			require.Equal(t, []importpkg.LockedBuildpack{
+				{Name: "some-buildpack", LockedImage: importpkg.LockedImage{Image: "some-registry.io/buildpack:latest", Digest: "sha256:buildpack"}},
+			}, lock.ClusterBuildpacks)
+			require.Equal(t, []importpkg.LockedBuildpack{
+				{Name: "some-buildpack", Namespace: "some-namespace", LockedImage: importpkg.LockedImage{Image: "some-registry.io/buildpack:latest", Digest: "sha256:buildpack-new"}},
+			}, lock.Buildpacks)
+
+			locked, unlocked := withBuildpacks.ApplyLock(lock)
+			require.Empty(t, unlocked)
+			require.Equal(t, "some-registry.io/buildpack:latest@sha256:buildpack", locked.ClusterBuildpacks[0].Image)
+			require.Equal(t, "some-registry.io/buildpack:latest@sha256:buildpack-new", locked.Buildpacks[0].Image)
+		})
+
+		it("errors when a selected resource is not in the descriptor", func() {
+			_, err := locker.Lock(keychain, descriptor, importpkg.DependencyLock{}, true,
+				importpkg.LockSelector{Kind: "clusterstore", Name: "missing-store"},
+			)
+			require.EqualError(t, err, "clusterstore 'missing-store' not found in the dependency descriptor")
+		})
+	})
+
+	when("ApplyLock", func() {
+		it("pins the images to their locked digests", func() {
+			locked, unlocked := descriptor.ApplyLock(expectedLock("lifecycle", "buildpack", "build", "run"))
+			require.Empty(t, unlocked)
+
+			require.Equal(t, importpkg.Lifecycle{Image: "some-registry.io/lifecycle:latest@sha256:lifecycle", Digest: "sha256:lifecycle"}, locked.Lifecycle)
+			require.Equal(t, []importpkg.Source{{Image: "some-registry.io/buildpack:latest@sha256:buildpack", Digest: "sha256:buildpack"}}, locked.ClusterStores[0].Sources)
+			require.Equal(t, importpkg.Source{Image: "some-registry.io/build:latest@sha256:build", Digest: "sha256:build"}, locked.ClusterStacks[0].BuildImage)
+			require.Equal(t, importpkg.Source{Image: "some-registry.io/run:latest@sha256:run", Digest: "sha256:run"}, locked.ClusterStacks[0].RunImage)
+			require.Equal(t, "some-registry.io/build:latest", descriptor.ClusterStacks[0].BuildImage.Image)
+		})
+
+		it("keeps the tag of images that are not locked and returns them", func() {
+			locked, unlocked := descriptor.ApplyLock(importpkg.DependencyLock{})
+			require.Equal(t, descriptor, locked)
+			require.Equal(t, []string{
+				"some-registry.io/lifecycle:latest",
+				"some-registry.io/buildpack:latest",
+				"some-registry.io/build:latest",
+				"some-registry.io/run:latest",
+			}, unlocked)
+		})
+
+		it("returns the images added to the descriptor after it was locked", func() {
+			withStack := descriptor
+			withStack.ClusterStacks = append([]importpkg.ClusterStack{}, descriptor.ClusterStacks...)
+			withStack.ClusterStacks = append(withStack.ClusterStacks, importpkg.ClusterStack{
+				Name:       "new-stack",
+				BuildImage: importpkg.Source{Image: "some-registry.io/new-build:latest"},
+				RunImage:   importpkg.Source{Image: "some-registry.io/new-run:latest", Digest: "sha256:new-run"},
+			})
+
+			_, unlocked := withStack.ApplyLock(expectedLock("lifecycle", "buildpack", "build", "run"))
+			require.Equal(t, []string{"some-registry.io/new-build:latest"}, unlocked)
+		})
+	})
+
+	when("ParseLockSelector", func() {
+		it("parses selectors", func() {
+			s, err := importpkg.ParseLockSelector("clusterstack=base")
+			require.NoError(t, err)
+			require.Equal(t, importpkg.LockSelector{Kind: "clusterstack", Name: "base"}, s)
+
+			s, err = importpkg.ParseLockSelector("lifecycle")
+			require.NoError(t, err)
+			require.Equal(t, importpkg.LockSelector{Kind: "lifecycle"}, s)
+
+			s, err = importpkg.ParseLockSelector("buildpack=some-namespace/some-buildpack")
+			require.NoError(t, err)
+			require.Equal(t, importpkg.LockSelector{Kind: "buildpack", Name: "some-namespace/some-buildpack"}, s)
+		})
+
+		it("errors on invalid selectors", func() {
+			_, err := importpkg.ParseLockSelector("clusterbuilder=default")
+			require.EqualError(t, err, `invalid selector "clusterbuilder=default", must be one of lifecycle, clusterstore=<name>, clusterbuildpack=<name>, buildpack=<namespace>/<name> or clusterstack=<name>`)
+
+			_, err = importpkg.ParseLockSelector("clusterstore")
+			require.EqualError(t, err, `invalid selector "clusterstore", expected clusterstore=<name>`)
+
+			_, err = importpkg.ParseLockSelector("buildpack=some-buildpack")
+			require.EqualError(t, err, `invalid selector "buildpack=some-buildpack", expected buildpack=<namespace>/<name>`)
+		})
+	})
+
+	when("lock files", func() {
+		it("are written next to the descriptor", func() {
+			require.Equal(t, "some/dir/dependencies.lock.yaml", importpkg.LockPath("some/dir/dependencies.yaml"))
+		})
+
+		it("are read back", func() {
+			path := filepath.Join(t.TempDir(), "dependencies.lock.yaml")
+
+			_, found, err := importpkg.ReadLock(path)
+			require.NoError(t, err)
+			require.False(t, found)
+
+			lock := expectedLock("lifecycle", "buildpack", "build", "run")
+			require.NoError(t, lock.Write(path))
+
+			read, found, err := importpkg.ReadLock(path)
+			require.NoError(t, err)
+			require.True(t, found)
+			require.Equal(t, lock, read)
+		})
+	})
+}
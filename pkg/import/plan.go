@@ -0,0 +1,706 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package _import
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ghodss/yaml"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/config"
+	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
+	"github.com/vmware-tanzu/kpack-cli/pkg/registry"
+)
+
+const (
+	PlanAPIVersion = "kp.kpack.io/v1alpha1"
+	PlanKind       = "ImportPlan"
+
+	PlanCreate    = "create"
+	PlanUpdate    = "update"
+	PlanUnchanged = "unchanged"
+	PlanPrune     = "prune"
+
+	importTimestampAnnotation = "kpack.io/import-timestamp"
+	lifecycleKind             = "ConfigMap"
+)
+
+// ImportPlan lists every resource an import would create, update, leave
+// unchanged or prune so that it can be reviewed and applied as is
+type ImportPlan struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Resources  []PlannedResource `json:"resources"`
+}
+
+// PlannedResource is a resource of an import plan. The resource version is the
+// version of the resource in the cluster when the plan was made and the object
+// is the resource that is saved when the plan is applied.
+type PlannedResource struct {
+	Action          string          `json:"action"`
+	Kind            string          `json:"kind"`
+	Name            string          `json:"name"`
+	Namespace       string          `json:"namespace,omitempty"`
+	ResourceVersion string          `json:"resourceVersion,omitempty"`
+	Images          []PlannedImage  `json:"images,omitempty"`
+	Changes         []FieldChange   `json:"changes,omitempty"`
+	Object          json.RawMessage `json:"object,omitempty"`
+}
+
+// PlannedImage is a source image and the image it is relocated to, the size
+// is the size in bytes of the manifest and layers to upload
+type PlannedImage struct {
+	Source    string `json:"source"`
+	Relocated string `json:"relocated"`
+	Size      int64  `json:"size"`
+}
+
+// FieldChange is a field of a resource that an import changes
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old,omitempty"`
+	New   interface{} `json:"new,omitempty"`
+}
+
+func (r PlannedResource) String() string {
+	return resourceName(r.Kind, r.Namespace, r.Name)
+}
+
+// ReadPlan parses an import plan in json or yaml
+func ReadPlan(rawPlan string) (ImportPlan, error) {
+	var plan ImportPlan
+	if err := yaml.Unmarshal([]byte(rawPlan), &plan); err != nil {
+		return ImportPlan{}, err
+	}
+
+	if plan.APIVersion != PlanAPIVersion || plan.Kind != PlanKind {
+		return ImportPlan{}, errors.Errorf("invalid import plan, expected apiVersion %s and kind %s", PlanAPIVersion, PlanKind)
+	}
+
+	for _, r := range plan.Resources {
+		switch r.Action {
+		case PlanCreate, PlanUpdate:
+			if len(r.Object) == 0 {
+				return ImportPlan{}, errors.Errorf("invalid import plan, %s has no object to %s", r, r.Action)
+			}
+		case PlanUnchanged, PlanPrune:
+		default:
+			return ImportPlan{}, errors.Errorf("invalid import plan, unknown action '%s' for %s", r.Action, r)
+		}
+	}
+
+	return plan, nil
+}
+
+// Plan returns the import plan of the descriptor without changing the cluster.
+// The importer must be created with a relocator that does not upload images.
+// Resources that were imported before but are not in the descriptor are only
+// pruned when prune is set.
+func (i *Importer) Plan(ctx context.Context, keychain authn.Keychain, kpConfig config.KpConfig, descriptor DependencyDescriptor, prune bool) (ImportPlan, error) {
+	if err := i.resolveReferences(ctx, descriptor); err != nil {
+		return ImportPlan{}, err
+	}
+
+	if err := i.verifySources(keychain, descriptor); err != nil {
+		return ImportPlan{}, err
+	}
+
+	_, objects, err := i.relocateDescriptor(ctx, keychain, kpConfig, i.timestampProvider.GetTimestamp(), descriptor)
+	if err != nil {
+		return ImportPlan{}, err
+	}
+
+	plan := ImportPlan{APIVersion: PlanAPIVersion, Kind: PlanKind}
+	planned := map[string]bool{}
+	for _, obj := range objects {
+		r, err := i.planResource(ctx, obj)
+		if err != nil {
+			return ImportPlan{}, err
+		}
+
+		planned[resourceKey(r.Kind, r.Namespace, r.Name)] = true
+		plan.Resources = append(plan.Resources, r)
+	}
+
+	if prune {
+		pruned, err := i.planPrunes(ctx, planned)
+		if err != nil {
+			return ImportPlan{}, err
+		}
+		plan.Resources = append(plan.Resources, pruned...)
+	}
+
+	return plan, nil
+}
+
+func (i *Importer) planResource(ctx context.Context, obj runtime.Object) (PlannedResource, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return PlannedResource{}, err
+	}
+
+	r := PlannedResource{
+		Kind:      kindOf(obj),
+		Name:      accessor.GetName(),
+		Namespace: accessor.GetNamespace(),
+		Images:    i.relocations.images(objectImages(obj)),
+	}
+
+	existing, err := i.getResource(ctx, r.Kind, r.Namespace, r.Name)
+	if err != nil {
+		return PlannedResource{}, err
+	}
+
+	if existing != nil {
+		r.ResourceVersion = resourceVersion(existing)
+	}
+
+	r.Changes, err = fieldChanges(existing, obj)
+	if err != nil {
+		return PlannedResource{}, err
+	}
+
+	switch {
+	case existing == nil:
+		r.Action = PlanCreate
+	case len(r.Changes) > 0:
+		r.Action = PlanUpdate
+	default:
+		r.Action = PlanUnchanged
+		return r, nil
+	}
+
+	r.Object, err = json.Marshal(obj)
+	return r, err
+}
+
+// planPrunes returns the resources that were imported before but are not
+// part of the plan
+func (i *Importer) planPrunes(ctx context.Context, planned map[string]bool) ([]PlannedResource, error) {
+	var objects []runtime.Object
+
+	stores, err := i.client.KpackV1alpha2().ClusterStores().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for idx := range stores.Items {
+		objects = append(objects, &stores.Items[idx])
+	}
+
+	clusterBuildpacks, err := i.client.KpackV1alpha2().ClusterBuildpacks().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for idx := range clusterBuildpacks.Items {
+		objects = append(objects, &clusterBuildpacks.Items[idx])
+	}
+
+	buildpacks, err := i.client.KpackV1alpha2().Buildpacks("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for idx := range buildpacks.Items {
+		objects = append(objects, &buildpacks.Items[idx])
+	}
+
+	stacks, err := i.client.KpackV1alpha2().ClusterStacks().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for idx := range stacks.Items {
+		objects = append(objects, &stacks.Items[idx])
+	}
+
+	clusterBuilders, err := i.client.KpackV1alpha2().ClusterBuilders().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for idx := range clusterBuilders.Items {
+		objects = append(objects, &clusterBuilders.Items[idx])
+	}
+
+	builders, err := i.client.KpackV1alpha2().Builders("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for idx := range builders.Items {
+		objects = append(objects, &builders.Items[idx])
+	}
+
+	var pruned []PlannedResource
+	for _, obj := range objects {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		kind := kindOf(obj)
+		if _, ok := accessor.GetAnnotations()[importTimestampAnnotation]; !ok || planned[resourceKey(kind, accessor.GetNamespace(), accessor.GetName())] {
+			continue
+		}
+
+		pruned = append(pruned, PlannedResource{
+			Action:          PlanPrune,
+			Kind:            kind,
+			Name:            accessor.GetName(),
+			Namespace:       accessor.GetNamespace(),
+			ResourceVersion: accessor.GetResourceVersion(),
+		})
+	}
+	return pruned, nil
+}
+
+// ApplyPlan applies an import plan as it was made. It fails before anything
+// is relocated or saved when a resource of the plan changed in the cluster or
+// a source image no longer has the planned digest.
+func (i *Importer) ApplyPlan(ctx context.Context, keychain authn.Keychain, plan ImportPlan) ([]runtime.Object, error) {
+	if err := i.checkLastImport(ctx); err != nil {
+		return nil, err
+	}
+
+	count := 0
+	for _, r := range plan.Resources {
+		if err := i.checkDrift(ctx, r); err != nil {
+			return nil, err
+		}
+
+		if r.Action != PlanCreate && r.Action != PlanUpdate {
+			continue
+		}
+
+		for _, img := range r.Images {
+			i.imageFetcher.Expect(img.Source, registry.Verification{Digest: digestOf(img.Relocated)})
+			count++
+		}
+	}
+
+	if count > 0 {
+		if err := i.printer.PrintStatus("Verifying %d source images...", count); err != nil {
+			return nil, err
+		}
+
+		if err := i.imageFetcher.VerifyAll(keychain); err != nil {
+			return nil, err
+		}
+	}
+
+	ts := i.timestampProvider.GetTimestamp()
+	var (
+		rDescriptor relocatedDescriptor
+		objects     []runtime.Object
+		pruned      []PlannedResource
+	)
+	for _, r := range plan.Resources {
+		if r.Action == PlanPrune {
+			pruned = append(pruned, r)
+		}
+
+		if r.Action != PlanCreate && r.Action != PlanUpdate {
+			continue
+		}
+
+		if err := i.printer.PrintStatus("Importing %s...", r); err != nil {
+			return nil, err
+		}
+
+		for _, img := range r.Images {
+			if err := i.relocatePlannedImage(keychain, img); err != nil {
+				return nil, err
+			}
+		}
+
+		obj, err := r.decode()
+		if err != nil {
+			return nil, err
+		}
+
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, err
+		}
+		accessor.SetAnnotations(k8s.MergeAnnotations(accessor.GetAnnotations(), map[string]string{importTimestampAnnotation: ts}))
+
+		rDescriptor.add(obj)
+		objects = append(objects, obj)
+	}
+
+	digest, err := plan.Digest()
+	if err != nil {
+		return nil, err
+	}
+
+	journal, err := i.startJournal(ctx, ts, digest, objects, pruned)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := i.saveDescriptor(ctx, rDescriptor, journal); err != nil {
+		return nil, err
+	}
+
+	for _, r := range pruned {
+		if err := i.printer.PrintStatus("Pruning %s...", r); err != nil {
+			return nil, err
+		}
+
+		if err := i.deleteResource(ctx, r.Kind, r.Namespace, r.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	return objects, i.completeJournal(ctx, journal)
+}
+
+func (i *Importer) checkDrift(ctx context.Context, r PlannedResource) error {
+	existing, err := i.getResource(ctx, r.Kind, r.Namespace, r.Name)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case r.Action == PlanCreate && existing != nil:
+		return errors.Errorf("%s was created after the plan was made", r)
+	case r.Action != PlanCreate && existing == nil:
+		return errors.Errorf("%s was deleted after the plan was made", r)
+	case existing != nil && resourceVersion(existing) != r.ResourceVersion:
+		return errors.Errorf("%s changed after the plan was made", r)
+	}
+	return nil
+}
+
+func (i *Importer) relocatePlannedImage(keychain authn.Keychain, img PlannedImage) error {
+	repository, _, _ := strings.Cut(img.Relocated, "@")
+	repo, err := name.NewRepository(repository, name.WeakValidation)
+	if err != nil {
+		return err
+	}
+
+	image, err := i.imageFetcher.Fetch(keychain, img.Source)
+	if err != nil {
+		return err
+	}
+
+	relocated, err := i.imageRelocator.Relocate(keychain, image, repo.Name())
+	if err != nil {
+		return err
+	}
+
+	if digestOf(relocated) != digestOf(img.Relocated) {
+		return errors.Errorf("'%s' was relocated to '%s' instead of '%s'", img.Source, relocated, img.Relocated)
+	}
+	return nil
+}
+
+func (r PlannedResource) decode() (runtime.Object, error) {
+	obj, err := newObject(r.Kind)
+	if err != nil {
+		return nil, err
+	}
+
+	return obj, json.Unmarshal(r.Object, obj)
+}
+
+func newObject(kind string) (runtime.Object, error) {
+	switch kind {
+	case lifecycleKind:
+		return &corev1.ConfigMap{}, nil
+	case v1alpha2.ClusterStoreKind:
+		return &v1alpha2.ClusterStore{}, nil
+	case v1alpha2.ClusterBuildpackKind:
+		return &v1alpha2.ClusterBuildpack{}, nil
+	case v1alpha2.BuildpackKind:
+		return &v1alpha2.Buildpack{}, nil
+	case v1alpha2.ClusterStackKind:
+		return &v1alpha2.ClusterStack{}, nil
+	case v1alpha2.ClusterBuilderKind:
+		return &v1alpha2.ClusterBuilder{}, nil
+	case v1alpha2.BuilderKind:
+		return &v1alpha2.Builder{}, nil
+	}
+	return nil, errors.Errorf("unsupported kind '%s'", kind)
+}
+
+func (d *relocatedDescriptor) add(obj runtime.Object) {
+	switch o := obj.(type) {
+	case *corev1.ConfigMap:
+		d.lifecycle = o
+	case *v1alpha2.ClusterStore:
+		d.clusterStores = append(d.clusterStores, o)
+	case *v1alpha2.ClusterBuildpack:
+		d.clusterBuildpacks = append(d.clusterBuildpacks, o)
+	case *v1alpha2.Buildpack:
+		d.buildpacks = append(d.buildpacks, o)
+	case *v1alpha2.ClusterStack:
+		d.clusterStacks = append(d.clusterStacks, o)
+	case *v1alpha2.ClusterBuilder:
+		d.clusterBuilders = append(d.clusterBuilders, o)
+	case *v1alpha2.Builder:
+		d.builders = append(d.builders, o)
+	}
+}
+
+func (i *Importer) getResource(ctx context.Context, kind, namespace, name string) (runtime.Object, error) {
+	var (
+		obj runtime.Object
+		err error
+	)
+	switch kind {
+	case lifecycleKind:
+		obj, err = i.k8sClient.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	case v1alpha2.ClusterStoreKind:
+		obj, err = i.client.KpackV1alpha2().ClusterStores().Get(ctx, name, metav1.GetOptions{})
+	case v1alpha2.ClusterBuildpackKind:
+		obj, err = i.client.KpackV1alpha2().ClusterBuildpacks().Get(ctx, name, metav1.GetOptions{})
+	case v1alpha2.BuildpackKind:
+		obj, err = i.client.KpackV1alpha2().Buildpacks(namespace).Get(ctx, name, metav1.GetOptions{})
+	case v1alpha2.ClusterStackKind:
+		obj, err = i.client.KpackV1alpha2().ClusterStacks().Get(ctx, name, metav1.GetOptions{})
+	case v1alpha2.ClusterBuilderKind:
+		obj, err = i.client.KpackV1alpha2().ClusterBuilders().Get(ctx, name, metav1.GetOptions{})
+	case v1alpha2.BuilderKind:
+		obj, err = i.client.KpackV1alpha2().Builders(namespace).Get(ctx, name, metav1.GetOptions{})
+	default:
+		return nil, errors.Errorf("unsupported kind '%s'", kind)
+	}
+
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return obj, err
+}
+
+func (i *Importer) deleteResource(ctx context.Context, kind, namespace, name string) error {
+	var err error
+	switch kind {
+	case v1alpha2.ClusterStoreKind:
+		err = i.client.KpackV1alpha2().ClusterStores().Delete(ctx, name, metav1.DeleteOptions{})
+	case v1alpha2.ClusterBuildpackKind:
+		err = i.client.KpackV1alpha2().ClusterBuildpacks().Delete(ctx, name, metav1.DeleteOptions{})
+	case v1alpha2.BuildpackKind:
+		err = i.client.KpackV1alpha2().Buildpacks(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	case v1alpha2.ClusterStackKind:
+		err = i.client.KpackV1alpha2().ClusterStacks().Delete(ctx, name, metav1.DeleteOptions{})
+	case v1alpha2.ClusterBuilderKind:
+		err = i.client.KpackV1alpha2().ClusterBuilders().Delete(ctx, name, metav1.DeleteOptions{})
+	case v1alpha2.BuilderKind:
+		err = i.client.KpackV1alpha2().Builders(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	default:
+		return errors.Errorf("%s cannot be deleted", resourceName(kind, namespace, name))
+	}
+	return err
+}
+
+func resourceName(kind, namespace, name string) string {
+	if kind == lifecycleKind {
+		return "Lifecycle"
+	}
+	if namespace != "" {
+		return fmt.Sprintf("%s '%s' in namespace '%s'", kind, name, namespace)
+	}
+	return fmt.Sprintf("%s '%s'", kind, name)
+}
+
+func kindOf(obj runtime.Object) string {
+	switch obj.(type) {
+	case *corev1.ConfigMap:
+		return lifecycleKind
+	case *v1alpha2.ClusterStore:
+		return v1alpha2.ClusterStoreKind
+	case *v1alpha2.ClusterBuildpack:
+		return v1alpha2.ClusterBuildpackKind
+	case *v1alpha2.Buildpack:
+		return v1alpha2.BuildpackKind
+	case *v1alpha2.ClusterStack:
+		return v1alpha2.ClusterStackKind
+	case *v1alpha2.ClusterBuilder:
+		return v1alpha2.ClusterBuilderKind
+	case *v1alpha2.Builder:
+		return v1alpha2.BuilderKind
+	}
+	return obj.GetObjectKind().GroupVersionKind().Kind
+}
+
+// objectImages returns the images a resource references
+func objectImages(obj runtime.Object) []string {
+	switch o := obj.(type) {
+	case *corev1.ConfigMap:
+		return []string{o.Data["image"]}
+	case *v1alpha2.ClusterStore:
+		var images []string
+		for _, src := range o.Spec.Sources {
+			images = append(images, src.Image)
+		}
+		return images
+	case *v1alpha2.ClusterBuildpack:
+		return []string{o.Spec.Image}
+	case *v1alpha2.Buildpack:
+		return []string{o.Spec.Image}
+	case *v1alpha2.ClusterStack:
+		return []string{o.Spec.BuildImage.Image, o.Spec.RunImage.Image}
+	}
+	return nil
+}
+
+func resourceKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+func resourceVersion(obj runtime.Object) string {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return ""
+	}
+	return accessor.GetResourceVersion()
+}
+
+func digestOf(ref string) string {
+	_, digest, _ := strings.Cut(ref, "@")
+	return digest
+}
+
+// fieldChanges returns the fields of the spec, or the data of the lifecycle
+// config map, that differ between the existing and the planned resource
+func fieldChanges(existing, planned runtime.Object) ([]FieldChange, error) {
+	field := specField(planned)
+
+	var old interface{}
+	if existing != nil {
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(existing)
+		if err != nil {
+			return nil, err
+		}
+		old = u[field]
+	}
+
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(planned)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []FieldChange
+	diffFields(field, old, u[field], &changes)
+	return changes, nil
+}
+
+func diffFields(path string, old, new interface{}, changes *[]FieldChange) {
+	oldMap, oldIsMap := old.(map[string]interface{})
+	newMap, newIsMap := new.(map[string]interface{})
+
+	if (oldIsMap || old == nil) && (newIsMap || new == nil) && (oldIsMap || newIsMap) {
+		keys := map[string]bool{}
+		for k := range oldMap {
+			keys[k] = true
+		}
+		for k := range newMap {
+			keys[k] = true
+		}
+
+		var sorted []string
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+
+		for _, k := range sorted {
+			diffFields(path+"."+k, oldMap[k], newMap[k], changes)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(old, new) {
+		*changes = append(*changes, FieldChange{Field: path, Old: old, New: new})
+	}
+}
+
+// relocationRecorder records the source of every relocated image so that
+// import plans can report which source image each relocated image is from
+type relocationRecorder struct {
+	fetcher   registry.Fetcher
+	relocator registry.Relocator
+
+	mux       sync.Mutex
+	sources   map[string]string
+	relocated map[string]PlannedImage
+}
+
+func newRelocationRecorder(fetcher registry.Fetcher, relocator registry.Relocator) *relocationRecorder {
+	return &relocationRecorder{
+		fetcher:   fetcher,
+		relocator: relocator,
+		sources:   map[string]string{},
+		relocated: map[string]PlannedImage{},
+	}
+}
+
+func (r *relocationRecorder) Fetch(keychain authn.Keychain, src string) (v1.Image, error) {
+	img, err := r.fetcher.Fetch(keychain, src)
+	if err != nil {
+		return nil, err
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		return nil, err
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.sources[digest.String()] = src
+	return img, nil
+}
+
+func (r *relocationRecorder) Relocate(keychain authn.Keychain, img v1.Image, destination string) (string, error) {
+	relocated, err := r.relocator.Relocate(keychain, img, destination)
+	if err != nil {
+		return "", err
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		return "", err
+	}
+
+	size, err := registry.ImageSize(img)
+	if err != nil {
+		return "", err
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.relocated[relocated] = PlannedImage{
+		Source:    r.sources[digest.String()],
+		Relocated: relocated,
+		Size:      size,
+	}
+	return relocated, nil
+}
+
+func (r *relocationRecorder) images(refs []string) []PlannedImage {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	var images []PlannedImage
+	for _, ref := range refs {
+		if img, ok := r.relocated[ref]; ok {
+			images = append(images, img)
+		}
+	}
+	return images
+}
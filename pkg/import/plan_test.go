@@ -0,0 +1,300 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package _import
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	kpackfakes "github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfakes "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/config"
+	"github.com/vmware-tanzu/kpack-cli/pkg/registry/fakes"
+)
+
+func TestPlan(t *testing.T) {
+	spec.Run(t, "TestPlan", testPlan)
+}
+
+func testPlan(t *testing.T, when spec.G, it spec.S) {
+	const (
+		stackId    = "io.stacks.mycoolstack"
+		descriptor = `
+apiVersion: kp.kpack.io/v1alpha4
+kind: DependencyDescriptor
+lifecycle:
+  image: new-image.com/lifecycle
+clusterStacks:
+- name: base
+  buildImage:
+    image: new-image.com/stacks/base/build
+  runImage:
+    image: new-image.com/stacks/base/run
+`
+	)
+
+	var (
+		ctx      = context.Background()
+		keychain = authn.NewMultiKeychain()
+		kpConfig = config.NewKpConfig(
+			"gcr.io/my-cool-repo",
+			corev1.ObjectReference{
+				Namespace: "some-namespace",
+				Name:      "some-serviceaccount",
+			},
+		)
+
+		images    map[string]v1.Image
+		client    *kpackfakes.Clientset
+		k8sClient *k8sfakes.Clientset
+	)
+
+	it.Before(func() {
+		images = map[string]v1.Image{
+			"new-image.com/lifecycle":         fakes.NewFakeImage("lifecycledigest"),
+			"new-image.com/stacks/base/build": fakes.NewFakeLabeledImage("io.buildpacks.stack.id", stackId, "buildimagedigest"),
+			"new-image.com/stacks/base/run":   fakes.NewFakeLabeledImage("io.buildpacks.stack.id", stackId, "runimagedigest"),
+		}
+
+		client = kpackfakes.NewSimpleClientset()
+		k8sClient = k8sfakes.NewSimpleClientset(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "lifecycle-image",
+				Namespace:       "kpack",
+				ResourceVersion: "1",
+			},
+			Data: map[string]string{
+				"image": "old/image",
+			},
+		})
+	})
+
+	newImporter := func() *Importer {
+		return NewImporter(testLogger{writer: &bytes.Buffer{}}, k8sClient, client, &fakeFetcher{Images: images}, &fakeRelocator{}, &fakeWaiter{}, &fakeTimestampProvider{ts: time.Time{}.String()})
+	}
+
+	plan := func(prune bool) ImportPlan {
+		importer := newImporter()
+		descriptor, err := importer.ReadDescriptor(descriptor)
+		require.NoError(t, err)
+
+		importPlan, err := importer.Plan(ctx, keychain, kpConfig, descriptor, prune)
+		require.NoError(t, err)
+		return importPlan
+	}
+
+	existingStack := func() *v1alpha2.ClusterStack {
+		return &v1alpha2.ClusterStack{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "base",
+				ResourceVersion: "1",
+			},
+			Spec: v1alpha2.ClusterStackSpec{
+				Id: stackId,
+				BuildImage: v1alpha2.ClusterStackSpecImage{
+					Image: "gcr.io/my-cool-repo@sha256:buildimagedigest",
+				},
+				RunImage: v1alpha2.ClusterStackSpecImage{
+					Image: "gcr.io/my-cool-repo@sha256:runimagedigest",
+				},
+				ServiceAccountRef: &corev1.ObjectReference{
+					Namespace: "some-namespace",
+					Name:      "some-serviceaccount",
+				},
+			},
+		}
+	}
+
+	when("Plan", func() {
+		it("lists the resources to create and update with their images and changes", func() {
+			importPlan := plan(false)
+
+			require.Equal(t, PlanAPIVersion, importPlan.APIVersion)
+			require.Equal(t, PlanKind, importPlan.Kind)
+			require.Len(t, importPlan.Resources, 2)
+
+			lifecycle := importPlan.Resources[0]
+			require.Equal(t, PlanUpdate, lifecycle.Action)
+			require.Equal(t, "ConfigMap", lifecycle.Kind)
+			require.Equal(t, "lifecycle-image", lifecycle.Name)
+			require.Equal(t, "kpack", lifecycle.Namespace)
+			require.Equal(t, "1", lifecycle.ResourceVersion)
+			require.Equal(t, []PlannedImage{
+				{Source: "new-image.com/lifecycle", Relocated: "gcr.io/my-cool-repo@sha256:lifecycledigest"},
+			}, lifecycle.Images)
+			require.Equal(t, []FieldChange{
+				{Field: "data.image", Old: "old/image", New: "gcr.io/my-cool-repo@sha256:lifecycledigest"},
+			}, lifecycle.Changes)
+			require.NotEmpty(t, lifecycle.Object)
+
+			stack := importPlan.Resources[1]
+			require.Equal(t, PlanCreate, stack.Action)
+			require.Equal(t, v1alpha2.ClusterStackKind, stack.Kind)
+			require.Equal(t, "base", stack.Name)
+			require.Empty(t, stack.ResourceVersion)
+			require.Equal(t, []PlannedImage{
+				{Source: "new-image.com/stacks/base/build", Relocated: "gcr.io/my-cool-repo@sha256:buildimagedigest"},
+				{Source: "new-image.com/stacks/base/run", Relocated: "gcr.io/my-cool-repo@sha256:runimagedigest"},
+			}, stack.Images)
+			require.Contains(t, stack.Changes, FieldChange{Field: "spec.id", New: stackId})
+
+			for _, action := range append(client.Actions(), k8sClient.Actions()...) {
+				require.Contains(t, []string{"get", "list"}, action.GetVerb())
+			}
+		})
+
+		it("lists resources that do not change as unchanged", func() {
+			_, err := client.KpackV1alpha2().ClusterStacks().Create(ctx, existingStack(), metav1.CreateOptions{})
+			require.NoError(t, err)
+
+			stack := plan(false).Resources[1]
+			require.Equal(t, PlanUnchanged, stack.Action)
+			require.Equal(t, "1", stack.ResourceVersion)
+			require.Empty(t, stack.Changes)
+			require.Empty(t, stack.Object)
+		})
+
+		it("lists imported resources that are not in the descriptor to prune", func() {
+			_, err := client.KpackV1alpha2().ClusterStacks().Create(ctx, &v1alpha2.ClusterStack{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "imported",
+					ResourceVersion: "3",
+					Annotations:     map[string]string{importTimestampAnnotation: "some-timestamp"},
+				},
+			}, metav1.CreateOptions{})
+			require.NoError(t, err)
+
+			_, err = client.KpackV1alpha2().ClusterStacks().Create(ctx, &v1alpha2.ClusterStack{
+				ObjectMeta: metav1.ObjectMeta{Name: "not-imported"},
+			}, metav1.CreateOptions{})
+			require.NoError(t, err)
+
+			require.Len(t, plan(false).Resources, 2)
+
+			importPlan := plan(true)
+			require.Len(t, importPlan.Resources, 3)
+			require.Equal(t, PlannedResource{
+				Action:          PlanPrune,
+				Kind:            v1alpha2.ClusterStackKind,
+				Name:            "imported",
+				ResourceVersion: "3",
+			}, importPlan.Resources[2])
+		})
+	})
+
+	when("ReadPlan", func() {
+		it("reads a plan in json or yaml", func() {
+			importPlan := plan(false)
+
+			raw, err := json.Marshal(importPlan)
+			require.NoError(t, err)
+
+			fromJSON, err := ReadPlan(string(raw))
+			require.NoError(t, err)
+			require.Equal(t, importPlan.Resources[1].Images, fromJSON.Resources[1].Images)
+			require.JSONEq(t, string(importPlan.Resources[1].Object), string(fromJSON.Resources[1].Object))
+
+			fromYAML, err := ReadPlan(`apiVersion: kp.kpack.io/v1alpha1
+kind: ImportPlan
+resources:
+- action: prune
+  kind: ClusterStack
+  name: some-stack
+`)
+			require.NoError(t, err)
+			require.Equal(t, []PlannedResource{{Action: PlanPrune, Kind: v1alpha2.ClusterStackKind, Name: "some-stack"}}, fromYAML.Resources)
+		})
+
+		it("rejects plans that cannot be applied", func() {
+			_, err := ReadPlan(`apiVersion: kp.kpack.io/v1alpha4
+kind: DependencyDescriptor
+`)
+			require.EqualError(t, err, "invalid import plan, expected apiVersion kp.kpack.io/v1alpha1 and kind ImportPlan")
+
+			_, err = ReadPlan(`apiVersion: kp.kpack.io/v1alpha1
+kind: ImportPlan
+resources:
+- action: create
+  kind: ClusterStack
+  name: some-stack
+`)
+			require.EqualError(t, err, "invalid import plan, ClusterStack 'some-stack' has no object to create")
+		})
+	})
+
+	when("ApplyPlan", func() {
+		it("relocates the images and saves the resources of the plan", func() {
+			_, err := client.KpackV1alpha2().ClusterStacks().Create(ctx, &v1alpha2.ClusterStack{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "imported",
+					Annotations: map[string]string{importTimestampAnnotation: "some-timestamp"},
+				},
+			}, metav1.CreateOptions{})
+			require.NoError(t, err)
+
+			importPlan := plan(true)
+
+			relocator := &fakes.Relocator{}
+			importer := NewImporter(testLogger{writer: &bytes.Buffer{}}, k8sClient, client, &fakeFetcher{Images: images}, relocator, &fakeWaiter{}, &fakeTimestampProvider{ts: "applied"})
+			objects, err := importer.ApplyPlan(ctx, keychain, importPlan)
+			require.NoError(t, err)
+			require.Len(t, objects, 2)
+			require.Equal(t, 3, relocator.CallCount())
+
+			lifecycle, err := k8sClient.CoreV1().ConfigMaps("kpack").Get(ctx, "lifecycle-image", metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Equal(t, "gcr.io/my-cool-repo@sha256:lifecycledigest", lifecycle.Data["image"])
+			require.Equal(t, "applied", lifecycle.Annotations[importTimestampAnnotation])
+
+			stack, err := client.KpackV1alpha2().ClusterStacks().Get(ctx, "base", metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Equal(t, existingStack().Spec, stack.Spec)
+			require.Equal(t, "applied", stack.Annotations[importTimestampAnnotation])
+
+			_, err = client.KpackV1alpha2().ClusterStacks().Get(ctx, "imported", metav1.GetOptions{})
+			require.True(t, k8serrors.IsNotFound(err))
+		})
+
+		it("does not relocate or save anything when a resource changed after the plan was made", func() {
+			importPlan := plan(false)
+
+			_, err := client.KpackV1alpha2().ClusterStacks().Create(ctx, existingStack(), metav1.CreateOptions{})
+			require.NoError(t, err)
+
+			relocator := &fakes.Relocator{}
+			importer := NewImporter(testLogger{writer: &bytes.Buffer{}}, k8sClient, client, &fakeFetcher{Images: images}, relocator, &fakeWaiter{}, &fakeTimestampProvider{})
+			_, err = importer.ApplyPlan(ctx, keychain, importPlan)
+			require.EqualError(t, err, "ClusterStack 'base' was created after the plan was made")
+			require.Zero(t, relocator.CallCount())
+		})
+
+		it("does not relocate or save anything when a source image changed after the plan was made", func() {
+			importPlan := plan(false)
+
+			images["new-image.com/stacks/base/run"] = fakes.NewFakeLabeledImage("io.buildpacks.stack.id", stackId, "newrunimagedigest")
+
+			relocator := &fakes.Relocator{}
+			importer := NewImporter(testLogger{writer: &bytes.Buffer{}}, k8sClient, client, &fakeFetcher{Images: images}, relocator, &fakeWaiter{}, &fakeTimestampProvider{})
+			_, err := importer.ApplyPlan(ctx, keychain, importPlan)
+			require.EqualError(t, err, `verification failed for 1 source image(s), no images were relocated:
+	new-image.com/stacks/base/run: digest sha256:newrunimagedigest does not match the expected digest sha256:runimagedigest`)
+			require.Zero(t, relocator.CallCount())
+
+			_, err = client.KpackV1alpha2().ClusterStacks().Get(ctx, "base", metav1.GetOptions{})
+			require.True(t, k8serrors.IsNotFound(err))
+		})
+	})
+}
@@ -0,0 +1,204 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package _import
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	"github.com/pivotal/kpack/pkg/client/clientset/versioned"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var referenceKinds = map[string]string{
+	v1alpha2.ClusterStackKind:     "cluster stack",
+	v1alpha2.ClusterStoreKind:     "cluster store",
+	v1alpha2.ClusterBuildpackKind: "cluster buildpack",
+	v1alpha2.BuildpackKind:        "buildpack",
+}
+
+// Reference is a cluster stack, cluster store or buildpack referenced by a
+// builder of the descriptor, Path locates the reference in the descriptor
+// such as clusterBuilders[0].clusterStack
+type Reference struct {
+	Path      string
+	Referrer  string
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+func (r Reference) unresolved(inCluster bool) string {
+	where := "the descriptor"
+	if inCluster {
+		where = "the descriptor or the cluster"
+	}
+	return fmt.Sprintf("%s references %s '%s' which is not defined in %s", r.Referrer, referenceKinds[r.Kind], r.Name, where)
+}
+
+// References returns the cluster stacks, cluster stores and buildpacks
+// referenced by the builders of the descriptor
+func (d DependencyDescriptor) References() []Reference {
+	var refs []Reference
+	for _, b := range d.builders() {
+		refs = append(refs, b.references()...)
+	}
+	return refs
+}
+
+// descriptorBuilder is a cluster builder or builder of the descriptor
+type descriptorBuilder struct {
+	path      string
+	referrer  string
+	namespace string
+	stack     string
+	store     string
+	order     []v1alpha2.BuilderOrderEntry
+}
+
+func (d DependencyDescriptor) builders() []descriptorBuilder {
+	var builders []descriptorBuilder
+	for i, cb := range d.ClusterBuilders {
+		builders = append(builders, descriptorBuilder{
+			path:     fmt.Sprintf("clusterBuilders[%d]", i),
+			referrer: fmt.Sprintf("cluster builder '%s'", cb.Name),
+			stack:    cb.ClusterStack,
+			store:    cb.ClusterStore,
+			order:    cb.Order,
+		})
+	}
+	for i, b := range d.Builders {
+		builders = append(builders, descriptorBuilder{
+			path:      fmt.Sprintf("builders[%d]", i),
+			referrer:  fmt.Sprintf("builder '%s' in namespace '%s'", b.Name, b.Namespace),
+			namespace: b.Namespace,
+			stack:     b.ClusterStack,
+			store:     b.ClusterStore,
+			order:     b.Order,
+		})
+	}
+	return builders
+}
+
+func (b descriptorBuilder) references() []Reference {
+	refs := []Reference{{
+		Path:     b.path + ".clusterStack",
+		Referrer: b.referrer,
+		Kind:     v1alpha2.ClusterStackKind,
+		Name:     b.stack,
+	}}
+
+	if b.store != "" {
+		refs = append(refs, Reference{
+			Path:     b.path + ".clusterStore",
+			Referrer: b.referrer,
+			Kind:     v1alpha2.ClusterStoreKind,
+			Name:     b.store,
+		})
+	}
+
+	for i, entry := range b.order {
+		for j, ref := range entry.Group {
+			if ref.Kind != v1alpha2.ClusterBuildpackKind && ref.Kind != v1alpha2.BuildpackKind {
+				continue
+			}
+
+			r := Reference{
+				Path:     b.orderPath(i, j),
+				Referrer: b.referrer,
+				Kind:     ref.Kind,
+				Name:     ref.Name,
+			}
+			if ref.Kind == v1alpha2.BuildpackKind {
+				r.Namespace = b.namespace
+			}
+			refs = append(refs, r)
+		}
+	}
+
+	return refs
+}
+
+func (b descriptorBuilder) orderPath(entry, group int) string {
+	return fmt.Sprintf("%s.order[%d].group[%d]", b.path, entry, group)
+}
+
+func (d DependencyDescriptor) defines(ref Reference) bool {
+	switch ref.Kind {
+	case v1alpha2.ClusterStackKind:
+		if ref.Name == "default" && d.DefaultClusterStack != "" {
+			return true
+		}
+		for _, stack := range d.ClusterStacks {
+			if stack.Name == ref.Name {
+				return true
+			}
+		}
+	case v1alpha2.ClusterStoreKind:
+		for _, store := range d.ClusterStores {
+			if store.Name == ref.Name {
+				return true
+			}
+		}
+	case v1alpha2.ClusterBuildpackKind:
+		for _, bp := range d.ClusterBuildpacks {
+			if bp.Name == ref.Name {
+				return true
+			}
+		}
+	case v1alpha2.BuildpackKind:
+		for _, bp := range d.Buildpacks {
+			if bp.Namespace == ref.Namespace && bp.Name == ref.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// unresolvedReferences returns the references of the descriptor that are
+// neither defined in the descriptor nor in the cluster, the cluster is not
+// looked up when the client is nil
+func unresolvedReferences(ctx context.Context, client versioned.Interface, d DependencyDescriptor) ([]Reference, error) {
+	var unresolved []Reference
+	for _, ref := range d.References() {
+		if d.defines(ref) {
+			continue
+		}
+
+		if client != nil {
+			exists, err := referenceExists(ctx, client, ref)
+			if err != nil {
+				return nil, err
+			}
+			if exists {
+				continue
+			}
+		}
+
+		unresolved = append(unresolved, ref)
+	}
+	return unresolved, nil
+}
+
+func referenceExists(ctx context.Context, client versioned.Interface, ref Reference) (bool, error) {
+	var err error
+	switch ref.Kind {
+	case v1alpha2.ClusterStackKind:
+		_, err = client.KpackV1alpha2().ClusterStacks().Get(ctx, ref.Name, metav1.GetOptions{})
+	case v1alpha2.ClusterStoreKind:
+		_, err = client.KpackV1alpha2().ClusterStores().Get(ctx, ref.Name, metav1.GetOptions{})
+	case v1alpha2.ClusterBuildpackKind:
+		_, err = client.KpackV1alpha2().ClusterBuildpacks().Get(ctx, ref.Name, metav1.GetOptions{})
+	case v1alpha2.BuildpackKind:
+		_, err = client.KpackV1alpha2().Buildpacks(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	}
+
+	if k8serrors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
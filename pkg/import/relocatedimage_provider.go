@@ -18,8 +18,8 @@ func NewDefaultRelocatedImageProvider(fetcher registry.Fetcher) *DefaultRelocate
 	return &DefaultRelocatedImageProvider{fetcher: fetcher}
 }
 
-func (r *DefaultRelocatedImageProvider) RelocatedImage(keychain authn.Keychain, kpConfig config.KpConfig, srcImage string) (string, error) {
-	relocationRepo, err := kpConfig.DefaultRepository()
+func (r *DefaultRelocatedImageProvider) RelocatedImage(keychain authn.Keychain, kpConfig config.KpConfig, artifact config.Artifact, srcImage string) (string, error) {
+	relocationRepo, err := kpConfig.Repository(artifact)
 	if err != nil {
 		return "", err
 	}
@@ -31,10 +31,26 @@ func testRelocatedImageProvider(t *testing.T, when spec.G, it spec.S) {
 
 			srcImage := "some-registry.com/some-repo/image@sha256:some-digest"
 
-			image, err := relocatedImageProvider.RelocatedImage(keychain, kpConfig, srcImage)
+			image, err := relocatedImageProvider.RelocatedImage(keychain, kpConfig, config.StackArtifact, srcImage)
 			require.NoError(t, err)
 
 			assert.Equal(t, "my-registy.com/my-repo@sha256:some-digest", image)
 		})
+
+		it("uses the repository of the artifact", func() {
+			fetcher := &fakeFetcher{Images: map[string]v1.Image{
+				"some-registry.com/some-repo/image@sha256:some-digest": fakes.NewFakeImage("some-digest"),
+			}}
+
+			relocatedImageProvider := NewDefaultRelocatedImageProvider(fetcher)
+			keychain := &registryfakes.FakeKeychain{Name: "someKeychain"}
+			kpConfig := config.NewKpConfig("my-registy.com/my-repo", corev1.ObjectReference{Name: "service account"}).
+				WithRepository(config.StackArtifact, "my-registy.com/my-stacks")
+
+			image, err := relocatedImageProvider.RelocatedImage(keychain, kpConfig, config.StackArtifact, "some-registry.com/some-repo/image@sha256:some-digest")
+			require.NoError(t, err)
+
+			assert.Equal(t, "my-registy.com/my-stacks@sha256:some-digest", image)
+		})
 	})
 }
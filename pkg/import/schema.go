@@ -0,0 +1,201 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package _import
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var descriptorTypes = map[string]reflect.Type{
+	APIVersionV1:      reflect.TypeOf(DependencyDescriptorV1{}),
+	APIVersionV3:      reflect.TypeOf(DependencyDescriptorV3{}),
+	CurrentAPIVersion: reflect.TypeOf(DependencyDescriptor{}),
+}
+
+// Schema returns the JSON Schema of the current dependency descriptor version
+// that editors can use to complete and validate dependency descriptors
+func Schema() ([]byte, error) {
+	schema := typeSchema(reflect.TypeOf(DependencyDescriptor{}))
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	schema["title"] = "kp dependency descriptor " + CurrentAPIVersion
+	schema["required"] = []string{"apiVersion"}
+	schema["properties"].(map[string]interface{})["apiVersion"] = map[string]interface{}{
+		"type":  "string",
+		"const": CurrentAPIVersion,
+	}
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+func typeSchema(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeSchema(t.Elem())
+	case reflect.Struct:
+		properties := map[string]interface{}{}
+		for name, field := range descriptorFields(t) {
+			properties[name] = typeSchema(field)
+		}
+		return map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{
+			"type":  "array",
+			"items": typeSchema(t.Elem()),
+		}
+	case reflect.Map:
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": typeSchema(t.Elem()),
+		}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	default:
+		return map[string]interface{}{"type": "string"}
+	}
+}
+
+// descriptorFields returns the fields of a descriptor type by the name they
+// have in a dependency descriptor, the fields of embedded structs are inlined
+// the way they are when the descriptor is unmarshalled
+func descriptorFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+
+		name := tagName(f)
+		if name == "-" {
+			continue
+		}
+
+		if name == "" && f.Anonymous && f.Type.Kind() == reflect.Struct {
+			for n, ft := range descriptorFields(f.Type) {
+				fields[n] = ft
+			}
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func tagName(f reflect.StructField) string {
+	for _, key := range []string{"json", "yaml"} {
+		if tag, ok := f.Tag.Lookup(key); ok {
+			name, _, _ := strings.Cut(tag, ",")
+			return name
+		}
+	}
+	return ""
+}
+
+// checkFields reports the fields of the raw descriptor that are not fields of
+// its apiVersion with their line and records the line of every field and list
+// item by its path such as clusterBuilders[0].clusterStack
+func checkFields(rawDescriptor string) (map[string]int, []Problem, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(rawDescriptor), &node); err != nil {
+		return nil, nil, err
+	}
+
+	var api API
+	if err := node.Decode(&api); err != nil {
+		return nil, nil, err
+	}
+
+	positions := map[string]int{}
+	t, ok := descriptorTypes[api.Version]
+	if !ok {
+		return positions, nil, nil
+	}
+
+	var problems []Problem
+	walkFields(&node, t, "", positions, &problems)
+	return positions, problems, nil
+}
+
+func walkFields(node *yaml.Node, t reflect.Type, path string, positions map[string]int, problems *[]Problem) {
+	if node.Kind == yaml.DocumentNode {
+		for _, n := range node.Content {
+			walkFields(n, t, path, positions, problems)
+		}
+		return
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fields := descriptorFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			fieldPath := joinPath(path, key.Value)
+
+			field, ok := fields[key.Value]
+			if !ok {
+				*problems = append(*problems, Problem{Line: key.Line, Message: unknownField(key.Value, path, fields)})
+				continue
+			}
+
+			positions[fieldPath] = key.Line
+			walkFields(value, field, fieldPath, positions, problems)
+		}
+	case t.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			fieldPath := joinPath(path, key.Value)
+			positions[fieldPath] = key.Line
+			walkFields(value, t.Elem(), fieldPath, positions, problems)
+		}
+	case (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && node.Kind == yaml.SequenceNode:
+		for i, item := range node.Content {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			positions[itemPath] = item.Line
+			walkFields(item, t.Elem(), itemPath, positions, problems)
+		}
+	}
+}
+
+func unknownField(field, path string, fields map[string]reflect.Type) string {
+	msg := fmt.Sprintf("unknown field '%s'", field)
+	if path != "" {
+		msg = fmt.Sprintf("%s in %s", msg, path)
+	}
+
+	for name := range fields {
+		if strings.EqualFold(name, field) {
+			return fmt.Sprintf("%s, did you mean '%s'?", msg, name)
+		}
+	}
+	return msg
+}
+
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
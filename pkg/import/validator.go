@@ -0,0 +1,215 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package _import
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	"github.com/pivotal/kpack/pkg/client/clientset/versioned"
+	"github.com/pivotal/kpack/pkg/registry/imagehelpers"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/registry"
+)
+
+const (
+	buildpackLayersLabel      = "io.buildpacks.buildpack.layers"
+	buildpackageMetadataLabel = "io.buildpacks.buildpackage.metadata"
+)
+
+// Problem is a problem found in a dependency descriptor, Line is 0 when the
+// problem has no position in the descriptor
+type Problem struct {
+	Line    int
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Line == 0 {
+		return p.Message
+	}
+	return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+}
+
+// Validator validates a dependency descriptor beyond what is validated when it
+// is imported. References are resolved against the cluster when Client is set
+// and the buildpack ids of builder orders are checked against the buildpacks
+// their cluster store provides when Fetcher is set.
+type Validator struct {
+	Fetcher registry.Fetcher
+	Client  versioned.Interface
+}
+
+// Validate returns every problem found in the raw dependency descriptor
+func (v Validator) Validate(ctx context.Context, keychain authn.Keychain, rawDescriptor string) ([]Problem, error) {
+	positions, problems, err := checkFields(rawDescriptor)
+	if err != nil {
+		return []Problem{{Message: err.Error()}}, nil
+	}
+
+	descriptor, err := ReadDescriptor(rawDescriptor)
+	if err != nil {
+		return append(problems, Problem{Message: err.Error()}), nil
+	}
+
+	if !descriptor.HasLifecycleImage() {
+		problems = append(problems, Problem{Line: positions["lifecycle"], Message: "lifecycle image is empty"})
+	}
+
+	unresolved, err := unresolvedReferences(ctx, v.Client, descriptor)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ref := range unresolved {
+		problems = append(problems, Problem{Line: positions[ref.Path], Message: ref.unresolved(v.Client != nil)})
+	}
+
+	orderProblems, err := v.validateOrders(ctx, keychain, descriptor, unresolved, positions)
+	if err != nil {
+		return nil, err
+	}
+
+	problems = append(problems, orderProblems...)
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[j].Line == 0 {
+			return problems[i].Line != 0
+		}
+		return problems[i].Line != 0 && problems[i].Line < problems[j].Line
+	})
+	return problems, nil
+}
+
+// validateOrders reports the buildpack ids of builder orders that are not
+// provided by the cluster store of the builder
+func (v Validator) validateOrders(ctx context.Context, keychain authn.Keychain, descriptor DependencyDescriptor, unresolved []Reference, positions map[string]int) ([]Problem, error) {
+	skip := map[string]bool{}
+	for _, ref := range unresolved {
+		if ref.Kind == v1alpha2.ClusterStoreKind {
+			skip[ref.Name] = true
+		}
+	}
+
+	storeIds := map[string]map[string]bool{}
+	var problems []Problem
+	for _, b := range descriptor.builders() {
+		if b.store == "" || skip[b.store] {
+			continue
+		}
+
+		ids, ok := storeIds[b.store]
+		if !ok {
+			var err error
+			ids, ok, err = v.storeBuildpackIds(ctx, keychain, descriptor, b.store)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				skip[b.store] = true
+				continue
+			}
+			storeIds[b.store] = ids
+		}
+
+		for i, entry := range b.order {
+			for j, bp := range entry.Group {
+				if bp.Kind != "" || bp.Image != "" || bp.Id == "" || ids[bp.Id] {
+					continue
+				}
+
+				problems = append(problems, Problem{
+					Line:    positions[b.orderPath(i, j)],
+					Message: fmt.Sprintf("%s references buildpack id '%s' which is not provided by cluster store '%s'", b.referrer, bp.Id, b.store),
+				})
+			}
+		}
+	}
+	return problems, nil
+}
+
+// storeBuildpackIds returns the ids of the buildpacks a cluster store provides,
+// from the buildpackages of the descriptor or the status of the cluster store.
+// It returns false when the ids cannot be looked up.
+func (v Validator) storeBuildpackIds(ctx context.Context, keychain authn.Keychain, descriptor DependencyDescriptor, storeName string) (map[string]bool, bool, error) {
+	ids := map[string]bool{}
+	for _, store := range descriptor.ClusterStores {
+		if store.Name != storeName {
+			continue
+		}
+
+		if v.Fetcher == nil {
+			return nil, false, nil
+		}
+
+		for _, src := range store.Sources {
+			img, err := v.Fetcher.Fetch(keychain, src.Image)
+			if err != nil {
+				return nil, false, err
+			}
+
+			bpIds, err := buildpackIds(img)
+			if err != nil {
+				return nil, false, err
+			}
+
+			for _, id := range bpIds {
+				ids[id] = true
+			}
+		}
+		return ids, true, nil
+	}
+
+	if v.Client == nil {
+		return nil, false, nil
+	}
+
+	store, err := v.Client.KpackV1alpha2().ClusterStores().Get(ctx, storeName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	for _, bp := range store.Status.Buildpacks {
+		ids[bp.Id] = true
+	}
+	return ids, true, nil
+}
+
+// buildpackIds returns the ids of the buildpacks of a buildpackage, which are
+// the keys of its buildpack layers or the id of its metadata
+func buildpackIds(img v1.Image) ([]string, error) {
+	hasLayers, err := imagehelpers.HasLabel(img, buildpackLayersLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	if hasLayers {
+		var layers map[string]json.RawMessage
+		if err := imagehelpers.GetLabel(img, buildpackLayersLabel, &layers); err != nil {
+			return nil, err
+		}
+
+		var ids []string
+		for id := range layers {
+			ids = append(ids, id)
+		}
+		return ids, nil
+	}
+
+	var metadata struct {
+		Id string `json:"id"`
+	}
+	if err := imagehelpers.GetLabel(img, buildpackageMetadataLabel, &metadata); err != nil {
+		return nil, err
+	}
+	return []string{metadata.Id}, nil
+}
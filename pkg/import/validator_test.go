@@ -0,0 +1,264 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package _import_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	corev1alpha1 "github.com/pivotal/kpack/pkg/apis/core/v1alpha1"
+	kpackfakes "github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"github.com/pivotal/kpack/pkg/registry/registryfakes"
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	importpkg "github.com/vmware-tanzu/kpack-cli/pkg/import"
+	"github.com/vmware-tanzu/kpack-cli/pkg/registry/fakes"
+)
+
+func TestValidator(t *testing.T) {
+	spec.Run(t, "TestValidator", testValidator)
+}
+
+func testValidator(t *testing.T, when spec.G, it spec.S) {
+	var (
+		keychain  = &registryfakes.FakeKeychain{}
+		fetcher   *fakes.Fetcher
+		client    *kpackfakes.Clientset
+		validator importpkg.Validator
+	)
+
+	it.Before(func() {
+		fetcher = fakes.NewBuildpackImagesFetcher(fakes.BuildpackImgInfo{
+			Id:        "some-buildpack-id",
+			ImageInfo: fakes.ImageInfo{Ref: "some-registry.io/buildpack", Digest: "buildpack-digest"},
+		})
+		client = kpackfakes.NewSimpleClientset()
+		validator = importpkg.Validator{Fetcher: fetcher, Client: client}
+	})
+
+	validate := func(descriptor string) []string {
+		problems, err := validator.Validate(context.Background(), keychain, descriptor)
+		require.NoError(t, err)
+
+		var messages []string
+		for _, p := range problems {
+			messages = append(messages, p.String())
+		}
+		return messages
+	}
+
+	const validDescriptor = `apiVersion: kp.kpack.io/v1alpha4
+kind: DependencyDescriptor
+lifecycle:
+  image: some-registry.io/lifecycle
+clusterStores:
+- name: some-store
+  sources:
+  - image: some-registry.io/buildpack
+clusterStacks:
+- name: some-stack
+  buildImage:
+    image: some-registry.io/build
+  runImage:
+    image: some-registry.io/run
+clusterBuilders:
+- name: some-builder
+  clusterStack: some-stack
+  clusterStore: some-store
+  order:
+  - group:
+    - id: some-buildpack-id
+`
+
+	it("finds no problems in a valid descriptor", func() {
+		require.Empty(t, validate(validDescriptor))
+	})
+
+	it("reports unknown fields with their line", func() {
+		require.Equal(t, []string{
+			"line 2: unknown field 'Kind', did you mean 'kind'?",
+			"line 5: unknown field 'digset' in lifecycle",
+			"line 14: unknown field 'clusterstack' in clusterBuilders[0], did you mean 'clusterStack'?",
+		}, validate(`apiVersion: kp.kpack.io/v1alpha4
+Kind: DependencyDescriptor
+lifecycle:
+  image: some-registry.io/lifecycle
+  digset: sha256:1234
+clusterStacks:
+- name: some-stack
+  buildImage:
+    image: some-registry.io/build
+  runImage:
+    image: some-registry.io/run
+clusterBuilders:
+- name: some-builder
+  clusterstack: some-stack
+`))
+	})
+
+	it("reports an empty lifecycle", func() {
+		require.Equal(t, []string{
+			"line 3: lifecycle image is empty",
+		}, validate(`apiVersion: kp.kpack.io/v1alpha4
+kind: DependencyDescriptor
+lifecycle:
+  image: ""
+`))
+	})
+
+	it("reports references that are neither in the descriptor nor in the cluster", func() {
+		require.Equal(t, []string{
+			"line 6: builder 'some-builder' in namespace 'some-namespace' references cluster stack 'some-stack' which is not defined in the descriptor or the cluster",
+			"line 9: builder 'some-builder' in namespace 'some-namespace' references buildpack 'some-buildpack' which is not defined in the descriptor or the cluster",
+		}, validate(`apiVersion: kp.kpack.io/v1alpha4
+kind: DependencyDescriptor
+builders:
+- name: some-builder
+  namespace: some-namespace
+  clusterStack: some-stack
+  order:
+  - group:
+    - kind: Buildpack
+      name: some-buildpack
+lifecycle:
+  image: some-registry.io/lifecycle
+`))
+	})
+
+	it("resolves references in the cluster", func() {
+		client = kpackfakes.NewSimpleClientset(
+			&v1alpha2.ClusterStack{ObjectMeta: metav1.ObjectMeta{Name: "some-stack"}},
+			&v1alpha2.ClusterStore{
+				ObjectMeta: metav1.ObjectMeta{Name: "some-store"},
+				Status: v1alpha2.ClusterStoreStatus{
+					Buildpacks: []corev1alpha1.BuildpackStatus{
+						{BuildpackInfo: corev1alpha1.BuildpackInfo{Id: "some-buildpack-id"}},
+					},
+				},
+			},
+		)
+		validator.Client = client
+
+		require.Equal(t, []string{
+			"line 10: cluster builder 'some-builder' references buildpack id 'other-buildpack-id' which is not provided by cluster store 'some-store'",
+		}, validate(`apiVersion: kp.kpack.io/v1alpha4
+kind: DependencyDescriptor
+clusterBuilders:
+- name: some-builder
+  clusterStack: some-stack
+  clusterStore: some-store
+  order:
+  - group:
+    - id: some-buildpack-id
+    - id: other-buildpack-id
+lifecycle:
+  image: some-registry.io/lifecycle
+`))
+	})
+
+	it("reports buildpack ids that are not provided by the store of the descriptor", func() {
+		require.Equal(t, []string{
+			"line 22: cluster builder 'some-builder' references buildpack id 'other-buildpack-id' which is not provided by cluster store 'some-store'",
+		}, validate(validDescriptor+"    - id: other-buildpack-id\n"))
+	})
+
+	it("only resolves references in the descriptor when offline", func() {
+		validator = importpkg.Validator{}
+
+		require.Equal(t, []string{
+			"line 5: cluster builder 'some-builder' references cluster stack 'some-stack' which is not defined in the descriptor",
+		}, validate(`apiVersion: kp.kpack.io/v1alpha4
+kind: DependencyDescriptor
+clusterBuilders:
+- name: some-builder
+  clusterStack: some-stack
+  order:
+  - group:
+    - id: other-buildpack-id
+lifecycle:
+  image: some-registry.io/lifecycle
+`))
+		require.Zero(t, fetcher.CallCount())
+	})
+
+	it("reports a descriptor that cannot be read", func() {
+		require.Equal(t, []string{
+			"duplicate cluster builder name 'some-builder'",
+		}, validate(validDescriptor+`- name: some-builder
+  clusterStack: some-stack
+`))
+	})
+
+	it("validates older descriptor versions", func() {
+		require.Equal(t, []string{
+			"line 11: unknown field 'clusterStack' in clusterBuilders[0]",
+			"lifecycle image is empty",
+		}, validate(`apiVersion: kp.kpack.io/v1alpha1
+kind: DependencyDescriptor
+stacks:
+- name: some-stack
+  buildImage:
+    image: some-registry.io/build
+  runImage:
+    image: some-registry.io/run
+clusterBuilders:
+- name: some-builder
+  clusterStack: some-stack
+  stack: some-stack
+`))
+	})
+
+	it("reports fields of newer descriptor versions as unknown", func() {
+		require.Equal(t, []string{
+			"line 7: unknown field 'repository' in clusterStores[0]",
+			"line 14: unknown field 'repository' in clusterStacks[0]",
+		}, validate(`apiVersion: kp.kpack.io/v1alpha3
+kind: DependencyDescriptor
+lifecycle:
+  image: some-registry.io/lifecycle
+clusterStores:
+- name: some-store
+  repository: some-registry.io/store
+  sources:
+  - image: some-registry.io/buildpack
+clusterStacks:
+- name: some-stack
+  buildImage:
+    image: some-registry.io/build
+  repository: some-registry.io/stack
+  runImage:
+    image: some-registry.io/run
+`))
+	})
+
+	when("Schema", func() {
+		it("describes the fields of the current descriptor version", func() {
+			raw, err := importpkg.Schema()
+			require.NoError(t, err)
+
+			var schema struct {
+				Properties map[string]struct {
+					Const string `json:"const"`
+					Items struct {
+						Properties           map[string]interface{} `json:"properties"`
+						AdditionalProperties bool                   `json:"additionalProperties"`
+					} `json:"items"`
+				} `json:"properties"`
+				AdditionalProperties bool `json:"additionalProperties"`
+			}
+			require.NoError(t, json.Unmarshal(raw, &schema))
+
+			require.False(t, schema.AdditionalProperties)
+			require.Equal(t, importpkg.CurrentAPIVersion, schema.Properties["apiVersion"].Const)
+			require.Contains(t, schema.Properties["clusterBuildpacks"].Items.Properties, "image")
+			require.Contains(t, schema.Properties["clusterBuildpacks"].Items.Properties, "serviceAccountRef")
+			require.Contains(t, schema.Properties["clusterBuilders"].Items.Properties, "order")
+			require.False(t, schema.Properties["clusterBuilders"].Items.AdditionalProperties)
+		})
+	})
+}
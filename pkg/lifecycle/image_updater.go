@@ -50,7 +50,7 @@ func UpdateImage(ctx context.Context, keychain authn.Keychain, srcImgLocation st
 		return cm, err
 	}
 
-	relocatedImgTag, err := relocateImageToDefaultRepo(ctx, keychain, img, cfg)
+	relocatedImgTag, err := relocateImage(ctx, keychain, img, cfg)
 	if err != nil {
 		return cm, err
 	}
@@ -79,9 +79,16 @@ func validateImage(img ggcrv1.Image) error {
 	return nil
 }
 
-func relocateImageToDefaultRepo(ctx context.Context, keychain authn.Keychain, img ggcrv1.Image, cfg ImageUpdaterConfig) (string, error) {
+// relocateImage relocates the lifecycle image to the lifecycle repository of
+// the kp-config, or to the lifecycle image name in the default repository when
+// it is not set
+func relocateImage(ctx context.Context, keychain authn.Keychain, img ggcrv1.Image, cfg ImageUpdaterConfig) (string, error) {
 	kpConfig := config.NewKpConfigProvider(cfg.ClientSet.K8sClient).GetKpConfig(ctx)
 
+	if repo := kpConfig.ArtifactRepository(config.LifecycleArtifact); repo != "" {
+		return cfg.ImgRelocator.Relocate(keychain, img, repo)
+	}
+
 	defaultRepo, err := kpConfig.DefaultRepository()
 	if err != nil {
 		return "", err
@@ -5,7 +5,8 @@ package registry
 
 import v1 "github.com/google/go-containerregistry/pkg/v1"
 
-func imageSize(image v1.Image) (int64, error) {
+// ImageSize returns the size of the image manifest and its layers
+func ImageSize(image v1.Image) (int64, error) {
 	size, err := image.Size()
 	if err != nil {
 		return 0, err
@@ -104,7 +104,7 @@ func getDstImageInfo(srcImage v1.Image, dstRepoStr string) (relocateImageInfo, e
 		return imgInfo, err
 	}
 
-	size, err := imageSize(srcImage)
+	size, err := ImageSize(srcImage)
 	if err != nil {
 		return imgInfo, err
 	}
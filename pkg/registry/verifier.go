@@ -0,0 +1,308 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+	cosignSignatureTagSuffix  = ".sig"
+)
+
+// Verification is what a source image must match before it is relocated.
+// The digest is the expected image digest and the public key is a PEM
+// encoded cosign public key that must have signed the image.
+type Verification struct {
+	Digest    string
+	PublicKey []byte
+}
+
+func (v Verification) isEmpty() bool {
+	return v.Digest == "" && len(v.PublicKey) == 0
+}
+
+// ReadPublicKey reads a PEM encoded cosign public key file
+func ReadPublicKey(path string) ([]byte, error) {
+	key, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ValidatePublicKey(key); err != nil {
+		return nil, errors.Wrapf(err, "invalid public key %s", path)
+	}
+	return key, nil
+}
+
+// ValidatePublicKey validates that the key is a PEM encoded ECDSA, RSA or ED25519 public key
+func ValidatePublicKey(publicKey []byte) error {
+	_, err := parsePublicKey(publicKey)
+	return err
+}
+
+// VerificationError reports every source image that failed verification
+type VerificationError struct {
+	Failures []VerificationFailure
+}
+
+type VerificationFailure struct {
+	Source string
+	Reason string
+}
+
+func (e *VerificationError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("verification failed for %d source image(s), no images were relocated:", len(e.Failures)))
+	for _, f := range e.Failures {
+		sb.WriteString(fmt.Sprintf("\n\t%s: %s", f.Source, f.Reason))
+	}
+	return sb.String()
+}
+
+// VerifyingFetcher fetches source images and verifies the ones it expects
+// against their digest and cosign signature. Source images pinned to a
+// digest with <image>@sha256:<digest> are always verified against it.
+type VerifyingFetcher struct {
+	fetcher  Fetcher
+	expected map[string]Verification
+}
+
+func NewVerifyingFetcher(fetcher Fetcher) *VerifyingFetcher {
+	return &VerifyingFetcher{
+		fetcher:  fetcher,
+		expected: map[string]Verification{},
+	}
+}
+
+// Expect registers the verification of a source image, it is checked by
+// VerifyAll and every time the source image is fetched
+func (f *VerifyingFetcher) Expect(src string, verification Verification) {
+	f.expected[src] = verification
+}
+
+func (f *VerifyingFetcher) Fetch(keychain authn.Keychain, src string) (v1.Image, error) {
+	img, reason, err := f.fetchAndVerify(keychain, src)
+	if err != nil {
+		return nil, err
+	} else if reason != "" {
+		return nil, &VerificationError{Failures: []VerificationFailure{{Source: src, Reason: reason}}}
+	}
+	return img, nil
+}
+
+// VerifyAll verifies every expected source image and reports all failures
+// together so that nothing is relocated unless every source image is trusted
+func (f *VerifyingFetcher) VerifyAll(keychain authn.Keychain) error {
+	var sources []string
+	for src, verification := range f.expected {
+		if verification.isEmpty() && pinnedDigest(src) == "" {
+			continue
+		}
+		sources = append(sources, src)
+	}
+	sort.Strings(sources)
+
+	verificationErr := &VerificationError{}
+	for _, src := range sources {
+		_, reason, err := f.fetchAndVerify(keychain, src)
+		if err != nil {
+			reason = err.Error()
+		}
+		if reason != "" {
+			verificationErr.Failures = append(verificationErr.Failures, VerificationFailure{Source: src, Reason: reason})
+		}
+	}
+
+	if len(verificationErr.Failures) > 0 {
+		return verificationErr
+	}
+	return nil
+}
+
+// fetchAndVerify returns the reason the source image failed verification,
+// errors are only returned when the source image cannot be fetched
+func (f *VerifyingFetcher) fetchAndVerify(keychain authn.Keychain, src string) (v1.Image, string, error) {
+	img, err := f.fetcher.Fetch(keychain, src)
+	if err != nil {
+		return nil, "", err
+	}
+
+	verification := f.expected[src]
+	if verification.Digest == "" {
+		verification.Digest = pinnedDigest(src)
+	}
+	if verification.isEmpty() {
+		return img, "", nil
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if verification.Digest != "" && verification.Digest != digest.String() {
+		return nil, fmt.Sprintf("digest %s does not match the expected digest %s", digest, verification.Digest), nil
+	}
+
+	if len(verification.PublicKey) > 0 {
+		reason, err := f.verifySignature(keychain, src, digest, verification.PublicKey)
+		if err != nil || reason != "" {
+			return nil, reason, err
+		}
+	}
+
+	return img, "", nil
+}
+
+func (f *VerifyingFetcher) verifySignature(keychain authn.Keychain, src string, digest v1.Hash, publicKey []byte) (string, error) {
+	key, err := parsePublicKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+
+	if isLocalFile(src) {
+		return "signatures of local files cannot be verified, provide the expected digest instead", nil
+	}
+
+	ref, err := name.ParseReference(src, name.WeakValidation)
+	if err != nil {
+		return "", err
+	}
+
+	sigTag := ref.Context().Tag(strings.Replace(digest.String(), ":", "-", 1) + cosignSignatureTagSuffix)
+	sigImg, err := f.fetcher.Fetch(keychain, sigTag.String())
+	if err != nil {
+		return fmt.Sprintf("no cosign signature found at %s", sigTag), nil
+	}
+
+	manifest, err := sigImg.Manifest()
+	if err != nil {
+		return "", err
+	}
+
+	layers, err := sigImg.Layers()
+	if err != nil {
+		return "", err
+	}
+
+	for i, desc := range manifest.Layers {
+		if i >= len(layers) {
+			break
+		}
+
+		signature, err := base64.StdEncoding.DecodeString(desc.Annotations[cosignSignatureAnnotation])
+		if err != nil || len(signature) == 0 {
+			continue
+		}
+
+		payload, err := readLayer(layers[i])
+		if err != nil {
+			return "", err
+		}
+
+		if verifySignature(key, payload, signature) && signedDigest(payload) == digest.String() {
+			return "", nil
+		}
+	}
+
+	return fmt.Sprintf("no cosign signature at %s was signed by the public key for digest %s", sigTag, digest), nil
+}
+
+func parsePublicKey(publicKey []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(publicKey)
+	if block == nil {
+		return nil, errors.New("public key is not PEM encoded")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch key.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+		return key, nil
+	default:
+		return nil, errors.Errorf("unsupported public key type %T", key)
+	}
+}
+
+func verifySignature(key crypto.PublicKey, payload, signature []byte) bool {
+	hash := sha256.Sum256(payload)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(k, hash[:], signature)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, hash[:], signature) == nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, payload, signature)
+	}
+	return false
+}
+
+// signedDigest returns the image digest of a cosign simple signing payload
+func signedDigest(payload []byte) string {
+	var p struct {
+		Critical struct {
+			Image struct {
+				DockerManifestDigest string `json:"docker-manifest-digest"`
+			} `json:"image"`
+		} `json:"critical"`
+	}
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return ""
+	}
+	return p.Critical.Image.DockerManifestDigest
+}
+
+func readLayer(layer v1.Layer) ([]byte, error) {
+	rc, err := layer.Compressed()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// pinnedDigest returns the digest of a source image reference such as <image>@sha256:<digest>
+func pinnedDigest(src string) string {
+	if isLocalFile(src) {
+		return ""
+	}
+
+	ref, err := name.ParseReference(src, name.WeakValidation)
+	if err != nil {
+		return ""
+	}
+
+	if d, ok := ref.(name.Digest); ok {
+		return d.DigestStr()
+	}
+	return ""
+}
+
+func isLocalFile(src string) bool {
+	_, err := os.Stat(src)
+	return err == nil
+}
@@ -0,0 +1,173 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package registry_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/pivotal/kpack/pkg/registry/registryfakes"
+	"github.com/sclevine/spec"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/kpack-cli/pkg/registry"
+	"github.com/vmware-tanzu/kpack-cli/pkg/registry/fakes"
+)
+
+func TestVerifyingFetcher(t *testing.T) {
+	spec.Run(t, "Test VerifyingFetcher", testVerifyingFetcher)
+}
+
+func testVerifyingFetcher(t *testing.T, when spec.G, it spec.S) {
+	const source = "some-registry.io/some-stack:latest"
+
+	var (
+		keychain = &registryfakes.FakeKeychain{}
+		fetcher  *fakes.Fetcher
+		verifier *registry.VerifyingFetcher
+		digest   string
+	)
+
+	it.Before(func() {
+		img, err := random.Image(10, 1)
+		require.NoError(t, err)
+
+		hash, err := img.Digest()
+		require.NoError(t, err)
+		digest = hash.String()
+
+		fetcher = &fakes.Fetcher{}
+		fetcher.AddImage(source, img)
+		verifier = registry.NewVerifyingFetcher(fetcher)
+	})
+
+	signatureTag := func() string {
+		return "some-registry.io/some-stack:" + strings.Replace(digest, ":", "-", 1) + ".sig"
+	}
+
+	when("no verification is expected", func() {
+		it("fetches the source image", func() {
+			_, err := verifier.Fetch(keychain, source)
+			require.NoError(t, err)
+			require.NoError(t, verifier.VerifyAll(keychain))
+		})
+	})
+
+	when("a digest is expected", func() {
+		it("accepts the source image with the digest", func() {
+			verifier.Expect(source, registry.Verification{Digest: digest})
+
+			_, err := verifier.Fetch(keychain, source)
+			require.NoError(t, err)
+		})
+
+		it("rejects the source image with another digest", func() {
+			verifier.Expect(source, registry.Verification{Digest: "sha256:0000"})
+
+			_, err := verifier.Fetch(keychain, source)
+			require.EqualError(t, err, fmt.Sprintf(`verification failed for 1 source image(s), no images were relocated:
+	%s: digest %s does not match the expected digest sha256:0000`, source, digest))
+		})
+	})
+
+	when("a public key is expected", func() {
+		var (
+			key       *ecdsa.PrivateKey
+			publicKey []byte
+		)
+
+		it.Before(func() {
+			var err error
+			key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			require.NoError(t, err)
+
+			der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+			require.NoError(t, err)
+			publicKey = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+			verifier.Expect(source, registry.Verification{PublicKey: publicKey})
+		})
+
+		addSignature := func(signedDigest string) {
+			payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"some-registry.io/some-stack"},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, signedDigest))
+			hash := sha256.Sum256(payload)
+			signature, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+			require.NoError(t, err)
+
+			sigImg, err := mutate.Append(empty.Image, mutate.Addendum{
+				Layer: static.NewLayer(payload, "application/vnd.dev.cosign.simplesigning.v1+json"),
+				Annotations: map[string]string{
+					"dev.cosignproject.cosign/signature": base64.StdEncoding.EncodeToString(signature),
+				},
+				MediaType: types.MediaType("application/vnd.dev.cosign.simplesigning.v1+json"),
+			})
+			require.NoError(t, err)
+			fetcher.AddImage(signatureTag(), sigImg)
+		}
+
+		it("accepts a source image signed with the key", func() {
+			addSignature(digest)
+
+			_, err := verifier.Fetch(keychain, source)
+			require.NoError(t, err)
+			require.NoError(t, verifier.VerifyAll(keychain))
+		})
+
+		it("rejects a source image without a signature", func() {
+			err := verifier.VerifyAll(keychain)
+			require.EqualError(t, err, fmt.Sprintf(`verification failed for 1 source image(s), no images were relocated:
+	%s: no cosign signature found at %s`, source, signatureTag()))
+		})
+
+		it("rejects a signature of another digest", func() {
+			addSignature("sha256:0000")
+
+			err := verifier.VerifyAll(keychain)
+			require.EqualError(t, err, fmt.Sprintf(`verification failed for 1 source image(s), no images were relocated:
+	%s: no cosign signature at %s was signed by the public key for digest %s`, source, signatureTag(), digest))
+		})
+
+		it("rejects a signature made with another key", func() {
+			var err error
+			key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			require.NoError(t, err)
+			addSignature(digest)
+
+			_, err = verifier.Fetch(keychain, source)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "was signed by the public key")
+		})
+	})
+
+	when("several source images fail verification", func() {
+		it("reports all of them", func() {
+			const other = "some-registry.io/other-stack:latest"
+			img, err := random.Image(10, 1)
+			require.NoError(t, err)
+			fetcher.AddImage(other, img)
+
+			verifier.Expect(source, registry.Verification{Digest: "sha256:0000"})
+			verifier.Expect(other, registry.Verification{Digest: "sha256:1111"})
+			verifier.Expect("some-registry.io/missing:latest", registry.Verification{Digest: "sha256:2222"})
+
+			err = verifier.VerifyAll(keychain)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "verification failed for 3 source image(s)")
+			require.Contains(t, err.Error(), `some-registry.io/missing:latest: image not found: "some-registry.io/missing:latest"`)
+		})
+	})
+}
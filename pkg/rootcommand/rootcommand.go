@@ -3,9 +3,12 @@ package rootcommand
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/pivotal/kpack/pkg/logs"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/dynamic"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/commands"
 	buildcmds "github.com/vmware-tanzu/kpack-cli/pkg/commands/build"
@@ -33,7 +36,11 @@ var (
 )
 
 func GetRootCommand() *cobra.Command {
-	var clientSetProvider k8s.DefaultClientSetProvider
+	var (
+		clientSetProvider k8s.DefaultClientSetProvider
+		waitTimeout       time.Duration
+	)
+	newWaiter := commands.NewResourceWaiterWithTimeout(&waitTimeout)
 
 	rootCmd := &cobra.Command{
 		Use: "kp",
@@ -42,20 +49,27 @@ func GetRootCommand() *cobra.Command {
 kpack extends Kubernetes and utilizes unprivileged kubernetes primitives to provide 
 builds of OCI images as a platform implementation of Cloud Native Buildpacks (CNB).
 Learn more about kpack @ https://github.com/pivotal/kpack`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if waitTimeout <= 0 {
+				return errors.Errorf("--%s must be greater than zero", commands.WaitTimeoutFlag)
+			}
+			return nil
+		},
 	}
+	rootCmd.PersistentFlags().DurationVar(&waitTimeout, commands.WaitTimeoutFlag, commands.DefaultWaitTimeout, "maximum time to wait for kpack resources to become ready")
 	rootCmd.AddCommand(
 		getVersionCommand(),
 		getImageCommand(clientSetProvider),
 		getBuildCommand(clientSetProvider),
-		getBuildpackCommand(clientSetProvider),
+		getBuildpackCommand(clientSetProvider, newWaiter),
 		getSecretCommand(clientSetProvider),
-		getClusterBuilderCommand(clientSetProvider),
-		getClusterBuildpackCommand(clientSetProvider),
-		getBuilderCommand(clientSetProvider),
-		getStackCommand(clientSetProvider),
-		getStoreCommand(clientSetProvider),
-		getLifecycleCommand(clientSetProvider),
-		getImportCommand(clientSetProvider),
+		getClusterBuilderCommand(clientSetProvider, newWaiter),
+		getClusterBuildpackCommand(clientSetProvider, newWaiter),
+		getBuilderCommand(clientSetProvider, newWaiter),
+		getStackCommand(clientSetProvider, newWaiter),
+		getStoreCommand(clientSetProvider, newWaiter),
+		getLifecycleCommand(clientSetProvider, newWaiter),
+		getImportCommand(clientSetProvider, newWaiter),
 		getConfigCommand(clientSetProvider),
 		getCompletionCommand(),
 	)
@@ -86,12 +100,15 @@ func getImageCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 	}
 	imageRootCmd.AddCommand(
 		imgcmds.NewCreateCommand(clientSetProvider, registry.DefaultUtilProvider{}, newImageWaiter),
-		imgcmds.NewPatchCommand(clientSetProvider, registry.DefaultUtilProvider{}, newImageWaiter),
-		imgcmds.NewSaveCommand(clientSetProvider, registry.DefaultUtilProvider{}, newImageWaiter),
+		imgcmds.NewPatchCommand(clientSetProvider, registry.DefaultUtilProvider{}, newImageWaiter, commands.NewConfirmationProvider()),
+		imgcmds.NewSaveCommand(clientSetProvider, registry.DefaultUtilProvider{}, newImageWaiter, commands.NewConfirmationProvider()),
 		imgcmds.NewListCommand(clientSetProvider),
 		imgcmds.NewDeleteCommand(clientSetProvider),
 		imgcmds.NewTriggerCommand(clientSetProvider),
 		imgcmds.NewStatusCommand(clientSetProvider),
+		imgcmds.NewHistoryCommand(clientSetProvider),
+		imgcmds.NewWatchCommand(clientSetProvider),
+		imgcmds.NewWaitCommand(clientSetProvider),
 	)
 	return imageRootCmd
 }
@@ -106,6 +123,12 @@ func getBuildCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 		buildcmds.NewListCommand(clientSetProvider),
 		buildcmds.NewStatusCommand(clientSetProvider),
 		buildcmds.NewLogsCommand(clientSetProvider),
+		buildcmds.NewDiffCommand(clientSetProvider, commands.Differ{}, registry.DefaultUtilProvider{}),
+		buildcmds.NewCancelCommand(clientSetProvider),
+		buildcmds.NewRetryCommand(clientSetProvider),
+		buildcmds.NewWatchCommand(clientSetProvider),
+		buildcmds.NewDeleteCommand(clientSetProvider, commands.NewConfirmationProvider()),
+		buildcmds.NewPruneCommand(clientSetProvider, commands.NewConfirmationProvider()),
 	)
 	return buildRootCmd
 }
@@ -129,16 +152,16 @@ func getSecretCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 	return secretRootCmd
 }
 
-func getClusterBuilderCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+func getClusterBuilderCommand(clientSetProvider k8s.ClientSetProvider, newWaiter func(dynamic.Interface) commands.ResourceWaiter) *cobra.Command {
 	clusterBuilderRootCmd := &cobra.Command{
 		Use:     "clusterbuilder",
 		Short:   "ClusterBuilder Commands",
 		Aliases: []string{"clusterbuilders", "clstrbldrs", "clstrbldr", "cbldrs", "cbldr", "cbs", "cb"},
 	}
 	clusterBuilderRootCmd.AddCommand(
-		clusterbuildercmds.NewCreateCommand(clientSetProvider, commands.NewResourceWaiter),
-		clusterbuildercmds.NewPatchCommand(clientSetProvider, commands.NewResourceWaiter),
-		clusterbuildercmds.NewSaveCommand(clientSetProvider, commands.NewResourceWaiter),
+		clusterbuildercmds.NewCreateCommand(clientSetProvider, newWaiter),
+		clusterbuildercmds.NewPatchCommand(clientSetProvider, newWaiter),
+		clusterbuildercmds.NewSaveCommand(clientSetProvider, newWaiter),
 		clusterbuildercmds.NewListCommand(clientSetProvider),
 		clusterbuildercmds.NewStatusCommand(clientSetProvider),
 		clusterbuildercmds.NewDeleteCommand(clientSetProvider),
@@ -146,16 +169,16 @@ func getClusterBuilderCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Co
 	return clusterBuilderRootCmd
 }
 
-func getClusterBuildpackCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+func getClusterBuildpackCommand(clientSetProvider k8s.ClientSetProvider, newWaiter func(dynamic.Interface) commands.ResourceWaiter) *cobra.Command {
 	clusterBuilderRootCmd := &cobra.Command{
 		Use:     "clusterbuildpack",
 		Short:   "ClusterBuildpack Commands",
 		Aliases: []string{"clusterbuildpacks", "clstrbps", "clstrbp", "cbps", "cbp"},
 	}
 	clusterBuilderRootCmd.AddCommand(
-		clusterbuildpackcmds.NewCreateCommand(clientSetProvider, commands.NewResourceWaiter),
-		clusterbuildpackcmds.NewPatchCommand(clientSetProvider, commands.NewResourceWaiter),
-		clusterbuildpackcmds.NewSaveCommand(clientSetProvider, commands.NewResourceWaiter),
+		clusterbuildpackcmds.NewCreateCommand(clientSetProvider, newWaiter),
+		clusterbuildpackcmds.NewPatchCommand(clientSetProvider, newWaiter),
+		clusterbuildpackcmds.NewSaveCommand(clientSetProvider, newWaiter),
 		clusterbuildpackcmds.NewListCommand(clientSetProvider),
 		clusterbuildpackcmds.NewStatusCommand(clientSetProvider),
 		clusterbuildpackcmds.NewDeleteCommand(clientSetProvider),
@@ -163,16 +186,16 @@ func getClusterBuildpackCommand(clientSetProvider k8s.ClientSetProvider) *cobra.
 	return clusterBuilderRootCmd
 }
 
-func getBuilderCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+func getBuilderCommand(clientSetProvider k8s.ClientSetProvider, newWaiter func(dynamic.Interface) commands.ResourceWaiter) *cobra.Command {
 	builderRootCmd := &cobra.Command{
 		Use:     "builder",
 		Short:   "Builder Commands",
 		Aliases: []string{"builders", "bldrs", "bldr"},
 	}
 	builderRootCmd.AddCommand(
-		buildercmds.NewCreateCommand(clientSetProvider, commands.NewResourceWaiter),
-		buildercmds.NewPatchCommand(clientSetProvider, commands.NewResourceWaiter),
-		buildercmds.NewSaveCommand(clientSetProvider, commands.NewResourceWaiter),
+		buildercmds.NewCreateCommand(clientSetProvider, newWaiter),
+		buildercmds.NewPatchCommand(clientSetProvider, newWaiter),
+		buildercmds.NewSaveCommand(clientSetProvider, newWaiter),
 		buildercmds.NewListCommand(clientSetProvider),
 		buildercmds.NewDeleteCommand(clientSetProvider),
 		buildercmds.NewStatusCommand(clientSetProvider),
@@ -180,16 +203,16 @@ func getBuilderCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 	return builderRootCmd
 }
 
-func getBuildpackCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+func getBuildpackCommand(clientSetProvider k8s.ClientSetProvider, newWaiter func(dynamic.Interface) commands.ResourceWaiter) *cobra.Command {
 	builderRootCmd := &cobra.Command{
 		Use:     "buildpack",
 		Short:   "Buildpack Commands",
 		Aliases: []string{"buildpacks", "bp", "bps"},
 	}
 	builderRootCmd.AddCommand(
-		buildpackcmds.NewCreateCommand(clientSetProvider, commands.NewResourceWaiter),
-		buildpackcmds.NewPatchCommand(clientSetProvider, commands.NewResourceWaiter),
-		buildpackcmds.NewSaveCommand(clientSetProvider, commands.NewResourceWaiter),
+		buildpackcmds.NewCreateCommand(clientSetProvider, newWaiter),
+		buildpackcmds.NewPatchCommand(clientSetProvider, newWaiter),
+		buildpackcmds.NewSaveCommand(clientSetProvider, newWaiter),
 		buildpackcmds.NewListCommand(clientSetProvider),
 		buildpackcmds.NewDeleteCommand(clientSetProvider),
 		buildpackcmds.NewStatusCommand(clientSetProvider),
@@ -197,16 +220,16 @@ func getBuildpackCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command
 	return builderRootCmd
 }
 
-func getStackCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+func getStackCommand(clientSetProvider k8s.ClientSetProvider, newWaiter func(dynamic.Interface) commands.ResourceWaiter) *cobra.Command {
 	stackRootCmd := &cobra.Command{
 		Use:     "clusterstack",
 		Aliases: []string{"clusterstacks", "clstrcsks", "clstrcsk", "cstacks", "cstack", "cstks", "cstk", "csks", "csk"},
 		Short:   "ClusterStack Commands",
 	}
 	stackRootCmd.AddCommand(
-		clusterstackcmds.NewCreateCommand(clientSetProvider, registry.DefaultUtilProvider{}, commands.NewResourceWaiter),
-		clusterstackcmds.NewPatchCommand(clientSetProvider, registry.DefaultUtilProvider{}, commands.NewResourceWaiter),
-		clusterstackcmds.NewSaveCommand(clientSetProvider, registry.DefaultUtilProvider{}, commands.NewResourceWaiter),
+		clusterstackcmds.NewCreateCommand(clientSetProvider, registry.DefaultUtilProvider{}, newWaiter),
+		clusterstackcmds.NewPatchCommand(clientSetProvider, registry.DefaultUtilProvider{}, newWaiter),
+		clusterstackcmds.NewSaveCommand(clientSetProvider, registry.DefaultUtilProvider{}, newWaiter),
 		clusterstackcmds.NewListCommand(clientSetProvider),
 		clusterstackcmds.NewStatusCommand(clientSetProvider),
 		clusterstackcmds.NewDeleteCommand(clientSetProvider),
@@ -214,45 +237,51 @@ func getStackCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 	return stackRootCmd
 }
 
-func getStoreCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+func getStoreCommand(clientSetProvider k8s.ClientSetProvider, newWaiter func(dynamic.Interface) commands.ResourceWaiter) *cobra.Command {
 	storeRootCommand := &cobra.Command{
 		Use:     "clusterstore",
 		Aliases: []string{"clusterstores", "clstrcsrs", "clstrcsr", "cstores", "cstore", "cstrs", "cstr", "csrs", "csr"},
 		Short:   "ClusterStore Commands",
 	}
 	storeRootCommand.AddCommand(
-		clusterstorecmds.NewCreateCommand(clientSetProvider, registry.DefaultUtilProvider{}, commands.NewResourceWaiter),
-		clusterstorecmds.NewAddCommand(clientSetProvider, registry.DefaultUtilProvider{}, commands.NewResourceWaiter),
-		clusterstorecmds.NewSaveCommand(clientSetProvider, registry.DefaultUtilProvider{}, commands.NewResourceWaiter),
+		clusterstorecmds.NewCreateCommand(clientSetProvider, registry.DefaultUtilProvider{}, newWaiter),
+		clusterstorecmds.NewAddCommand(clientSetProvider, registry.DefaultUtilProvider{}, newWaiter),
+		clusterstorecmds.NewSaveCommand(clientSetProvider, registry.DefaultUtilProvider{}, newWaiter),
 		clusterstorecmds.NewDeleteCommand(clientSetProvider, commands.NewConfirmationProvider()),
 		clusterstorecmds.NewStatusCommand(clientSetProvider),
-		clusterstorecmds.NewRemoveCommand(clientSetProvider, commands.NewResourceWaiter),
+		clusterstorecmds.NewRemoveCommand(clientSetProvider, newWaiter),
 		clusterstorecmds.NewListCommand(clientSetProvider),
 	)
 
 	return storeRootCommand
 }
 
-func getLifecycleCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
+func getLifecycleCommand(clientSetProvider k8s.ClientSetProvider, newWaiter func(dynamic.Interface) commands.ResourceWaiter) *cobra.Command {
 	lifecycleRootCommand := &cobra.Command{
 		Use:   "lifecycle",
 		Short: "Lifecycle Commands",
 	}
 	lifecycleRootCommand.AddCommand(
-		lifecycle.NewUpdateCommand(clientSetProvider, registry.DefaultUtilProvider{}),
+		lifecycle.NewUpdateCommand(clientSetProvider, registry.DefaultUtilProvider{}, newWaiter),
 	)
 	return lifecycleRootCommand
 }
 
-func getImportCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
-	return importcmds.NewImportCommand(
+func getImportCommand(clientSetProvider k8s.ClientSetProvider, newWaiter func(dynamic.Interface) commands.ResourceWaiter) *cobra.Command {
+	importCmd := importcmds.NewImportCommand(
 		commands.Differ{},
 		clientSetProvider,
 		registry.DefaultUtilProvider{},
 		importpkg.DefaultTimestampProvider(),
 		commands.NewConfirmationProvider(),
-		commands.NewResourceWaiter,
+		newWaiter,
+	)
+	importCmd.AddCommand(
+		importcmds.NewLockCommand(commands.Differ{}, registry.DefaultUtilProvider{}),
+		importcmds.NewValidateCommand(clientSetProvider, registry.DefaultUtilProvider{}),
+		importcmds.NewRollbackCommand(clientSetProvider),
 	)
+	return importCmd
 }
 
 func getConfigCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
@@ -263,6 +292,7 @@ func getConfigCommand(clientSetProvider k8s.ClientSetProvider) *cobra.Command {
 	}
 	configRootCmd.AddCommand(
 		configcmds.NewDefaultRepositoryCommand(clientSetProvider),
+		configcmds.NewRepositoryCommand(clientSetProvider),
 		configcmds.NewDefaultServiceAccountCommand(clientSetProvider),
 	)
 
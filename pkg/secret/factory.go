@@ -5,6 +5,7 @@ package secret
 
 import (
 	"encoding/json"
+	"encoding/pem"
 	"os"
 	"regexp"
 	"sort"
@@ -19,10 +20,14 @@ import (
 )
 
 const (
-	DockerhubUrl  = "https://index.docker.io/v1/"
-	GcrUrl        = "gcr.io"
-	GcrUser       = "_json_key"
-	GitAnnotation = "kpack.io/git"
+	DockerhubUrl               = "https://index.docker.io/v1/"
+	GcrUrl                     = "gcr.io"
+	GcrUser                    = "_json_key"
+	GitAnnotation              = "kpack.io/git"
+	CosignRepositoryAnnotation = "kpack.io/cosign.repository"
+	CosignKeyDataKey           = "cosign.key"
+	CosignPasswordDataKey      = "cosign.password"
+	CosignTarget               = "cosign signing"
 )
 
 var gitHttpUrlRegex = regexp.MustCompile(`^(?:https?://)?(?:[a-zA-Z0-9\-\.])+\.(?:\w+)(:(?:\d){3,5})?$`)
@@ -41,6 +46,8 @@ type Factory struct {
 	GitUrl                string
 	GitSshKeyFile         string
 	GitUser               string
+	CosignKeyFile         string
+	CosignRepository      string
 }
 
 func (f *Factory) MakeSecret(name, namespace string) (*corev1.Secret, string, error) {
@@ -64,6 +71,8 @@ func (f *Factory) MakeSecret(name, namespace string) (*corev1.Secret, string, er
 		return f.makeGitSshSecret(name, namespace)
 	case gitBasicAuthKind:
 		return f.makeGitBasicAuthSecret(name, namespace)
+	case cosignKind:
+		return f.makeCosignSecret(name, namespace)
 	}
 
 	return nil, "", errors.Errorf("incorrect flags provided")
@@ -75,14 +84,16 @@ func (f *Factory) validate() error {
 	set.add("registry", f.Registry)
 	set.add("gcr", f.GcrServiceAccountFile)
 	set.add("git", f.GitUrl)
+	set.add("cosign-key", f.CosignKeyFile)
 
 	if len(set) != 1 {
-		return errors.Errorf("secret must be one of dockerhub, gcr, registry, or git")
+		return errors.Errorf("secret must be one of dockerhub, gcr, registry, git, or cosign-key")
 	}
 
 	set.add("registry-user", f.RegistryUser)
 	set.add("git-user", f.GitUser)
 	set.add("git-ssh-key", f.GitSshKeyFile)
+	set.add("cosign-repository", f.CosignRepository)
 
 	if set.contains("dockerhub") && len(set) != 1 {
 		return set.getExtraParamsError("dockerhub")
@@ -110,6 +121,16 @@ func (f *Factory) validate() error {
 		}
 	}
 
+	if set.contains("cosign-key") && len(set) != 1 {
+		if len(set) != 2 || !set.contains("cosign-repository") {
+			return set.getExtraParamsError("cosign-key", "cosign-repository")
+		}
+	}
+
+	if set.contains("cosign-repository") && !set.contains("cosign-key") {
+		return errors.Errorf("cosign-repository can only be used with cosign-key")
+	}
+
 	if f.GitUser != "" && !gitHttpUrlRegex.MatchString(f.GitUrl) {
 		return errors.Errorf("must provide a valid git url without the repository path for basic auth (ex. https://github.com)")
 	}
@@ -132,6 +153,8 @@ func (f *Factory) getSecretKind() (secretKind, error) {
 		return gitSshKind, nil
 	} else if f.GitUrl != "" && f.GitUser != "" {
 		return gitBasicAuthKind, nil
+	} else if f.CosignKeyFile != "" {
+		return cosignKind, nil
 	}
 	return "", errors.Errorf("received secret with unknown type")
 }
@@ -280,6 +303,47 @@ func (f *Factory) makeGitBasicAuthSecret(name string, namespace string) (*corev1
 	}, f.GitUrl, nil
 }
 
+// makeCosignSecret makes a secret with a cosign private key that kpack uses
+// to sign the images built with the service account the secret is attached to
+func (f *Factory) makeCosignSecret(secretName string, namespace string) (*corev1.Secret, string, error) {
+	key, err := os.ReadFile(f.CosignKeyFile)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if block, _ := pem.Decode(key); block == nil || !strings.HasSuffix(block.Type, "PRIVATE KEY") {
+		return nil, "", errors.Errorf("cosign key %s is not a PEM encoded private key", f.CosignKeyFile)
+	}
+
+	password, err := f.CredentialFetcher.FetchPassword("COSIGN_PASSWORD", "cosign key password: ")
+	if err != nil {
+		return nil, "", err
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{
+			CosignKeyDataKey:      key,
+			CosignPasswordDataKey: []byte(password),
+		},
+		Type: corev1.SecretTypeOpaque,
+	}
+
+	if f.CosignRepository != "" {
+		if _, err := name.NewRepository(f.CosignRepository, name.WeakValidation); err != nil {
+			return nil, "", errors.Wrapf(err, "invalid cosign repository %q", f.CosignRepository)
+		}
+		secret.Annotations = map[string]string{
+			CosignRepositoryAnnotation: f.CosignRepository,
+		}
+	}
+
+	return secret, CosignTarget, nil
+}
+
 type secretKind string
 
 const (
@@ -288,6 +352,7 @@ const (
 	registryKind                = "registry"
 	gitSshKind                  = "git ssh"
 	gitBasicAuthKind            = "git basic auth"
+	cosignKind                  = "cosign"
 )
 
 type paramSet map[string]interface{}
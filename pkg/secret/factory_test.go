@@ -37,7 +37,7 @@ func testSecretFactory(t *testing.T, when spec.G, it spec.S) {
 	when("no params are set", func() {
 		it("returns an error message", func() {
 			_, _, err := factory.MakeSecret("test-name", "test-namespace")
-			require.EqualError(t, err, "secret must be one of dockerhub, gcr, registry, or git")
+			require.EqualError(t, err, "secret must be one of dockerhub, gcr, registry, git, or cosign-key")
 		})
 	})
 
@@ -46,7 +46,7 @@ func testSecretFactory(t *testing.T, when spec.G, it spec.S) {
 			factory.DockerhubId = "some-dockerhub-id"
 			factory.GcrServiceAccountFile = "some-gcr-service-account"
 			_, _, err := factory.MakeSecret("test-name", "test-namespace")
-			require.EqualError(t, err, "secret must be one of dockerhub, gcr, registry, or git")
+			require.EqualError(t, err, "secret must be one of dockerhub, gcr, registry, git, or cosign-key")
 		})
 	})
 
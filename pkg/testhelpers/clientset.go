@@ -5,6 +5,7 @@ package testhelpers
 
 import (
 	kpackfakes "github.com/pivotal/kpack/pkg/client/clientset/versioned/fake"
+	"k8s.io/client-go/dynamic"
 	k8sfakes "k8s.io/client-go/kubernetes/fake"
 
 	"github.com/vmware-tanzu/kpack-cli/pkg/k8s"
@@ -55,3 +56,22 @@ func GetFakeClusterProvider(k8sClient *k8sfakes.Clientset, kpackClient *kpackfak
 		},
 	}
 }
+
+func GetFakeDynamicProvider(dynamicClient dynamic.Interface, namespace string) FakeClientSetProvider {
+	return FakeClientSetProvider{
+		clientSet: k8s.ClientSet{
+			DynamicClient: dynamicClient,
+			Namespace:     namespace,
+		},
+	}
+}
+
+func GetFakeProvider(k8sClient *k8sfakes.Clientset, kpackClient *kpackfakes.Clientset, namespace string) FakeClientSetProvider {
+	return FakeClientSetProvider{
+		clientSet: k8s.ClientSet{
+			K8sClient:   k8sClient,
+			KpackClient: kpackClient,
+			Namespace:   namespace,
+		},
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2020-Present VMware, Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package testhelpers
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/pivotal/kpack/pkg/apis/build/v1alpha2"
+	kpackscheme "github.com/pivotal/kpack/pkg/client/clientset/versioned/scheme"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+var (
+	ImagesResource = v1alpha2.SchemeGroupVersion.WithResource("images")
+	BuildsResource = v1alpha2.SchemeGroupVersion.WithResource("builds")
+)
+
+// NewFakeDynamicClient returns a fake dynamic client that can list and watch kpack images and builds
+func NewFakeDynamicClient(objs ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(kpackscheme.Scheme, map[schema.GroupVersionResource]string{
+		ImagesResource: "ImageList",
+		BuildsResource: "BuildList",
+	}, objs...)
+}
+
+// CreateDynamicObject creates a kpack object through the fake dynamic client
+func CreateDynamicObject(client *dynamicfake.FakeDynamicClient, resource schema.GroupVersionResource, obj runtime.Object) error {
+	u, err := toUnstructured(obj)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Resource(resource).Namespace(u.GetNamespace()).Create(context.Background(), u, metav1.CreateOptions{})
+	return err
+}
+
+// UpdateDynamicObject updates a kpack object through the fake dynamic client
+func UpdateDynamicObject(t *testing.T, client *dynamicfake.FakeDynamicClient, resource schema.GroupVersionResource, obj runtime.Object) {
+	t.Helper()
+
+	u, err := toUnstructured(obj)
+	require.NoError(t, err)
+
+	_, err = client.Resource(resource).Namespace(u.GetNamespace()).Update(context.Background(), u, metav1.UpdateOptions{})
+	require.NoError(t, err)
+}
+
+func toUnstructured(obj runtime.Object) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	gvks, _, err := kpackscheme.Scheme.ObjectKinds(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(gvks[0])
+	return u, nil
+}
+
+// SyncBuffer is a buffer that is safe to write from one goroutine while
+// reading from another, for commands that print in the background
+type SyncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *SyncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *SyncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}